@@ -2,6 +2,9 @@ package main
 
 import (
 	"log"
+	// embeds the time zone database, the alpine image does not ship one and profiles store
+	// IANA time zone names
+	_ "time/tzdata"
 
 	"github.com/gabrielnakaema/project-chat/internal/api"
 )
@@ -15,7 +15,11 @@ import (
 	"github.com/gabrielnakaema/project-chat/internal/db"
 	"github.com/gabrielnakaema/project-chat/internal/handlers"
 	"github.com/gabrielnakaema/project-chat/internal/logger"
+	"github.com/gabrielnakaema/project-chat/internal/mailer"
+	"github.com/gabrielnakaema/project-chat/internal/metrics"
+	"github.com/gabrielnakaema/project-chat/internal/oidc"
 	"github.com/gabrielnakaema/project-chat/internal/publisher"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
 	"github.com/gabrielnakaema/project-chat/internal/repository"
 	"github.com/gabrielnakaema/project-chat/internal/service"
 	"github.com/gabrielnakaema/project-chat/internal/subscriber"
@@ -34,11 +38,19 @@ type Api struct {
 }
 
 type Handlers struct {
+	AccessToken    *handlers.AccessTokenHandler
+	Activity       *handlers.ActivityHandler
 	AuthMiddleware *handlers.AuthMiddleware
 	Chat           *handlers.ChatHandler
+	Jwks           *handlers.JwksHandler
+	Oidc           *handlers.OidcHandler
+	Organization   *handlers.OrganizationHandler
 	Project        *handlers.ProjectHandler
+	RateLimit      *handlers.RateLimitMiddleware
 	Task           *handlers.TaskHandler
 	User           *handlers.UserHandler
+	UserDataExport *handlers.UserDataExportHandler
+	Websocket      *handlers.WebsocketHandler
 }
 
 func NewApi() (*Api, error) {
@@ -54,25 +66,47 @@ func NewApi() (*Api, error) {
 		return nil, err
 	}
 
+	db.RegisterMetrics(pool)
+
 	pub, err := publisher.NewPublisher(config, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	jwtProvider := token.NewTokenProvider(config)
-	authMiddleware := handlers.NewAuthMiddleware(jwtProvider)
+	jwtProvider, err := token.NewTokenProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
+	accessTokenRepo := repository.NewAccessTokenRepository(pool)
+	accessTokenService := service.NewAccessTokenService(accessTokenRepo)
+	accessTokenHandler := handlers.NewAccessTokenHandler(accessTokenService)
+
+	userRepo := repository.NewUserRepository(pool)
+	sessionService := service.NewSessionService(userRepo)
+
+	authMiddleware := handlers.NewAuthMiddleware(jwtProvider, accessTokenService, sessionService)
+	rateLimitMiddleware := handlers.NewRateLimitMiddleware(ratelimit.NewMemoryStore(config.RateLimitMaxKeys))
+	jwksHandler := handlers.NewJwksHandler(jwtProvider)
 
+	activityRepo := repository.NewActivityRepository(pool)
 	chatRepo := repository.NewChatRepository(pool)
+	organizationRepo := repository.NewOrganizationRepository(pool)
 	projectRepo := repository.NewProjectRepository(pool)
 	taskRepo := repository.NewTaskRepository(pool)
-	userRepo := repository.NewUserRepository(pool)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(pool)
 
-	projectService := service.NewProjectService(projectRepo, userRepo, pub)
+	projectService := service.NewProjectService(projectRepo, userRepo, pub, organizationRepo)
 	projectHandler := handlers.NewProjectHandler(projectService)
 
-	chatService := service.NewChatService(chatRepo, userRepo, pub)
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService)
 
-	ws := ws.NewServer(jwtProvider, logger, chatService, projectService, pub)
+	chatService := service.NewChatService(chatRepo, projectRepo, userRepo, pub)
+
+	ws := ws.NewServer(jwtProvider, sessionService, logger, chatService, projectService, pub, config.CORSOrigins, config.RateLimitWebsocket)
+	ws.RegisterMetrics()
+	websocketHandler := handlers.NewWebsocketHandler(ws)
 
 	_, err = subscriber.NewChatSubscriber(config, logger, chatService, ws)
 	if err != nil {
@@ -84,20 +118,64 @@ func NewApi() (*Api, error) {
 		return nil, err
 	}
 
+	_, err = subscriber.NewSessionSubscriber(config, logger, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	activityService := service.NewActivityService(activityRepo, projectRepo, chatRepo)
+	activityHandler := handlers.NewActivityHandler(activityService)
+
+	_, err = subscriber.NewActivitySubscriber(config, logger, activityService, ws)
+	if err != nil {
+		return nil, err
+	}
+
 	chatHandler := handlers.NewChatHandler(chatService)
 
-	userService := service.NewUserService(jwtProvider, userRepo)
+	mail, err := mailer.New(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	userService := service.NewUserService(jwtProvider, userRepo, loginAttemptRepo, projectService, mail, pub, config.AppURL)
 	userHandler := handlers.NewUserHandler(userService)
 
+	_, err = subscriber.NewProfileSubscriber(config, logger, userService, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	userDataExportRepo := repository.NewUserDataExportRepository(pool)
+	userDataExportService := service.NewUserDataExportService(userDataExportRepo, userRepo, mail, pub, config.AppURL)
+	userDataExportHandler := handlers.NewUserDataExportHandler(userDataExportService)
+
+	_, err = subscriber.NewExportSubscriber(config, logger, userDataExportService)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcRepo := repository.NewOidcRepository(pool)
+	oidcService := service.NewOidcService(oidc.NewProvider(config), oidcRepo, userRepo, projectService, userService)
+	oidcHandler := handlers.NewOidcHandler(oidcService)
+
 	taskService := service.NewTaskService(taskRepo, projectRepo, userRepo, pub)
 	taskHandler := handlers.NewTaskHandler(taskService)
 
 	handlers := Handlers{
+		AccessToken:    accessTokenHandler,
+		Activity:       activityHandler,
 		AuthMiddleware: authMiddleware,
 		Chat:           chatHandler,
+		Jwks:           jwksHandler,
+		Oidc:           oidcHandler,
+		Organization:   organizationHandler,
 		Project:        projectHandler,
+		RateLimit:      rateLimitMiddleware,
 		Task:           taskHandler,
 		User:           userHandler,
+		UserDataExport: userDataExportHandler,
+		Websocket:      websocketHandler,
 	}
 
 	api := Api{
@@ -124,6 +202,8 @@ func (a *Api) Serve() error {
 		ErrorLog:     slog.NewLogLogger(a.logger.Handler(), slog.LevelError),
 	}
 
+	metricsServer := a.metricsServer()
+
 	shutdownError := make(chan error)
 
 	go func() {
@@ -137,9 +217,24 @@ func (a *Api) Serve() error {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
+		if metricsServer != nil {
+			metricsServer.Shutdown(ctx)
+		}
+
 		shutdownError <- server.Shutdown(ctx)
 	}()
 
+	if metricsServer != nil {
+		go func() {
+			a.logger.Info("starting metrics server", "addr", metricsServer.Addr)
+
+			err := metricsServer.ListenAndServe()
+			if !errors.Is(err, http.ErrServerClosed) {
+				a.logger.Error("metrics server stopped", "error", err.Error())
+			}
+		}()
+	}
+
 	a.logger.Info("starting server", "addr", addr, "environment", a.config.Environment)
 
 	err := server.ListenAndServe()
@@ -156,3 +251,23 @@ func (a *Api) Serve() error {
 
 	return nil
 }
+
+// metricsServer serves /metrics on MetricsAddr, away from the public router so the metrics
+// are only reachable where that address is. It is nil when the listener is turned off.
+func (a *Api) metricsServer() *http.Server {
+	if a.config.MetricsAddr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:         a.config.MetricsAddr,
+		IdleTimeout:  30 * time.Second,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		Handler:      mux,
+		ErrorLog:     slog.NewLogLogger(a.logger.Handler(), slog.LevelError),
+	}
+}
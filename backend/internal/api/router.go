@@ -6,12 +6,18 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/handlers"
 	"github.com/gabrielnakaema/project-chat/internal/logger"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 func (a *Api) Router() http.Handler {
@@ -23,60 +29,162 @@ func (a *Api) Router() http.Handler {
 		AllowedOrigins:   a.config.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(handlers.RealIP(a.config.TrustedProxies))
 
 	r.Use(a.addLoggerMiddleware)
 	r.Use(a.slogMiddleware)
+	r.Use(metricsMiddleware)
 
 	r.Use(middleware.Recoverer)
 
 	r.Use(middleware.Timeout(30 * time.Second))
 
+	r.Use(a.handlers.RateLimit.LimitByIp("ip", a.config.RateLimitIp))
 	r.Use(a.handlers.AuthMiddleware.IdentifyUser)
+	r.Use(a.handlers.RateLimit.Limit("default", a.config.RateLimitDefault))
+
+	r.Get("/.well-known/jwks.json", a.handlers.Jwks.Get)
 
 	r.Route("/users", func(r chi.Router) {
-		r.Post("/", a.handlers.User.Create)
+		r.With(a.handlers.RateLimit.Limit("auth", a.config.RateLimitAuth)).Post("/", a.handlers.User.Create)
 		r.Get("/me", a.handlers.User.GetMe)
+		r.Post("/me/email/confirm", a.handlers.User.ConfirmEmailChange)
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
+			r.Patch("/me", a.handlers.User.UpdateMe)
+			r.Post("/me/email", a.handlers.User.RequestEmailChange)
+			r.With(a.handlers.RateLimit.Limit("auth", a.config.RateLimitAuth)).Put("/me/password", a.handlers.User.ChangePassword)
+			r.Delete("/me", a.handlers.User.DeleteMe)
+			r.Post("/me/exports", a.handlers.UserDataExport.Request)
+			r.Get("/me/exports/{id}", a.handlers.UserDataExport.Get)
+			r.Get("/me/exports/{id}/download", a.handlers.UserDataExport.Download)
+		})
 	})
 
 	r.Route("/auth", func(r chi.Router) {
+		r.Use(a.handlers.RateLimit.Limit("auth", a.config.RateLimitAuth))
 		r.Post("/login", a.handlers.User.Login)
 		r.Post("/refresh-token", a.handlers.User.RefreshToken)
 		r.Post("/logout", a.handlers.User.Logout)
+		r.Post("/verify-email", a.handlers.User.VerifyEmail)
+		r.With(a.handlers.AuthMiddleware.ProtectRoutes).Post("/verify-email/resend", a.handlers.User.RequestEmailVerification)
+		r.Post("/password-reset", a.handlers.User.RequestPasswordReset)
+		r.Post("/password-reset/confirm", a.handlers.User.ResetPassword)
+		r.Post("/mfa/verify", a.handlers.User.VerifyMfa)
+		r.Post("/oidc/authorize", a.handlers.Oidc.Authorize)
+		r.Post("/oidc/callback", a.handlers.Oidc.Callback)
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
+			r.Get("/sessions", a.handlers.User.ListSessions)
+			r.Delete("/sessions/{id}", a.handlers.User.RevokeSession)
+			r.Post("/mfa/totp", a.handlers.User.EnrollTotp)
+			r.Post("/mfa/totp/confirm", a.handlers.User.ConfirmTotp)
+			r.Delete("/mfa/totp", a.handlers.User.DisableTotp)
+			r.Get("/access-tokens", a.handlers.AccessToken.List)
+			r.Post("/access-tokens", a.handlers.AccessToken.Create)
+			r.Delete("/access-tokens/{id}", a.handlers.AccessToken.Revoke)
+		})
 	})
 
 	r.Route("/projects", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.Authorize(domain.AccessTokenScopeProjectsRead))
+			r.Get("/", a.handlers.Project.List)
+			r.Get("/{id}", a.handlers.Project.Get)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.Authorize(domain.AccessTokenScopeChatRead))
+			r.Get("/{id}/chat", a.handlers.Chat.GetChatByProjectId)
+			r.Get("/{id}/chat/messages", a.handlers.Chat.ListMessagesByProjectId)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
+			r.Post("/", a.handlers.Project.Create)
+			r.Put("/{id}", a.handlers.Project.Update)
+			r.Delete("/{id}", a.handlers.Project.Delete)
+			r.Post("/{id}/archive", a.handlers.Project.Archive)
+			r.Post("/{id}/unarchive", a.handlers.Project.Unarchive)
+			r.Post("/{id}/clone", a.handlers.Project.Clone)
+			r.Post("/{id}/templates", a.handlers.Project.CreateTemplate)
+			r.Post("/{id}/members", a.handlers.Project.CreateMember)
+			r.Put("/{id}/members/{userId}", a.handlers.Project.UpdateMemberRole)
+			r.Delete("/{id}/members/{userId}", a.handlers.Project.RemoveMember)
+			r.Post("/{id}/leave", a.handlers.Project.Leave)
+			r.Post("/{id}/transfer-ownership", a.handlers.Project.TransferOwnership)
+			r.Get("/{id}/invitations", a.handlers.Project.ListInvitations)
+			r.Post("/{id}/invitations", a.handlers.Project.CreateInvitation)
+			r.Delete("/{id}/invitations/{invitationId}", a.handlers.Project.RevokeInvitation)
+			r.Get("/{id}/activity", a.handlers.Activity.ListByProjectId)
+		})
+	})
+
+	r.Route("/organizations", func(r chi.Router) {
 		r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
-		r.Post("/", a.handlers.Project.Create)
-		r.Get("/", a.handlers.Project.List)
-		r.Get("/{id}", a.handlers.Project.Get)
-		r.Put("/{id}", a.handlers.Project.Update)
-		r.Post("/{id}/members", a.handlers.Project.CreateMember)
-		r.Get("/{id}/chat", a.handlers.Chat.GetChatByProjectId)
-		r.Get("/{id}/chat/messages", a.handlers.Chat.ListMessagesByProjectId)
+		r.Post("/", a.handlers.Organization.Create)
+		r.Get("/", a.handlers.Organization.List)
+		r.Get("/{id}", a.handlers.Organization.Get)
+		r.Put("/{id}", a.handlers.Organization.Update)
+		r.Get("/{id}/members", a.handlers.Organization.ListMembers)
+		r.Post("/{id}/members", a.handlers.Organization.CreateMember)
+		r.Put("/{id}/members/{userId}", a.handlers.Organization.UpdateMemberRole)
+		r.Delete("/{id}/members/{userId}", a.handlers.Organization.RemoveMember)
+		r.Get("/{id}/projects", a.handlers.Project.ListByOrganization)
 	})
 
-	r.Route("/chats", func(r chi.Router) {
+	r.Route("/templates", func(r chi.Router) {
 		r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
-		r.Post("/messages", a.handlers.Chat.CreateMessage)
+		r.Get("/", a.handlers.Project.ListTemplates)
+		r.Delete("/{id}", a.handlers.Project.DeleteTemplate)
+		r.Post("/{id}/projects", a.handlers.Project.CreateFromTemplate)
 	})
 
-	r.Route("/tasks", func(r chi.Router) {
+	r.Route("/invitations", func(r chi.Router) {
 		r.Use(a.handlers.AuthMiddleware.ProtectRoutes)
-		r.Get("/", a.handlers.Task.List)
-		r.Post("/", a.handlers.Task.Create)
-		r.Get("/{id}", a.handlers.Task.Get)
-		r.Put("/{id}", a.handlers.Task.Update)
+		r.Post("/accept", a.handlers.Project.AcceptInvitation)
+	})
+
+	r.Route("/chats", func(r chi.Router) {
+		r.Use(a.handlers.AuthMiddleware.Authorize(domain.AccessTokenScopeChatWrite))
+		r.With(a.handlers.RateLimit.Limit("chat_messages", a.config.RateLimitChatMessages)).Post("/messages", a.handlers.Chat.CreateMessage)
+	})
+
+	r.Route("/tasks", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.Authorize(domain.AccessTokenScopeTasksRead))
+			r.Get("/", a.handlers.Task.List)
+			r.Get("/{id}", a.handlers.Task.Get)
+			r.Get("/{id}/comments", a.handlers.Task.ListComments)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.handlers.AuthMiddleware.Authorize(domain.AccessTokenScopeTasksWrite))
+			r.Post("/", a.handlers.Task.Create)
+			r.Put("/{id}", a.handlers.Task.Update)
+			r.Put("/{id}/parent", a.handlers.Task.SetParent)
+			r.Post("/{id}/blockers", a.handlers.Task.AddBlocker)
+			r.Delete("/{id}/blockers/{blockerId}", a.handlers.Task.RemoveBlocker)
+			r.Post("/{id}/checklist", a.handlers.Task.CreateChecklistItem)
+			r.Put("/{id}/checklist/{itemId}", a.handlers.Task.UpdateChecklistItem)
+			r.Delete("/{id}/checklist/{itemId}", a.handlers.Task.DeleteChecklistItem)
+			r.Post("/{id}/comments", a.handlers.Task.CreateComment)
+			r.Put("/{id}/comments/{commentId}", a.handlers.Task.UpdateComment)
+			r.Delete("/{id}/comments/{commentId}", a.handlers.Task.DeleteComment)
+		})
 	})
 
 	r.Route("/ws", func(r chi.Router) {
 		r.Get("/", a.Ws.Handler)
+		r.With(a.handlers.AuthMiddleware.ProtectRoutes).Post("/tickets", a.handlers.Websocket.IssueTicket)
 	})
 
 	return r
@@ -145,10 +253,63 @@ func (a *Api) slogMiddleware(next http.Handler) http.Handler {
 
 		l.Log(r.Context(), logLevel, "http_request",
 			"method", r.Method,
-			"url", r.URL.String(),
+			"url", redactedURL(r.URL),
 			"remote_addr", ip,
 			"status", ww.statusCode,
 			"duration_ms", duration.Milliseconds(),
 		)
 	})
 }
+
+var httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "http_request_duration_seconds",
+	Help: "Duration of HTTP requests by method, chi route pattern and status.",
+}, []string{"method", "route", "status"})
+
+// metricsMiddleware labels requests by their route pattern instead of the path so ids don't
+// create a series each. Websocket connections are left out, they last as long as the client
+// stays connected.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(ww, r)
+
+		if ww.hijacked {
+			return
+		}
+
+		route := chi.RouteContext(r.Context()).RoutePattern()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		httpRequestDuration.WithLabelValues(r.Method, route, strconv.Itoa(ww.statusCode)).Observe(time.Since(start).Seconds())
+	})
+}
+
+// sensitiveQueryParams carry credentials, their values are left out of the request log.
+var sensitiveQueryParams = []string{"ticket", "token", "jwt"}
+
+func redactedURL(u *url.URL) string {
+	query := u.Query()
+
+	redacted := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+
+	if !redacted {
+		return u.String()
+	}
+
+	clean := *u
+	clean.RawQuery = query.Encode()
+
+	return clean.String()
+}
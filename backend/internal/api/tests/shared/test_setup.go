@@ -55,6 +55,11 @@ func SetupTestAPI(t *testing.T) (*TestAPI, func()) {
 	os.Setenv("JWT_SECRET", "test-jwt-secret-key-for-testing")
 	os.Setenv("ENV", "test")
 	os.Setenv("PUBSUB_BROKERS", "localhost:9092")
+	// Every test request comes from the same address, the limits are covered by unit tests.
+	os.Setenv("RATE_LIMIT_IP", "off")
+	os.Setenv("RATE_LIMIT_DEFAULT", "off")
+	os.Setenv("RATE_LIMIT_AUTH", "off")
+	os.Setenv("RATE_LIMIT_CHAT_MESSAGES", "off")
 
 	projectRoot := findProjectRoot(t)
 	originalDir, _ := os.Getwd()
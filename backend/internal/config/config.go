@@ -1,9 +1,14 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
 	"github.com/joho/godotenv"
 )
 
@@ -14,12 +19,61 @@ type Config struct {
 	PubsubBrokers []string
 	Environment   string
 	CORSOrigins   []string
+
+	// MetricsAddr is where the Prometheus metrics are served, on a listener of their own so
+	// they stay off the public port. Empty turns the listener off.
+	MetricsAddr string
+
+	// TrustedProxies are the addresses allowed to set X-Forwarded-For and X-Real-IP, the
+	// headers of any other client are ignored.
+	TrustedProxies []netip.Prefix
+
+	// JwtSigningKeyFile is a PEM encoded RSA or Ed25519 private key. When empty, tokens
+	// are signed with JwtSecret instead.
+	JwtSigningKeyFile string
+	// JwtVerificationKeyFiles are PEM encoded public keys still accepted while rotating
+	// away from them.
+	JwtVerificationKeyFiles []string
+
+	// AppURL is the address of the frontend, used to build the links sent by email.
+	AppURL         string
+	Mailer         string
+	MailerFilePath string
+	MailFrom       string
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUsername   string
+	SMTPPassword   string
+
+	// Single sign-on is enabled when OidcIssuer is set, the redirect URL is the frontend page
+	// that posts the code and the state back to the api.
+	OidcIssuer       string
+	OidcClientId     string
+	OidcClientSecret string
+	OidcRedirectURL  string
+	OidcScopes       []string
+
+	// Rate limits are written as requests/period, such as "60/1m", or "off". Every route
+	// shares the default limit, the stricter ones are counted on top of it.
+	// RateLimitIp is counted per ip before the user is identified, so it also covers requests
+	// with invalid credentials.
+	RateLimitIp           ratelimit.Limit
+	RateLimitDefault      ratelimit.Limit
+	RateLimitAuth         ratelimit.Limit
+	RateLimitChatMessages ratelimit.Limit
+	// RateLimitWebsocket limits the messages a single websocket connection can send.
+	RateLimitWebsocket ratelimit.Limit
+	// RateLimitMaxKeys caps the clients the in-memory rate limiter keeps track of.
+	RateLimitMaxKeys int
 }
 
+const defaultJwtSecret = "SECRET"
+
 func New() (*Config, error) {
 	godotenv.Load()
 
-	env := getEnv("ENV", "development")
+	// a missing ENV must not turn off the checks that only run outside development
+	env := getEnv("ENV", "production")
 
 	port := getEnv("API_PORT", "3333")
 
@@ -27,9 +81,76 @@ func New() (*Config, error) {
 		Port:          port,
 		DSN:           getEnv("DB_DSN", ""),
 		PubsubBrokers: strings.Split(getEnv("PUBSUB_BROKERS", ""), ","),
-		JwtSecret:     getEnv("JWT_SECRET", "SECRET"),
+		JwtSecret:     getEnv("JWT_SECRET", defaultJwtSecret),
 		Environment:   env,
 		CORSOrigins:   strings.Split(getEnv("CORS_ORIGINS", "http://localhost:3000"), ","),
+		MetricsAddr:   getEnv("METRICS_ADDR", ":9090"),
+
+		JwtSigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+		JwtVerificationKeyFiles: getEnvList("JWT_VERIFICATION_KEY_FILES"),
+
+		AppURL:         getEnv("APP_URL", "http://localhost:3000"),
+		Mailer:         getEnv("MAILER", "log"),
+		MailerFilePath: getEnv("MAILER_FILE_PATH", "mails.log"),
+		MailFrom:       getEnv("MAIL_FROM", "Project Chat <no-reply@localhost>"),
+		SMTPHost:       getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:       getEnv("SMTP_PORT", "587"),
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+
+		OidcIssuer:       getEnv("OIDC_ISSUER", ""),
+		OidcClientId:     getEnv("OIDC_CLIENT_ID", ""),
+		OidcClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		OidcRedirectURL:  getEnv("OIDC_REDIRECT_URL", ""),
+		OidcScopes:       getEnvList("OIDC_SCOPES"),
+	}
+
+	if config.MetricsAddr == "off" {
+		config.MetricsAddr = ""
+	}
+
+	trustedProxies, err := parsePrefixes(getEnvList("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	config.TrustedProxies = trustedProxies
+
+	if config.OidcRedirectURL == "" {
+		config.OidcRedirectURL = strings.TrimSuffix(config.AppURL, "/") + "/auth/oidc/callback"
+	}
+
+	if len(config.OidcScopes) == 0 {
+		config.OidcScopes = []string{"openid", "email", "profile"}
+	}
+
+	rateLimits := []struct {
+		key          string
+		defaultValue string
+		limit        *ratelimit.Limit
+	}{
+		{"RATE_LIMIT_IP", "1200/1m", &config.RateLimitIp},
+		{"RATE_LIMIT_DEFAULT", "600/1m", &config.RateLimitDefault},
+		{"RATE_LIMIT_AUTH", "30/1m", &config.RateLimitAuth},
+		{"RATE_LIMIT_CHAT_MESSAGES", "60/1m", &config.RateLimitChatMessages},
+		{"RATE_LIMIT_WEBSOCKET", "20/1s", &config.RateLimitWebsocket},
+	}
+
+	for _, rateLimit := range rateLimits {
+		limit, err := ratelimit.ParseLimit(getEnv(rateLimit.key, rateLimit.defaultValue))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rateLimit.key, err)
+		}
+		*rateLimit.limit = limit
+	}
+
+	rateLimitMaxKeys, err := strconv.Atoi(getEnv("RATE_LIMIT_MAX_KEYS", "100000"))
+	if err != nil || rateLimitMaxKeys <= 0 {
+		return nil, errors.New("RATE_LIMIT_MAX_KEYS must be a positive number")
+	}
+	config.RateLimitMaxKeys = rateLimitMaxKeys
+
+	if env != "development" && config.JwtSigningKeyFile == "" && config.JwtSecret == defaultJwtSecret {
+		return nil, errors.New("JWT_SIGNING_KEY_FILE or JWT_SECRET must be set outside development")
 	}
 
 	return &config, nil
@@ -42,3 +163,36 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parsePrefixes accepts CIDR ranges and single addresses.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"github.com/gabrielnakaema/project-chat/internal/metrics"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterMetrics exposes the statistics of pool, they are read from the pool on every scrape.
+func RegisterMetrics(pool *pgxpool.Pool) {
+	gauges := []struct {
+		name  string
+		help  string
+		value func(stat *pgxpool.Stat) float64
+	}{
+		{"db_pool_acquired_connections", "Connections currently in use.", func(stat *pgxpool.Stat) float64 { return float64(stat.AcquiredConns()) }},
+		{"db_pool_idle_connections", "Idle connections in the pool.", func(stat *pgxpool.Stat) float64 { return float64(stat.IdleConns()) }},
+		{"db_pool_constructing_connections", "Connections being established.", func(stat *pgxpool.Stat) float64 { return float64(stat.ConstructingConns()) }},
+		{"db_pool_total_connections", "Open connections, acquired, idle and constructing.", func(stat *pgxpool.Stat) float64 { return float64(stat.TotalConns()) }},
+		{"db_pool_max_connections", "Maximum size of the pool.", func(stat *pgxpool.Stat) float64 { return float64(stat.MaxConns()) }},
+	}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(stat *pgxpool.Stat) float64
+	}{
+		{"db_pool_acquires_total", "Connections acquired from the pool.", func(stat *pgxpool.Stat) float64 { return float64(stat.AcquireCount()) }},
+		{"db_pool_acquire_duration_seconds_total", "Time spent acquiring connections.", func(stat *pgxpool.Stat) float64 { return stat.AcquireDuration().Seconds() }},
+		{"db_pool_empty_acquires_total", "Acquires that had to wait for a connection.", func(stat *pgxpool.Stat) float64 { return float64(stat.EmptyAcquireCount()) }},
+		{"db_pool_canceled_acquires_total", "Acquires canceled by their context.", func(stat *pgxpool.Stat) float64 { return float64(stat.CanceledAcquireCount()) }},
+		{"db_pool_new_connections_total", "Connections opened by the pool.", func(stat *pgxpool.Stat) float64 { return float64(stat.NewConnsCount()) }},
+	}
+
+	for _, gauge := range gauges {
+		metrics.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: gauge.name, Help: gauge.help}, func() float64 { return gauge.value(pool.Stat()) }))
+	}
+
+	for _, counter := range counters {
+		metrics.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{Name: counter.name, Help: counter.help}, func() float64 { return counter.value(pool.Stat()) }))
+	}
+}
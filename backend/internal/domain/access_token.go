@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessTokenPrefix starts every personal access token, it tells them apart from JWTs in the
+// Authorization header and makes leaked tokens easy to scan for.
+const AccessTokenPrefix = "pat_"
+
+type AccessTokenScope string
+
+var (
+	AccessTokenScopeProjectsRead AccessTokenScope = "projects:read"
+	AccessTokenScopeTasksRead    AccessTokenScope = "tasks:read"
+	AccessTokenScopeTasksWrite   AccessTokenScope = "tasks:write"
+	AccessTokenScopeChatRead     AccessTokenScope = "chat:read"
+	AccessTokenScopeChatWrite    AccessTokenScope = "chat:write"
+)
+
+var AllowedAccessTokenScopes = []AccessTokenScope{
+	AccessTokenScopeProjectsRead,
+	AccessTokenScopeTasksRead,
+	AccessTokenScopeTasksWrite,
+	AccessTokenScopeChatRead,
+	AccessTokenScopeChatWrite,
+}
+
+// PersonalAccessToken is a long lived credential the user hands to scripts and bots. It acts
+// on behalf of the user but only on the routes its scopes allow, only the hash of the token
+// is stored.
+type PersonalAccessToken struct {
+	Id         uuid.UUID          `json:"id"`
+	UserId     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"-"`
+	Scopes     []AccessTokenScope `json:"scopes"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	LastUsedAt *time.Time         `json:"last_used_at"`
+	RevokedAt  *time.Time         `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+func (t *PersonalAccessToken) IsValid(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+func (t *PersonalAccessToken) HasScope(scope AccessTokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectActivity is an entry of the project activity feed, Type is the topic of the event
+// that produced it and Payload is a ProjectActivityPayload with what the feed shows of it.
+type ProjectActivity struct {
+	Id        uuid.UUID       `json:"id"`
+	ProjectId uuid.UUID       `json:"project_id"`
+	ActorId   *uuid.UUID      `json:"actor_id"` // If ActorId is nil, the activity was not attributed to a user
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	Actor *User `json:"actor,omitempty"`
+	// User is the user the payload names, their name is read from the users table so the feed
+	// does not keep it.
+	User *User `json:"user,omitempty"`
+}
+
+// ProjectActivityPayload is the part of an event kept in the feed, users are kept by id so
+// the feed does not keep copies of their names, emails or other personal data.
+type ProjectActivityPayload struct {
+	ProjectName     string     `json:"project_name,omitempty"`
+	TaskId          *uuid.UUID `json:"task_id,omitempty"`
+	TaskTitle       string     `json:"task_title,omitempty"`
+	TaskStatus      TaskStatus `json:"task_status,omitempty"`
+	CommentId       *uuid.UUID `json:"comment_id,omitempty"`
+	UserId          *uuid.UUID `json:"user_id,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	PreviousOwnerId *uuid.UUID `json:"previous_owner_id,omitempty"`
+	NewOwnerId      *uuid.UUID `json:"new_owner_id,omitempty"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,6 +42,23 @@ type ChatMessage struct {
 	Content     string      `json:"content"`
 	CreatedAt   time.Time   `json:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at"`
+	// ActorId and SubjectId are the users a system message names, the stored content has
+	// placeholders instead of their names.
+	ActorId   *uuid.UUID `json:"actor_id,omitempty"`
+	SubjectId *uuid.UUID `json:"subject_id,omitempty"`
 
 	Member *ChatMember `json:"member,omitempty"`
 }
+
+// System messages are stored with these placeholders and get the names the users have when
+// they are read, so renamed and deleted users are not kept in the chat history.
+const (
+	SystemMessageJoined               = "{subject} has joined the chat"
+	SystemMessageLeft                 = "{subject} left the chat"
+	SystemMessageOwnershipTransferred = "{actor} transferred project ownership to {subject}"
+)
+
+// RenderNames fills the names of the actor and subject into the content of a system message.
+func (m *ChatMessage) RenderNames(actorName string, subjectName string) {
+	m.Content = strings.NewReplacer("{actor}", actorName, "{subject}", subjectName).Replace(m.Content)
+}
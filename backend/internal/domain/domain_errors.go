@@ -1,6 +1,10 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 type ErrorCode string
 
@@ -12,6 +16,7 @@ var (
 	DuplicateEntryErrorCode     ErrorCode = "DUPLICATE_ENTRY"
 	ServerErrorCode             ErrorCode = "SERVER_ERROR"
 	BusinessValidationErrorCode ErrorCode = "BUSINESS_VALIDATION_FAILED"
+	TooManyRequestsErrorCode    ErrorCode = "TOO_MANY_REQUESTS"
 )
 
 type DomainError struct {
@@ -79,6 +84,20 @@ func DuplicateEntryError(message string) DomainError {
 	return err
 }
 
+// RetryAfter is the meta of too many requests errors, it is also sent as the Retry-After header.
+type RetryAfter struct {
+	Seconds int `json:"retry_after"`
+}
+
+func TooManyRequestsError(message string, retryAfter time.Duration) DomainError {
+	err := DomainError{
+		Message: message,
+		Code:    TooManyRequestsErrorCode,
+		Meta:    RetryAfter{Seconds: int(math.Ceil(retryAfter.Seconds()))},
+	}
+	return err
+}
+
 func ServerError(message string, cause error) DomainError {
 	err := DomainError{
 		Message: message,
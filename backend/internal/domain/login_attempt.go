@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginFailureReason string
+
+var (
+	LoginFailureReasonInvalidCredentials LoginFailureReason = "invalid_credentials"
+	LoginFailureReasonLocked             LoginFailureReason = "locked"
+	LoginFailureReasonMfaRequired        LoginFailureReason = "mfa_required"
+)
+
+// LoginAttempt is the audit record of a login, attempts rejected because of a lockout or
+// waiting for the second factor are recorded too but do not count towards the lockout.
+type LoginAttempt struct {
+	Id            uuid.UUID
+	Email         string
+	IpAddress     string
+	UserId        *uuid.UUID
+	Success       bool
+	FailureReason LoginFailureReason
+	CreatedAt     time.Time
+}
+
+type LoginFailures struct {
+	Count        int
+	LastFailedAt time.Time
+}
+
+// LockedUntil returns when logins are allowed again, the lockout starts at base once the
+// failures reach threshold and doubles with every failure after it up to max.
+func (f LoginFailures) LockedUntil(threshold int, base time.Duration, max time.Duration) time.Time {
+	if f.Count < threshold {
+		return time.Time{}
+	}
+
+	lockout := base
+	for i := threshold; i < f.Count && lockout < max; i++ {
+		lockout *= 2
+	}
+
+	if lockout > max {
+		lockout = max
+	}
+
+	return f.LastFailedAt.Add(lockout)
+}
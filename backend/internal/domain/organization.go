@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization groups projects and people above the project level, every user gets a
+// personal organization when they sign up that cannot be shared.
+type Organization struct {
+	Id        uuid.UUID `json:"id"`
+	UserId    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Personal  bool      `json:"personal"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Members []OrganizationMember `json:"members,omitempty"`
+}
+
+const PersonalOrganizationName = "Personal"
+
+type OrganizationMemberRole string
+
+var (
+	OrganizationMemberRoleOwner  OrganizationMemberRole = "owner"
+	OrganizationMemberRoleAdmin  OrganizationMemberRole = "admin"
+	OrganizationMemberRoleMember OrganizationMemberRole = "member"
+)
+
+var AllowedOrganizationMemberRoles = []OrganizationMemberRole{OrganizationMemberRoleOwner, OrganizationMemberRoleAdmin, OrganizationMemberRoleMember}
+
+// CanManage reports whether a member with this role can add or remove a member with the
+// target role, the owner cannot be added or removed.
+func (r OrganizationMemberRole) CanManage(target OrganizationMemberRole) bool {
+	if target == OrganizationMemberRoleOwner {
+		return false
+	}
+
+	switch r {
+	case OrganizationMemberRoleOwner:
+		return true
+	case OrganizationMemberRoleAdmin:
+		return target == OrganizationMemberRoleMember
+	default:
+		return false
+	}
+}
+
+// CanCreateProjects reports whether a member with this role can create, clone or instantiate
+// projects in the organization.
+func (r OrganizationMemberRole) CanCreateProjects() bool {
+	return slices.Contains(AllowedOrganizationMemberRoles, r)
+}
+
+type OrganizationMember struct {
+	Id             uuid.UUID              `json:"id"`
+	OrganizationId uuid.UUID              `json:"organization_id"`
+	UserId         uuid.UUID              `json:"user_id"`
+	User           *User                  `json:"user,omitempty"`
+	Role           OrganizationMemberRole `json:"role"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
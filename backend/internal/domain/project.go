@@ -1,29 +1,88 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Project struct {
-	Id          uuid.UUID `json:"id"`
-	UserId      uuid.UUID `json:"user_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id             uuid.UUID  `json:"id"`
+	UserId         uuid.UUID  `json:"user_id"`
+	OrganizationId uuid.UUID  `json:"organization_id"`
+	Name           string     `json:"name"`
+	Description    string     `json:"description"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
 
 	Members []ProjectMember `json:"members,omitempty"`
 }
 
+// DeletedProject is published after a project and everything that belongs to it is removed,
+// it keeps the chat ids so connections to those rooms can be cleaned up.
+type DeletedProject struct {
+	Project
+	ChatIds []uuid.UUID `json:"chat_ids"`
+}
+
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
 type ProjectMemberRole string
 
 var (
-	ProjectMemberRoleCreator ProjectMemberRole = "creator"
-	ProjectMemberRoleMember  ProjectMemberRole = "member"
+	ProjectMemberRoleOwner  ProjectMemberRole = "owner"
+	ProjectMemberRoleAdmin  ProjectMemberRole = "admin"
+	ProjectMemberRoleMember ProjectMemberRole = "member"
+	ProjectMemberRoleViewer ProjectMemberRole = "viewer"
+)
+
+var AllowedProjectMemberRoles = []ProjectMemberRole{ProjectMemberRoleOwner, ProjectMemberRoleAdmin, ProjectMemberRoleMember, ProjectMemberRoleViewer}
+
+type Permission string
+
+var (
+	PermissionProjectView          Permission = "project:view"
+	PermissionProjectUpdate        Permission = "project:update"
+	PermissionProjectManageMembers Permission = "project:manage_members"
+	PermissionTaskView             Permission = "task:view"
+	PermissionTaskEdit             Permission = "task:edit"
+	PermissionChatView             Permission = "chat:view"
+	PermissionChatWrite            Permission = "chat:write"
 )
 
+var viewerPermissions = []Permission{PermissionProjectView, PermissionTaskView, PermissionChatView}
+var memberPermissions = append(slices.Clone(viewerPermissions), PermissionTaskEdit, PermissionChatWrite)
+var adminPermissions = append(slices.Clone(memberPermissions), PermissionProjectUpdate, PermissionProjectManageMembers)
+
+var rolePermissions = map[ProjectMemberRole][]Permission{
+	ProjectMemberRoleOwner:  adminPermissions,
+	ProjectMemberRoleAdmin:  adminPermissions,
+	ProjectMemberRoleMember: memberPermissions,
+	ProjectMemberRoleViewer: viewerPermissions,
+}
+
+func (r ProjectMemberRole) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// CanManage reports whether a member with this role can add, remove or change the role
+// of a member with the target role, the owner can only be changed by transferring ownership.
+func (r ProjectMemberRole) CanManage(target ProjectMemberRole) bool {
+	if !r.Can(PermissionProjectManageMembers) || target == ProjectMemberRoleOwner {
+		return false
+	}
+
+	if r == ProjectMemberRoleAdmin {
+		return target == ProjectMemberRoleMember || target == ProjectMemberRoleViewer
+	}
+
+	return true
+}
+
 type ProjectMember struct {
 	Id        uuid.UUID         `json:"id"`
 	UserId    uuid.UUID         `json:"user_id"`
@@ -31,3 +90,53 @@ type ProjectMember struct {
 	ProjectId uuid.UUID         `json:"project_id"`
 	Role      ProjectMemberRole `json:"role"`
 }
+
+func (p *Project) GetMember(userId uuid.UUID) (*ProjectMember, bool) {
+	for i := range p.Members {
+		if p.Members[i].UserId == userId {
+			return &p.Members[i], true
+		}
+	}
+	return nil, false
+}
+
+// Authorize is the single place where project permissions are checked, it must be called
+// with Members loaded. Archived projects are read-only so only viewer permissions are granted.
+func (p *Project) Authorize(userId uuid.UUID, permission Permission) (*ProjectMember, error) {
+	member, ok := p.GetMember(userId)
+	if !ok || !member.Role.Can(permission) {
+		return nil, ForbiddenError("forbidden")
+	}
+	if p.IsArchived() && !slices.Contains(viewerPermissions, permission) {
+		return nil, BusinessValidationError("project is archived")
+	}
+	return member, nil
+}
+
+// ProjectOwnershipTransfer is published when the owner hands the project to another member,
+// the previous owner stays in the project as an admin.
+type ProjectOwnershipTransfer struct {
+	ProjectId       uuid.UUID `json:"project_id"`
+	PreviousOwnerId uuid.UUID `json:"previous_owner_id"`
+	NewOwnerId      uuid.UUID `json:"new_owner_id"`
+}
+
+// ProjectInvitation invites an email to a project, only the hash of the token is stored,
+// the plain token is returned once when the invitation is created.
+type ProjectInvitation struct {
+	Id         uuid.UUID         `json:"id"`
+	ProjectId  uuid.UUID         `json:"project_id"`
+	Email      string            `json:"email"`
+	Role       ProjectMemberRole `json:"role"`
+	Token      string            `json:"token,omitempty"`
+	TokenHash  string            `json:"-"`
+	InvitedBy  uuid.UUID         `json:"invited_by"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	AcceptedAt *time.Time        `json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time        `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func (i *ProjectInvitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
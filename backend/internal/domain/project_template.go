@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProjectTemplate struct {
+	Id          uuid.UUID             `json:"id"`
+	UserId      uuid.UUID             `json:"user_id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Tasks       []ProjectTemplateTask `json:"tasks"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// ProjectTemplateTask is a task copied into a template, ParentIndex points to the position of
+// its parent in the same list and parents always come before their subtasks.
+type ProjectTemplateTask struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	ParentIndex *int       `json:"parent_index,omitempty"`
+}
+
+// NewProjectTemplateTasks copies the tasks of a project keeping their order and subtask
+// hierarchy.
+func NewProjectTemplateTasks(tasks []Task) []ProjectTemplateTask {
+	tasksById := make(map[uuid.UUID]Task, len(tasks))
+	for _, task := range tasks {
+		tasksById[task.Id] = task
+	}
+
+	indexes := make(map[uuid.UUID]int, len(tasks))
+	templateTasks := make([]ProjectTemplateTask, 0, len(tasks))
+
+	var add func(task Task) int
+	add = func(task Task) int {
+		if index, ok := indexes[task.Id]; ok {
+			return index
+		}
+
+		var parentIndex *int
+		if task.ParentId != nil {
+			if parent, ok := tasksById[*task.ParentId]; ok {
+				index := add(parent)
+				parentIndex = &index
+			}
+		}
+
+		templateTasks = append(templateTasks, ProjectTemplateTask{
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      task.Status,
+			ParentIndex: parentIndex,
+		})
+		indexes[task.Id] = len(templateTasks) - 1
+
+		return indexes[task.Id]
+	}
+
+	for _, task := range tasks {
+		add(task)
+	}
+
+	return templateTasks
+}
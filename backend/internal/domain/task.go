@@ -20,6 +20,7 @@ var (
 type Task struct {
 	Id          uuid.UUID  `json:"id"`
 	ProjectId   uuid.UUID  `json:"project_id"`
+	ParentId    *uuid.UUID `json:"parent_id"`
 	AuthorId    uuid.UUID  `json:"author_id"`
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
@@ -27,12 +28,44 @@ type Task struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 
-	Author  *User        `json:"author,omitempty"`
-	Changes []TaskChange `json:"changes,omitempty"`
+	CommentCount int `json:"comment_count"`
+
+	Author    *User               `json:"author,omitempty"`
+	Changes   []TaskChange        `json:"changes,omitempty"`
+	Subtasks  []Task              `json:"subtasks,omitempty"`
+	Blockers  []Task              `json:"blockers,omitempty"`
+	Checklist []TaskChecklistItem `json:"checklist,omitempty"`
+	Progress  *TaskProgress       `json:"progress,omitempty"`
 }
 
 var AllowedTaskStatuses = []TaskStatus{TaskStatusPending, TaskStatusDoing, TaskStatusDone, TaskStatusArchived}
 
+type TaskSortField string
+
+var (
+	TaskSortFieldCreatedAt TaskSortField = "created_at"
+	TaskSortFieldUpdatedAt TaskSortField = "updated_at"
+)
+
+var AllowedTaskSortFields = []TaskSortField{TaskSortFieldCreatedAt, TaskSortFieldUpdatedAt}
+
+type TaskListFilter struct {
+	Status       *TaskStatus
+	AuthorId     *uuid.UUID
+	UpdatedSince *time.Time
+	Search       string
+	SortBy       TaskSortField
+	SortDesc     bool
+}
+
+// SortValue returns the value of the field the task list is sorted by.
+func (t *Task) SortValue(field TaskSortField) time.Time {
+	if field == TaskSortFieldUpdatedAt {
+		return t.UpdatedAt
+	}
+	return t.CreatedAt
+}
+
 func (t *Task) ChangeStatus(status TaskStatus) error {
 	if !slices.Contains(AllowedTaskStatuses, status) {
 		return BusinessValidationError("invalid status")
@@ -43,44 +76,125 @@ func (t *Task) ChangeStatus(status TaskStatus) error {
 	return nil
 }
 
+// IsOpen reports whether the task still has work left, archived tasks are
+// considered closed the same way done tasks are.
+func (t *Task) IsOpen() bool {
+	return t.Status != TaskStatusDone && t.Status != TaskStatusArchived
+}
+
+// HasOpenSubtasks must be called with Subtasks loaded.
+func (t *Task) HasOpenSubtasks() bool {
+	for _, subtask := range t.Subtasks {
+		if subtask.IsOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOpenBlockers must be called with Blockers loaded.
+func (t *Task) HasOpenBlockers() bool {
+	for _, blocker := range t.Blockers {
+		if blocker.IsOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// ComputeProgress counts checklist items and subtasks, so it must be called
+// with both Checklist and Subtasks loaded.
+func (t *Task) ComputeProgress() {
+	progress := TaskProgress{}
+
+	for _, item := range t.Checklist {
+		progress.Total++
+		if item.Done {
+			progress.Done++
+		}
+	}
+
+	for _, subtask := range t.Subtasks {
+		if subtask.Status == TaskStatusArchived {
+			continue
+		}
+		progress.Total++
+		if subtask.Status == TaskStatusDone {
+			progress.Done++
+		}
+	}
+
+	t.Progress = &progress
+}
+
+type TaskChecklistItem struct {
+	Id        uuid.UUID `json:"id"`
+	TaskId    uuid.UUID `json:"task_id"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskChange is an entry of the task history. Description is what is stored, it does not name
+// the author, ChangeDescription adds the name the author has when the change is read so the
+// history follows renamed and deleted users.
 type TaskChange struct {
 	Id                uuid.UUID `json:"id"`
 	TaskId            uuid.UUID `json:"task_id"`
 	AuthorId          uuid.UUID `json:"author_id"`
+	Description       string    `json:"-"`
 	ChangeDescription string    `json:"change_description"`
 	CreatedAt         time.Time `json:"created_at"`
 
 	Author *User `json:"author,omitempty"`
 }
 
+type TaskComment struct {
+	Id        uuid.UUID `json:"id"`
+	TaskId    uuid.UUID `json:"task_id"`
+	ProjectId uuid.UUID `json:"project_id"`
+	AuthorId  uuid.UUID `json:"author_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Author *User `json:"author,omitempty"`
+}
+
+// NewTaskCreatedChange is the first entry of the task history.
+func NewTaskCreatedChange(taskId uuid.UUID, author *User) TaskChange {
+	return NewTaskChange(taskId, author, "Task created")
+}
+
+func NewTaskChange(taskId uuid.UUID, author *User, description string) TaskChange {
+	return TaskChange{
+		TaskId:            taskId,
+		AuthorId:          author.Id,
+		Description:       description,
+		ChangeDescription: fmt.Sprintf("%s by %s", description, author.Name),
+		CreatedAt:         time.Now(),
+	}
+}
+
 func NewTaskChanges(oldTask *Task, newTask *Task, author *User) []TaskChange {
 	changes := []TaskChange{}
 
 	if oldTask.Title != newTask.Title {
-		changes = append(changes, TaskChange{
-			TaskId:            oldTask.Id,
-			AuthorId:          author.Id,
-			ChangeDescription: fmt.Sprintf("Title changed from %s to %s by %s", oldTask.Title, newTask.Title, author.Name),
-			CreatedAt:         time.Now(),
-		})
+		changes = append(changes, NewTaskChange(oldTask.Id, author, fmt.Sprintf("Title changed from %s to %s", oldTask.Title, newTask.Title)))
 	}
 
 	if oldTask.Description != newTask.Description {
-		changes = append(changes, TaskChange{
-			TaskId:            oldTask.Id,
-			AuthorId:          author.Id,
-			ChangeDescription: fmt.Sprintf("Description changed from %s to %s by %s", oldTask.Description, newTask.Description, author.Name),
-			CreatedAt:         time.Now(),
-		})
+		changes = append(changes, NewTaskChange(oldTask.Id, author, fmt.Sprintf("Description changed from %s to %s", oldTask.Description, newTask.Description)))
 	}
 
 	if oldTask.Status != newTask.Status {
-		changes = append(changes, TaskChange{
-			TaskId:            oldTask.Id,
-			AuthorId:          author.Id,
-			ChangeDescription: fmt.Sprintf("Status changed from %s to %s by %s", oldTask.Status, newTask.Status, author.Name),
-			CreatedAt:         time.Now(),
-		})
+		changes = append(changes, NewTaskChange(oldTask.Id, author, fmt.Sprintf("Status changed from %s to %s", oldTask.Status, newTask.Status)))
 	}
 
 	return changes
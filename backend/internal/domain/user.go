@@ -6,19 +6,125 @@ import (
 	"github.com/google/uuid"
 )
 
+// DeletedUserName replaces the name of users who deleted their account, their messages and
+// task history are kept under it.
+const DeletedUserName = "Deleted user"
+
 type User struct {
-	Id        uuid.UUID `json:"id,omitempty"`
-	Name      string    `json:"name,omitempty"`
-	Email     string    `json:"email,omitempty"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	Id              uuid.UUID  `json:"id,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	AvatarUrl       string     `json:"avatar_url,omitempty"`
+	Timezone        string     `json:"timezone,omitempty"`
+	Locale          string     `json:"locale,omitempty"`
+	Password        string     `json:"-"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	TotpSecret      string     `json:"-"`
+	TotpEnabledAt   *time.Time `json:"totp_enabled_at,omitempty"`
+	TotpLastStep    int64      `json:"-"`
+	DeletedAt       *time.Time `json:"-"`
+	CreatedAt       time.Time  `json:"created_at,omitempty"`
+}
+
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsTotpEnabled reports whether logins require a TOTP code, a secret without TotpEnabledAt
+// is an enrollment that was not confirmed yet.
+func (u *User) IsTotpEnabled() bool {
+	return u.TotpEnabledAt != nil && u.TotpSecret != ""
 }
 
+// RefreshToken is rotated on every refresh, all the tokens issued from the same login share
+// a session so reusing a rotated token can revoke the whole family. RotatedAt is only kept
+// on the last token that was rotated in the session.
 type RefreshToken struct {
 	Id        uuid.UUID
 	UserId    uuid.UUID
+	SessionId uuid.UUID
 	Active    bool
 	Token     string
 	CreatedAt time.Time
 	ExpiresAt time.Time
+	RotatedAt *time.Time
+}
+
+// WasJustRotated reports whether the token is the one the session rotated last and it was
+// rotated less than grace ago, two tabs refreshing at once send the same token and only the
+// first one gets to rotate it.
+func (t *RefreshToken) WasJustRotated(now time.Time, grace time.Duration) bool {
+	return !t.Active && t.RotatedAt != nil && now.Sub(*t.RotatedAt) < grace
+}
+
+// UserSession is a signed in device of the user, it lives until the user logs out, it is
+// revoked or its refresh tokens expire.
+type UserSession struct {
+	Id         uuid.UUID  `json:"id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IpAddress  string     `json:"ip_address"`
+	Current    bool       `json:"current"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (s *UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+type UserTokenPurpose string
+
+var (
+	UserTokenPurposeEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenPurposePasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenPurposeMfaChallenge      UserTokenPurpose = "mfa_challenge"
+	UserTokenPurposeEmailChange       UserTokenPurpose = "email_change"
+)
+
+// UserToken is a single-use token sent to the user by email or handed out as the MFA
+// challenge of a login, only the hash of the token is stored. Email is only set for email
+// changes, it is the new address the token was sent to.
+type UserToken struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	Purpose   UserTokenPurpose
+	Token     string
+	TokenHash string
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (t *UserToken) IsValid(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// UserIdentity links the user to an account of an external identity provider, the subject
+// is only unique within its issuer.
+type UserIdentity struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OidcLoginState keeps what the single sign-on callback needs to finish the login started
+// by the browser, only the hash of the state sent to the provider is stored.
+type OidcLoginState struct {
+	Id           uuid.UUID
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
 }
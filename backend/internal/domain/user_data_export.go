@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserDataExportStatus string
+
+var (
+	UserDataExportStatusPending UserDataExportStatus = "pending"
+	UserDataExportStatusReady   UserDataExportStatus = "ready"
+	UserDataExportStatusFailed  UserDataExportStatus = "failed"
+)
+
+// UserDataExport is an archive of the personal data of the user, it is built in the
+// background after the user asks for it and can be downloaded until it expires.
+type UserDataExport struct {
+	Id          uuid.UUID            `json:"id"`
+	UserId      uuid.UUID            `json:"user_id"`
+	Status      UserDataExportStatus `json:"status"`
+	Archive     []byte               `json:"-"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+func (e *UserDataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == UserDataExportStatusReady && now.Before(e.ExpiresAt)
+}
+
+type UserDataExportProject struct {
+	Id          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Role        ProjectMemberRole `json:"role"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// UserData is everything the archive of an export holds, each field is written to its own
+// JSON file.
+type UserData struct {
+	Profile      User                    `json:"profile"`
+	Projects     []UserDataExportProject `json:"projects"`
+	Tasks        []Task                  `json:"tasks"`
+	ChatMessages []ChatMessage           `json:"chat_messages"`
+}
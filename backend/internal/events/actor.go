@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ActorHeader is the message header carrying the user whose request published the event.
+const ActorHeader = "actor_id"
+
+type actorContextKey string
+
+const ActorContextKey actorContextKey = "actor_id"
+
+// WithActor marks the events published with the returned context as caused by the user.
+func WithActor(ctx context.Context, userId uuid.UUID) context.Context {
+	return context.WithValue(ctx, ActorContextKey, userId)
+}
+
+// ActorFromContext returns the user set by WithActor, it is uuid.Nil for events that were
+// not caused by a request, such as the ones published by subscribers.
+func ActorFromContext(ctx context.Context) uuid.UUID {
+	userId, ok := ctx.Value(ActorContextKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+	return userId
+}
@@ -8,8 +8,13 @@ const (
 	// Project
 	ProjectCreated       Topic = "project.created"
 	ProjectUpdated       Topic = "project.updated"
+	ProjectArchived      Topic = "project.archived"
+	ProjectDeleted       Topic = "project.deleted"
 	ProjectMemberCreated Topic = "project.member.created"
 	ProjectMemberRemoved Topic = "project.member.removed"
+	ProjectMemberUpdated Topic = "project.member.updated"
+
+	ProjectOwnershipTransferred Topic = "project.ownership.transferred"
 
 	ChatMemberCreated  Topic = "chat.member.created"
 	ChatMemberViewed   Topic = "chat.member.viewed"
@@ -17,24 +22,42 @@ const (
 
 	TaskCreated Topic = "task.created"
 	TaskUpdated Topic = "task.updated"
+
+	TaskCommentCreated Topic = "task.comment.created"
+
+	UserSessionRevoked Topic = "user.session.revoked"
+	UserProfileUpdated Topic = "user.profile.updated"
+	UserDeleted        Topic = "user.deleted"
+
+	UserDataExportRequested Topic = "user.data_export.requested"
 )
 
 func (t Topic) String() string {
 	return string(t)
 }
 
+// AllTopics lists every topic published by the application.
+var AllTopics = []Topic{
+	ProjectCreated,
+	ProjectUpdated,
+	ProjectArchived,
+	ProjectDeleted,
+	ProjectMemberCreated,
+	ProjectMemberRemoved,
+	ProjectMemberUpdated,
+	ProjectOwnershipTransferred,
+	ChatMemberCreated,
+	ChatMessageCreated,
+	ChatMemberViewed,
+	TaskCreated,
+	TaskUpdated,
+	TaskCommentCreated,
+	UserSessionRevoked,
+	UserProfileUpdated,
+	UserDeleted,
+	UserDataExportRequested,
+}
+
 func (t Topic) Valid() bool {
-	var allowedTopics = []Topic{
-		ProjectCreated,
-		ProjectUpdated,
-		ProjectMemberCreated,
-		ProjectMemberRemoved,
-		ChatMemberCreated,
-		ChatMessageCreated,
-		ChatMemberViewed,
-		TaskCreated,
-		TaskUpdated,
-	}
-
-	return slices.Contains(allowedTopics, t)
+	return slices.Contains(AllTopics, t)
 }
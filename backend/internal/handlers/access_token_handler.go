@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type accessTokenService interface {
+	Create(ctx context.Context, request service.CreateAccessTokenRequest) (*service.CreatedAccessToken, error)
+	List(ctx context.Context, userId uuid.UUID) ([]domain.PersonalAccessToken, error)
+	Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+}
+
+type AccessTokenHandler struct {
+	accessTokenService accessTokenService
+}
+
+func NewAccessTokenHandler(accessTokenService accessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		accessTokenService: accessTokenService,
+	}
+}
+
+func (h *AccessTokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var request CreateAccessTokenRequest
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateAccessTokenRequest{
+		UserId:    UserIdFromContext(r.Context()),
+		Name:      request.Name,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(request.Scopes))),
+		ExpiresIn: time.Duration(request.ExpiresInDays) * 24 * time.Hour,
+	}
+
+	token, err := h.accessTokenService.Create(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, token, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *AccessTokenHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	tokens, err := h.accessTokenService.List(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, tokens, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *AccessTokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid access token id"))
+		return
+	}
+
+	err = h.accessTokenService.Revoke(r.Context(), userId, parsed)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
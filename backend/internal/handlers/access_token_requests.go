@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"slices"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+)
+
+const maxAccessTokenExpirationDays = 365
+
+type CreateAccessTokenRequest struct {
+	Name          string                    `json:"name"`
+	Scopes        []domain.AccessTokenScope `json:"scopes"`
+	ExpiresInDays int                       `json:"expires_in_days"`
+}
+
+func (r *CreateAccessTokenRequest) Validate(v *validator.Validator) {
+	v.Check("name", "name is required", validator.NotBlank(r.Name))
+	v.Check("name", "name must have at most 100 characters", len(r.Name) <= 100)
+
+	v.Check("scopes", "at least one scope is required", len(r.Scopes) > 0)
+	for _, scope := range r.Scopes {
+		v.Check("scopes", "scope is invalid", slices.Contains(domain.AllowedAccessTokenScopes, scope))
+	}
+
+	v.Check("expires_in_days", "expires_in_days must be between 1 and 365", r.ExpiresInDays >= 1 && r.ExpiresInDays <= maxAccessTokenExpirationDays)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type activityService interface {
+	ListByProjectId(ctx context.Context, request service.ListActivitiesByProjectIdRequest) (*utils.CursorPaginated[domain.ProjectActivity], error)
+}
+
+type ActivityHandler struct {
+	activityService activityService
+}
+
+func NewActivityHandler(activityService activityService) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+	}
+}
+
+func (h *ActivityHandler) ListByProjectId(w http.ResponseWriter, r *http.Request) {
+	projectId := chi.URLParam(r, "id")
+	parsedProjectId, err := uuid.Parse(projectId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	limit := utils.GetQueryInt(r, "limit", 20)
+	if limit <= 0 {
+		BadRequestResponse(w, errors.New("limit must be greater than 0"))
+		return
+	}
+
+	if limit > 100 {
+		BadRequestResponse(w, errors.New("limit must be at most 100"))
+		return
+	}
+
+	before := utils.GetQueryString(r, "before", "")
+	beforeTime := time.Now()
+	if before != "" {
+		date, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			BadRequestResponse(w, errors.New("invalid before date"))
+			return
+		}
+		beforeTime = date
+	}
+
+	beforeId := utils.GetQueryString(r, "id", "")
+	beforeIdUUID := uuid.Nil
+	if beforeId != "" {
+		parsedBeforeId, err := uuid.Parse(beforeId)
+		if err != nil {
+			BadRequestResponse(w, err)
+			return
+		}
+		beforeIdUUID = parsedBeforeId
+	}
+
+	serviceRequest := service.ListActivitiesByProjectIdRequest{
+		ProjectId: parsedProjectId,
+		UserId:    UserIdFromContext(r.Context()),
+		Params: utils.PaginationBeforeParams{
+			Limit:  limit,
+			Before: beforeTime,
+			Id:     beforeIdUUID,
+		},
+	}
+
+	activities, err := h.activityService.ListByProjectId(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, activities, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/logger"
@@ -33,6 +34,9 @@ func ErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 
 	if errors.As(err, &domainErr) {
 		apiErr = mapDomainErrors(domainErr)
+		if retryAfter, ok := domainErr.Meta.(domain.RetryAfter); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter.Seconds))
+		}
 		if apiErr.Status == http.StatusInternalServerError {
 			if domainErr.Cause != nil {
 				log.Error("internal_server_error", "message", domainErr.Message, "error", domainErr.Cause.Error())
@@ -125,6 +129,12 @@ func mapDomainErrors(err domain.DomainError) ApiError {
 			Status:  http.StatusInternalServerError,
 			Message: "Internal server error",
 		}
+	case domain.TooManyRequestsErrorCode:
+		return ApiError{
+			Status:  http.StatusTooManyRequests,
+			Message: err.Message,
+			Meta:    err.Meta,
+		}
 	case domain.BusinessValidationErrorCode:
 		return ApiError{
 			Status:  http.StatusUnprocessableEntity,
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gabrielnakaema/project-chat/internal/token"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+)
+
+type keySetProvider interface {
+	JWKS() token.JWKSet
+}
+
+type JwksHandler struct {
+	keySetProvider keySetProvider
+}
+
+func NewJwksHandler(keySetProvider keySetProvider) *JwksHandler {
+	return &JwksHandler{
+		keySetProvider: keySetProvider,
+	}
+}
+
+func (h *JwksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	headers := http.Header{}
+	headers.Set("Cache-Control", "public, max-age=300")
+
+	utils.WriteJSON(w, http.StatusOK, h.keySetProvider.JWKS(), headers)
+}
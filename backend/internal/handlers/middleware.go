@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -14,13 +18,25 @@ type tokenProvider interface {
 	Verify(token string) (*jwt.Token, error)
 }
 
+type accessTokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.PersonalAccessToken, error)
+}
+
+type sessionChecker interface {
+	CheckSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error
+}
+
 type AuthMiddleware struct {
-	tokenProvider tokenProvider
+	tokenProvider            tokenProvider
+	accessTokenAuthenticator accessTokenAuthenticator
+	sessionChecker           sessionChecker
 }
 
-func NewAuthMiddleware(tokenProvider tokenProvider) *AuthMiddleware {
+func NewAuthMiddleware(tokenProvider tokenProvider, accessTokenAuthenticator accessTokenAuthenticator, sessionChecker sessionChecker) *AuthMiddleware {
 	return &AuthMiddleware{
-		tokenProvider: tokenProvider,
+		tokenProvider:            tokenProvider,
+		accessTokenAuthenticator: accessTokenAuthenticator,
+		sessionChecker:           sessionChecker,
 	}
 }
 
@@ -36,6 +52,48 @@ func UserIdFromContext(ctx context.Context) uuid.UUID {
 	return userId
 }
 
+type sessionIdContextKey string
+
+const SessionIdContextKey sessionIdContextKey = "session_id"
+
+// SessionIdFromContext returns the session of the access token, it is uuid.Nil for anonymous
+// requests.
+func SessionIdFromContext(ctx context.Context) uuid.UUID {
+	sessionId, ok := ctx.Value(SessionIdContextKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+	return sessionId
+}
+
+type tokenExpiresAtContextKey string
+
+const TokenExpiresAtContextKey tokenExpiresAtContextKey = "token_expires_at"
+
+// TokenExpiresAtFromContext returns when the JWT of the request expires, it is the zero time
+// for anonymous requests and personal access tokens.
+func TokenExpiresAtFromContext(ctx context.Context) time.Time {
+	expiresAt, ok := ctx.Value(TokenExpiresAtContextKey).(time.Time)
+	if !ok {
+		return time.Time{}
+	}
+	return expiresAt
+}
+
+type accessTokenContextKey string
+
+const AccessTokenContextKey accessTokenContextKey = "access_token"
+
+// AccessTokenFromContext returns the personal access token the request was authenticated
+// with, it is nil for requests made with a JWT.
+func AccessTokenFromContext(ctx context.Context) *domain.PersonalAccessToken {
+	token, ok := ctx.Value(AccessTokenContextKey).(*domain.PersonalAccessToken)
+	if !ok {
+		return nil
+	}
+	return token
+}
+
 func WithAnonymousUser(ctx context.Context) context.Context {
 	return context.WithValue(ctx, UserIdContextKey, uuid.Nil)
 }
@@ -63,13 +121,33 @@ func (am *AuthMiddleware) IdentifyUser(next http.Handler) http.Handler {
 			return
 		}
 
-		jwt, err := am.tokenProvider.Verify(token)
+		if strings.HasPrefix(token, domain.AccessTokenPrefix) {
+			accessToken, err := am.accessTokenAuthenticator.Authenticate(r.Context(), token)
+			if err != nil {
+				var domainErr domain.DomainError
+				if errors.As(err, &domainErr) && domainErr.Code == domain.UnauthorizedErrorCode {
+					UnauthorizedResponse(w, INVALID_TOKEN_ERROR_MESSAGE)
+					return
+				}
+				ErrorResponse(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserIdContextKey, accessToken.UserId)
+			ctx = context.WithValue(ctx, AccessTokenContextKey, accessToken)
+			ctx = events.WithActor(ctx, accessToken.UserId)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		jwtToken, err := am.tokenProvider.Verify(token)
 		if err != nil {
 			UnauthorizedResponse(w, INVALID_TOKEN_ERROR_MESSAGE)
 			return
 		}
 
-		claims := jwt.Claims
+		claims := jwtToken.Claims
 
 		exp, err := claims.GetExpirationTime()
 		if err != nil {
@@ -94,12 +172,39 @@ func (am *AuthMiddleware) IdentifyUser(next http.Handler) http.Handler {
 			return
 		}
 
-		req := r.WithContext(context.WithValue(r.Context(), UserIdContextKey, tokenUserId))
+		// every access token belongs to a session, the token stops working as soon as the
+		// session is revoked instead of when it expires
+		var sessionId uuid.UUID
+		if mapClaims, ok := claims.(jwt.MapClaims); ok {
+			if sid, ok := mapClaims["sid"].(string); ok {
+				sessionId, _ = uuid.Parse(sid)
+			}
+		}
+
+		err = am.sessionChecker.CheckSession(r.Context(), tokenUserId, sessionId)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) && domainErr.Code == domain.UnauthorizedErrorCode {
+				UnauthorizedResponse(w, INVALID_TOKEN_ERROR_MESSAGE)
+				return
+			}
+			ErrorResponse(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserIdContextKey, tokenUserId)
+		ctx = context.WithValue(ctx, TokenExpiresAtContextKey, exp.Time)
+		ctx = context.WithValue(ctx, SessionIdContextKey, sessionId)
+		ctx = events.WithActor(ctx, tokenUserId)
+
+		req := r.WithContext(ctx)
 
 		next.ServeHTTP(w, req)
 	})
 }
 
+// ProtectRoutes only lets signed in users through, personal access tokens are refused since
+// they can only be used on routes guarded by Authorize.
 func (am *AuthMiddleware) ProtectRoutes(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userId := UserIdFromContext(r.Context())
@@ -107,6 +212,34 @@ func (am *AuthMiddleware) ProtectRoutes(next http.Handler) http.Handler {
 			UnauthorizedResponse(w, "unauthorized")
 			return
 		}
+
+		if AccessTokenFromContext(r.Context()) != nil {
+			ErrorResponse(w, r, domain.ForbiddenError("personal access tokens cannot be used on this route"))
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Authorize lets signed in users through, as well as personal access tokens that were
+// granted the scope.
+func (am *AuthMiddleware) Authorize(scope domain.AccessTokenScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userId := UserIdFromContext(r.Context())
+			if userId == uuid.Nil {
+				UnauthorizedResponse(w, "unauthorized")
+				return
+			}
+
+			accessToken := AccessTokenFromContext(r.Context())
+			if accessToken != nil && !accessToken.HasScope(scope) {
+				ErrorResponse(w, r, domain.ForbiddenError(fmt.Sprintf("access token is missing the %s scope", scope)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
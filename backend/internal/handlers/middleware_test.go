@@ -1,12 +1,14 @@
 package handlers_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/handlers"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -26,9 +28,31 @@ func (m *mockTokenProvider) Verify(token string) (*jwt.Token, error) {
 	return args.Get(0).(*jwt.Token), args.Error(1)
 }
 
-func createValidJwt(userId uuid.UUID, validExpirationTime time.Time) *jwt.Token {
+type mockAccessTokenAuthenticator struct {
+	mock.Mock
+}
+
+func (m *mockAccessTokenAuthenticator) Authenticate(ctx context.Context, token string) (*domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonalAccessToken), args.Error(1)
+}
+
+type mockSessionChecker struct {
+	mock.Mock
+}
+
+func (m *mockSessionChecker) CheckSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	args := m.Called(ctx, userId, sessionId)
+	return args.Error(0)
+}
+
+func createValidJwt(userId uuid.UUID, sessionId uuid.UUID, validExpirationTime time.Time) *jwt.Token {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": userId.String(),
+		"sid": sessionId.String(),
 		"exp": float64(validExpirationTime.Unix()),
 		"iat": float64(time.Now().Unix()),
 		"iss": "projectmanagementapi",
@@ -45,24 +69,59 @@ func TestAuthMiddleware(t *testing.T) {
 		authHeader  string
 		status      int
 		checkUserId uuid.UUID
-		mockSetup   func(*mockTokenProvider)
+		// checkTokenExpiresAt is only set for requests authenticated with a JWT.
+		checkTokenExpiresAt time.Time
+		mockSetup           func(*mockTokenProvider)
+		// accessTokenSetup is optional, most cases never reach the access token authenticator.
+		accessTokenSetup func(*mockAccessTokenAuthenticator)
+		// sessionSetup is optional, by default every session is active.
+		sessionSetup func(*mockSessionChecker)
 	}
 
 	validUserId := uuid.New()
+	validSessionId := uuid.New()
 	validExpirationTime := time.Now().Add(time.Hour)
 
 	tests := []testCase{
 		{
-			name:        "valid token",
-			authHeader:  "Bearer valid-token",
-			status:      http.StatusOK,
-			checkUserId: validUserId,
+			name:                "valid token",
+			authHeader:          "Bearer valid-token",
+			status:              http.StatusOK,
+			checkUserId:         validUserId,
+			checkTokenExpiresAt: validExpirationTime,
 			mockSetup: func(mockTokenProvider *mockTokenProvider) {
-				token := createValidJwt(validUserId, validExpirationTime)
+				token := createValidJwt(validUserId, validSessionId, validExpirationTime)
 
 				mockTokenProvider.On("Verify", "valid-token").Return(token, nil)
 			},
 		},
+		{
+			name:        "token of a revoked session",
+			authHeader:  "Bearer revoked-session-token",
+			status:      http.StatusUnauthorized,
+			checkUserId: uuid.Nil,
+			mockSetup: func(mockTokenProvider *mockTokenProvider) {
+				token := createValidJwt(validUserId, validSessionId, validExpirationTime)
+				mockTokenProvider.On("Verify", "revoked-session-token").Return(token, nil)
+			},
+			sessionSetup: func(mockChecker *mockSessionChecker) {
+				mockChecker.On("CheckSession", mock.Anything, validUserId, validSessionId).Return(domain.UnauthorizedError("invalid session"))
+			},
+		},
+		{
+			name:        "token without session",
+			authHeader:  "Bearer sessionless-token",
+			status:      http.StatusUnauthorized,
+			checkUserId: uuid.Nil,
+			mockSetup: func(mockTokenProvider *mockTokenProvider) {
+				token := createValidJwt(validUserId, validSessionId, validExpirationTime)
+				delete(token.Claims.(jwt.MapClaims), "sid")
+				mockTokenProvider.On("Verify", "sessionless-token").Return(token, nil)
+			},
+			sessionSetup: func(mockChecker *mockSessionChecker) {
+				mockChecker.On("CheckSession", mock.Anything, validUserId, uuid.Nil).Return(domain.UnauthorizedError("invalid session"))
+			},
+		},
 		{
 			name:        "empty header",
 			authHeader:  "",
@@ -102,10 +161,44 @@ func TestAuthMiddleware(t *testing.T) {
 			status:      http.StatusUnauthorized,
 			checkUserId: uuid.Nil,
 			mockSetup: func(mockTokenProvider *mockTokenProvider) {
-				token := createValidJwt(validUserId, time.Now().Add(-time.Hour))
+				token := createValidJwt(validUserId, validSessionId, time.Now().Add(-time.Hour))
 				mockTokenProvider.On("Verify", "expired-token").Return(token, nil)
 			},
 		},
+		{
+			name:        "valid personal access token",
+			authHeader:  "Bearer pat_valid",
+			status:      http.StatusOK,
+			checkUserId: validUserId,
+			mockSetup: func(mockTokenProvider *mockTokenProvider) {
+			},
+			accessTokenSetup: func(mockAuthenticator *mockAccessTokenAuthenticator) {
+				token := &domain.PersonalAccessToken{Id: uuid.New(), UserId: validUserId, Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeTasksRead}}
+				mockAuthenticator.On("Authenticate", mock.Anything, "pat_valid").Return(token, nil)
+			},
+		},
+		{
+			name:        "revoked personal access token",
+			authHeader:  "Bearer pat_revoked",
+			status:      http.StatusUnauthorized,
+			checkUserId: uuid.Nil,
+			mockSetup: func(mockTokenProvider *mockTokenProvider) {
+			},
+			accessTokenSetup: func(mockAuthenticator *mockAccessTokenAuthenticator) {
+				mockAuthenticator.On("Authenticate", mock.Anything, "pat_revoked").Return(nil, domain.UnauthorizedError("invalid token"))
+			},
+		},
+		{
+			name:        "personal access token lookup failure",
+			authHeader:  "Bearer pat_valid",
+			status:      http.StatusInternalServerError,
+			checkUserId: uuid.Nil,
+			mockSetup: func(mockTokenProvider *mockTokenProvider) {
+			},
+			accessTokenSetup: func(mockAuthenticator *mockAccessTokenAuthenticator) {
+				mockAuthenticator.On("Authenticate", mock.Anything, "pat_valid").Return(nil, errors.New("database error"))
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -113,7 +206,19 @@ func TestAuthMiddleware(t *testing.T) {
 			mockTokenProvider := &mockTokenProvider{}
 			tt.mockSetup(mockTokenProvider)
 
-			authMiddleware := handlers.NewAuthMiddleware(mockTokenProvider)
+			mockAuthenticator := &mockAccessTokenAuthenticator{}
+			if tt.accessTokenSetup != nil {
+				tt.accessTokenSetup(mockAuthenticator)
+			}
+
+			mockChecker := &mockSessionChecker{}
+			if tt.sessionSetup != nil {
+				tt.sessionSetup(mockChecker)
+			} else {
+				mockChecker.On("CheckSession", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+			}
+
+			authMiddleware := handlers.NewAuthMiddleware(mockTokenProvider, mockAuthenticator, mockChecker)
 
 			req := httptest.NewRequest("GET", "/test", nil)
 			if tt.authHeader != "" {
@@ -123,8 +228,10 @@ func TestAuthMiddleware(t *testing.T) {
 			recorder := httptest.NewRecorder()
 
 			var userId uuid.UUID
+			var tokenExpiresAt time.Time
 			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				userId = handlers.UserIdFromContext(r.Context())
+				tokenExpiresAt = handlers.TokenExpiresAtFromContext(r.Context())
 				w.WriteHeader(http.StatusOK)
 			})
 
@@ -133,8 +240,95 @@ func TestAuthMiddleware(t *testing.T) {
 
 			assert.Equal(t, tt.checkUserId, userId)
 			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, tt.checkTokenExpiresAt.Unix(), tokenExpiresAt.Unix())
 
 			mockTokenProvider.AssertExpectations(t)
+			mockAuthenticator.AssertExpectations(t)
+			mockChecker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthMiddleware_Authorize(t *testing.T) {
+	userId := uuid.New()
+	scopedToken := &domain.PersonalAccessToken{
+		Id:     uuid.New(),
+		UserId: userId,
+		Scopes: []domain.AccessTokenScope{domain.AccessTokenScopeTasksRead, domain.AccessTokenScopeChatWrite},
+	}
+
+	withAccessToken := func(ctx context.Context) context.Context {
+		ctx = context.WithValue(ctx, handlers.UserIdContextKey, userId)
+		return context.WithValue(ctx, handlers.AccessTokenContextKey, scopedToken)
+	}
+
+	tests := []struct {
+		name       string
+		middleware func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler
+		context    func(ctx context.Context) context.Context
+		status     int
+	}{
+		{
+			name: "signed in user on scoped route",
+			middleware: func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler {
+				return am.Authorize(domain.AccessTokenScopeTasksWrite)
+			},
+			context: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, handlers.UserIdContextKey, userId)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "anonymous user on scoped route",
+			middleware: func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler {
+				return am.Authorize(domain.AccessTokenScopeTasksRead)
+			},
+			context: handlers.WithAnonymousUser,
+			status:  http.StatusUnauthorized,
+		},
+		{
+			name: "access token with the scope",
+			middleware: func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler {
+				return am.Authorize(domain.AccessTokenScopeChatWrite)
+			},
+			context: withAccessToken,
+			status:  http.StatusOK,
+		},
+		{
+			name: "access token without the scope",
+			middleware: func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler {
+				return am.Authorize(domain.AccessTokenScopeTasksWrite)
+			},
+			context: withAccessToken,
+			status:  http.StatusForbidden,
+		},
+		{
+			name: "access token on session only route",
+			middleware: func(am *handlers.AuthMiddleware) func(http.Handler) http.Handler {
+				return am.ProtectRoutes
+			},
+			context: withAccessToken,
+			status:  http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authMiddleware := handlers.NewAuthMiddleware(&mockTokenProvider{}, &mockAccessTokenAuthenticator{}, &mockSessionChecker{})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req = req.WithContext(tt.context(req.Context()))
+
+			recorder := httptest.NewRecorder()
+
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := tt.middleware(authMiddleware)(nextHandler)
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.status, recorder.Code)
 		})
 	}
 }
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+)
+
+const (
+	OidcStateCookieName = "project_chat_oidc_state"
+	// oidcStateCookieDuration matches how long the service keeps the login state.
+	oidcStateCookieDuration = 10 * time.Minute
+)
+
+type oidcService interface {
+	Authorize(ctx context.Context) (*service.OidcAuthorization, error)
+	Login(ctx context.Context, request service.OidcLoginRequest) (*service.LoginResult, error)
+}
+
+type OidcHandler struct {
+	oidcService oidcService
+}
+
+func NewOidcHandler(oidcService oidcService) *OidcHandler {
+	return &OidcHandler{
+		oidcService: oidcService,
+	}
+}
+
+type OidcAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// Authorize returns the identity provider URL the frontend sends the browser to. The state
+// is also kept in a cookie so only this browser can finish the login.
+func (h *OidcHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	authorization, err := h.oidcService.Authorize(r.Context())
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	setOidcStateCookie(w, authorization.State, time.Now().Add(oidcStateCookieDuration))
+
+	utils.WriteJSON(w, http.StatusOK, OidcAuthorizeResponse{AuthorizationURL: authorization.AuthorizationURL}, nil)
+}
+
+// Callback finishes the login with the code and the state the provider redirected the
+// browser back with, the response is the same as a password login.
+func (h *OidcHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	var request OidcCallbackRequest
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	var browserState string
+	if cookie, err := r.Cookie(OidcStateCookieName); err == nil {
+		browserState = cookie.Value
+	}
+
+	// the state can only be used once, whatever the outcome
+	setOidcStateCookie(w, "", time.Now().Add(-1*time.Hour))
+
+	serviceRequest := service.OidcLoginRequest{
+		Code:         request.Code,
+		State:        request.State,
+		BrowserState: browserState,
+		UserAgent:    r.UserAgent(),
+		IpAddress:    clientIp(r),
+	}
+
+	result, err := h.oidcService.Login(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	if result.MfaToken != "" {
+		utils.WriteJSON(w, http.StatusOK, MfaChallengeResponse{MfaRequired: true, MfaToken: result.MfaToken}, nil)
+		return
+	}
+
+	setRefreshTokenCookie(w, result.RefreshToken)
+
+	utils.WriteJSON(w, http.StatusOK, loginResultToResponse(result), nil)
+}
+
+func setOidcStateCookie(w http.ResponseWriter, state string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OidcStateCookieName,
+		Value:    state,
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+		Expires:  expires,
+	})
+}
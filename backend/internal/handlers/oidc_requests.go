@@ -0,0 +1,13 @@
+package handlers
+
+import "github.com/gabrielnakaema/project-chat/internal/validator"
+
+type OidcCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+func (r *OidcCallbackRequest) Validate(v *validator.Validator) {
+	v.Check("code", "code is required", validator.NotBlank(r.Code))
+	v.Check("state", "state is required", validator.NotBlank(r.State))
+}
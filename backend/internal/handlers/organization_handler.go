@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type organizationService interface {
+	Create(ctx context.Context, request service.CreateOrganizationRequest) (*domain.Organization, error)
+	GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Organization, error)
+	ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.Organization, error)
+	Update(ctx context.Context, request service.UpdateOrganizationRequest) (*domain.Organization, error)
+	CreateMember(ctx context.Context, request service.CreateOrganizationMemberRequest) (*domain.OrganizationMember, error)
+	ListMembers(ctx context.Context, request service.ListOrganizationMembersRequest) ([]domain.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, request service.UpdateOrganizationMemberRoleRequest) (*domain.OrganizationMember, error)
+	RemoveMember(ctx context.Context, request service.RemoveOrganizationMemberRequest) error
+}
+
+type OrganizationHandler struct {
+	organizationService organizationService
+}
+
+func NewOrganizationHandler(organizationService organizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+	}
+}
+
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var request OrganizationRequest
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateOrganizationRequest{
+		Name:   request.Name,
+		UserId: UserIdFromContext(r.Context()),
+	}
+
+	organization, err := h.organizationService.Create(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, organization, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	organizations, err := h.organizationService.ListByUserId(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, organizations, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	organization, err := h.organizationService.GetById(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, organization, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	var request OrganizationRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.UpdateOrganizationRequest{
+		OrganizationId: parsed,
+		Name:           request.Name,
+		RequestUserId:  userId,
+	}
+
+	organization, err := h.organizationService.Update(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, organization, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+// ListMembers is the member directory of the organization, the q query param filters
+// members by name or email for autocomplete.
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	limit := utils.GetQueryInt(r, "limit", 20)
+	if limit <= 0 {
+		BadRequestResponse(w, errors.New("limit must be greater than 0"))
+		return
+	}
+
+	if limit > 100 {
+		BadRequestResponse(w, errors.New("limit must be at most 100"))
+		return
+	}
+
+	serviceRequest := service.ListOrganizationMembersRequest{
+		OrganizationId: parsed,
+		Search:         utils.GetQueryString(r, "q", ""),
+		Limit:          int(limit),
+		RequestUserId:  userId,
+	}
+
+	members, err := h.organizationService.ListMembers(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, members, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	var request CreateOrganizationMemberRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateOrganizationMemberRequest{
+		OrganizationId: parsed,
+		Email:          request.Email,
+		Role:           request.Role,
+		RequestUserId:  userId,
+	}
+
+	member, err := h.organizationService.CreateMember(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, member, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	memberUserId := chi.URLParam(r, "userId")
+	parsedMemberUserId, err := uuid.Parse(memberUserId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid user id"))
+		return
+	}
+
+	var request UpdateOrganizationMemberRoleRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.UpdateOrganizationMemberRoleRequest{
+		OrganizationId: parsed,
+		UserId:         parsedMemberUserId,
+		Role:           request.Role,
+		RequestUserId:  userId,
+	}
+
+	member, err := h.organizationService.UpdateMemberRole(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, member, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	memberUserId := chi.URLParam(r, "userId")
+	parsedMemberUserId, err := uuid.Parse(memberUserId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid user id"))
+		return
+	}
+
+	serviceRequest := service.RemoveOrganizationMemberRequest{
+		OrganizationId: parsed,
+		UserId:         parsedMemberUserId,
+		RequestUserId:  userId,
+	}
+
+	err = h.organizationService.RemoveMember(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
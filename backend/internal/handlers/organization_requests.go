@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"slices"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+)
+
+type OrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *OrganizationRequest) Validate(v *validator.Validator) {
+	v.Check("name", "name is required", validator.NotBlank(r.Name))
+}
+
+type CreateOrganizationMemberRequest struct {
+	Email string                        `json:"email"`
+	Role  domain.OrganizationMemberRole `json:"role"`
+}
+
+func (r *CreateOrganizationMemberRequest) Validate(v *validator.Validator) {
+	v.Check("email", "email is required", validator.NotBlank(r.Email))
+	v.Check("email", "email is invalid", validator.ValidEmail(r.Email))
+	v.Check("role", "role is invalid", r.Role == "" || slices.Contains(domain.AllowedOrganizationMemberRoles, r.Role))
+}
+
+type UpdateOrganizationMemberRoleRequest struct {
+	Role domain.OrganizationMemberRole `json:"role"`
+}
+
+func (r *UpdateOrganizationMemberRoleRequest) Validate(v *validator.Validator) {
+	v.Check("role", "role is invalid", slices.Contains(domain.AllowedOrganizationMemberRoles, r.Role))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/service"
@@ -19,6 +20,22 @@ type projectService interface {
 	ListByUserId(ctx context.Context, request service.ListProjectsByUserIdRequest) ([]domain.Project, error)
 	Update(ctx context.Context, request service.UpdateProjectRequest) (*domain.Project, error)
 	CreateMember(ctx context.Context, request service.CreateMemberRequest) (*domain.ProjectMember, error)
+	RemoveMember(ctx context.Context, request service.RemoveMemberRequest) error
+	UpdateMemberRole(ctx context.Context, request service.UpdateMemberRoleRequest) (*domain.ProjectMember, error)
+	Leave(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) error
+	Archive(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.Project, error)
+	Unarchive(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.Project, error)
+	Delete(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) error
+	Clone(ctx context.Context, request service.CloneProjectRequest) (*domain.Project, error)
+	CreateTemplate(ctx context.Context, request service.CreateTemplateRequest) (*domain.ProjectTemplate, error)
+	ListTemplates(ctx context.Context, userId uuid.UUID) ([]domain.ProjectTemplate, error)
+	DeleteTemplate(ctx context.Context, templateId uuid.UUID, userId uuid.UUID) error
+	CreateFromTemplate(ctx context.Context, request service.CreateFromTemplateRequest) (*domain.Project, error)
+	TransferOwnership(ctx context.Context, request service.TransferOwnershipRequest) (*domain.Project, error)
+	CreateInvitation(ctx context.Context, request service.CreateInvitationRequest) (*domain.ProjectInvitation, error)
+	ListInvitations(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) ([]domain.ProjectInvitation, error)
+	RevokeInvitation(ctx context.Context, request service.RevokeInvitationRequest) error
+	AcceptInvitation(ctx context.Context, request service.AcceptInvitationRequest) (*domain.ProjectMember, error)
 }
 
 type ProjectHandler struct {
@@ -32,7 +49,7 @@ func NewProjectHandler(projectService projectService) *ProjectHandler {
 }
 
 func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var request ProjectRequest
+	var request CreateProjectRequest
 	err := utils.ReadJSON(w, r, &request)
 	if err != nil {
 		BadRequestResponse(w, err)
@@ -49,9 +66,10 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userId := UserIdFromContext(r.Context())
 
 	serviceRequest := service.CreateProjectRequest{
-		Name:        request.Name,
-		Description: request.Description,
-		UserId:      userId,
+		Name:           request.Name,
+		Description:    request.Description,
+		UserId:         userId,
+		OrganizationId: request.OrganizationId,
 	}
 
 	project, err := h.projectService.Create(r.Context(), serviceRequest)
@@ -68,19 +86,41 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.listProjects(w, r, uuid.Nil)
+}
+
+// ListByOrganization lists the projects of an organization the request user is a member of.
+func (h *ProjectHandler) ListByOrganization(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid organization id"))
+		return
+	}
+
+	h.listProjects(w, r, parsed)
+}
+
+func (h *ProjectHandler) listProjects(w http.ResponseWriter, r *http.Request, organizationId uuid.UUID) {
 	userId := UserIdFromContext(r.Context())
 
 	memberRole := utils.GetQueryString(r, "member_role", "")
 
 	v := validator.New()
 	if memberRole != "" {
-		v.Check("member_role", "member_role is required", memberRole == string(domain.ProjectMemberRoleMember) || memberRole == string(domain.ProjectMemberRoleCreator))
+		v.Check("member_role", "member_role is invalid", slices.Contains(domain.AllowedProjectMemberRoles, domain.ProjectMemberRole(memberRole)))
+	}
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
 	}
 
 	serviceRequest := service.ListProjectsByUserIdRequest{
 		UserId:             userId,
 		MemberRole:         domain.ProjectMemberRole(memberRole),
 		ShouldFilterByRole: memberRole != "",
+		IncludeArchived:    utils.GetQueryBool(r, "include_archived", false),
+		OrganizationId:     organizationId,
 	}
 
 	projects, err := h.projectService.ListByUserId(r.Context(), serviceRequest)
@@ -163,6 +203,71 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *ProjectHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	project, err := h.projectService.Archive(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, project, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	project, err := h.projectService.Unarchive(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, project, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	err = h.projectService.Delete(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *ProjectHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
 	userId := UserIdFromContext(r.Context())
 
@@ -189,6 +294,7 @@ func (h *ProjectHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
 
 	serviceRequest := service.CreateMemberRequest{
 		Email:         request.Email,
+		Role:          request.Role,
 		ProjectId:     parsed,
 		RequestUserId: userId,
 	}
@@ -205,3 +311,451 @@ func (h *ProjectHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	memberUserId := chi.URLParam(r, "userId")
+	parsedMemberUserId, err := uuid.Parse(memberUserId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid user id"))
+		return
+	}
+
+	serviceRequest := service.RemoveMemberRequest{
+		ProjectId:     parsed,
+		UserId:        parsedMemberUserId,
+		RequestUserId: userId,
+	}
+
+	err = h.projectService.RemoveMember(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	memberUserId := chi.URLParam(r, "userId")
+	parsedMemberUserId, err := uuid.Parse(memberUserId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid user id"))
+		return
+	}
+
+	var request UpdateMemberRoleRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.UpdateMemberRoleRequest{
+		ProjectId:     parsed,
+		UserId:        parsedMemberUserId,
+		Role:          request.Role,
+		RequestUserId: userId,
+	}
+
+	member, err := h.projectService.UpdateMemberRole(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, member, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	err = h.projectService.Leave(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	var request TransferOwnershipRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.TransferOwnershipRequest{
+		ProjectId:     parsed,
+		UserId:        request.UserId,
+		RequestUserId: userId,
+	}
+
+	project, err := h.projectService.TransferOwnership(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, project, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	var request CreateInvitationRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateInvitationRequest{
+		ProjectId:     parsed,
+		Email:         request.Email,
+		Role:          request.Role,
+		RequestUserId: userId,
+	}
+
+	invitation, err := h.projectService.CreateInvitation(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, invitation, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	invitations, err := h.projectService.ListInvitations(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, invitations, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	invitationId := chi.URLParam(r, "invitationId")
+	parsedInvitationId, err := uuid.Parse(invitationId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid invitation id"))
+		return
+	}
+
+	serviceRequest := service.RevokeInvitationRequest{
+		ProjectId:     parsed,
+		InvitationId:  parsedInvitationId,
+		RequestUserId: userId,
+	}
+
+	err = h.projectService.RevokeInvitation(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	var request AcceptInvitationRequest
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.AcceptInvitationRequest{
+		Token:  request.Token,
+		UserId: userId,
+	}
+
+	member, err := h.projectService.AcceptInvitation(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, member, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	var request CreateProjectRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CloneProjectRequest{
+		ProjectId:      parsed,
+		Name:           request.Name,
+		Description:    request.Description,
+		UserId:         userId,
+		OrganizationId: request.OrganizationId,
+	}
+
+	project, err := h.projectService.Clone(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, project, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid project id"))
+		return
+	}
+
+	var request ProjectRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateTemplateRequest{
+		ProjectId:     parsed,
+		Name:          request.Name,
+		Description:   request.Description,
+		RequestUserId: userId,
+	}
+
+	template, err := h.projectService.CreateTemplate(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, template, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	templates, err := h.projectService.ListTemplates(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, templates, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *ProjectHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid template id"))
+		return
+	}
+
+	err = h.projectService.DeleteTemplate(r.Context(), parsed, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid template id"))
+		return
+	}
+
+	var request CreateProjectRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.CreateFromTemplateRequest{
+		TemplateId:     parsed,
+		Name:           request.Name,
+		Description:    request.Description,
+		UserId:         userId,
+		OrganizationId: request.OrganizationId,
+	}
+
+	project, err := h.projectService.CreateFromTemplate(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, project, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
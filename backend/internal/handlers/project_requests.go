@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"slices"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/validator"
+	"github.com/google/uuid"
 )
 
 type ProjectRequest struct {
@@ -14,11 +18,55 @@ func (r *ProjectRequest) Validate(v *validator.Validator) {
 	v.Check("description", "description is required", validator.NotBlank(r.Description))
 }
 
+// CreateProjectRequest is used by the endpoints that create a project, OrganizationId is
+// optional and defaults to the personal organization of the user.
+type CreateProjectRequest struct {
+	ProjectRequest
+	OrganizationId uuid.UUID `json:"organization_id"`
+}
+
 type CreateMemberRequest struct {
-	Email string `json:"email"`
+	Email string                   `json:"email"`
+	Role  domain.ProjectMemberRole `json:"role"`
 }
 
 func (r *CreateMemberRequest) Validate(v *validator.Validator) {
 	v.Check("email", "email is required", validator.NotBlank(r.Email))
 	v.Check("email", "email is invalid", validator.ValidEmail(r.Email))
+	v.Check("role", "role is invalid", r.Role == "" || slices.Contains(domain.AllowedProjectMemberRoles, r.Role))
+}
+
+type UpdateMemberRoleRequest struct {
+	Role domain.ProjectMemberRole `json:"role"`
+}
+
+func (r *UpdateMemberRoleRequest) Validate(v *validator.Validator) {
+	v.Check("role", "role is invalid", slices.Contains(domain.AllowedProjectMemberRoles, r.Role))
+}
+
+type TransferOwnershipRequest struct {
+	UserId uuid.UUID `json:"user_id"`
+}
+
+func (r *TransferOwnershipRequest) Validate(v *validator.Validator) {
+	v.Check("user_id", "user_id is required", r.UserId != uuid.Nil)
+}
+
+type CreateInvitationRequest struct {
+	Email string                   `json:"email"`
+	Role  domain.ProjectMemberRole `json:"role"`
+}
+
+func (r *CreateInvitationRequest) Validate(v *validator.Validator) {
+	v.Check("email", "email is required", validator.NotBlank(r.Email))
+	v.Check("email", "email is invalid", validator.ValidEmail(r.Email))
+	v.Check("role", "role is invalid", r.Role == "" || slices.Contains(domain.AllowedProjectMemberRoles, r.Role))
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+func (r *AcceptInvitationRequest) Validate(v *validator.Validator) {
+	v.Check("token", "token is required", validator.NotBlank(r.Token))
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/logger"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
+	"github.com/google/uuid"
+)
+
+type RateLimitMiddleware struct {
+	store ratelimit.Store
+}
+
+func NewRateLimitMiddleware(store ratelimit.Store) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store: store,
+	}
+}
+
+// Limit spends one request of group for every request, signed in users are limited by their
+// id and anonymous ones by their ip. The ip is the remote address set by RealIP, which only
+// takes forwarded headers from trusted proxies so clients cannot pick their own bucket.
+// Nested groups are counted separately, the headers sent are the ones of the innermost group.
+func (rm *RateLimitMiddleware) Limit(group string, limit ratelimit.Limit) func(http.Handler) http.Handler {
+	return rm.limit(group, limit, func(r *http.Request) string {
+		if userId := UserIdFromContext(r.Context()); userId != uuid.Nil {
+			return group + ":user:" + userId.String()
+		}
+		return group + ":ip:" + clientIp(r)
+	})
+}
+
+// LimitByIp spends one request of group for every request from the ip, whoever the request
+// claims to be. It runs before the user is identified so requests with made up credentials
+// are limited too.
+func (rm *RateLimitMiddleware) LimitByIp(group string, limit ratelimit.Limit) func(http.Handler) http.Handler {
+	return rm.limit(group, limit, func(r *http.Request) string {
+		return group + ":ip:" + clientIp(r)
+	})
+}
+
+func (rm *RateLimitMiddleware) limit(group string, limit ratelimit.Limit, key func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !limit.Enabled() {
+			return next
+		}
+
+		policy := fmt.Sprintf("%d;w=%d", limit.Requests, int(math.Ceil(limit.Period.Seconds())))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := rm.store.Take(r.Context(), key(r), limit)
+			if err != nil {
+				// A broken store should not take the whole api down with it.
+				logger.FromContext(r.Context()).Error("failed to check rate limit", "error", err.Error(), "group", group)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", policy)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds()))))
+
+			if !result.Allowed {
+				ErrorResponse(w, r, domain.TooManyRequestsError("too many requests, try again later", result.RetryAfter))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/handlers"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *mockRateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	args := m.Called(ctx, key, limit)
+	return args.Get(0).(ratelimit.Result), args.Error(1)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	userId := uuid.New()
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name            string
+		context         func(ctx context.Context) context.Context
+		mockSetup       func(*mockRateLimitStore)
+		status          int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:    "anonymous user within the limit",
+			context: handlers.WithAnonymousUser,
+			mockSetup: func(store *mockRateLimitStore) {
+				store.On("Take", mock.Anything, "auth:ip:192.0.2.1", limit).
+					Return(ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, ResetAfter: 6 * time.Second}, nil)
+			},
+			status: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Policy":    "10;w=60",
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "9",
+				"RateLimit-Reset":     "6",
+			},
+		},
+		{
+			name: "signed in user is limited by id",
+			context: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, handlers.UserIdContextKey, userId)
+			},
+			mockSetup: func(store *mockRateLimitStore) {
+				store.On("Take", mock.Anything, "auth:user:"+userId.String(), limit).
+					Return(ratelimit.Result{Allowed: true, Limit: 10, Remaining: 4, ResetAfter: 36 * time.Second}, nil)
+			},
+			status: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Remaining": "4",
+				"RateLimit-Reset":     "36",
+			},
+		},
+		{
+			name:    "limit exceeded",
+			context: handlers.WithAnonymousUser,
+			mockSetup: func(store *mockRateLimitStore) {
+				store.On("Take", mock.Anything, "auth:ip:192.0.2.1", limit).
+					Return(ratelimit.Result{Allowed: false, Limit: 10, Remaining: 0, ResetAfter: time.Minute, RetryAfter: 5500 * time.Millisecond}, nil)
+			},
+			status: http.StatusTooManyRequests,
+			expectedHeaders: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"Retry-After":         "6",
+			},
+		},
+		{
+			name:    "store failure lets the request through",
+			context: handlers.WithAnonymousUser,
+			mockSetup: func(store *mockRateLimitStore) {
+				store.On("Take", mock.Anything, "auth:ip:192.0.2.1", limit).
+					Return(ratelimit.Result{}, errors.New("connection refused"))
+			},
+			status: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"RateLimit-Limit": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockRateLimitStore{}
+			tt.mockSetup(store)
+
+			rateLimitMiddleware := handlers.NewRateLimitMiddleware(store)
+
+			req := httptest.NewRequest("POST", "/auth/login", nil)
+			req.RemoteAddr = "192.0.2.1:4321"
+			req = req.WithContext(tt.context(req.Context()))
+
+			recorder := httptest.NewRecorder()
+
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := rateLimitMiddleware.Limit("auth", limit)(nextHandler)
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			for header, value := range tt.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(header), header)
+			}
+
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	store := &mockRateLimitStore{}
+	rateLimitMiddleware := handlers.NewRateLimitMiddleware(store)
+
+	req := httptest.NewRequest("POST", "/chats/messages", nil)
+	recorder := httptest.NewRecorder()
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := rateLimitMiddleware.Limit("chat_messages", ratelimit.Limit{})(nextHandler)
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+	store.AssertNotCalled(t, "Take", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateLimitMiddleware_KeysAnonymousUsersByTrustedAddress(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name        string
+		remoteAddr  string
+		expectedKey string
+	}{
+		{"forwarded header of an untrusted client is ignored", "203.0.113.7:5000", "auth:ip:203.0.113.7"},
+		{"forwarded header of a trusted proxy is used", "10.0.0.1:5000", "auth:ip:198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockRateLimitStore{}
+			store.On("Take", mock.Anything, tt.expectedKey, limit).
+				Return(ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9}, nil)
+
+			rateLimitMiddleware := handlers.NewRateLimitMiddleware(store)
+
+			req := httptest.NewRequest("POST", "/auth/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			req = req.WithContext(handlers.WithAnonymousUser(req.Context()))
+
+			recorder := httptest.NewRecorder()
+
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := handlers.RealIP(trustedProxies)(rateLimitMiddleware.Limit("auth", limit)(nextHandler))
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_LimitByIp(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name    string
+		context func(ctx context.Context) context.Context
+		allowed bool
+		status  int
+	}{
+		{
+			name: "signed in user is still limited by ip",
+			context: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, handlers.UserIdContextKey, uuid.New())
+			},
+			allowed: true,
+			status:  http.StatusOK,
+		},
+		{
+			name:    "limit exceeded stops the request before it is identified",
+			context: func(ctx context.Context) context.Context { return ctx },
+			allowed: false,
+			status:  http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockRateLimitStore{}
+			store.On("Take", mock.Anything, "ip:ip:192.0.2.1", limit).
+				Return(ratelimit.Result{Allowed: tt.allowed, Limit: 10, RetryAfter: time.Second}, nil)
+
+			rateLimitMiddleware := handlers.NewRateLimitMiddleware(store)
+
+			req := httptest.NewRequest("GET", "/projects", nil)
+			req.RemoteAddr = "192.0.2.1:4321"
+			req.Header.Set("Authorization", "Bearer pat_made_up")
+			req = req.WithContext(tt.context(req.Context()))
+
+			recorder := httptest.NewRecorder()
+
+			reached := false
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				w.WriteHeader(http.StatusOK)
+			})
+
+			handler := rateLimitMiddleware.LimitByIp("ip", limit)(nextHandler)
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, tt.allowed, reached)
+			store.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP replaces the remote address of requests that came through one of the trusted
+// proxies with the client address the proxies forwarded. X-Forwarded-For is read from the
+// right, every proxy appends the address it received the request from, so the first address
+// that is not a trusted proxy is the client. Headers sent by anyone else are ignored, they
+// would let clients choose the address rate limits and lockouts are counted by.
+func RealIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remote, err := netip.ParseAddr(clientIp(r))
+			if err != nil || !isTrusted(remote) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ip, ok := forwardedIp(r, isTrusted); ok {
+				r.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forwardedIp(r *http.Request, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	values := r.Header.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		return addr.Unmap(), true
+	}
+
+	forwardedFor := strings.Split(strings.Join(values, ","), ",")
+
+	// when every address is a trusted proxy or an entry is malformed, the last proxy that
+	// could be read is the closest known hop to the client
+	var last netip.Addr
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwardedFor[i]))
+		if err != nil {
+			break
+		}
+
+		last = addr.Unmap()
+		if !isTrusted(last) {
+			break
+		}
+	}
+
+	return last, last.IsValid()
+}
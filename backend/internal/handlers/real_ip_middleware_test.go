@@ -0,0 +1,90 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gabrielnakaema/project-chat/internal/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	type testCase struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		realIp         string
+		expectedRemote string
+	}
+
+	tests := []testCase{
+		{
+			name:           "untrusted client cannot forward an address",
+			remoteAddr:     "203.0.113.7:5000",
+			forwardedFor:   []string{"198.51.100.1"},
+			realIp:         "198.51.100.2",
+			expectedRemote: "203.0.113.7:5000",
+		},
+		{
+			name:           "trusted proxy forwards the client address",
+			remoteAddr:     "10.0.0.1:5000",
+			forwardedFor:   []string{"198.51.100.1"},
+			expectedRemote: "198.51.100.1:0",
+		},
+		{
+			name:           "addresses sent by the client before the proxies are ignored",
+			remoteAddr:     "10.0.0.1:5000",
+			forwardedFor:   []string{"192.0.2.66, 198.51.100.1, 10.0.0.2"},
+			expectedRemote: "198.51.100.1:0",
+		},
+		{
+			name:           "forwarded for headers are read as one list",
+			remoteAddr:     "10.0.0.1:5000",
+			forwardedFor:   []string{"192.0.2.66", "198.51.100.1"},
+			expectedRemote: "198.51.100.1:0",
+		},
+		{
+			name:           "malformed entry stops at the last proxy",
+			remoteAddr:     "10.0.0.1:5000",
+			forwardedFor:   []string{"not-an-ip, 10.0.0.2"},
+			expectedRemote: "10.0.0.2:0",
+		},
+		{
+			name:           "real ip header of a trusted proxy",
+			remoteAddr:     "10.0.0.1:5000",
+			realIp:         "198.51.100.1",
+			expectedRemote: "198.51.100.1:0",
+		},
+		{
+			name:           "trusted proxy without headers",
+			remoteAddr:     "10.0.0.1:5000",
+			expectedRemote: "10.0.0.1:5000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIp != "" {
+				req.Header.Set("X-Real-IP", tt.realIp)
+			}
+
+			var remoteAddr string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				remoteAddr = r.RemoteAddr
+			})
+
+			handlers.RealIP(trustedProxies)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedRemote, remoteAddr)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/service"
@@ -15,9 +17,19 @@ import (
 
 type taskService interface {
 	Create(ctx context.Context, request service.CreateTaskRequest) (*domain.Task, error)
-	List(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) ([]domain.Task, error)
+	List(ctx context.Context, request service.ListTasksRequest) (*utils.CursorPaginated[domain.Task], error)
 	GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Task, error)
 	Update(ctx context.Context, request service.UpdateTaskRequest) (*domain.Task, error)
+	CreateComment(ctx context.Context, request service.CreateTaskCommentRequest) (*domain.TaskComment, error)
+	ListComments(ctx context.Context, taskId uuid.UUID, userId uuid.UUID) ([]domain.TaskComment, error)
+	UpdateComment(ctx context.Context, request service.UpdateTaskCommentRequest) (*domain.TaskComment, error)
+	DeleteComment(ctx context.Context, request service.DeleteTaskCommentRequest) error
+	SetParent(ctx context.Context, request service.SetTaskParentRequest) (*domain.Task, error)
+	AddBlocker(ctx context.Context, request service.TaskBlockerRequest) (*domain.Task, error)
+	RemoveBlocker(ctx context.Context, request service.TaskBlockerRequest) (*domain.Task, error)
+	CreateChecklistItem(ctx context.Context, request service.CreateChecklistItemRequest) (*domain.TaskChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, request service.UpdateChecklistItemRequest) (*domain.TaskChecklistItem, error)
+	DeleteChecklistItem(ctx context.Context, request service.DeleteChecklistItemRequest) error
 }
 
 type TaskHandler struct {
@@ -50,6 +62,7 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	serviceRequest := service.CreateTaskRequest{
 		ProjectId:     request.ProjectId,
+		ParentId:      request.ParentId,
 		Title:         request.Title,
 		Description:   request.Description,
 		RequestUserId: userId,
@@ -82,19 +95,96 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit := utils.GetQueryInt(r, "limit", 50)
+	if limit <= 0 {
+		BadRequestResponse(w, errors.New("limit must be greater than 0"))
+		return
+	}
+
+	if limit > 100 {
+		BadRequestResponse(w, errors.New("limit must be at most 100"))
+		return
+	}
+
+	filter := domain.TaskListFilter{
+		Search: utils.GetQueryString(r, "q", ""),
+		SortBy: domain.TaskSortField(utils.GetQueryString(r, "sort", string(domain.TaskSortFieldCreatedAt))),
+	}
+
+	if !slices.Contains(domain.AllowedTaskSortFields, filter.SortBy) {
+		BadRequestResponse(w, errors.New("invalid sort"))
+		return
+	}
+
+	switch utils.GetQueryString(r, "order", "asc") {
+	case "asc":
+		filter.SortDesc = false
+	case "desc":
+		filter.SortDesc = true
+	default:
+		BadRequestResponse(w, errors.New("order must be asc or desc"))
+		return
+	}
+
+	status := utils.GetQueryString(r, "status", "")
+	if status != "" {
+		taskStatus := domain.TaskStatus(status)
+		if !slices.Contains(domain.AllowedTaskStatuses, taskStatus) {
+			BadRequestResponse(w, errors.New("invalid status"))
+			return
+		}
+		filter.Status = &taskStatus
+	}
+
+	authorId := utils.GetQueryString(r, "author_id", "")
+	if authorId != "" {
+		parsedAuthorId, err := uuid.Parse(authorId)
+		if err != nil {
+			BadRequestResponse(w, errors.New("invalid author_id"))
+			return
+		}
+		filter.AuthorId = &parsedAuthorId
+	}
+
+	updatedSince := utils.GetQueryString(r, "updated_since", "")
+	if updatedSince != "" {
+		date, err := time.Parse(time.RFC3339, updatedSince)
+		if err != nil {
+			BadRequestResponse(w, errors.New("invalid updated_since date"))
+			return
+		}
+		filter.UpdatedSince = &date
+	}
+
+	paginationParams := utils.PaginationAfterParams{
+		Limit: limit,
+	}
+
+	cursor := utils.GetQueryString(r, "cursor", "")
+	if cursor != "" {
+		paginationParams, err = utils.DecodeAfterCursor(cursor, limit)
+		if err != nil {
+			BadRequestResponse(w, err)
+			return
+		}
+	}
+
 	userId := UserIdFromContext(r.Context())
 
-	tasks, err := h.taskService.List(r.Context(), parsedProjectId, userId)
+	serviceRequest := service.ListTasksRequest{
+		ProjectId: parsedProjectId,
+		UserId:    userId,
+		Filter:    filter,
+		Params:    paginationParams,
+	}
+
+	tasks, err := h.taskService.List(r.Context(), serviceRequest)
 	if err != nil {
 		ErrorResponse(w, r, err)
 		return
 	}
 
-	type response struct {
-		Data []domain.Task `json:"data"`
-	}
-
-	err = utils.WriteJSON(w, http.StatusOK, response{Data: tasks}, nil)
+	err = utils.WriteJSON(w, http.StatusOK, tasks, nil)
 	if err != nil {
 		ErrorResponse(w, r, err)
 		return
@@ -169,3 +259,405 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *TaskHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	comments, err := h.taskService.ListComments(r.Context(), parsedId, userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	type response struct {
+		Data []domain.TaskComment `json:"data"`
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, response{Data: comments}, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	var request TaskCommentRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.CreateTaskCommentRequest{
+		TaskId:        parsedId,
+		Content:       request.Content,
+		RequestUserId: userId,
+	}
+
+	comment, err := h.taskService.CreateComment(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, comment, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	commentId := chi.URLParam(r, "commentId")
+	parsedCommentId, err := uuid.Parse(commentId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid comment id"))
+		return
+	}
+
+	var request TaskCommentRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.UpdateTaskCommentRequest{
+		TaskId:        parsedId,
+		CommentId:     parsedCommentId,
+		Content:       request.Content,
+		RequestUserId: userId,
+	}
+
+	comment, err := h.taskService.UpdateComment(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, comment, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	commentId := chi.URLParam(r, "commentId")
+	parsedCommentId, err := uuid.Parse(commentId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid comment id"))
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.DeleteTaskCommentRequest{
+		TaskId:        parsedId,
+		CommentId:     parsedCommentId,
+		RequestUserId: userId,
+	}
+
+	err = h.taskService.DeleteComment(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) SetParent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	var request SetTaskParentRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.SetTaskParentRequest{
+		TaskId:        parsedId,
+		ParentId:      request.ParentId,
+		RequestUserId: userId,
+	}
+
+	task, err := h.taskService.SetParent(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, task, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) AddBlocker(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	var request AddTaskBlockerRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.TaskBlockerRequest{
+		TaskId:          parsedId,
+		BlockedByTaskId: request.BlockedByTaskId,
+		RequestUserId:   userId,
+	}
+
+	task, err := h.taskService.AddBlocker(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, task, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) RemoveBlocker(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	blockerId := chi.URLParam(r, "blockerId")
+	parsedBlockerId, err := uuid.Parse(blockerId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid blocking task id"))
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.TaskBlockerRequest{
+		TaskId:          parsedId,
+		BlockedByTaskId: parsedBlockerId,
+		RequestUserId:   userId,
+	}
+
+	task, err := h.taskService.RemoveBlocker(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, task, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) CreateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	var request CreateChecklistItemRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.CreateChecklistItemRequest{
+		TaskId:        parsedId,
+		Title:         request.Title,
+		RequestUserId: userId,
+	}
+
+	item, err := h.taskService.CreateChecklistItem(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, item, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	itemId := chi.URLParam(r, "itemId")
+	parsedItemId, err := uuid.Parse(itemId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid checklist item id"))
+		return
+	}
+
+	var request UpdateChecklistItemRequest
+	err = utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.UpdateChecklistItemRequest{
+		TaskId:        parsedId,
+		ItemId:        parsedItemId,
+		Title:         request.Title,
+		Done:          request.Done,
+		RequestUserId: userId,
+	}
+
+	item, err := h.taskService.UpdateChecklistItem(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, item, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	itemId := chi.URLParam(r, "itemId")
+	parsedItemId, err := uuid.Parse(itemId)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid checklist item id"))
+		return
+	}
+
+	userId := UserIdFromContext(r.Context())
+
+	serviceRequest := service.DeleteChecklistItemRequest{
+		TaskId:        parsedId,
+		ItemId:        parsedItemId,
+		RequestUserId: userId,
+	}
+
+	err = h.taskService.DeleteChecklistItem(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
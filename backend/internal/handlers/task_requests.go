@@ -9,13 +9,15 @@ import (
 )
 
 type CreateTaskRequest struct {
-	ProjectId   uuid.UUID `json:"project_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
+	ProjectId   uuid.UUID  `json:"project_id"`
+	ParentId    *uuid.UUID `json:"parent_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
 }
 
 func (r *CreateTaskRequest) Validate(v *validator.Validator) {
 	v.Check("project_id", "project_id is required", r.ProjectId != uuid.Nil)
+	v.Check("parent_id", "parent_id is invalid", r.ParentId == nil || *r.ParentId != uuid.Nil)
 	v.Check("title", "title is required", validator.NotBlank(r.Title))
 	v.Check("description", "description is required", validator.NotBlank(r.Description))
 }
@@ -35,3 +37,44 @@ func (r *UpdateTaskRequest) Validate(v *validator.Validator) {
 	allowedStatuses := domain.AllowedTaskStatuses
 	v.Check("status", "status is invalid", slices.Contains(allowedStatuses, domain.TaskStatus(r.Status)))
 }
+
+type TaskCommentRequest struct {
+	Content string `json:"content"`
+}
+
+func (r *TaskCommentRequest) Validate(v *validator.Validator) {
+	v.Check("content", "content is required", validator.NotBlank(r.Content))
+}
+
+type SetTaskParentRequest struct {
+	ParentId *uuid.UUID `json:"parent_id"`
+}
+
+func (r *SetTaskParentRequest) Validate(v *validator.Validator) {
+	v.Check("parent_id", "parent_id is invalid", r.ParentId == nil || *r.ParentId != uuid.Nil)
+}
+
+type AddTaskBlockerRequest struct {
+	BlockedByTaskId uuid.UUID `json:"blocked_by_task_id"`
+}
+
+func (r *AddTaskBlockerRequest) Validate(v *validator.Validator) {
+	v.Check("blocked_by_task_id", "blocked_by_task_id is required", r.BlockedByTaskId != uuid.Nil)
+}
+
+type CreateChecklistItemRequest struct {
+	Title string `json:"title"`
+}
+
+func (r *CreateChecklistItemRequest) Validate(v *validator.Validator) {
+	v.Check("title", "title is required", validator.NotBlank(r.Title))
+}
+
+type UpdateChecklistItemRequest struct {
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+func (r *UpdateChecklistItemRequest) Validate(v *validator.Validator) {
+	v.Check("title", "title is required", validator.NotBlank(r.Title))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type userDataExportService interface {
+	Request(ctx context.Context, userId uuid.UUID) (*domain.UserDataExport, error)
+	Get(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*domain.UserDataExport, error)
+	Download(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*domain.UserDataExport, error)
+}
+
+type UserDataExportHandler struct {
+	exportService userDataExportService
+}
+
+func NewUserDataExportHandler(exportService userDataExportService) *UserDataExportHandler {
+	return &UserDataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Request answers with 202 as the archive is built in the background, its status can be
+// polled until it is ready.
+func (eh *UserDataExportHandler) Request(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	export, err := eh.exportService.Request(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusAccepted, export, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (eh *UserDataExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid export id"))
+		return
+	}
+
+	export, err := eh.exportService.Get(r.Context(), userId, id)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusOK, export, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
+
+func (eh *UserDataExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid export id"))
+		return
+	}
+
+	export, err := eh.exportService.Download(r.Context(), userId, id)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("project-chat-export-%s.zip", export.CreatedAt.UTC().Format("2006-01-02"))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(export.Archive)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export.Archive)
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/gabrielnakaema/project-chat/internal/service"
 	"github.com/gabrielnakaema/project-chat/internal/utils"
 	"github.com/gabrielnakaema/project-chat/internal/validator"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -22,7 +25,22 @@ type userService interface {
 	Login(context.Context, service.LoginRequest) (*service.LoginResult, error)
 	RefreshToken(context.Context, service.RefreshTokenRequest) (*service.LoginResult, error)
 	GetMe(context.Context, uuid.UUID) (*domain.User, error)
+	UpdateProfile(context.Context, service.UpdateProfileRequest) (*domain.User, error)
+	RequestEmailChange(context.Context, service.RequestEmailChangeRequest) error
+	ConfirmEmailChange(context.Context, service.ConfirmEmailChangeRequest) error
+	ChangePassword(context.Context, service.ChangePasswordRequest) error
+	DeleteAccount(context.Context, service.DeleteAccountRequest) error
 	Logout(context.Context, uuid.UUID, string) error
+	RequestEmailVerification(context.Context, uuid.UUID) error
+	VerifyEmail(context.Context, service.VerifyEmailRequest) error
+	RequestPasswordReset(context.Context, service.RequestPasswordResetRequest) error
+	ResetPassword(context.Context, service.ResetPasswordRequest) error
+	ListSessions(context.Context, uuid.UUID, uuid.UUID) ([]domain.UserSession, error)
+	RevokeSession(context.Context, uuid.UUID, uuid.UUID) error
+	EnrollTotp(context.Context, uuid.UUID) (*service.TotpEnrollment, error)
+	ConfirmTotp(context.Context, uuid.UUID, string) ([]string, error)
+	DisableTotp(context.Context, uuid.UUID, string) error
+	VerifyMfa(context.Context, service.VerifyMfaRequest) (*service.LoginResult, error)
 }
 
 type UserHandler struct {
@@ -72,6 +90,13 @@ type LoginResponse struct {
 	User        domain.User `json:"user"`
 }
 
+// MfaChallengeResponse is returned by login instead of LoginResponse when the user has
+// two-factor authentication enabled.
+type MfaChallengeResponse struct {
+	MfaRequired bool   `json:"mfa_required"`
+	MfaToken    string `json:"mfa_token"`
+}
+
 func loginResultToResponse(result *service.LoginResult) LoginResponse {
 	response := LoginResponse{
 		AccessToken: result.AccessToken,
@@ -98,8 +123,10 @@ func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	serviceRequest := service.LoginRequest{
-		Email:    request.Email,
-		Password: request.Password,
+		Email:     request.Email,
+		Password:  request.Password,
+		UserAgent: r.UserAgent(),
+		IpAddress: clientIp(r),
 	}
 
 	result, err := uh.userService.Login(r.Context(), serviceRequest)
@@ -108,6 +135,11 @@ func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if result.MfaToken != "" {
+		utils.WriteJSON(w, http.StatusOK, MfaChallengeResponse{MfaRequired: true, MfaToken: result.MfaToken}, nil)
+		return
+	}
+
 	refreshToken := result.RefreshToken
 
 	setRefreshTokenCookie(w, refreshToken)
@@ -128,7 +160,9 @@ func (uh *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	serviceRequest := service.RefreshTokenRequest{
-		Token: refreshToken.Value,
+		Token:     refreshToken.Value,
+		UserAgent: r.UserAgent(),
+		IpAddress: clientIp(r),
 	}
 
 	result, err := uh.userService.RefreshToken(r.Context(), serviceRequest)
@@ -175,6 +209,175 @@ func (uh *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, nil, nil)
 }
 
+func (uh *UserHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	sessions, err := uh.userService.ListSessions(r.Context(), userId, SessionIdFromContext(r.Context()))
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, sessions, nil)
+}
+
+func (uh *UserHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	sessionId, err := uuid.Parse(id)
+	if err != nil {
+		BadRequestResponse(w, errors.New("invalid session id"))
+		return
+	}
+
+	err = uh.userService.RevokeSession(r.Context(), userId, sessionId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	if sessionId == SessionIdFromContext(r.Context()) {
+		clearRefreshTokenCookie(w)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
+
+func (uh *UserHandler) VerifyMfa(w http.ResponseWriter, r *http.Request) {
+	var request VerifyMfaRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.VerifyMfaRequest{
+		Token:     request.MfaToken,
+		Code:      request.Code,
+		UserAgent: r.UserAgent(),
+		IpAddress: clientIp(r),
+	}
+
+	result, err := uh.userService.VerifyMfa(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	setRefreshTokenCookie(w, result.RefreshToken)
+
+	utils.WriteJSON(w, http.StatusOK, loginResultToResponse(result), nil)
+}
+
+func (uh *UserHandler) EnrollTotp(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	enrollment, err := uh.userService.EnrollTotp(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, enrollment, nil)
+}
+
+type ConfirmTotpResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (uh *UserHandler) ConfirmTotp(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request TotpCodeRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	recoveryCodes, err := uh.userService.ConfirmTotp(r.Context(), userId, request.Code)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, ConfirmTotpResponse{RecoveryCodes: recoveryCodes}, nil)
+}
+
+func (uh *UserHandler) DisableTotp(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request TotpCodeRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	err = uh.userService.DisableTotp(r.Context(), userId, request.Code)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
+
+// clientIp returns the address of the client without the port, RealIP already replaced it
+// with the forwarded address when the request went through a trusted proxy.
+func clientIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func clearRefreshTokenCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     RefreshTokenCookieName,
@@ -215,3 +418,280 @@ func (uh *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusOK, user, nil)
 }
+
+func (uh *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request UpdateProfileRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.UpdateProfileRequest{
+		UserId:    userId,
+		Name:      request.Name,
+		AvatarUrl: request.AvatarUrl,
+		Timezone:  request.Timezone,
+		Locale:    request.Locale,
+	}
+
+	user, err := uh.userService.UpdateProfile(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, user, nil)
+}
+
+func (uh *UserHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request ChangeEmailRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.RequestEmailChangeRequest{
+		UserId:   userId,
+		Email:    request.Email,
+		Password: request.Password,
+	}
+
+	err = uh.userService.RequestEmailChange(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, nil, nil)
+}
+
+func (uh *UserHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var request ConfirmEmailChangeRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	err = uh.userService.ConfirmEmailChange(r.Context(), service.ConfirmEmailChangeRequest{Token: request.Token})
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
+
+func (uh *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request ChangePasswordRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.ChangePasswordRequest{
+		UserId:          userId,
+		SessionId:       SessionIdFromContext(r.Context()),
+		CurrentPassword: request.CurrentPassword,
+		NewPassword:     request.NewPassword,
+		IpAddress:       clientIp(r),
+	}
+
+	err = uh.userService.ChangePassword(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
+
+// DeleteMe deletes the account of the signed in user, the refresh token cookie is cleared as
+// the session is no longer valid.
+func (uh *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	var request DeleteAccountRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.DeleteAccountRequest{
+		UserId:   userId,
+		Password: request.Password,
+		Code:     request.Code,
+	}
+
+	err = uh.userService.DeleteAccount(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (uh *UserHandler) RequestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	err := uh.userService.RequestEmailVerification(r.Context(), userId)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, nil, nil)
+}
+
+func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var request VerifyEmailRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	err = uh.userService.VerifyEmail(r.Context(), service.VerifyEmailRequest{Token: request.Token})
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
+
+func (uh *UserHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var request PasswordResetRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	err = uh.userService.RequestPasswordReset(r.Context(), service.RequestPasswordResetRequest{Email: request.Email})
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, nil, nil)
+}
+
+func (uh *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request ConfirmPasswordResetRequest
+
+	err := utils.ReadJSON(w, r, &request)
+	if err != nil {
+		BadRequestResponse(w, err)
+		return
+	}
+
+	v := validator.New()
+	request.Validate(v)
+	if !v.Valid() {
+		ValidationFailedResponse(w, v)
+		return
+	}
+
+	serviceRequest := service.ResetPasswordRequest{
+		Token:    request.Token,
+		Password: request.Password,
+	}
+
+	err = uh.userService.ResetPassword(r.Context(), serviceRequest)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	clearRefreshTokenCookie(w)
+	utils.WriteJSON(w, http.StatusOK, nil, nil)
+}
@@ -62,6 +62,96 @@ func (m *mockUserService) Logout(ctx context.Context, userId uuid.UUID, token st
 	return args.Error(0)
 }
 
+func (m *mockUserService) RequestEmailVerification(ctx context.Context, userId uuid.UUID) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *mockUserService) VerifyEmail(ctx context.Context, req service.VerifyEmailRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) RequestPasswordReset(ctx context.Context, req service.RequestPasswordResetRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ResetPassword(ctx context.Context, req service.ResetPasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ListSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) ([]domain.UserSession, error) {
+	args := m.Called(ctx, userId, currentSessionId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.UserSession), args.Error(1)
+}
+
+func (m *mockUserService) RevokeSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	args := m.Called(ctx, userId, sessionId)
+	return args.Error(0)
+}
+
+func (m *mockUserService) EnrollTotp(ctx context.Context, userId uuid.UUID) (*service.TotpEnrollment, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.TotpEnrollment), args.Error(1)
+}
+
+func (m *mockUserService) ConfirmTotp(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	args := m.Called(ctx, userId, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserService) DisableTotp(ctx context.Context, userId uuid.UUID, code string) error {
+	args := m.Called(ctx, userId, code)
+	return args.Error(0)
+}
+
+func (m *mockUserService) UpdateProfile(ctx context.Context, req service.UpdateProfileRequest) (*domain.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserService) RequestEmailChange(ctx context.Context, req service.RequestEmailChangeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ConfirmEmailChange(ctx context.Context, req service.ConfirmEmailChangeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ChangePassword(ctx context.Context, req service.ChangePasswordRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) DeleteAccount(ctx context.Context, req service.DeleteAccountRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) VerifyMfa(ctx context.Context, req service.VerifyMfaRequest) (*service.LoginResult, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.LoginResult), args.Error(1)
+}
+
 func TestUserHandler_Create(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -240,8 +330,9 @@ func TestUserHandler_Login(t *testing.T) {
 			},
 			mockSetup: func(mockService *mockUserService) {
 				expectedRequest := service.LoginRequest{
-					Email:    "john@example.com",
-					Password: "password123",
+					Email:     "john@example.com",
+					Password:  "password123",
+					IpAddress: "192.0.2.1",
 				}
 				mockService.On("Login", mock.Anything, expectedRequest).Return(validLoginResult, nil)
 			},
@@ -289,13 +380,38 @@ func TestUserHandler_Login(t *testing.T) {
 			},
 			mockSetup: func(mockService *mockUserService) {
 				expectedRequest := service.LoginRequest{
-					Email:    "john@example.com",
-					Password: "wrongpassword",
+					Email:     "john@example.com",
+					Password:  "wrongpassword",
+					IpAddress: "192.0.2.1",
 				}
 				mockService.On("Login", mock.Anything, expectedRequest).Return(nil, domain.UnauthorizedError("invalid credentials"))
 			},
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name: "mfa required",
+			requestBody: handlers.LoginRequest{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(mockService *mockUserService) {
+				expectedRequest := service.LoginRequest{
+					Email:     "john@example.com",
+					Password:  "password123",
+					IpAddress: "192.0.2.1",
+				}
+				mockService.On("Login", mock.Anything, expectedRequest).Return(&service.LoginResult{MfaToken: "mfa-token", User: validUser}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var response handlers.MfaChallengeResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.True(t, response.MfaRequired)
+				assert.Equal(t, "mfa-token", response.MfaToken)
+				assert.Empty(t, w.Result().Cookies())
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -358,7 +474,8 @@ func TestUserHandler_Login(t *testing.T) {
 			},
 			mockSetup: func(mockService *mockUserService) {
 				expectedRequest := service.RefreshTokenRequest{
-					Token: "valid-refresh-token",
+					Token:     "valid-refresh-token",
+					IpAddress: "192.0.2.1",
 				}
 				mockService.On("RefreshToken", mock.Anything, expectedRequest).Return(validLoginResult, nil)
 			},
@@ -393,7 +510,8 @@ func TestUserHandler_Login(t *testing.T) {
 			},
 			mockSetup: func(mockService *mockUserService) {
 				expectedRequest := service.RefreshTokenRequest{
-					Token: "invalid-refresh-token",
+					Token:     "invalid-refresh-token",
+					IpAddress: "192.0.2.1",
 				}
 				mockService.On("RefreshToken", mock.Anything, expectedRequest).Return(nil, domain.UnauthorizedError("invalid refresh token"))
 			},
@@ -432,3 +550,168 @@ func TestUserHandler_Login(t *testing.T) {
 	}
 }
 */
+
+func TestUserHandler_UpdateMe(t *testing.T) {
+	userId := uuid.New()
+	name := "Jane Doe"
+	timezone := "America/Sao_Paulo"
+
+	tests := []struct {
+		name           string
+		userId         uuid.UUID
+		requestBody    string
+		mockSetup      func(*mockUserService)
+		expectedStatus int
+	}{
+		{
+			name:        "partial update",
+			userId:      userId,
+			requestBody: `{"name":"Jane Doe","timezone":"America/Sao_Paulo"}`,
+			mockSetup: func(mockService *mockUserService) {
+				mockService.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(request service.UpdateProfileRequest) bool {
+					return request.UserId == userId && *request.Name == name && *request.Timezone == timezone && request.AvatarUrl == nil && request.Locale == nil
+				})).Return(&domain.User{Id: userId, Name: name, Timezone: timezone}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "removing the avatar",
+			userId:      userId,
+			requestBody: `{"avatar_url":""}`,
+			mockSetup: func(mockService *mockUserService) {
+				mockService.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(request service.UpdateProfileRequest) bool {
+					return request.AvatarUrl != nil && *request.AvatarUrl == ""
+				})).Return(&domain.User{Id: userId}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "blank name",
+			userId:         userId,
+			requestBody:    `{"name":""}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "unknown timezone",
+			userId:         userId,
+			requestBody:    `{"timezone":"Mars/Olympus_Mons"}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid locale",
+			userId:         userId,
+			requestBody:    `{"locale":"english please"}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "avatar that is not a web url",
+			userId:         userId,
+			requestBody:    `{"avatar_url":"javascript:alert(1)"}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "anonymous user",
+			userId:         uuid.Nil,
+			requestBody:    `{"name":"Jane Doe"}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockUserService{}
+			tt.mockSetup(mockService)
+
+			handler := handlers.NewUserHandler(mockService)
+
+			req := httptest.NewRequest("PATCH", "/users/me", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), handlers.UserIdContextKey, tt.userId))
+			w := httptest.NewRecorder()
+
+			handler.UpdateMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_DeleteMe(t *testing.T) {
+	userId := uuid.New()
+
+	tests := []struct {
+		name           string
+		userId         uuid.UUID
+		requestBody    string
+		mockSetup      func(*mockUserService)
+		expectedStatus int
+	}{
+		{
+			name:        "successful deletion",
+			userId:      userId,
+			requestBody: `{"password":"password123"}`,
+			mockSetup: func(mockService *mockUserService) {
+				mockService.On("DeleteAccount", mock.Anything, service.DeleteAccountRequest{UserId: userId, Password: "password123"}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "wrong password",
+			userId:      userId,
+			requestBody: `{"password":"wrongpassword"}`,
+			mockSetup: func(mockService *mockUserService) {
+				mockService.On("DeleteAccount", mock.Anything, service.DeleteAccountRequest{UserId: userId, Password: "wrongpassword"}).Return(domain.BusinessValidationError("current password is incorrect"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "missing password",
+			userId:         userId,
+			requestBody:    `{}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "anonymous user",
+			userId:         uuid.Nil,
+			requestBody:    `{"password":"password123"}`,
+			mockSetup:      func(mockService *mockUserService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockUserService{}
+			tt.mockSetup(mockService)
+
+			handler := handlers.NewUserHandler(mockService)
+
+			req := httptest.NewRequest("DELETE", "/users/me", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), handlers.UserIdContextKey, tt.userId))
+			w := httptest.NewRecorder()
+
+			handler.DeleteMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusNoContent {
+				cookies := w.Result().Cookies()
+				if assert.Len(t, cookies, 1) {
+					assert.Equal(t, handlers.RefreshTokenCookieName, cookies[0].Name)
+					assert.Empty(t, cookies[0].Value)
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
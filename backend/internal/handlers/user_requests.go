@@ -1,6 +1,14 @@
 package handlers
 
-import "github.com/gabrielnakaema/project-chat/internal/validator"
+import (
+	"regexp"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/validator"
+)
+
+// localeRegex accepts BCP 47 tags such as "en", "pt-BR" or "zh-Hant-TW".
+var localeRegex = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
 
 type CreateUserRequest struct {
 	Name     string `json:"name"`
@@ -34,3 +42,129 @@ type RefreshTokenRequest struct {
 func (req *RefreshTokenRequest) Validate(v *validator.Validator) {
 	v.Check("refresh_token", "refresh token is required", validator.NotBlank(req.RefreshToken))
 }
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (req *VerifyEmailRequest) Validate(v *validator.Validator) {
+	v.Check("token", "token is required", validator.NotBlank(req.Token))
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+func (req *PasswordResetRequest) Validate(v *validator.Validator) {
+	v.Check("email", "email is required", validator.NotBlank(req.Email))
+	v.Check("email", "email is invalid", validator.ValidEmail(req.Email))
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (req *ConfirmPasswordResetRequest) Validate(v *validator.Validator) {
+	v.Check("token", "token is required", validator.NotBlank(req.Token))
+	v.Check("password", "password is required", validator.NotBlank(req.Password))
+	v.Check("password", "password must be at least 6 characters", validator.MinLength(req.Password, 6))
+}
+
+type VerifyMfaRequest struct {
+	MfaToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+func (req *VerifyMfaRequest) Validate(v *validator.Validator) {
+	v.Check("mfa_token", "mfa token is required", validator.NotBlank(req.MfaToken))
+	v.Check("code", "code is required", validator.NotBlank(req.Code))
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (req *TotpCodeRequest) Validate(v *validator.Validator) {
+	v.Check("code", "code is required", validator.NotBlank(req.Code))
+}
+
+// UpdateProfileRequest is a partial update, only the fields that are sent are changed.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name"`
+	AvatarUrl *string `json:"avatar_url"`
+	Timezone  *string `json:"timezone"`
+	Locale    *string `json:"locale"`
+}
+
+func (req *UpdateProfileRequest) Validate(v *validator.Validator) {
+	if req.Name != nil {
+		v.Check("name", "name is required", validator.NotBlank(*req.Name))
+		v.Check("name", "name must be at most 100 characters", validator.MaxLength(*req.Name, 100))
+	}
+
+	// an empty avatar url removes the avatar
+	if req.AvatarUrl != nil && *req.AvatarUrl != "" {
+		v.Check("avatar_url", "avatar_url must be an http or https url", validator.ValidURL(*req.AvatarUrl))
+		v.Check("avatar_url", "avatar_url must be at most 2048 characters", validator.MaxLength(*req.AvatarUrl, 2048))
+	}
+
+	if req.Timezone != nil {
+		v.Check("timezone", "timezone is required", validator.NotBlank(*req.Timezone))
+		v.Check("timezone", "timezone is invalid", validTimezone(*req.Timezone))
+	}
+
+	if req.Locale != nil {
+		v.Check("locale", "locale is required", validator.NotBlank(*req.Locale))
+		v.Check("locale", "locale is invalid", localeRegex.MatchString(*req.Locale))
+	}
+}
+
+// validTimezone accepts IANA time zone names, time.LoadLocation also accepts "Local" which
+// only means something on the server.
+func validTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (req *ChangeEmailRequest) Validate(v *validator.Validator) {
+	v.Check("email", "email is required", validator.NotBlank(req.Email))
+	v.Check("email", "email is invalid", validator.ValidEmail(req.Email))
+	v.Check("password", "password is required", validator.NotBlank(req.Password))
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+func (req *ConfirmEmailChangeRequest) Validate(v *validator.Validator) {
+	v.Check("token", "token is required", validator.NotBlank(req.Token))
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (req *ChangePasswordRequest) Validate(v *validator.Validator) {
+	v.Check("current_password", "current password is required", validator.NotBlank(req.CurrentPassword))
+	v.Check("new_password", "new password is required", validator.NotBlank(req.NewPassword))
+	v.Check("new_password", "new password must be at least 6 characters", validator.MinLength(req.NewPassword, 6))
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+func (req *DeleteAccountRequest) Validate(v *validator.Validator) {
+	v.Check("password", "password is required", validator.NotBlank(req.Password))
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/gabrielnakaema/project-chat/internal/ws"
+	"github.com/google/uuid"
+)
+
+type ticketIssuer interface {
+	IssueTicket(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, tokenExpiresAt time.Time) (*ws.Ticket, error)
+}
+
+type WebsocketHandler struct {
+	ticketIssuer ticketIssuer
+}
+
+func NewWebsocketHandler(ticketIssuer ticketIssuer) *WebsocketHandler {
+	return &WebsocketHandler{
+		ticketIssuer: ticketIssuer,
+	}
+}
+
+// IssueTicket hands out the single-use ticket the client passes when opening the websocket,
+// the connection is tied to the session of the access token used here.
+func (wh *WebsocketHandler) IssueTicket(w http.ResponseWriter, r *http.Request) {
+	userId := UserIdFromContext(r.Context())
+	if userId == uuid.Nil {
+		UnauthorizedResponse(w, "unauthorized")
+		return
+	}
+
+	ticket, err := wh.ticketIssuer.IssueTicket(r.Context(), userId, SessionIdFromContext(r.Context()), TokenExpiresAtFromContext(r.Context()))
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+
+	err = utils.WriteJSON(w, http.StatusCreated, ticket, nil)
+	if err != nil {
+		ErrorResponse(w, r, err)
+		return
+	}
+}
@@ -12,7 +12,7 @@ import (
 func Init(config *config.Config) *slog.Logger {
 	environment := config.Environment
 	if config.Environment == "" {
-		environment = "development"
+		environment = "production"
 	}
 
 	addSource := false
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// FileMailer writes every email to w in the same format it would be sent over SMTP, it is
+// meant for local development where the emails can be read from a file.
+type FileMailer struct {
+	mutex sync.Mutex
+	w     io.Writer
+	from  string
+}
+
+func NewFileMailer(w io.Writer, from string) *FileMailer {
+	return &FileMailer{
+		w:    w,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, err := m.w.Write(append(formatMessage(m.from, message), '\n'))
+	return err
+}
+
+// LogMailer logs the emails instead of sending them.
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{
+		logger: logger,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	m.logger.InfoContext(ctx, "email sent", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
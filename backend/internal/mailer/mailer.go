@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends transactional emails, SMTPMailer is used in production while FileMailer and
+// LogMailer keep the emails local for development and tests.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New returns the mailer selected by config.Mailer: "smtp", "file" or "log".
+func New(config *config.Config, logger *slog.Logger) (Mailer, error) {
+	switch config.Mailer {
+	case "smtp":
+		return NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom), nil
+	case "file":
+		file, err := os.OpenFile(config.MailerFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return NewFileMailer(file, config.MailFrom), nil
+	case "log", "":
+		return NewLogMailer(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", config.Mailer)
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics live in the default Prometheus registry, it already collects the Go runtime and
+// process metrics. Metrics that do not depend on a component are created with promauto where
+// they are used, the ones reading from a component are added with Register.
+
+// Register adds collectors to the default registry, replacing the collector registered before
+// with the same metrics. Replacing keeps functions that read from a component pointing at the
+// latest one when the component is created again.
+func Register(collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		err := prometheus.Register(collector)
+
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			prometheus.Unregister(alreadyRegistered.ExistingCollector)
+			err = prometheus.Register(collector)
+		}
+
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Handler serves the default registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
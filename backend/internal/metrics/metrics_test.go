@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterReplacesPreviousCollector(t *testing.T) {
+	opts := prometheus.GaugeOpts{Name: "test_replaced_gauge", Help: "Gauge registered twice."}
+
+	Register(prometheus.NewGaugeFunc(opts, func() float64 { return 1 }))
+	Register(prometheus.NewGaugeFunc(opts, func() float64 { return 2 }))
+	defer prometheus.Unregister(prometheus.NewGaugeFunc(opts, func() float64 { return 0 }))
+
+	body := scrape(t)
+
+	assert.Contains(t, body, "test_replaced_gauge 2\n")
+	assert.NotContains(t, body, "test_replaced_gauge 1\n")
+}
+
+func TestHandlerServesRuntimeAndProcessMetrics(t *testing.T) {
+	body := scrape(t)
+
+	assert.Contains(t, body, "# TYPE go_goroutines gauge")
+	assert.Contains(t, body, "# TYPE process_resident_memory_bytes gauge")
+}
+
+func scrape(t *testing.T) string {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, recorder.Code)
+	return recorder.Body.String()
+}
@@ -0,0 +1,126 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/oidc"
+	"github.com/gabrielnakaema/project-chat/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "http://localhost:3000/auth/oidc/callback"
+
+func newProvider(issuer string) *oidc.Provider {
+	return oidc.NewProvider(&config.Config{
+		OidcIssuer:       issuer,
+		OidcClientId:     oidctest.ClientId,
+		OidcClientSecret: oidctest.ClientSecret,
+		OidcRedirectURL:  redirectURL,
+		OidcScopes:       []string{"openid", "email", "profile"},
+	})
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	identity := oidctest.Identity{
+		Subject:       "employee-1",
+		Email:         "employee@example.com",
+		EmailVerified: true,
+		Name:          "Employee",
+	}
+
+	mockProvider := oidctest.NewProvider(identity)
+	defer mockProvider.Close()
+
+	provider := newProvider(mockProvider.Issuer())
+	ctx := context.Background()
+
+	codeVerifier, err := oidc.RandomString()
+	require.NoError(t, err)
+
+	authorizationURL, err := provider.AuthorizationURL(ctx, "state-1", "nonce-1", codeVerifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, redirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, oidc.CodeChallenge(codeVerifier), query.Get("code_challenge"))
+	assert.NotContains(t, authorizationURL, codeVerifier)
+
+	code, state, err := mockProvider.Authorize(authorizationURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	claims, err := provider.Exchange(ctx, code, codeVerifier, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, mockProvider.Issuer(), claims.Issuer)
+	assert.Equal(t, identity.Subject, claims.Subject)
+	assert.Equal(t, identity.Email, claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, identity.Name, claims.Name)
+
+	_, err = provider.Exchange(ctx, code, codeVerifier, "nonce-1")
+	assert.Error(t, err, "codes can only be exchanged once")
+}
+
+func TestProvider_ExchangeFailures(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{Subject: "employee-1", Email: "employee@example.com"})
+	defer mockProvider.Close()
+
+	tests := []struct {
+		name         string
+		codeVerifier string
+		nonce        string
+	}{
+		{
+			name:         "wrong code verifier",
+			codeVerifier: "another-verifier",
+			nonce:        "nonce-1",
+		},
+		{
+			name:         "nonce does not match",
+			codeVerifier: "verifier",
+			nonce:        "another-nonce",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newProvider(mockProvider.Issuer())
+			ctx := context.Background()
+
+			authorizationURL, err := provider.AuthorizationURL(ctx, "state-1", "nonce-1", "verifier")
+			require.NoError(t, err)
+
+			code, _, err := mockProvider.Authorize(authorizationURL)
+			require.NoError(t, err)
+
+			claims, err := provider.Exchange(ctx, code, tt.codeVerifier, tt.nonce)
+			assert.Error(t, err)
+			assert.Nil(t, claims)
+		})
+	}
+}
+
+func TestProvider_NotConfigured(t *testing.T) {
+	provider := newProvider("")
+	assert.False(t, provider.Enabled())
+
+	_, err := provider.AuthorizationURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrNotConfigured)
+}
+
+func TestProvider_UnknownIssuer(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{Subject: "employee-1"})
+	defer mockProvider.Close()
+
+	provider := newProvider(mockProvider.Issuer() + "/tenant")
+
+	_, err := provider.AuthorizationURL(context.Background(), "state", "nonce", "verifier")
+	assert.Error(t, err)
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/gabrielnakaema/project-chat/internal/config"
 	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type Publisher struct {
@@ -47,11 +50,17 @@ func NewPublisher(config *config.Config, logger *slog.Logger) (*Publisher, error
 	return publisher, nil
 }
 
+var publishedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "publisher_messages_total",
+	Help: "Messages the broker acknowledged or rejected, by topic and result.",
+}, []string{"topic", "result"})
+
 func (p *Publisher) handleSuccesses() {
 	defer p.wg.Done()
 	for {
 		select {
 		case success := <-p.producer.Successes():
+			publishedMessages.WithLabelValues(success.Topic, "success").Inc()
 			p.logger.Debug("message sent successfully", "topic", success.Topic, "partition", success.Partition, "offset", success.Offset)
 		case <-p.done:
 			return
@@ -64,6 +73,7 @@ func (p *Publisher) handleErrors() {
 	for {
 		select {
 		case err := <-p.producer.Errors():
+			publishedMessages.WithLabelValues(err.Msg.Topic, "error").Inc()
 			p.logger.Error("producer error", "topic", err.Msg.Topic, "error", err.Err.Error())
 		case <-p.done:
 			return
@@ -86,6 +96,12 @@ func (p *Publisher) Publish(ctx context.Context, topic events.Topic, payload int
 		Value: sarama.ByteEncoder(bytes),
 	}
 
+	if actorId := events.ActorFromContext(ctx); actorId != uuid.Nil {
+		message.Headers = []sarama.RecordHeader{
+			{Key: []byte(events.ActorHeader), Value: []byte(actorId.String())},
+		}
+	}
+
 	select {
 	case p.producer.Input() <- message:
 		return nil
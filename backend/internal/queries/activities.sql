@@ -0,0 +1,24 @@
+-- name: CreateProjectActivity :one
+INSERT INTO
+  project_activities (project_id, actor_id, type, payload)
+VALUES
+  ($1, $2, $3, $4) returning id, created_at;
+
+-- name: ListProjectActivities :many
+select
+	pa.id,
+	pa.project_id,
+	pa.actor_id,
+	pa.type,
+	pa.payload,
+	pa.created_at,
+	u.name as actor_name,
+	pu.id as user_id,
+	pu.name as user_name
+from project_activities pa
+left join users u on u.id = pa.actor_id
+left join users pu on pu.id = (pa.payload->>'user_id')::uuid
+where pa.project_id = $1
+and (pa.created_at, pa.id) < ($2, $3::uuid)
+order by pa.created_at desc, pa.id desc
+limit $4;
@@ -4,11 +4,14 @@ INSERT INTO chats (project_id) VALUES ($1) returning id;
 -- name: CreateChatMember :exec
 INSERT INTO chat_members (user_id, chat_id, last_seen_at, joined_at) VALUES ($1, $2, $3, $4);
 
+-- name: DeleteChatMember :exec
+DELETE FROM chat_members WHERE user_id = $1 AND chat_id = $2;
+
 -- name: UpdateChatMemberLastSeenAt :exec
 UPDATE chat_members SET last_seen_at = $1 WHERE user_id = $2 AND chat_id = $3;
 
 -- name: CreateChatMessage :one
-INSERT INTO chat_messages (chat_id, user_id, content, created_at, updated_at, message_type) VALUES ($1, $2, $3, $4, $5, $6) returning id;
+INSERT INTO chat_messages (chat_id, user_id, content, created_at, updated_at, message_type, actor_id, subject_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) returning id;
 
 -- name: GetChatById :one
 with chat_members_cte as (
@@ -85,9 +88,14 @@ select
 	cm.updated_at,
 	cm.user_id,
 	cm.message_type,
-	u.name as user_name
+	u.name as user_name,
+	u.avatar_url as user_avatar_url,
+	a.name as actor_name,
+	s.name as subject_name
 from chat_messages cm
 left join users u on u.id = cm.user_id
+left join users a on a.id = cm.actor_id
+left join users s on s.id = cm.subject_id
 where cm.chat_id = $1
 and (cm.created_at, cm.id) < ($2, $3::uuid)
 order by cm.created_at desc, cm.id desc
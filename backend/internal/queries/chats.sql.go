@@ -45,7 +45,7 @@ func (q *Queries) CreateChatMember(ctx context.Context, arg CreateChatMemberPara
 }
 
 const createChatMessage = `-- name: CreateChatMessage :one
-INSERT INTO chat_messages (chat_id, user_id, content, created_at, updated_at, message_type) VALUES ($1, $2, $3, $4, $5, $6) returning id
+INSERT INTO chat_messages (chat_id, user_id, content, created_at, updated_at, message_type, actor_id, subject_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) returning id
 `
 
 type CreateChatMessageParams struct {
@@ -55,6 +55,8 @@ type CreateChatMessageParams struct {
 	CreatedAt   pgtype.Timestamptz
 	UpdatedAt   pgtype.Timestamptz
 	MessageType string
+	ActorID     pgtype.UUID
+	SubjectID   pgtype.UUID
 }
 
 func (q *Queries) CreateChatMessage(ctx context.Context, arg CreateChatMessageParams) (uuid.UUID, error) {
@@ -65,12 +67,28 @@ func (q *Queries) CreateChatMessage(ctx context.Context, arg CreateChatMessagePa
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.MessageType,
+		arg.ActorID,
+		arg.SubjectID,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const deleteChatMember = `-- name: DeleteChatMember :exec
+DELETE FROM chat_members WHERE user_id = $1 AND chat_id = $2
+`
+
+type DeleteChatMemberParams struct {
+	UserID uuid.UUID
+	ChatID uuid.UUID
+}
+
+func (q *Queries) DeleteChatMember(ctx context.Context, arg DeleteChatMemberParams) error {
+	_, err := q.db.Exec(ctx, deleteChatMember, arg.UserID, arg.ChatID)
+	return err
+}
+
 const getChatById = `-- name: GetChatById :one
 with chat_members_cte as (
 	select 
@@ -190,9 +208,14 @@ select
 	cm.updated_at,
 	cm.user_id,
 	cm.message_type,
-	u.name as user_name
+	u.name as user_name,
+	u.avatar_url as user_avatar_url,
+	a.name as actor_name,
+	s.name as subject_name
 from chat_messages cm
 left join users u on u.id = cm.user_id
+left join users a on a.id = cm.actor_id
+left join users s on s.id = cm.subject_id
 where cm.chat_id = $1
 and (cm.created_at, cm.id) < ($2, $3::uuid)
 order by cm.created_at desc, cm.id desc
@@ -207,14 +230,17 @@ type ListChatMessagesParams struct {
 }
 
 type ListChatMessagesRow struct {
-	ID          uuid.UUID
-	ChatID      uuid.UUID
-	Content     string
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
-	UserID      pgtype.UUID
-	MessageType string
-	UserName    pgtype.Text
+	ID            uuid.UUID
+	ChatID        uuid.UUID
+	Content       string
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+	UserID        pgtype.UUID
+	MessageType   string
+	UserName      pgtype.Text
+	UserAvatarUrl pgtype.Text
+	ActorName     pgtype.Text
+	SubjectName   pgtype.Text
 }
 
 func (q *Queries) ListChatMessages(ctx context.Context, arg ListChatMessagesParams) ([]ListChatMessagesRow, error) {
@@ -240,6 +266,9 @@ func (q *Queries) ListChatMessages(ctx context.Context, arg ListChatMessagesPara
 			&i.UserID,
 			&i.MessageType,
 			&i.UserName,
+			&i.UserAvatarUrl,
+			&i.ActorName,
+			&i.SubjectName,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,28 @@
+-- name: CreateLoginAttempt :one
+INSERT INTO login_attempts (email, ip_address, user_id, success, failure_reason) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;
+
+-- name: UpdateLoginAttemptResult :exec
+UPDATE login_attempts SET user_id = $1, success = $2, failure_reason = $3 WHERE id = $4;
+
+-- name: LockLoginAttempts :exec
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(key)::text, 0));
+
+-- name: GetLoginFailuresByEmail :one
+SELECT count(*) AS failed_attempts, max(created_at)::timestamptz AS last_failed_at
+FROM login_attempts
+WHERE email = $1
+  AND NOT success
+  AND failure_reason = 'invalid_credentials'
+  AND created_at > $2
+  AND created_at > COALESCE(
+    (SELECT max(successful.created_at) FROM login_attempts AS successful WHERE successful.email = $1 AND successful.success),
+    '-infinity'::timestamptz
+  );
+
+-- name: GetLoginFailuresByIpAddress :one
+SELECT count(*) AS failed_attempts, max(created_at)::timestamptz AS last_failed_at
+FROM login_attempts
+WHERE ip_address = $1
+  AND NOT success
+  AND failure_reason = 'invalid_credentials'
+  AND created_at > $2;
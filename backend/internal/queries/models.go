@@ -33,13 +33,76 @@ type ChatMessage struct {
 	MessageType string
 }
 
+type OidcLoginState struct {
+	ID           uuid.UUID
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    pgtype.Timestamptz
+	UsedAt       pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+}
+
+type Organization struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Personal  bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type OrganizationMember struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	Role           string
+	CreatedAt      pgtype.Timestamptz
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
 type Project struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Name        string
-	Description string
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    string
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	ArchivedAt     pgtype.Timestamptz
+	OrganizationID uuid.UUID
+}
+
+type ProjectActivity struct {
+	ID        uuid.UUID
+	ProjectID uuid.UUID
+	ActorID   pgtype.UUID
+	Type      string
+	Payload   []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+type ProjectInvitation struct {
+	ID         uuid.UUID
+	ProjectID  uuid.UUID
+	Email      string
+	Role       string
+	TokenHash  string
+	InvitedBy  uuid.UUID
+	ExpiresAt  pgtype.Timestamptz
+	AcceptedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
 }
 
 type ProjectMember struct {
@@ -56,6 +119,17 @@ type RefreshToken struct {
 	UserID    uuid.UUID
 	CreatedAt pgtype.Timestamptz
 	ExpiresAt pgtype.Timestamptz
+	SessionID uuid.UUID
+	RotatedAt pgtype.Timestamptz
+}
+
+type ProjectTemplate struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Description string
+	Tasks       []byte
+	CreatedAt   pgtype.Timestamptz
 }
 
 type Task struct {
@@ -67,6 +141,7 @@ type Task struct {
 	CreatedAt   pgtype.Timestamptz
 	UpdatedAt   pgtype.Timestamptz
 	AuthorID    uuid.UUID
+	ParentID    pgtype.UUID
 }
 
 type TaskChange struct {
@@ -77,11 +152,101 @@ type TaskChange struct {
 	CreatedAt   pgtype.Timestamptz
 }
 
+type TaskChecklistItem struct {
+	ID        uuid.UUID
+	TaskID    uuid.UUID
+	Title     string
+	Done      bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type TaskComment struct {
+	ID        uuid.UUID
+	TaskID    uuid.UUID
+	UserID    uuid.UUID
+	Content   string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type TaskDependency struct {
+	TaskID          uuid.UUID
+	BlockedByTaskID uuid.UUID
+	CreatedAt       pgtype.Timestamptz
+}
+
 type User struct {
+	ID              uuid.UUID
+	Name            string
+	Email           string
+	Password        string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	EmailVerifiedAt pgtype.Timestamptz
+	TotpSecret      pgtype.Text
+	TotpEnabledAt   pgtype.Timestamptz
+	TotpLastStep    int64
+	AvatarUrl       string
+	Timezone        string
+	Locale          string
+	DeletedAt       pgtype.Timestamptz
+}
+
+type UserDataExport struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	Archive     []byte
+	ExpiresAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+}
+
+type UserToken struct {
 	ID        uuid.UUID
-	Name      string
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+	Email     pgtype.Text
+}
+
+type LoginAttempt struct {
+	ID            uuid.UUID
+	Email         string
+	IpAddress     string
+	UserID        pgtype.UUID
+	Success       bool
+	FailureReason string
+	CreatedAt     pgtype.Timestamptz
+}
+
+type UserIdentity struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Issuer    string
+	Subject   string
 	Email     string
-	Password  string
 	CreatedAt pgtype.Timestamptz
-	UpdatedAt pgtype.Timestamptz
+}
+
+type UserRecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserSession struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	UserAgent  string
+	IpAddress  string
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
 }
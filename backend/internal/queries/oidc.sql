@@ -0,0 +1,16 @@
+-- name: CreateOidcLoginState :exec
+INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at) VALUES ($1, $2, $3, $4);
+
+-- name: UseOidcLoginState :one
+UPDATE oidc_login_states SET used_at = current_timestamp
+WHERE state_hash = $1 AND used_at IS NULL AND expires_at > current_timestamp
+RETURNING id, state_hash, nonce, code_verifier, expires_at, used_at, created_at;
+
+-- name: GetUserIdentity :one
+SELECT id, user_id, issuer, subject, email, created_at FROM user_identities WHERE issuer = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, issuer, subject, email) VALUES ($1, $2, $3, $4) RETURNING id, created_at;
+
+-- name: DeleteUserIdentities :exec
+DELETE FROM user_identities WHERE user_id = $1;
@@ -0,0 +1,72 @@
+-- name: CreateOrganization :one
+INSERT INTO
+  organizations (user_id, name, personal)
+VALUES
+  ($1, $2, $3) returning id, created_at, updated_at;
+
+-- name: CreateOrganizationMember :one
+INSERT INTO
+  organization_members (organization_id, user_id, role)
+VALUES
+  ($1, $2, $3) returning id, created_at;
+
+-- name: GetOrganizationById :one
+SELECT * FROM organizations
+WHERE id = $1;
+
+-- name: GetPersonalOrganizationByUserId :one
+SELECT * FROM organizations
+WHERE user_id = $1
+  AND personal;
+
+-- name: ListOrganizationsByUserId :many
+SELECT o.* FROM organizations o
+  JOIN organization_members om ON om.organization_id = o.id
+WHERE om.user_id = $1
+ORDER BY o.personal DESC, o.name ASC;
+
+-- name: UpdateOrganization :exec
+UPDATE
+  organizations
+SET
+  name = $1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE
+  id = $2;
+
+-- name: GetOrganizationMember :one
+SELECT * FROM organization_members
+WHERE organization_id = $1
+  AND user_id = $2;
+
+-- name: ListOrganizationMembers :many
+SELECT
+  om.id,
+  om.organization_id,
+  om.user_id,
+  om.role,
+  om.created_at,
+  u.name as user_name,
+  u.email as user_email
+FROM organization_members om
+  JOIN users u ON u.id = om.user_id
+WHERE om.organization_id = sqlc.arg('organization_id')
+  AND (
+    sqlc.narg('search')::text is null
+    or u.name ILIKE sqlc.narg('search')::text
+    or u.email ILIKE sqlc.narg('search')::text
+  )
+ORDER BY u.name ASC, om.id ASC
+LIMIT sqlc.arg('page_limit');
+
+-- name: UpdateOrganizationMemberRole :exec
+UPDATE organization_members
+SET
+  role = $1
+WHERE organization_id = $2
+  AND user_id = $3;
+
+-- name: RemoveOrganizationMember :exec
+DELETE FROM organization_members
+WHERE organization_id = $1
+  AND user_id = $2;
@@ -0,0 +1,20 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;
+
+-- name: GetPersonalAccessTokenByTokenHash :one
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM personal_access_tokens WHERE token_hash = $1;
+
+-- name: ListPersonalAccessTokensByUserId :many
+SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > current_timestamp
+ORDER BY created_at DESC;
+
+-- name: UpdatePersonalAccessTokenLastUsed :exec
+UPDATE personal_access_tokens SET last_used_at = current_timestamp WHERE id = $1;
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens SET revoked_at = current_timestamp WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserPersonalAccessTokens :exec
+UPDATE personal_access_tokens SET revoked_at = current_timestamp WHERE user_id = $1 AND revoked_at IS NULL;
@@ -1,8 +1,8 @@
 -- name: CreateProject :one
 INSERT INTO
-  projects (user_id, name, description)
+  projects (user_id, name, description, organization_id)
 VALUES
-  ($1, $2, $3) returning id;
+  ($1, $2, $3, $4) returning id;
 
 -- name: CreateProjectMember :one
 INSERT INTO
@@ -131,6 +131,14 @@ WHERE
       or role = sqlc.narg('role')::text
     )
   )
+  AND (
+    sqlc.arg('include_archived')::boolean
+    or p.archived_at is null
+  )
+  AND (
+    sqlc.narg('organization_id')::uuid is null
+    or p.organization_id = sqlc.narg('organization_id')::uuid
+  )
 GROUP BY
   p.id;
 
@@ -144,6 +152,16 @@ SET
 WHERE
   id = $3;
 
+-- name: UpdateProjectOwner :exec
+UPDATE
+  projects
+SET
+  user_id = $1,
+  organization_id = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE
+  id = $3;
+
 -- name: RemoveProjectMember :exec
 DELETE FROM project_members
 WHERE user_id = $1
@@ -152,4 +170,140 @@ WHERE user_id = $1
 -- name: GetProjectMemberByUserIdAndProjectId :one
 SELECT * FROM project_members
 WHERE user_id = $1
-  AND project_id = $2;
\ No newline at end of file
+  AND project_id = $2;
+
+-- name: UpdateProjectMemberRole :exec
+UPDATE project_members
+SET
+  role = $1
+WHERE user_id = $2
+  AND project_id = $3;
+
+-- name: CreateProjectInvitation :one
+INSERT INTO
+  project_invitations (project_id, email, role, token_hash, invited_by, expires_at)
+VALUES
+  ($1, $2, $3, $4, $5, $6) returning id, created_at;
+
+-- name: GetProjectInvitationById :one
+SELECT * FROM project_invitations
+WHERE id = $1;
+
+-- name: GetProjectInvitationByTokenHash :one
+SELECT * FROM project_invitations
+WHERE token_hash = $1;
+
+-- name: ListPendingProjectInvitationsByProjectId :many
+SELECT * FROM project_invitations
+WHERE project_id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC;
+
+-- name: ListPendingProjectInvitationsByEmail :many
+SELECT * FROM project_invitations
+WHERE lower(email) = lower(sqlc.arg('email')::text)
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at ASC;
+
+-- name: AcceptProjectInvitation :execrows
+UPDATE project_invitations
+SET
+  accepted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: RevokeProjectInvitation :execrows
+UPDATE project_invitations
+SET
+  revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL;
+
+-- name: UpdateProjectArchivedAt :exec
+UPDATE
+  projects
+SET
+  archived_at = $1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE
+  id = $2;
+
+-- name: DeleteProjectTaskDependencies :exec
+DELETE FROM task_dependencies
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+  OR blocked_by_task_id IN (SELECT id FROM tasks WHERE project_id = $1);
+
+-- name: DeleteProjectTaskChecklistItems :exec
+DELETE FROM task_checklist_items
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1);
+
+-- name: DeleteProjectTaskComments :exec
+DELETE FROM task_comments
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1);
+
+-- name: DeleteProjectTaskChanges :exec
+DELETE FROM task_changes
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1);
+
+-- name: DeleteProjectTasks :exec
+DELETE FROM tasks
+WHERE project_id = $1;
+
+-- name: DeleteProjectActivities :exec
+DELETE FROM project_activities
+WHERE project_id = $1;
+
+-- name: DeleteProjectChatMessages :exec
+DELETE FROM chat_messages
+WHERE chat_id IN (SELECT id FROM chats WHERE project_id = $1);
+
+-- name: DeleteProjectChatMembers :exec
+DELETE FROM chat_members
+WHERE chat_id IN (SELECT id FROM chats WHERE project_id = $1);
+
+-- name: DeleteProjectChats :many
+DELETE FROM chats
+WHERE project_id = $1 returning id;
+
+-- name: DeleteProjectInvitations :exec
+DELETE FROM project_invitations
+WHERE project_id = $1;
+
+-- name: DeleteProjectMembers :exec
+DELETE FROM project_members
+WHERE project_id = $1;
+
+-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1;
+
+-- name: ListProjectTasks :many
+SELECT id, parent_id, title, description, status FROM tasks
+WHERE project_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: CreateProjectTemplate :one
+INSERT INTO
+  project_templates (user_id, name, description, tasks)
+VALUES
+  ($1, $2, $3, $4) returning id, created_at;
+
+-- name: GetProjectTemplateById :one
+SELECT * FROM project_templates
+WHERE id = $1;
+
+-- name: ListProjectTemplatesByUserId :many
+SELECT * FROM project_templates
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteProjectTemplate :exec
+DELETE FROM project_templates
+WHERE id = $1;
@@ -12,26 +12,85 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acceptProjectInvitation = `-- name: AcceptProjectInvitation :execrows
+UPDATE project_invitations
+SET
+  accepted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+`
+
+func (q *Queries) AcceptProjectInvitation(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, acceptProjectInvitation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createProject = `-- name: CreateProject :one
 INSERT INTO
-  projects (user_id, name, description)
+  projects (user_id, name, description, organization_id)
 VALUES
-  ($1, $2, $3) returning id
+  ($1, $2, $3, $4) returning id
 `
 
 type CreateProjectParams struct {
-	UserID      uuid.UUID
-	Name        string
-	Description string
+	UserID         uuid.UUID
+	Name           string
+	Description    string
+	OrganizationID uuid.UUID
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (uuid.UUID, error) {
-	row := q.db.QueryRow(ctx, createProject, arg.UserID, arg.Name, arg.Description)
+	row := q.db.QueryRow(ctx, createProject,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.OrganizationID,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createProjectInvitation = `-- name: CreateProjectInvitation :one
+INSERT INTO
+  project_invitations (project_id, email, role, token_hash, invited_by, expires_at)
+VALUES
+  ($1, $2, $3, $4, $5, $6) returning id, created_at
+`
+
+type CreateProjectInvitationParams struct {
+	ProjectID uuid.UUID
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy uuid.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+type CreateProjectInvitationRow struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateProjectInvitation(ctx context.Context, arg CreateProjectInvitationParams) (CreateProjectInvitationRow, error) {
+	row := q.db.QueryRow(ctx, createProjectInvitation,
+		arg.ProjectID,
+		arg.Email,
+		arg.Role,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i CreateProjectInvitationRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
 const createProjectMember = `-- name: CreateProjectMember :one
 INSERT INTO
   project_members (user_id, project_id, role)
@@ -52,6 +111,183 @@ func (q *Queries) CreateProjectMember(ctx context.Context, arg CreateProjectMemb
 	return id, err
 }
 
+const createProjectTemplate = `-- name: CreateProjectTemplate :one
+INSERT INTO
+  project_templates (user_id, name, description, tasks)
+VALUES
+  ($1, $2, $3, $4) returning id, created_at
+`
+
+type CreateProjectTemplateParams struct {
+	UserID      uuid.UUID
+	Name        string
+	Description string
+	Tasks       []byte
+}
+
+type CreateProjectTemplateRow struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateProjectTemplate(ctx context.Context, arg CreateProjectTemplateParams) (CreateProjectTemplateRow, error) {
+	row := q.db.QueryRow(ctx, createProjectTemplate,
+		arg.UserID,
+		arg.Name,
+		arg.Description,
+		arg.Tasks,
+	)
+	var i CreateProjectTemplateRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const deleteProject = `-- name: DeleteProject :exec
+DELETE FROM projects
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProject(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProject, id)
+	return err
+}
+
+const deleteProjectActivities = `-- name: DeleteProjectActivities :exec
+DELETE FROM project_activities
+WHERE project_id = $1
+`
+
+func (q *Queries) DeleteProjectActivities(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectActivities, projectID)
+	return err
+}
+
+const deleteProjectChatMembers = `-- name: DeleteProjectChatMembers :exec
+DELETE FROM chat_members
+WHERE chat_id IN (SELECT id FROM chats WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectChatMembers(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectChatMembers, projectID)
+	return err
+}
+
+const deleteProjectChatMessages = `-- name: DeleteProjectChatMessages :exec
+DELETE FROM chat_messages
+WHERE chat_id IN (SELECT id FROM chats WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectChatMessages(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectChatMessages, projectID)
+	return err
+}
+
+const deleteProjectChats = `-- name: DeleteProjectChats :many
+DELETE FROM chats
+WHERE project_id = $1 returning id
+`
+
+func (q *Queries) DeleteProjectChats(ctx context.Context, projectID pgtype.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, deleteProjectChats, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteProjectInvitations = `-- name: DeleteProjectInvitations :exec
+DELETE FROM project_invitations
+WHERE project_id = $1
+`
+
+func (q *Queries) DeleteProjectInvitations(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectInvitations, projectID)
+	return err
+}
+
+const deleteProjectMembers = `-- name: DeleteProjectMembers :exec
+DELETE FROM project_members
+WHERE project_id = $1
+`
+
+func (q *Queries) DeleteProjectMembers(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectMembers, projectID)
+	return err
+}
+
+const deleteProjectTaskChanges = `-- name: DeleteProjectTaskChanges :exec
+DELETE FROM task_changes
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectTaskChanges(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTaskChanges, projectID)
+	return err
+}
+
+const deleteProjectTaskChecklistItems = `-- name: DeleteProjectTaskChecklistItems :exec
+DELETE FROM task_checklist_items
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectTaskChecklistItems(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTaskChecklistItems, projectID)
+	return err
+}
+
+const deleteProjectTaskComments = `-- name: DeleteProjectTaskComments :exec
+DELETE FROM task_comments
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectTaskComments(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTaskComments, projectID)
+	return err
+}
+
+const deleteProjectTaskDependencies = `-- name: DeleteProjectTaskDependencies :exec
+DELETE FROM task_dependencies
+WHERE task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+  OR blocked_by_task_id IN (SELECT id FROM tasks WHERE project_id = $1)
+`
+
+func (q *Queries) DeleteProjectTaskDependencies(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTaskDependencies, projectID)
+	return err
+}
+
+const deleteProjectTasks = `-- name: DeleteProjectTasks :exec
+DELETE FROM tasks
+WHERE project_id = $1
+`
+
+func (q *Queries) DeleteProjectTasks(ctx context.Context, projectID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTasks, projectID)
+	return err
+}
+
+const deleteProjectTemplate = `-- name: DeleteProjectTemplate :exec
+DELETE FROM project_templates
+WHERE id = $1
+`
+
+func (q *Queries) DeleteProjectTemplate(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProjectTemplate, id)
+	return err
+}
+
 const getProjectById = `-- name: GetProjectById :one
 WITH project_members_cte AS (
   SELECT
@@ -71,7 +307,7 @@ WITH project_members_cte AS (
     pm.project_id = $1
 )
 SELECT
-  p.id, p.user_id, p.name, p.description, p.created_at, p.updated_at,
+  p.id, p.user_id, p.name, p.description, p.created_at, p.updated_at, p.archived_at, p.organization_id,
   coalesce(
     jsonb_agg(
       jsonb_build_object(
@@ -113,13 +349,15 @@ GROUP BY
 `
 
 type GetProjectByIdRow struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Name        string
-	Description string
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
-	Members     interface{}
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    string
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	ArchivedAt     pgtype.Timestamptz
+	OrganizationID uuid.UUID
+	Members        interface{}
 }
 
 func (q *Queries) GetProjectById(ctx context.Context, id uuid.UUID) (GetProjectByIdRow, error) {
@@ -132,11 +370,59 @@ func (q *Queries) GetProjectById(ctx context.Context, id uuid.UUID) (GetProjectB
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ArchivedAt,
+		&i.OrganizationID,
 		&i.Members,
 	)
 	return i, err
 }
 
+const getProjectInvitationById = `-- name: GetProjectInvitationById :one
+SELECT id, project_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM project_invitations
+WHERE id = $1
+`
+
+func (q *Queries) GetProjectInvitationById(ctx context.Context, id uuid.UUID) (ProjectInvitation, error) {
+	row := q.db.QueryRow(ctx, getProjectInvitationById, id)
+	var i ProjectInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProjectInvitationByTokenHash = `-- name: GetProjectInvitationByTokenHash :one
+SELECT id, project_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM project_invitations
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetProjectInvitationByTokenHash(ctx context.Context, tokenHash string) (ProjectInvitation, error) {
+	row := q.db.QueryRow(ctx, getProjectInvitationByTokenHash, tokenHash)
+	var i ProjectInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getProjectMemberByUserIdAndProjectId = `-- name: GetProjectMemberByUserIdAndProjectId :one
 SELECT id, user_id, project_id, role FROM project_members
 WHERE user_id = $1
@@ -160,6 +446,105 @@ func (q *Queries) GetProjectMemberByUserIdAndProjectId(ctx context.Context, arg
 	return i, err
 }
 
+const getProjectTemplateById = `-- name: GetProjectTemplateById :one
+SELECT id, user_id, name, description, tasks, created_at FROM project_templates
+WHERE id = $1
+`
+
+func (q *Queries) GetProjectTemplateById(ctx context.Context, id uuid.UUID) (ProjectTemplate, error) {
+	row := q.db.QueryRow(ctx, getProjectTemplateById, id)
+	var i ProjectTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Description,
+		&i.Tasks,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPendingProjectInvitationsByEmail = `-- name: ListPendingProjectInvitationsByEmail :many
+SELECT id, project_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM project_invitations
+WHERE lower(email) = lower($1::text)
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListPendingProjectInvitationsByEmail(ctx context.Context, email string) ([]ProjectInvitation, error) {
+	rows, err := q.db.Query(ctx, listPendingProjectInvitationsByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectInvitation
+	for rows.Next() {
+		var i ProjectInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Email,
+			&i.Role,
+			&i.TokenHash,
+			&i.InvitedBy,
+			&i.ExpiresAt,
+			&i.AcceptedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingProjectInvitationsByProjectId = `-- name: ListPendingProjectInvitationsByProjectId :many
+SELECT id, project_id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM project_invitations
+WHERE project_id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPendingProjectInvitationsByProjectId(ctx context.Context, projectID uuid.UUID) ([]ProjectInvitation, error) {
+	rows, err := q.db.Query(ctx, listPendingProjectInvitationsByProjectId, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectInvitation
+	for rows.Next() {
+		var i ProjectInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Email,
+			&i.Role,
+			&i.TokenHash,
+			&i.InvitedBy,
+			&i.ExpiresAt,
+			&i.AcceptedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjectsByUserId = `-- name: ListProjectsByUserId :many
 WITH project_members_cte AS (
   SELECT
@@ -177,7 +562,7 @@ WITH project_members_cte AS (
     JOIN users u ON u.id = pm.user_id
 )
 SELECT
-  p.id, p.user_id, p.name, p.description, p.created_at, p.updated_at,
+  p.id, p.user_id, p.name, p.description, p.created_at, p.updated_at, p.archived_at, p.organization_id,
   coalesce(
     jsonb_agg(
       jsonb_build_object(
@@ -222,27 +607,44 @@ WHERE
       or role = $2::text
     )
   )
+  AND (
+    $3::boolean
+    or p.archived_at is null
+  )
+  AND (
+    $4::uuid is null
+    or p.organization_id = $4::uuid
+  )
 GROUP BY
   p.id
 `
 
 type ListProjectsByUserIdParams struct {
-	UserID uuid.UUID
-	Role   pgtype.Text
+	UserID          uuid.UUID
+	Role            pgtype.Text
+	IncludeArchived bool
+	OrganizationID  pgtype.UUID
 }
 
 type ListProjectsByUserIdRow struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	Name        string
-	Description string
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
-	Members     interface{}
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Description    string
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	ArchivedAt     pgtype.Timestamptz
+	OrganizationID uuid.UUID
+	Members        interface{}
 }
 
 func (q *Queries) ListProjectsByUserId(ctx context.Context, arg ListProjectsByUserIdParams) ([]ListProjectsByUserIdRow, error) {
-	rows, err := q.db.Query(ctx, listProjectsByUserId, arg.UserID, arg.Role)
+	rows, err := q.db.Query(ctx, listProjectsByUserId,
+		arg.UserID,
+		arg.Role,
+		arg.IncludeArchived,
+		arg.OrganizationID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +659,8 @@ func (q *Queries) ListProjectsByUserId(ctx context.Context, arg ListProjectsByUs
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ArchivedAt,
+			&i.OrganizationID,
 			&i.Members,
 		); err != nil {
 			return nil, err
@@ -269,6 +673,79 @@ func (q *Queries) ListProjectsByUserId(ctx context.Context, arg ListProjectsByUs
 	return items, nil
 }
 
+const listProjectTasks = `-- name: ListProjectTasks :many
+SELECT id, parent_id, title, description, status FROM tasks
+WHERE project_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+type ListProjectTasksRow struct {
+	ID          uuid.UUID
+	ParentID    pgtype.UUID
+	Title       string
+	Description string
+	Status      string
+}
+
+func (q *Queries) ListProjectTasks(ctx context.Context, projectID uuid.UUID) ([]ListProjectTasksRow, error) {
+	rows, err := q.db.Query(ctx, listProjectTasks, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProjectTasksRow
+	for rows.Next() {
+		var i ListProjectTasksRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectTemplatesByUserId = `-- name: ListProjectTemplatesByUserId :many
+SELECT id, user_id, name, description, tasks, created_at FROM project_templates
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListProjectTemplatesByUserId(ctx context.Context, userID uuid.UUID) ([]ProjectTemplate, error) {
+	rows, err := q.db.Query(ctx, listProjectTemplatesByUserId, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectTemplate
+	for rows.Next() {
+		var i ProjectTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Description,
+			&i.Tasks,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removeProjectMember = `-- name: RemoveProjectMember :exec
 DELETE FROM project_members
 WHERE user_id = $1
@@ -285,6 +762,23 @@ func (q *Queries) RemoveProjectMember(ctx context.Context, arg RemoveProjectMemb
 	return err
 }
 
+const revokeProjectInvitation = `-- name: RevokeProjectInvitation :execrows
+UPDATE project_invitations
+SET
+  revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeProjectInvitation(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeProjectInvitation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateProject = `-- name: UpdateProject :exec
 UPDATE
   projects
@@ -306,3 +800,64 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) er
 	_, err := q.db.Exec(ctx, updateProject, arg.Name, arg.Description, arg.ID)
 	return err
 }
+
+const updateProjectArchivedAt = `-- name: UpdateProjectArchivedAt :exec
+UPDATE
+  projects
+SET
+  archived_at = $1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE
+  id = $2
+`
+
+type UpdateProjectArchivedAtParams struct {
+	ArchivedAt pgtype.Timestamptz
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateProjectArchivedAt(ctx context.Context, arg UpdateProjectArchivedAtParams) error {
+	_, err := q.db.Exec(ctx, updateProjectArchivedAt, arg.ArchivedAt, arg.ID)
+	return err
+}
+
+const updateProjectMemberRole = `-- name: UpdateProjectMemberRole :exec
+UPDATE project_members
+SET
+  role = $1
+WHERE user_id = $2
+  AND project_id = $3
+`
+
+type UpdateProjectMemberRoleParams struct {
+	Role      string
+	UserID    uuid.UUID
+	ProjectID uuid.UUID
+}
+
+func (q *Queries) UpdateProjectMemberRole(ctx context.Context, arg UpdateProjectMemberRoleParams) error {
+	_, err := q.db.Exec(ctx, updateProjectMemberRole, arg.Role, arg.UserID, arg.ProjectID)
+	return err
+}
+
+const updateProjectOwner = `-- name: UpdateProjectOwner :exec
+UPDATE
+  projects
+SET
+  user_id = $1,
+  organization_id = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE
+  id = $3
+`
+
+type UpdateProjectOwnerParams struct {
+	UserID         uuid.UUID
+	OrganizationID uuid.UUID
+	ID             uuid.UUID
+}
+
+func (q *Queries) UpdateProjectOwner(ctx context.Context, arg UpdateProjectOwnerParams) error {
+	_, err := q.db.Exec(ctx, updateProjectOwner, arg.UserID, arg.OrganizationID, arg.ID)
+	return err
+}
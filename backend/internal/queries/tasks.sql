@@ -1,5 +1,5 @@
 -- name: CreateTask :one
-INSERT INTO tasks (project_id, title, description, status, author_id) VALUES ($1, $2, $3, $4, $5) returning id;
+INSERT INTO tasks (project_id, title, description, status, author_id, parent_id) VALUES ($1, $2, $3, $4, $5, $6) returning id;
 
 -- name: GetTaskById :one
 WITH task_changes_cte AS (
@@ -7,7 +7,7 @@ WITH task_changes_cte AS (
     tc.id as task_change_id,
     tc.task_id as task_change_task_id,
     tc.user_id as task_change_user_id,
-    tc.description as task_change_description,
+    tc.description || ' by ' || a.name as task_change_description,
     tc.created_at as task_change_created_at,
     a.id as task_change_author_id,
     a.name as task_change_author_name,
@@ -27,9 +27,11 @@ SELECT
   t.created_at as task_created_at,
   t.updated_at as task_updated_at,
   t.author_id as task_author_id,
+  t.parent_id as task_parent_id,
   a.name as task_author_name,
   a.email as task_author_email,
   a.created_at as task_author_created_at,
+  a.avatar_url as task_author_avatar_url,
   coalesce(jsonb_agg(
     jsonb_build_object(
       'id',
@@ -59,19 +61,129 @@ FROM tasks t
 LEFT JOIN task_changes_cte tc ON tc.task_change_task_id = t.id
 LEFT JOIN users a ON a.id = t.author_id
 WHERE t.id = $1
-GROUP BY t.id, a.name, a.email, a.created_at;
+GROUP BY t.id, a.name, a.email, a.created_at, a.avatar_url;
 
 -- name: ListTasksByProjectId :many
 SELECT 
   t.*,
   a.id as author_author_id,
-  a.name as author_name
+  a.name as author_name,
+  a.avatar_url as author_avatar_url,
+  (SELECT count(*) FROM task_comments tc WHERE tc.task_id = t.id) as comment_count
 FROM tasks t
 LEFT JOIN users a ON a.id = t.author_id
-WHERE project_id = $1;
+WHERE t.project_id = sqlc.arg(project_id)
+AND (sqlc.narg(status)::text IS NULL OR t.status = sqlc.narg(status)::text)
+AND (sqlc.narg(author_id)::uuid IS NULL OR t.author_id = sqlc.narg(author_id)::uuid)
+AND (sqlc.narg(updated_since)::timestamptz IS NULL OR t.updated_at >= sqlc.narg(updated_since)::timestamptz)
+AND (sqlc.narg(search)::text IS NULL OR t.title ILIKE sqlc.narg(search)::text OR t.description ILIKE sqlc.narg(search)::text)
+AND (
+  sqlc.narg(after)::timestamptz IS NULL
+  OR (
+    sqlc.arg(sort_desc)::boolean
+    AND (CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' THEN t.updated_at ELSE t.created_at END, t.id) < (sqlc.narg(after)::timestamptz, sqlc.narg(after_id)::uuid)
+  )
+  OR (
+    NOT sqlc.arg(sort_desc)::boolean
+    AND (CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' THEN t.updated_at ELSE t.created_at END, t.id) > (sqlc.narg(after)::timestamptz, sqlc.narg(after_id)::uuid)
+  )
+)
+ORDER BY
+  CASE WHEN NOT sqlc.arg(sort_desc)::boolean THEN CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' THEN t.updated_at ELSE t.created_at END END ASC,
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN CASE WHEN sqlc.arg(sort_by)::text = 'updated_at' THEN t.updated_at ELSE t.created_at END END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::boolean THEN t.id END ASC,
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN t.id END DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: UpdateTask :exec
 UPDATE tasks SET title = $1, description = $2, status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4;
 
 -- name: CreateTaskChange :one
 INSERT INTO task_changes (task_id, user_id, description) VALUES ($1, $2, $3) returning id;
+
+
+-- name: CreateTaskComment :one
+INSERT INTO task_comments (task_id, user_id, content) VALUES ($1, $2, $3) returning id;
+
+-- name: GetTaskCommentById :one
+SELECT
+  tc.*,
+  t.project_id,
+  u.name as author_name,
+  u.avatar_url as author_avatar_url
+FROM task_comments tc
+JOIN tasks t ON t.id = tc.task_id
+LEFT JOIN users u ON u.id = tc.user_id
+WHERE tc.id = $1;
+
+-- name: ListTaskCommentsByTaskId :many
+SELECT
+  tc.*,
+  t.project_id,
+  u.name as author_name,
+  u.avatar_url as author_avatar_url
+FROM task_comments tc
+JOIN tasks t ON t.id = tc.task_id
+LEFT JOIN users u ON u.id = tc.user_id
+WHERE tc.task_id = $1
+ORDER BY tc.created_at ASC, tc.id ASC;
+
+-- name: UpdateTaskComment :exec
+UPDATE task_comments SET content = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: DeleteTaskComment :exec
+DELETE FROM task_comments WHERE id = $1;
+
+-- name: UpdateTaskParent :exec
+UPDATE tasks SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: ListSubtasksByParentId :many
+SELECT * FROM tasks WHERE parent_id = $1 ORDER BY created_at ASC, id ASC;
+
+-- name: ListTaskAncestorIds :many
+WITH RECURSIVE ancestors AS (
+  SELECT t.id, t.parent_id FROM tasks t WHERE t.id = $1
+  UNION
+  SELECT p.id, p.parent_id FROM tasks p
+  JOIN ancestors a ON p.id = a.parent_id
+)
+SELECT id FROM ancestors;
+
+-- name: CreateTaskChecklistItem :one
+INSERT INTO task_checklist_items (task_id, title, done) VALUES ($1, $2, $3) returning id;
+
+-- name: GetTaskChecklistItemById :one
+SELECT * FROM task_checklist_items WHERE id = $1;
+
+-- name: ListTaskChecklistItemsByTaskId :many
+SELECT * FROM task_checklist_items WHERE task_id = $1 ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateTaskChecklistItem :exec
+UPDATE task_checklist_items SET title = $1, done = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;
+
+-- name: DeleteTaskChecklistItem :exec
+DELETE FROM task_checklist_items WHERE id = $1;
+
+-- name: LockProjectTaskDependencies :exec
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(project_id)::text, 0));
+
+-- name: CreateTaskDependency :execrows
+INSERT INTO task_dependencies (task_id, blocked_by_task_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: DeleteTaskDependency :exec
+DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_task_id = $2;
+
+-- name: ListTaskBlockers :many
+SELECT t.* FROM tasks t
+JOIN task_dependencies td ON td.blocked_by_task_id = t.id
+WHERE td.task_id = $1
+ORDER BY t.created_at ASC, t.id ASC;
+
+-- name: ListTaskTransitiveBlockerIds :many
+WITH RECURSIVE blockers AS (
+  SELECT t.id FROM tasks t WHERE t.id = $1
+  UNION
+  SELECT td.blocked_by_task_id FROM task_dependencies td
+  JOIN blockers b ON td.task_id = b.id
+)
+SELECT id FROM blockers;
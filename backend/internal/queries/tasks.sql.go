@@ -13,7 +13,7 @@ import (
 )
 
 const createTask = `-- name: CreateTask :one
-INSERT INTO tasks (project_id, title, description, status, author_id) VALUES ($1, $2, $3, $4, $5) returning id
+INSERT INTO tasks (project_id, title, description, status, author_id, parent_id) VALUES ($1, $2, $3, $4, $5, $6) returning id
 `
 
 type CreateTaskParams struct {
@@ -22,6 +22,7 @@ type CreateTaskParams struct {
 	Description string
 	Status      string
 	AuthorID    uuid.UUID
+	ParentID    pgtype.UUID
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (uuid.UUID, error) {
@@ -31,6 +32,7 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (uuid.UU
 		arg.Description,
 		arg.Status,
 		arg.AuthorID,
+		arg.ParentID,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
@@ -54,13 +56,96 @@ func (q *Queries) CreateTaskChange(ctx context.Context, arg CreateTaskChangePara
 	return id, err
 }
 
+const createTaskChecklistItem = `-- name: CreateTaskChecklistItem :one
+INSERT INTO task_checklist_items (task_id, title, done) VALUES ($1, $2, $3) returning id
+`
+
+type CreateTaskChecklistItemParams struct {
+	TaskID uuid.UUID
+	Title  string
+	Done   bool
+}
+
+func (q *Queries) CreateTaskChecklistItem(ctx context.Context, arg CreateTaskChecklistItemParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createTaskChecklistItem, arg.TaskID, arg.Title, arg.Done)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTaskComment = `-- name: CreateTaskComment :one
+INSERT INTO task_comments (task_id, user_id, content) VALUES ($1, $2, $3) returning id
+`
+
+type CreateTaskCommentParams struct {
+	TaskID  uuid.UUID
+	UserID  uuid.UUID
+	Content string
+}
+
+func (q *Queries) CreateTaskComment(ctx context.Context, arg CreateTaskCommentParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createTaskComment, arg.TaskID, arg.UserID, arg.Content)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTaskDependency = `-- name: CreateTaskDependency :execrows
+INSERT INTO task_dependencies (task_id, blocked_by_task_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type CreateTaskDependencyParams struct {
+	TaskID          uuid.UUID
+	BlockedByTaskID uuid.UUID
+}
+
+func (q *Queries) CreateTaskDependency(ctx context.Context, arg CreateTaskDependencyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createTaskDependency, arg.TaskID, arg.BlockedByTaskID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTaskChecklistItem = `-- name: DeleteTaskChecklistItem :exec
+DELETE FROM task_checklist_items WHERE id = $1
+`
+
+func (q *Queries) DeleteTaskChecklistItem(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTaskChecklistItem, id)
+	return err
+}
+
+const deleteTaskComment = `-- name: DeleteTaskComment :exec
+DELETE FROM task_comments WHERE id = $1
+`
+
+func (q *Queries) DeleteTaskComment(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTaskComment, id)
+	return err
+}
+
+const deleteTaskDependency = `-- name: DeleteTaskDependency :exec
+DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_task_id = $2
+`
+
+type DeleteTaskDependencyParams struct {
+	TaskID          uuid.UUID
+	BlockedByTaskID uuid.UUID
+}
+
+func (q *Queries) DeleteTaskDependency(ctx context.Context, arg DeleteTaskDependencyParams) error {
+	_, err := q.db.Exec(ctx, deleteTaskDependency, arg.TaskID, arg.BlockedByTaskID)
+	return err
+}
+
 const getTaskById = `-- name: GetTaskById :one
 WITH task_changes_cte AS (
   SELECT 
     tc.id as task_change_id,
     tc.task_id as task_change_task_id,
     tc.user_id as task_change_user_id,
-    tc.description as task_change_description,
+    tc.description || ' by ' || a.name as task_change_description,
     tc.created_at as task_change_created_at,
     a.id as task_change_author_id,
     a.name as task_change_author_name,
@@ -80,9 +165,11 @@ SELECT
   t.created_at as task_created_at,
   t.updated_at as task_updated_at,
   t.author_id as task_author_id,
+  t.parent_id as task_parent_id,
   a.name as task_author_name,
   a.email as task_author_email,
   a.created_at as task_author_created_at,
+  a.avatar_url as task_author_avatar_url,
   coalesce(jsonb_agg(
     jsonb_build_object(
       'id',
@@ -112,7 +199,7 @@ FROM tasks t
 LEFT JOIN task_changes_cte tc ON tc.task_change_task_id = t.id
 LEFT JOIN users a ON a.id = t.author_id
 WHERE t.id = $1
-GROUP BY t.id, a.name, a.email, a.created_at
+GROUP BY t.id, a.name, a.email, a.created_at, a.avatar_url
 `
 
 type GetTaskByIdRow struct {
@@ -124,9 +211,11 @@ type GetTaskByIdRow struct {
 	TaskCreatedAt       pgtype.Timestamptz
 	TaskUpdatedAt       pgtype.Timestamptz
 	TaskAuthorID        uuid.UUID
+	TaskParentID        pgtype.UUID
 	TaskAuthorName      pgtype.Text
 	TaskAuthorEmail     pgtype.Text
 	TaskAuthorCreatedAt pgtype.Timestamptz
+	TaskAuthorAvatarUrl pgtype.Text
 	TaskChanges         interface{}
 }
 
@@ -142,39 +231,367 @@ func (q *Queries) GetTaskById(ctx context.Context, id uuid.UUID) (GetTaskByIdRow
 		&i.TaskCreatedAt,
 		&i.TaskUpdatedAt,
 		&i.TaskAuthorID,
+		&i.TaskParentID,
 		&i.TaskAuthorName,
 		&i.TaskAuthorEmail,
 		&i.TaskAuthorCreatedAt,
+		&i.TaskAuthorAvatarUrl,
 		&i.TaskChanges,
 	)
 	return i, err
 }
 
+const getTaskChecklistItemById = `-- name: GetTaskChecklistItemById :one
+SELECT id, task_id, title, done, created_at, updated_at FROM task_checklist_items WHERE id = $1
+`
+
+func (q *Queries) GetTaskChecklistItemById(ctx context.Context, id uuid.UUID) (TaskChecklistItem, error) {
+	row := q.db.QueryRow(ctx, getTaskChecklistItemById, id)
+	var i TaskChecklistItem
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.Title,
+		&i.Done,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTaskCommentById = `-- name: GetTaskCommentById :one
+SELECT
+  tc.id, tc.task_id, tc.user_id, tc.content, tc.created_at, tc.updated_at,
+  t.project_id,
+  u.name as author_name,
+  u.avatar_url as author_avatar_url
+FROM task_comments tc
+JOIN tasks t ON t.id = tc.task_id
+LEFT JOIN users u ON u.id = tc.user_id
+WHERE tc.id = $1
+`
+
+type GetTaskCommentByIdRow struct {
+	ID              uuid.UUID
+	TaskID          uuid.UUID
+	UserID          uuid.UUID
+	Content         string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	ProjectID       uuid.UUID
+	AuthorName      pgtype.Text
+	AuthorAvatarUrl pgtype.Text
+}
+
+func (q *Queries) GetTaskCommentById(ctx context.Context, id uuid.UUID) (GetTaskCommentByIdRow, error) {
+	row := q.db.QueryRow(ctx, getTaskCommentById, id)
+	var i GetTaskCommentByIdRow
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UserID,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectID,
+		&i.AuthorName,
+		&i.AuthorAvatarUrl,
+	)
+	return i, err
+}
+
+const listSubtasksByParentId = `-- name: ListSubtasksByParentId :many
+SELECT id, project_id, title, description, status, created_at, updated_at, author_id, parent_id FROM tasks WHERE parent_id = $1 ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListSubtasksByParentId(ctx context.Context, parentID pgtype.UUID) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listSubtasksByParentId, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskAncestorIds = `-- name: ListTaskAncestorIds :many
+WITH RECURSIVE ancestors AS (
+  SELECT t.id, t.parent_id FROM tasks t WHERE t.id = $1
+  UNION
+  SELECT p.id, p.parent_id FROM tasks p
+  JOIN ancestors a ON p.id = a.parent_id
+)
+SELECT id FROM ancestors
+`
+
+func (q *Queries) ListTaskAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listTaskAncestorIds, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskBlockers = `-- name: ListTaskBlockers :many
+SELECT t.id, t.project_id, t.title, t.description, t.status, t.created_at, t.updated_at, t.author_id, t.parent_id FROM tasks t
+JOIN task_dependencies td ON td.blocked_by_task_id = t.id
+WHERE td.task_id = $1
+ORDER BY t.created_at ASC, t.id ASC
+`
+
+func (q *Queries) ListTaskBlockers(ctx context.Context, taskID uuid.UUID) ([]Task, error) {
+	rows, err := q.db.Query(ctx, listTaskBlockers, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorID,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskChecklistItemsByTaskId = `-- name: ListTaskChecklistItemsByTaskId :many
+SELECT id, task_id, title, done, created_at, updated_at FROM task_checklist_items WHERE task_id = $1 ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListTaskChecklistItemsByTaskId(ctx context.Context, taskID uuid.UUID) ([]TaskChecklistItem, error) {
+	rows, err := q.db.Query(ctx, listTaskChecklistItemsByTaskId, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaskChecklistItem
+	for rows.Next() {
+		var i TaskChecklistItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.Title,
+			&i.Done,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskCommentsByTaskId = `-- name: ListTaskCommentsByTaskId :many
+SELECT
+  tc.id, tc.task_id, tc.user_id, tc.content, tc.created_at, tc.updated_at,
+  t.project_id,
+  u.name as author_name,
+  u.avatar_url as author_avatar_url
+FROM task_comments tc
+JOIN tasks t ON t.id = tc.task_id
+LEFT JOIN users u ON u.id = tc.user_id
+WHERE tc.task_id = $1
+ORDER BY tc.created_at ASC, tc.id ASC
+`
+
+type ListTaskCommentsByTaskIdRow struct {
+	ID              uuid.UUID
+	TaskID          uuid.UUID
+	UserID          uuid.UUID
+	Content         string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	ProjectID       uuid.UUID
+	AuthorName      pgtype.Text
+	AuthorAvatarUrl pgtype.Text
+}
+
+func (q *Queries) ListTaskCommentsByTaskId(ctx context.Context, taskID uuid.UUID) ([]ListTaskCommentsByTaskIdRow, error) {
+	rows, err := q.db.Query(ctx, listTaskCommentsByTaskId, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTaskCommentsByTaskIdRow
+	for rows.Next() {
+		var i ListTaskCommentsByTaskIdRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProjectID,
+			&i.AuthorName,
+			&i.AuthorAvatarUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskTransitiveBlockerIds = `-- name: ListTaskTransitiveBlockerIds :many
+WITH RECURSIVE blockers AS (
+  SELECT t.id FROM tasks t WHERE t.id = $1
+  UNION
+  SELECT td.blocked_by_task_id FROM task_dependencies td
+  JOIN blockers b ON td.task_id = b.id
+)
+SELECT id FROM blockers
+`
+
+func (q *Queries) ListTaskTransitiveBlockerIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listTaskTransitiveBlockerIds, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTasksByProjectId = `-- name: ListTasksByProjectId :many
 SELECT 
-  t.id, t.project_id, t.title, t.description, t.status, t.created_at, t.updated_at, t.author_id,
+  t.id, t.project_id, t.title, t.description, t.status, t.created_at, t.updated_at, t.author_id, t.parent_id,
   a.id as author_author_id,
-  a.name as author_name
+  a.name as author_name,
+  a.avatar_url as author_avatar_url,
+  (SELECT count(*) FROM task_comments tc WHERE tc.task_id = t.id) as comment_count
 FROM tasks t
 LEFT JOIN users a ON a.id = t.author_id
-WHERE project_id = $1
+WHERE t.project_id = $1
+AND ($2::text IS NULL OR t.status = $2::text)
+AND ($3::uuid IS NULL OR t.author_id = $3::uuid)
+AND ($4::timestamptz IS NULL OR t.updated_at >= $4::timestamptz)
+AND ($5::text IS NULL OR t.title ILIKE $5::text OR t.description ILIKE $5::text)
+AND (
+  $6::timestamptz IS NULL
+  OR (
+    $7::boolean
+    AND (CASE WHEN $8::text = 'updated_at' THEN t.updated_at ELSE t.created_at END, t.id) < ($6::timestamptz, $9::uuid)
+  )
+  OR (
+    NOT $7::boolean
+    AND (CASE WHEN $8::text = 'updated_at' THEN t.updated_at ELSE t.created_at END, t.id) > ($6::timestamptz, $9::uuid)
+  )
+)
+ORDER BY
+  CASE WHEN NOT $7::boolean THEN CASE WHEN $8::text = 'updated_at' THEN t.updated_at ELSE t.created_at END END ASC,
+  CASE WHEN $7::boolean THEN CASE WHEN $8::text = 'updated_at' THEN t.updated_at ELSE t.created_at END END DESC,
+  CASE WHEN NOT $7::boolean THEN t.id END ASC,
+  CASE WHEN $7::boolean THEN t.id END DESC
+LIMIT $10
 `
 
+type ListTasksByProjectIdParams struct {
+	ProjectID    uuid.UUID
+	Status       pgtype.Text
+	AuthorID     pgtype.UUID
+	UpdatedSince pgtype.Timestamptz
+	Search       pgtype.Text
+	After        pgtype.Timestamptz
+	SortDesc     bool
+	SortBy       string
+	AfterID      pgtype.UUID
+	PageLimit    int32
+}
+
 type ListTasksByProjectIdRow struct {
-	ID             uuid.UUID
-	ProjectID      uuid.UUID
-	Title          string
-	Description    string
-	Status         string
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	AuthorID       uuid.UUID
-	AuthorAuthorID pgtype.UUID
-	AuthorName     pgtype.Text
-}
-
-func (q *Queries) ListTasksByProjectId(ctx context.Context, projectID uuid.UUID) ([]ListTasksByProjectIdRow, error) {
-	rows, err := q.db.Query(ctx, listTasksByProjectId, projectID)
+	ID              uuid.UUID
+	ProjectID       uuid.UUID
+	Title           string
+	Description     string
+	Status          string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+	AuthorID        uuid.UUID
+	ParentID        pgtype.UUID
+	AuthorAuthorID  pgtype.UUID
+	AuthorName      pgtype.Text
+	AuthorAvatarUrl pgtype.Text
+	CommentCount    int64
+}
+
+func (q *Queries) ListTasksByProjectId(ctx context.Context, arg ListTasksByProjectIdParams) ([]ListTasksByProjectIdRow, error) {
+	rows, err := q.db.Query(ctx, listTasksByProjectId,
+		arg.ProjectID,
+		arg.Status,
+		arg.AuthorID,
+		arg.UpdatedSince,
+		arg.Search,
+		arg.After,
+		arg.SortDesc,
+		arg.SortBy,
+		arg.AfterID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -191,8 +608,11 @@ func (q *Queries) ListTasksByProjectId(ctx context.Context, projectID uuid.UUID)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.AuthorID,
+			&i.ParentID,
 			&i.AuthorAuthorID,
 			&i.AuthorName,
+			&i.AuthorAvatarUrl,
+			&i.CommentCount,
 		); err != nil {
 			return nil, err
 		}
@@ -204,6 +624,15 @@ func (q *Queries) ListTasksByProjectId(ctx context.Context, projectID uuid.UUID)
 	return items, nil
 }
 
+const lockProjectTaskDependencies = `-- name: LockProjectTaskDependencies :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))
+`
+
+func (q *Queries) LockProjectTaskDependencies(ctx context.Context, projectID string) error {
+	_, err := q.db.Exec(ctx, lockProjectTaskDependencies, projectID)
+	return err
+}
+
 const updateTask = `-- name: UpdateTask :exec
 UPDATE tasks SET title = $1, description = $2, status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4
 `
@@ -224,3 +653,46 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) error {
 	)
 	return err
 }
+
+const updateTaskChecklistItem = `-- name: UpdateTaskChecklistItem :exec
+UPDATE task_checklist_items SET title = $1, done = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3
+`
+
+type UpdateTaskChecklistItemParams struct {
+	Title string
+	Done  bool
+	ID    uuid.UUID
+}
+
+func (q *Queries) UpdateTaskChecklistItem(ctx context.Context, arg UpdateTaskChecklistItemParams) error {
+	_, err := q.db.Exec(ctx, updateTaskChecklistItem, arg.Title, arg.Done, arg.ID)
+	return err
+}
+
+const updateTaskComment = `-- name: UpdateTaskComment :exec
+UPDATE task_comments SET content = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+`
+
+type UpdateTaskCommentParams struct {
+	Content string
+	ID      uuid.UUID
+}
+
+func (q *Queries) UpdateTaskComment(ctx context.Context, arg UpdateTaskCommentParams) error {
+	_, err := q.db.Exec(ctx, updateTaskComment, arg.Content, arg.ID)
+	return err
+}
+
+const updateTaskParent = `-- name: UpdateTaskParent :exec
+UPDATE tasks SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+`
+
+type UpdateTaskParentParams struct {
+	ParentID pgtype.UUID
+	ID       uuid.UUID
+}
+
+func (q *Queries) UpdateTaskParent(ctx context.Context, arg UpdateTaskParentParams) error {
+	_, err := q.db.Exec(ctx, updateTaskParent, arg.ParentID, arg.ID)
+	return err
+}
@@ -0,0 +1,41 @@
+-- name: CreateUserDataExport :one
+INSERT INTO user_data_exports (user_id, expires_at) VALUES ($1, $2) RETURNING id, status, created_at;
+
+-- name: GetUserDataExportById :one
+SELECT * FROM user_data_exports WHERE id = $1;
+
+-- name: GetPendingUserDataExport :one
+SELECT * FROM user_data_exports WHERE user_id = $1 AND status = 'pending' ORDER BY created_at DESC LIMIT 1;
+
+-- name: CompleteUserDataExport :exec
+UPDATE user_data_exports SET status = 'ready', archive = $1, completed_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: FailUserDataExport :exec
+UPDATE user_data_exports SET status = 'failed', completed_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: DeleteUserDataExports :exec
+DELETE FROM user_data_exports WHERE user_id = $1;
+
+-- name: ListUserDataExportProjects :many
+SELECT
+  p.id,
+  p.name,
+  p.description,
+  pm.role,
+  p.created_at
+FROM project_members pm
+JOIN projects p ON p.id = pm.project_id
+WHERE pm.user_id = $1
+ORDER BY p.created_at ASC, p.id ASC;
+
+-- name: ListUserDataExportTasks :many
+SELECT id, project_id, title, description, status, created_at, updated_at
+FROM tasks
+WHERE author_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListUserDataExportChatMessages :many
+SELECT id, chat_id, content, message_type, created_at, updated_at
+FROM chat_messages
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -1,5 +1,5 @@
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = $1;
+SELECT * FROM users WHERE lower(email) = lower(sqlc.arg(email)) ORDER BY created_at LIMIT 1;
 
 -- name: GetUserById :one
 SELECT * FROM users WHERE id = $1;
@@ -8,10 +8,142 @@ SELECT * FROM users WHERE id = $1;
 INSERT INTO users (name, email, password) VALUES ($1, $2, $3) returning id;
 
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token, expires_at, active) VALUES ($1, $2, $3, $4) returning id;
+INSERT INTO refresh_tokens (user_id, token, expires_at, active, session_id) VALUES ($1, $2, $3, $4, $5) returning id;
 
 -- name: GetRefreshTokenByToken :one
 SELECT * FROM refresh_tokens WHERE token = $1;
 
--- name: UpdateRefreshToken :exec
-UPDATE refresh_tokens SET active = $1 WHERE token = $2;
+-- name: DeactivateRefreshToken :execrows
+UPDATE refresh_tokens SET active = false, rotated_at = CURRENT_TIMESTAMP WHERE id = $1 AND active;
+
+-- name: ClearSessionRefreshTokenRotations :exec
+UPDATE refresh_tokens SET rotated_at = NULL WHERE session_id = $1 AND id <> $2 AND rotated_at IS NOT NULL;
+
+-- name: DeactivateSessionRefreshTokens :exec
+UPDATE refresh_tokens SET active = false WHERE session_id = $1 AND active;
+
+-- name: DeactivateUserRefreshTokens :exec
+UPDATE refresh_tokens SET active = false WHERE user_id = $1 AND active;
+
+-- name: UpdateUserEmailVerifiedAt :exec
+UPDATE users SET email_verified_at = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: CreateUserToken :one
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, email) VALUES ($1, $2, $3, $4, $5) returning id, created_at;
+
+-- name: GetUserTokenByTokenHash :one
+SELECT * FROM user_tokens WHERE token_hash = $1 AND purpose = $2;
+
+-- name: UseUserToken :execrows
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateUserTokens :exec
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL;
+
+-- name: CreateUserSession :one
+INSERT INTO user_sessions (user_id, user_agent, ip_address) VALUES ($1, $2, $3) returning id, last_used_at, created_at;
+
+-- name: GetUserSessionById :one
+SELECT * FROM user_sessions WHERE id = $1;
+
+-- name: IsUserSessionActive :one
+SELECT EXISTS (
+  SELECT 1 FROM user_sessions s
+  JOIN users u ON u.id = s.user_id
+  WHERE s.id = $1 AND s.user_id = $2 AND s.revoked_at IS NULL AND u.deleted_at IS NULL
+);
+
+-- name: ListActiveUserSessions :many
+SELECT * FROM user_sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE refresh_tokens.session_id = user_sessions.id
+      AND refresh_tokens.active
+      AND refresh_tokens.expires_at > CURRENT_TIMESTAMP
+  )
+ORDER BY last_used_at DESC;
+
+-- name: UpdateUserSessionLastUsed :execrows
+UPDATE user_sessions SET user_agent = $1, ip_address = $2, last_used_at = CURRENT_TIMESTAMP WHERE id = $3 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :many
+UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL RETURNING id;
+
+-- name: UpdateUserTotpSecret :exec
+UPDATE users SET totp_secret = $1, totp_enabled_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $2;
+
+-- name: EnableUserTotp :exec
+UPDATE users SET totp_enabled_at = $1, totp_last_step = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;
+
+-- name: DisableUserTotp :exec
+UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = CURRENT_TIMESTAMP WHERE id = $1;
+
+-- name: UpdateUserTotpLastStep :execrows
+UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1;
+
+-- name: CreateUserRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2);
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1;
+
+-- name: UseUserRecoveryCode :execrows
+UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: UpdateUserProfile :exec
+UPDATE users SET name = $1, avatar_url = $2, timezone = $3, locale = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5;
+
+-- name: UpdateUserEmail :exec
+UPDATE users SET email = $1, email_verified_at = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3;
+
+-- name: ListUserRoomIds :many
+SELECT project_id FROM project_members WHERE user_id = $1
+UNION
+SELECT chat_id FROM chat_members WHERE user_id = $1;
+
+-- name: GetUserDeletionBlockers :one
+SELECT
+  (SELECT count(*) FROM projects p WHERE p.user_id = $1) AS owned_projects,
+  (SELECT count(*) FROM organizations o
+    WHERE o.user_id = $1
+      AND NOT o.personal
+      AND EXISTS (SELECT 1 FROM organization_members om WHERE om.organization_id = o.id AND om.user_id <> $1)) AS shared_organizations;
+
+-- name: AnonymizeUser :execrows
+UPDATE users SET
+  name = $1,
+  email = $2,
+  password = '',
+  avatar_url = '',
+  timezone = 'UTC',
+  locale = 'en',
+  email_verified_at = NULL,
+  totp_secret = NULL,
+  totp_enabled_at = NULL,
+  totp_last_step = 0,
+  deleted_at = $3,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $4 AND deleted_at IS NULL;
+
+-- name: AnonymizeUserPersonalOrganization :exec
+UPDATE organizations SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE user_id = $2 AND personal;
+
+-- name: DeleteUserChatMemberships :exec
+DELETE FROM chat_members WHERE user_id = $1;
+
+-- name: DeleteUserProjectMemberships :many
+DELETE FROM project_members WHERE user_id = $1 RETURNING *;
+
+-- name: DeleteUserOrganizationMemberships :exec
+DELETE FROM organization_members WHERE user_id = $1;
+
+-- name: InvalidateAllUserTokens :exec
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL;
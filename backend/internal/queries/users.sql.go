@@ -12,8 +12,73 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeUser = `-- name: AnonymizeUser :execrows
+UPDATE users SET
+  name = $1,
+  email = $2,
+  password = '',
+  avatar_url = '',
+  timezone = 'UTC',
+  locale = 'en',
+  email_verified_at = NULL,
+  totp_secret = NULL,
+  totp_enabled_at = NULL,
+  totp_last_step = 0,
+  deleted_at = $3,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $4 AND deleted_at IS NULL
+`
+
+type AnonymizeUserParams struct {
+	Name      string
+	Email     string
+	DeletedAt pgtype.Timestamptz
+	ID        uuid.UUID
+}
+
+func (q *Queries) AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, anonymizeUser,
+		arg.Name,
+		arg.Email,
+		arg.DeletedAt,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const anonymizeUserPersonalOrganization = `-- name: AnonymizeUserPersonalOrganization :exec
+UPDATE organizations SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE user_id = $2 AND personal
+`
+
+type AnonymizeUserPersonalOrganizationParams struct {
+	Name   string
+	UserID uuid.UUID
+}
+
+func (q *Queries) AnonymizeUserPersonalOrganization(ctx context.Context, arg AnonymizeUserPersonalOrganizationParams) error {
+	_, err := q.db.Exec(ctx, anonymizeUserPersonalOrganization, arg.Name, arg.UserID)
+	return err
+}
+
+const clearSessionRefreshTokenRotations = `-- name: ClearSessionRefreshTokenRotations :exec
+UPDATE refresh_tokens SET rotated_at = NULL WHERE session_id = $1 AND id <> $2 AND rotated_at IS NOT NULL
+`
+
+type ClearSessionRefreshTokenRotationsParams struct {
+	SessionID uuid.UUID
+	ID        uuid.UUID
+}
+
+func (q *Queries) ClearSessionRefreshTokenRotations(ctx context.Context, arg ClearSessionRefreshTokenRotationsParams) error {
+	_, err := q.db.Exec(ctx, clearSessionRefreshTokenRotations, arg.SessionID, arg.ID)
+	return err
+}
+
 const createRefreshToken = `-- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token, expires_at, active) VALUES ($1, $2, $3, $4) returning id
+INSERT INTO refresh_tokens (user_id, token, expires_at, active, session_id) VALUES ($1, $2, $3, $4, $5) returning id
 `
 
 type CreateRefreshTokenParams struct {
@@ -21,6 +86,7 @@ type CreateRefreshTokenParams struct {
 	Token     string
 	ExpiresAt pgtype.Timestamptz
 	Active    bool
+	SessionID uuid.UUID
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (uuid.UUID, error) {
@@ -29,6 +95,7 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		arg.Token,
 		arg.ExpiresAt,
 		arg.Active,
+		arg.SessionID,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
@@ -52,8 +119,185 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (uuid.UU
 	return id, err
 }
 
+const createUserRecoveryCode = `-- name: CreateUserRecoveryCode :exec
+INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)
+`
+
+type CreateUserRecoveryCodeParams struct {
+	UserID   uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) CreateUserRecoveryCode(ctx context.Context, arg CreateUserRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createUserRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const createUserSession = `-- name: CreateUserSession :one
+INSERT INTO user_sessions (user_id, user_agent, ip_address) VALUES ($1, $2, $3) returning id, last_used_at, created_at
+`
+
+type CreateUserSessionParams struct {
+	UserID    uuid.UUID
+	UserAgent string
+	IpAddress string
+}
+
+type CreateUserSessionRow struct {
+	ID         uuid.UUID
+	LastUsedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) CreateUserSession(ctx context.Context, arg CreateUserSessionParams) (CreateUserSessionRow, error) {
+	row := q.db.QueryRow(ctx, createUserSession, arg.UserID, arg.UserAgent, arg.IpAddress)
+	var i CreateUserSessionRow
+	err := row.Scan(&i.ID, &i.LastUsedAt, &i.CreatedAt)
+	return i, err
+}
+
+const createUserToken = `-- name: CreateUserToken :one
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, email) VALUES ($1, $2, $3, $4, $5) returning id, created_at
+`
+
+type CreateUserTokenParams struct {
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt pgtype.Timestamptz
+	Email     pgtype.Text
+}
+
+type CreateUserTokenRow struct {
+	ID        uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateUserToken(ctx context.Context, arg CreateUserTokenParams) (CreateUserTokenRow, error) {
+	row := q.db.QueryRow(ctx, createUserToken,
+		arg.UserID,
+		arg.Purpose,
+		arg.TokenHash,
+		arg.ExpiresAt,
+		arg.Email,
+	)
+	var i CreateUserTokenRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const deactivateRefreshToken = `-- name: DeactivateRefreshToken :execrows
+UPDATE refresh_tokens SET active = false, rotated_at = CURRENT_TIMESTAMP WHERE id = $1 AND active
+`
+
+func (q *Queries) DeactivateRefreshToken(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deactivateRefreshToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deactivateSessionRefreshTokens = `-- name: DeactivateSessionRefreshTokens :exec
+UPDATE refresh_tokens SET active = false WHERE session_id = $1 AND active
+`
+
+func (q *Queries) DeactivateSessionRefreshTokens(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deactivateSessionRefreshTokens, sessionID)
+	return err
+}
+
+const deactivateUserRefreshTokens = `-- name: DeactivateUserRefreshTokens :exec
+UPDATE refresh_tokens SET active = false WHERE user_id = $1 AND active
+`
+
+func (q *Queries) DeactivateUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deactivateUserRefreshTokens, userID)
+	return err
+}
+
+const deleteUserChatMemberships = `-- name: DeleteUserChatMemberships :exec
+DELETE FROM chat_members WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserChatMemberships(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserChatMemberships, userID)
+	return err
+}
+
+const deleteUserOrganizationMemberships = `-- name: DeleteUserOrganizationMemberships :exec
+DELETE FROM organization_members WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserOrganizationMemberships(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserOrganizationMemberships, userID)
+	return err
+}
+
+const deleteUserProjectMemberships = `-- name: DeleteUserProjectMemberships :many
+DELETE FROM project_members WHERE user_id = $1 RETURNING id, user_id, project_id, role
+`
+
+func (q *Queries) DeleteUserProjectMemberships(ctx context.Context, userID uuid.UUID) ([]ProjectMember, error) {
+	rows, err := q.db.Query(ctx, deleteUserProjectMemberships, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectMember
+	for rows.Next() {
+		var i ProjectMember
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ProjectID,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteUserRecoveryCodes = `-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserRecoveryCodes, userID)
+	return err
+}
+
+const disableUserTotp = `-- name: DisableUserTotp :exec
+UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+`
+
+func (q *Queries) DisableUserTotp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, disableUserTotp, id)
+	return err
+}
+
+const enableUserTotp = `-- name: EnableUserTotp :exec
+UPDATE users SET totp_enabled_at = $1, totp_last_step = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3
+`
+
+type EnableUserTotpParams struct {
+	TotpEnabledAt pgtype.Timestamptz
+	TotpLastStep  int64
+	ID            uuid.UUID
+}
+
+func (q *Queries) EnableUserTotp(ctx context.Context, arg EnableUserTotpParams) error {
+	_, err := q.db.Exec(ctx, enableUserTotp, arg.TotpEnabledAt, arg.TotpLastStep, arg.ID)
+	return err
+}
+
 const getRefreshTokenByToken = `-- name: GetRefreshTokenByToken :one
-SELECT id, active, token, user_id, created_at, expires_at FROM refresh_tokens WHERE token = $1
+SELECT id, active, token, user_id, created_at, expires_at, session_id, rotated_at FROM refresh_tokens WHERE token = $1
 `
 
 func (q *Queries) GetRefreshTokenByToken(ctx context.Context, token string) (RefreshToken, error) {
@@ -66,12 +310,14 @@ func (q *Queries) GetRefreshTokenByToken(ctx context.Context, token string) (Ref
 		&i.UserID,
 		&i.CreatedAt,
 		&i.ExpiresAt,
+		&i.SessionID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = $1
+SELECT id, name, email, password, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, avatar_url, timezone, locale, deleted_at FROM users WHERE lower(email) = lower($1) ORDER BY created_at LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -84,12 +330,20 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.AvatarUrl,
+		&i.Timezone,
+		&i.Locale,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = $1
+SELECT id, name, email, password, created_at, updated_at, email_verified_at, totp_secret, totp_enabled_at, totp_last_step, avatar_url, timezone, locale, deleted_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
@@ -102,20 +356,369 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastStep,
+		&i.AvatarUrl,
+		&i.Timezone,
+		&i.Locale,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const updateRefreshToken = `-- name: UpdateRefreshToken :exec
-UPDATE refresh_tokens SET active = $1 WHERE token = $2
+const getUserDeletionBlockers = `-- name: GetUserDeletionBlockers :one
+SELECT
+  (SELECT count(*) FROM projects p WHERE p.user_id = $1) AS owned_projects,
+  (SELECT count(*) FROM organizations o
+    WHERE o.user_id = $1
+      AND NOT o.personal
+      AND EXISTS (SELECT 1 FROM organization_members om WHERE om.organization_id = o.id AND om.user_id <> $1)) AS shared_organizations
 `
 
-type UpdateRefreshTokenParams struct {
-	Active bool
-	Token  string
+type GetUserDeletionBlockersRow struct {
+	OwnedProjects       int64
+	SharedOrganizations int64
 }
 
-func (q *Queries) UpdateRefreshToken(ctx context.Context, arg UpdateRefreshTokenParams) error {
-	_, err := q.db.Exec(ctx, updateRefreshToken, arg.Active, arg.Token)
+func (q *Queries) GetUserDeletionBlockers(ctx context.Context, userID uuid.UUID) (GetUserDeletionBlockersRow, error) {
+	row := q.db.QueryRow(ctx, getUserDeletionBlockers, userID)
+	var i GetUserDeletionBlockersRow
+	err := row.Scan(&i.OwnedProjects, &i.SharedOrganizations)
+	return i, err
+}
+
+const getUserSessionById = `-- name: GetUserSessionById :one
+SELECT id, user_id, user_agent, ip_address, last_used_at, revoked_at, created_at FROM user_sessions WHERE id = $1
+`
+
+func (q *Queries) GetUserSessionById(ctx context.Context, id uuid.UUID) (UserSession, error) {
+	row := q.db.QueryRow(ctx, getUserSessionById, id)
+	var i UserSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTokenByTokenHash = `-- name: GetUserTokenByTokenHash :one
+SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at, email FROM user_tokens WHERE token_hash = $1 AND purpose = $2
+`
+
+type GetUserTokenByTokenHashParams struct {
+	TokenHash string
+	Purpose   string
+}
+
+func (q *Queries) GetUserTokenByTokenHash(ctx context.Context, arg GetUserTokenByTokenHashParams) (UserToken, error) {
+	row := q.db.QueryRow(ctx, getUserTokenByTokenHash, arg.TokenHash, arg.Purpose)
+	var i UserToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.Email,
+	)
+	return i, err
+}
+
+const invalidateAllUserTokens = `-- name: InvalidateAllUserTokens :exec
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) InvalidateAllUserTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, invalidateAllUserTokens, userID)
 	return err
 }
+
+const invalidateUserTokens = `-- name: InvalidateUserTokens :exec
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+`
+
+type InvalidateUserTokensParams struct {
+	UserID  uuid.UUID
+	Purpose string
+}
+
+func (q *Queries) InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error {
+	_, err := q.db.Exec(ctx, invalidateUserTokens, arg.UserID, arg.Purpose)
+	return err
+}
+
+const isUserSessionActive = `-- name: IsUserSessionActive :one
+SELECT EXISTS (
+  SELECT 1 FROM user_sessions s
+  JOIN users u ON u.id = s.user_id
+  WHERE s.id = $1 AND s.user_id = $2 AND s.revoked_at IS NULL AND u.deleted_at IS NULL
+)
+`
+
+type IsUserSessionActiveParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) IsUserSessionActive(ctx context.Context, arg IsUserSessionActiveParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserSessionActive, arg.ID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listActiveUserSessions = `-- name: ListActiveUserSessions :many
+SELECT id, user_id, user_agent, ip_address, last_used_at, revoked_at, created_at FROM user_sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM refresh_tokens
+    WHERE refresh_tokens.session_id = user_sessions.id
+      AND refresh_tokens.active
+      AND refresh_tokens.expires_at > CURRENT_TIMESTAMP
+  )
+ORDER BY last_used_at DESC
+`
+
+func (q *Queries) ListActiveUserSessions(ctx context.Context, userID uuid.UUID) ([]UserSession, error) {
+	rows, err := q.db.Query(ctx, listActiveUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserSession
+	for rows.Next() {
+		var i UserSession
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRoomIds = `-- name: ListUserRoomIds :many
+SELECT project_id FROM project_members WHERE user_id = $1
+UNION
+SELECT chat_id FROM chat_members WHERE user_id = $1
+`
+
+func (q *Queries) ListUserRoomIds(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listUserRoomIds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var project_id uuid.UUID
+		if err := rows.Scan(&project_id); err != nil {
+			return nil, err
+		}
+		items = append(items, project_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeUserSession = `-- name: RevokeUserSession :execrows
+UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserSession(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserSession, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserSessions = `-- name: RevokeUserSessions :many
+UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL RETURNING id
+`
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, revokeUserSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserEmail = `-- name: UpdateUserEmail :exec
+UPDATE users SET email = $1, email_verified_at = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3
+`
+
+type UpdateUserEmailParams struct {
+	Email           string
+	EmailVerifiedAt pgtype.Timestamptz
+	ID              uuid.UUID
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) error {
+	_, err := q.db.Exec(ctx, updateUserEmail, arg.Email, arg.EmailVerifiedAt, arg.ID)
+	return err
+}
+
+const updateUserEmailVerifiedAt = `-- name: UpdateUserEmailVerifiedAt :exec
+UPDATE users SET email_verified_at = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+`
+
+type UpdateUserEmailVerifiedAtParams struct {
+	EmailVerifiedAt pgtype.Timestamptz
+	ID              uuid.UUID
+}
+
+func (q *Queries) UpdateUserEmailVerifiedAt(ctx context.Context, arg UpdateUserEmailVerifiedAtParams) error {
+	_, err := q.db.Exec(ctx, updateUserEmailVerifiedAt, arg.EmailVerifiedAt, arg.ID)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+`
+
+type UpdateUserPasswordParams struct {
+	Password string
+	ID       uuid.UUID
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.Password, arg.ID)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :exec
+UPDATE users SET name = $1, avatar_url = $2, timezone = $3, locale = $4, updated_at = CURRENT_TIMESTAMP WHERE id = $5
+`
+
+type UpdateUserProfileParams struct {
+	Name      string
+	AvatarUrl string
+	Timezone  string
+	Locale    string
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) error {
+	_, err := q.db.Exec(ctx, updateUserProfile,
+		arg.Name,
+		arg.AvatarUrl,
+		arg.Timezone,
+		arg.Locale,
+		arg.ID,
+	)
+	return err
+}
+
+const updateUserSessionLastUsed = `-- name: UpdateUserSessionLastUsed :execrows
+UPDATE user_sessions SET user_agent = $1, ip_address = $2, last_used_at = CURRENT_TIMESTAMP WHERE id = $3 AND revoked_at IS NULL
+`
+
+type UpdateUserSessionLastUsedParams struct {
+	UserAgent string
+	IpAddress string
+	ID        uuid.UUID
+}
+
+func (q *Queries) UpdateUserSessionLastUsed(ctx context.Context, arg UpdateUserSessionLastUsedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserSessionLastUsed, arg.UserAgent, arg.IpAddress, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserTotpLastStep = `-- name: UpdateUserTotpLastStep :execrows
+UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1
+`
+
+type UpdateUserTotpLastStepParams struct {
+	TotpLastStep int64
+	ID           uuid.UUID
+}
+
+func (q *Queries) UpdateUserTotpLastStep(ctx context.Context, arg UpdateUserTotpLastStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserTotpLastStep, arg.TotpLastStep, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserTotpSecret = `-- name: UpdateUserTotpSecret :exec
+UPDATE users SET totp_secret = $1, totp_enabled_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $2
+`
+
+type UpdateUserTotpSecretParams struct {
+	TotpSecret pgtype.Text
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateUserTotpSecret(ctx context.Context, arg UpdateUserTotpSecretParams) error {
+	_, err := q.db.Exec(ctx, updateUserTotpSecret, arg.TotpSecret, arg.ID)
+	return err
+}
+
+const useUserRecoveryCode = `-- name: UseUserRecoveryCode :execrows
+UPDATE user_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+type UseUserRecoveryCodeParams struct {
+	UserID   uuid.UUID
+	CodeHash string
+}
+
+func (q *Queries) UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useUserRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useUserToken = `-- name: UseUserToken :execrows
+UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UseUserToken(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, useUserToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are dropped, a full bucket behaves exactly like a
+// missing one so nothing is lost.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	key    string
+	bucket *Bucket
+}
+
+// MemoryStore keeps at most maxKeys buckets. Once it is full the least recently used bucket
+// is dropped, so a flood of new keys costs its oldest clients a fresh bucket instead of
+// growing without bound.
+type MemoryStore struct {
+	buckets map[string]*list.Element
+	// recent orders the entries from the most to the least recently used.
+	recent    *list.List
+	maxKeys   int
+	mutex     sync.Mutex
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*list.Element),
+		recent:    list.New(),
+		maxKeys:   maxKeys,
+		now:       time.Now,
+		lastSweep: time.Now(),
+	}
+}
+
+func (ms *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.now()
+
+	if now.Sub(ms.lastSweep) >= sweepInterval {
+		ms.sweep(now)
+	}
+
+	element, ok := ms.buckets[key]
+	if ok {
+		ms.recent.MoveToFront(element)
+	} else {
+		// full buckets are left to the sweep, a full store only drops its oldest bucket so new
+		// keys cost the same however many buckets it keeps
+		if len(ms.buckets) >= ms.maxKeys && ms.recent.Len() > 0 {
+			ms.remove(ms.recent.Back())
+		}
+
+		element = ms.recent.PushFront(&memoryEntry{key: key, bucket: NewBucket(limit, now)})
+		ms.buckets[key] = element
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if entry.bucket.limit != limit {
+		entry.bucket = NewBucket(limit, now)
+	}
+
+	return entry.bucket.Take(now), nil
+}
+
+func (ms *MemoryStore) sweep(now time.Time) {
+	for element := ms.recent.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*memoryEntry).bucket.full(now) {
+			ms.remove(element)
+		}
+		element = next
+	}
+	ms.lastSweep = now
+}
+
+func (ms *MemoryStore) remove(element *list.Element) {
+	ms.recent.Remove(element)
+	delete(ms.buckets, element.Value.(*memoryEntry).key)
+}
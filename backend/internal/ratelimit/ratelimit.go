@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests every Period. Limits are token buckets, a client can burst up to
+// Requests at once and spent requests come back one by one over the period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit restricts anything, the zero Limit lets every request through.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// interval is how long it takes for a single spent request to come back.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// ParseLimit reads limits written as requests/period, such as "60/1m" or "20/1s". An empty
+// value or "off" disables the limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected requests/period", value)
+	}
+
+	count, err := strconv.Atoi(requests)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, requests must be a positive number", value)
+	}
+
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, period must be a positive duration", value)
+	}
+
+	return Limit{Requests: count, Period: duration}, nil
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until every spent request has come back.
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next request is allowed, zero when Allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of every key. MemoryStore only limits the requests a single
+// instance sees, running several instances behind a load balancer needs a shared store.
+type Store interface {
+	// Take spends one request of key, creating its bucket with limit when missing.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket is a single token bucket. It is not safe for concurrent use, callers that share
+// a bucket between goroutines must guard it themselves.
+type Bucket struct {
+	limit     Limit
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewBucket creates a full bucket for limit.
+func NewBucket(limit Limit, now time.Time) *Bucket {
+	return &Bucket{
+		limit:     limit,
+		tokens:    float64(limit.Requests),
+		updatedAt: now,
+	}
+}
+
+// Take spends one request when there is one left.
+func (b *Bucket) Take(now time.Time) Result {
+	b.refill(now)
+
+	result := Result{Limit: b.limit.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = b.duration(1 - b.tokens)
+	}
+
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = b.duration(float64(b.limit.Requests) - b.tokens)
+
+	return result
+}
+
+// full reports whether every spent request has come back by now.
+func (b *Bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.limit.Requests)
+}
+
+func (b *Bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+float64(elapsed)/float64(b.limit.interval()))
+	b.updatedAt = now
+}
+
+func (b *Bucket) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(b.limit.interval())))
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expected      Limit
+		expectedError bool
+	}{
+		{"per minute", "60/1m", Limit{Requests: 60, Period: time.Minute}, false},
+		{"per second", "20/1s", Limit{Requests: 20, Period: time.Second}, false},
+		{"spaces", " 5/10s ", Limit{Requests: 5, Period: 10 * time.Second}, false},
+		{"empty", "", Limit{}, false},
+		{"off", "off", Limit{}, false},
+		{"missing period", "60", Limit{}, true},
+		{"zero requests", "0/1m", Limit{}, true},
+		{"negative requests", "-1/1m", Limit{}, true},
+		{"invalid period", "60/minute", Limit{}, true},
+		{"zero period", "60/0s", Limit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := ParseLimit(tt.value)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	bucket := NewBucket(Limit{Requests: 3, Period: 3 * time.Second}, now)
+
+	for i := 2; i >= 0; i-- {
+		result := bucket.Take(now)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+		assert.Zero(t, result.RetryAfter)
+	}
+
+	result := bucket.Take(now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	result = bucket.Take(now.Add(500 * time.Millisecond))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	result = bucket.Take(now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result = bucket.Take(now.Add(time.Hour))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, time.Second, result.ResetAfter)
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore(10)
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+
+	limit := Limit{Requests: 2, Period: time.Minute}
+	ctx := context.Background()
+
+	for _, expected := range []bool{true, true, false} {
+		result, err := store.Take(ctx, "user", limit)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result.Allowed)
+	}
+
+	result, err := store.Take(ctx, "other", limit)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed, "keys have their own buckets")
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = store.Take(ctx, "user", limit)
+	assert.NoError(t, err)
+	assert.Len(t, store.buckets, 1, "full buckets are swept")
+	assert.Contains(t, store.buckets, "user")
+}
+
+func TestMemoryStore_MaxKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore(2)
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+
+	limit := Limit{Requests: 1, Period: time.Minute}
+	ctx := context.Background()
+
+	for _, key := range []string{"first", "second", "first", "third"} {
+		_, err := store.Take(ctx, key, limit)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, store.buckets, 2)
+	assert.Contains(t, store.buckets, "first", "recently used buckets are kept")
+	assert.Contains(t, store.buckets, "third")
+
+	result, err := store.Take(ctx, "first", limit)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed, "kept buckets keep their spent requests")
+
+	now = now.Add(30 * time.Second)
+	shortLimit := Limit{Requests: 1, Period: time.Second}
+	for _, key := range []string{"fifth", "sixth"} {
+		_, err = store.Take(ctx, key, shortLimit)
+		assert.NoError(t, err)
+	}
+
+	now = now.Add(10 * time.Second)
+
+	_, err = store.Take(ctx, "seventh", shortLimit)
+	assert.NoError(t, err)
+	assert.Len(t, store.buckets, 2, "a new key only evicts the oldest bucket between sweeps")
+	assert.Contains(t, store.buckets, "sixth")
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = store.Take(ctx, "fourth", limit)
+	assert.NoError(t, err)
+	assert.Len(t, store.buckets, 1, "full buckets are swept")
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AccessTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccessTokenRepository(pool *pgxpool.Pool) *AccessTokenRepository {
+	return &AccessTokenRepository{
+		pool: pool,
+	}
+}
+
+func (ar *AccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	q := queries.New(ar.pool)
+
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	params := queries.CreatePersonalAccessTokenParams{
+		UserID:    token.UserId,
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Scopes:    scopes,
+		ExpiresAt: pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+	}
+
+	result, err := q.CreatePersonalAccessToken(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	token.Id = result.ID
+	token.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (ar *AccessTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	q := queries.New(ar.pool)
+
+	result, err := q.GetPersonalAccessTokenByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("access token not found")
+		}
+		return nil, err
+	}
+
+	return accessTokenFromQuery(result), nil
+}
+
+// ListByUserId returns the tokens of the user that were not revoked and have not expired,
+// newest first.
+func (ar *AccessTokenRepository) ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	q := queries.New(ar.pool)
+
+	results, err := q.ListPersonalAccessTokensByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]domain.PersonalAccessToken, 0, len(results))
+	for _, result := range results {
+		tokens = append(tokens, *accessTokenFromQuery(result))
+	}
+
+	return tokens, nil
+}
+
+func (ar *AccessTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID) error {
+	q := queries.New(ar.pool)
+
+	return q.UpdatePersonalAccessTokenLastUsed(ctx, id)
+}
+
+func (ar *AccessTokenRepository) Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	q := queries.New(ar.pool)
+
+	params := queries.RevokePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userId,
+	}
+
+	rows, err := q.RevokePersonalAccessToken(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("access token not found")
+	}
+
+	return nil
+}
+
+func accessTokenFromQuery(result queries.PersonalAccessToken) *domain.PersonalAccessToken {
+	token := domain.PersonalAccessToken{
+		Id:        result.ID,
+		UserId:    result.UserID,
+		Name:      result.Name,
+		TokenHash: result.TokenHash,
+		Scopes:    make([]domain.AccessTokenScope, 0, len(result.Scopes)),
+		ExpiresAt: result.ExpiresAt.Time,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	for _, scope := range result.Scopes {
+		token.Scopes = append(token.Scopes, domain.AccessTokenScope(scope))
+	}
+
+	if result.LastUsedAt.Valid {
+		token.LastUsedAt = &result.LastUsedAt.Time
+	}
+
+	if result.RevokedAt.Valid {
+		token.RevokedAt = &result.RevokedAt.Time
+	}
+
+	return &token
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ActivityRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewActivityRepository(pool *pgxpool.Pool) *ActivityRepository {
+	return &ActivityRepository{
+		pool: pool,
+	}
+}
+
+func (ar *ActivityRepository) Create(ctx context.Context, activity *domain.ProjectActivity) error {
+	q := queries.New(ar.pool)
+
+	params := queries.CreateProjectActivityParams{
+		ProjectID: activity.ProjectId,
+		Type:      activity.Type,
+		Payload:   activity.Payload,
+	}
+
+	if activity.ActorId != nil {
+		params.ActorID = pgtype.UUID{Bytes: *activity.ActorId, Valid: true}
+	}
+
+	result, err := q.CreateProjectActivity(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "fk_project_activities_projects" {
+			return domain.NotFoundError("project not found")
+		}
+		return err
+	}
+
+	activity.Id = result.ID
+	activity.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (ar *ActivityRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID, params utils.PaginationBeforeParams) ([]domain.ProjectActivity, error) {
+	q := queries.New(ar.pool)
+
+	queriesParams := queries.ListProjectActivitiesParams{
+		ProjectID: projectId,
+		Limit:     params.Limit,
+		CreatedAt: pgtype.Timestamptz{Time: params.Before, Valid: true},
+		Column3:   params.Id,
+	}
+
+	results, err := q.ListProjectActivities(ctx, queriesParams)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := []domain.ProjectActivity{}
+	for _, result := range results {
+		activity := domain.ProjectActivity{
+			Id:        result.ID,
+			ProjectId: result.ProjectID,
+			Type:      result.Type,
+			Payload:   result.Payload,
+			CreatedAt: result.CreatedAt.Time,
+		}
+
+		if result.ActorID.Valid {
+			actorId := uuid.UUID(result.ActorID.Bytes)
+			activity.ActorId = &actorId
+			activity.Actor = &domain.User{
+				Id:   actorId,
+				Name: result.ActorName.String,
+			}
+		}
+
+		if result.UserID.Valid {
+			activity.User = &domain.User{
+				Id:   result.UserID.Bytes,
+				Name: result.UserName.String,
+			}
+		}
+
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
@@ -70,6 +70,14 @@ func (cr *ChatRepository) CreateMember(ctx context.Context, member *domain.ChatM
 	})
 }
 
+func (cr *ChatRepository) RemoveMember(ctx context.Context, chatId uuid.UUID, userId uuid.UUID) error {
+	q := queries.New(cr.pool)
+	return q.DeleteChatMember(ctx, queries.DeleteChatMemberParams{
+		UserID: userId,
+		ChatID: chatId,
+	})
+}
+
 func (cr *ChatRepository) UpdateMemberLastSeenAt(ctx context.Context, member *domain.ChatMember) error {
 	q := queries.New(cr.pool)
 	return q.UpdateChatMemberLastSeenAt(ctx, queries.UpdateChatMemberLastSeenAtParams{
@@ -93,6 +101,14 @@ func (cr *ChatRepository) CreateMessage(ctx context.Context, message *domain.Cha
 		params.UserID = pgtype.UUID{Bytes: *message.UserId, Valid: true}
 	}
 
+	if message.ActorId != nil {
+		params.ActorID = pgtype.UUID{Bytes: *message.ActorId, Valid: true}
+	}
+
+	if message.SubjectId != nil {
+		params.SubjectID = pgtype.UUID{Bytes: *message.SubjectId, Valid: true}
+	}
+
 	id, err := q.CreateChatMessage(ctx, params)
 	if err != nil {
 		return err
@@ -198,8 +214,9 @@ func (cr *ChatRepository) ListMessages(ctx context.Context, chatId uuid.UUID, pa
 			message.UserId = (*uuid.UUID)(messageResult.UserID.Bytes[:])
 
 			user := domain.User{
-				Id:   *message.UserId,
-				Name: messageResult.UserName.String,
+				Id:        *message.UserId,
+				Name:      messageResult.UserName.String,
+				AvatarUrl: messageResult.UserAvatarUrl.String,
 			}
 
 			message.Member = &domain.ChatMember{
@@ -209,6 +226,10 @@ func (cr *ChatRepository) ListMessages(ctx context.Context, chatId uuid.UUID, pa
 			}
 		}
 
+		if message.MessageType == domain.MessageTypeSystem {
+			message.RenderNames(messageResult.ActorName.String, messageResult.SubjectName.String)
+		}
+
 		messages = append(messages, message)
 	}
 
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		pool: pool,
+	}
+}
+
+// Create inserts the attempt and returns the failures of its email and address counted
+// before it. Counting and inserting happen in one transaction holding a lock on the email and
+// the address, so concurrent attempts are counted one after the other and cannot all get in
+// under the lockout threshold.
+func (lr *LoginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt, since time.Time) (*domain.LoginFailures, *domain.LoginFailures, error) {
+	tx, err := lr.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(lr.pool)
+	qtx := q.WithTx(tx)
+
+	// the email is always locked before the address so two attempts cannot wait on each other
+	err = qtx.LockLoginAttempts(ctx, "email:"+attempt.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	emailFailures, err := getFailuresByEmail(ctx, qtx, attempt.Email, since)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ipAddressFailures := &domain.LoginFailures{}
+	if attempt.IpAddress != "" {
+		err = qtx.LockLoginAttempts(ctx, "ip_address:"+attempt.IpAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ipAddressFailures, err = getFailuresByIpAddress(ctx, qtx, attempt.IpAddress, since)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	params := queries.CreateLoginAttemptParams{
+		Email:         attempt.Email,
+		IpAddress:     attempt.IpAddress,
+		Success:       attempt.Success,
+		FailureReason: string(attempt.FailureReason),
+	}
+
+	if attempt.UserId != nil {
+		params.UserID = pgtype.UUID{Bytes: *attempt.UserId, Valid: true}
+	}
+
+	result, err := qtx.CreateLoginAttempt(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attempt.Id = result.ID
+	attempt.CreatedAt = result.CreatedAt.Time
+
+	return emailFailures, ipAddressFailures, nil
+}
+
+// UpdateResult stores the outcome of an attempt that was created before it was known.
+func (lr *LoginAttemptRepository) UpdateResult(ctx context.Context, attempt *domain.LoginAttempt) error {
+	q := queries.New(lr.pool)
+
+	params := queries.UpdateLoginAttemptResultParams{
+		ID:            attempt.Id,
+		Success:       attempt.Success,
+		FailureReason: string(attempt.FailureReason),
+	}
+
+	if attempt.UserId != nil {
+		params.UserID = pgtype.UUID{Bytes: *attempt.UserId, Valid: true}
+	}
+
+	return q.UpdateLoginAttemptResult(ctx, params)
+}
+
+// getFailuresByEmail counts the failed logins of the email since the given time, failures
+// before the last successful login are not counted.
+func getFailuresByEmail(ctx context.Context, q *queries.Queries, email string, since time.Time) (*domain.LoginFailures, error) {
+	params := queries.GetLoginFailuresByEmailParams{
+		Email:     email,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	}
+
+	result, err := q.GetLoginFailuresByEmail(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := domain.LoginFailures{
+		Count:        int(result.FailedAttempts),
+		LastFailedAt: result.LastFailedAt.Time,
+	}
+
+	return &failures, nil
+}
+
+// getFailuresByIpAddress counts the failed logins from the address since the given time
+// across every email.
+func getFailuresByIpAddress(ctx context.Context, q *queries.Queries, ipAddress string, since time.Time) (*domain.LoginFailures, error) {
+	params := queries.GetLoginFailuresByIpAddressParams{
+		IpAddress: ipAddress,
+		CreatedAt: pgtype.Timestamptz{Time: since, Valid: true},
+	}
+
+	result, err := q.GetLoginFailuresByIpAddress(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := domain.LoginFailures{
+		Count:        int(result.FailedAttempts),
+		LastFailedAt: result.LastFailedAt.Time,
+	}
+
+	return &failures, nil
+}
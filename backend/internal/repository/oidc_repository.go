@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OidcRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOidcRepository(pool *pgxpool.Pool) *OidcRepository {
+	return &OidcRepository{
+		pool: pool,
+	}
+}
+
+func (or *OidcRepository) CreateLoginState(ctx context.Context, state *domain.OidcLoginState) error {
+	q := queries.New(or.pool)
+
+	params := queries.CreateOidcLoginStateParams{
+		StateHash:    state.StateHash,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    pgtype.Timestamptz{Time: state.ExpiresAt, Valid: true},
+	}
+
+	return q.CreateOidcLoginState(ctx, params)
+}
+
+// UseLoginState marks the state as used and returns it, states that are unknown, expired or
+// already used return a not found error.
+func (or *OidcRepository) UseLoginState(ctx context.Context, stateHash string) (*domain.OidcLoginState, error) {
+	q := queries.New(or.pool)
+
+	result, err := q.UseOidcLoginState(ctx, stateHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("login state not found")
+		}
+		return nil, err
+	}
+
+	state := domain.OidcLoginState{
+		Id:           result.ID,
+		StateHash:    result.StateHash,
+		Nonce:        result.Nonce,
+		CodeVerifier: result.CodeVerifier,
+		ExpiresAt:    result.ExpiresAt.Time,
+		CreatedAt:    result.CreatedAt.Time,
+	}
+
+	if result.UsedAt.Valid {
+		state.UsedAt = &result.UsedAt.Time
+	}
+
+	return &state, nil
+}
+
+func (or *OidcRepository) GetIdentity(ctx context.Context, issuer string, subject string) (*domain.UserIdentity, error) {
+	q := queries.New(or.pool)
+
+	params := queries.GetUserIdentityParams{
+		Issuer:  issuer,
+		Subject: subject,
+	}
+
+	result, err := q.GetUserIdentity(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("identity not found")
+		}
+		return nil, err
+	}
+
+	identity := domain.UserIdentity{
+		Id:        result.ID,
+		UserId:    result.UserID,
+		Issuer:    result.Issuer,
+		Subject:   result.Subject,
+		Email:     result.Email,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	return &identity, nil
+}
+
+// CreateIdentity links the identity to its user. The provider vouches for the email, so the
+// email of the user is marked as verified in the same transaction when emailVerifiedAt is set.
+func (or *OidcRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity, emailVerifiedAt *time.Time) error {
+	tx, err := or.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(or.pool)
+	qtx := q.WithTx(tx)
+
+	params := queries.CreateUserIdentityParams{
+		UserID:  identity.UserId,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	}
+
+	result, err := qtx.CreateUserIdentity(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.DuplicateEntryError("identity is already linked")
+		}
+		return err
+	}
+
+	identity.Id = result.ID
+	identity.CreatedAt = result.CreatedAt.Time
+
+	if emailVerifiedAt != nil {
+		verifiedParams := queries.UpdateUserEmailVerifiedAtParams{
+			EmailVerifiedAt: pgtype.Timestamptz{Time: *emailVerifiedAt, Valid: true},
+			ID:              identity.UserId,
+		}
+
+		err = qtx.UpdateUserEmailVerifiedAt(ctx, verifiedParams)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
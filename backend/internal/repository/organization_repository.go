@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OrganizationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOrganizationRepository(pool *pgxpool.Pool) *OrganizationRepository {
+	return &OrganizationRepository{
+		pool: pool,
+	}
+}
+
+// Create creates the organization and its members in a single transaction.
+func (or *OrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	tx, err := or.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(or.pool)
+	qtx := q.WithTx(tx)
+
+	err = createOrganization(ctx, qtx, organization)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func createOrganization(ctx context.Context, q *queries.Queries, organization *domain.Organization) error {
+	params := queries.CreateOrganizationParams{
+		UserID:   organization.UserId,
+		Name:     organization.Name,
+		Personal: organization.Personal,
+	}
+
+	result, err := q.CreateOrganization(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	organization.Id = result.ID
+	organization.CreatedAt = result.CreatedAt.Time
+	organization.UpdatedAt = result.UpdatedAt.Time
+
+	for i, member := range organization.Members {
+		params := queries.CreateOrganizationMemberParams{
+			OrganizationID: organization.Id,
+			UserID:         member.UserId,
+			Role:           string(member.Role),
+		}
+
+		memberResult, err := q.CreateOrganizationMember(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		organization.Members[i].Id = memberResult.ID
+		organization.Members[i].OrganizationId = organization.Id
+		organization.Members[i].CreatedAt = memberResult.CreatedAt.Time
+	}
+
+	return nil
+}
+
+func (or *OrganizationRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	q := queries.New(or.pool)
+
+	result, err := q.GetOrganizationById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("organization not found")
+		}
+		return nil, err
+	}
+
+	return organizationFromQuery(result), nil
+}
+
+func (or *OrganizationRepository) GetPersonalByUserId(ctx context.Context, userId uuid.UUID) (*domain.Organization, error) {
+	q := queries.New(or.pool)
+
+	result, err := q.GetPersonalOrganizationByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("organization not found")
+		}
+		return nil, err
+	}
+
+	return organizationFromQuery(result), nil
+}
+
+func (or *OrganizationRepository) ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.Organization, error) {
+	q := queries.New(or.pool)
+
+	results, err := q.ListOrganizationsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	organizations := make([]domain.Organization, len(results))
+	for i, result := range results {
+		organizations[i] = *organizationFromQuery(result)
+	}
+
+	return organizations, nil
+}
+
+func (or *OrganizationRepository) Update(ctx context.Context, organization *domain.Organization) error {
+	q := queries.New(or.pool)
+
+	params := queries.UpdateOrganizationParams{
+		Name: organization.Name,
+		ID:   organization.Id,
+	}
+
+	return q.UpdateOrganization(ctx, params)
+}
+
+func (or *OrganizationRepository) CreateMember(ctx context.Context, member *domain.OrganizationMember) error {
+	q := queries.New(or.pool)
+
+	params := queries.CreateOrganizationMemberParams{
+		OrganizationID: member.OrganizationId,
+		UserID:         member.UserId,
+		Role:           string(member.Role),
+	}
+
+	result, err := q.CreateOrganizationMember(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return domain.DuplicateEntryError("member already exists")
+			}
+			return err
+		}
+
+		return err
+	}
+
+	member.Id = result.ID
+	member.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (or *OrganizationRepository) GetMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error) {
+	q := queries.New(or.pool)
+
+	params := queries.GetOrganizationMemberParams{
+		OrganizationID: organizationId,
+		UserID:         userId,
+	}
+
+	result, err := q.GetOrganizationMember(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("organization member not found")
+		}
+		return nil, err
+	}
+
+	member := domain.OrganizationMember{
+		Id:             result.ID,
+		OrganizationId: result.OrganizationID,
+		UserId:         result.UserID,
+		Role:           domain.OrganizationMemberRole(result.Role),
+		CreatedAt:      result.CreatedAt.Time,
+	}
+
+	return &member, nil
+}
+
+// ListMembers lists the members of an organization ordered by name, search matches the
+// start or middle of the member name or email.
+func (or *OrganizationRepository) ListMembers(ctx context.Context, organizationId uuid.UUID, search string, limit int) ([]domain.OrganizationMember, error) {
+	q := queries.New(or.pool)
+
+	params := queries.ListOrganizationMembersParams{
+		OrganizationID: organizationId,
+		PageLimit:      int32(limit),
+	}
+
+	if search != "" {
+		params.Search = pgtype.Text{String: "%" + escapeLikePattern(search) + "%", Valid: true}
+	}
+
+	results, err := q.ListOrganizationMembers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]domain.OrganizationMember, len(results))
+	for i, result := range results {
+		members[i] = domain.OrganizationMember{
+			Id:             result.ID,
+			OrganizationId: result.OrganizationID,
+			UserId:         result.UserID,
+			User: &domain.User{
+				Id:    result.UserID,
+				Name:  result.UserName,
+				Email: result.UserEmail,
+			},
+			Role:      domain.OrganizationMemberRole(result.Role),
+			CreatedAt: result.CreatedAt.Time,
+		}
+	}
+
+	return members, nil
+}
+
+func (or *OrganizationRepository) UpdateMemberRole(ctx context.Context, member *domain.OrganizationMember) error {
+	q := queries.New(or.pool)
+
+	params := queries.UpdateOrganizationMemberRoleParams{
+		Role:           string(member.Role),
+		OrganizationID: member.OrganizationId,
+		UserID:         member.UserId,
+	}
+
+	return q.UpdateOrganizationMemberRole(ctx, params)
+}
+
+func (or *OrganizationRepository) RemoveMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) error {
+	q := queries.New(or.pool)
+
+	params := queries.RemoveOrganizationMemberParams{
+		OrganizationID: organizationId,
+		UserID:         userId,
+	}
+
+	return q.RemoveOrganizationMember(ctx, params)
+}
+
+func organizationFromQuery(result queries.Organization) *domain.Organization {
+	return &domain.Organization{
+		Id:        result.ID,
+		UserId:    result.UserID,
+		Name:      result.Name,
+		Personal:  result.Personal,
+		CreatedAt: result.CreatedAt.Time,
+		UpdatedAt: result.UpdatedAt.Time,
+	}
+}
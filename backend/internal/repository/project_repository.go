@@ -25,6 +25,13 @@ func NewProjectRepository(pool *pgxpool.Pool) *ProjectRepository {
 }
 
 func (pr *ProjectRepository) Create(ctx context.Context, project *domain.Project) error {
+	return pr.CreateWithTasks(ctx, project, nil, nil)
+}
+
+// CreateWithTasks creates the project, its members and the given tasks in a single
+// transaction, the tasks are authored by the project owner and their history starts with
+// a created change by author.
+func (pr *ProjectRepository) CreateWithTasks(ctx context.Context, project *domain.Project, tasks []domain.ProjectTemplateTask, author *domain.User) error {
 	tx, err := pr.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -35,9 +42,10 @@ func (pr *ProjectRepository) Create(ctx context.Context, project *domain.Project
 	qtx := q.WithTx(tx)
 
 	params := queries.CreateProjectParams{
-		UserID:      project.UserId,
-		Name:        project.Name,
-		Description: project.Description,
+		UserID:         project.UserId,
+		Name:           project.Name,
+		Description:    project.Description,
+		OrganizationID: project.OrganizationId,
 	}
 
 	id, err := qtx.CreateProject(ctx, params)
@@ -63,6 +71,40 @@ func (pr *ProjectRepository) Create(ctx context.Context, project *domain.Project
 		project.Members[i].ProjectId = project.Id
 	}
 
+	taskIds := make([]uuid.UUID, len(tasks))
+
+	for i, task := range tasks {
+		params := queries.CreateTaskParams{
+			ProjectID:   project.Id,
+			Title:       task.Title,
+			Description: task.Description,
+			Status:      string(task.Status),
+			AuthorID:    project.UserId,
+		}
+
+		if task.ParentIndex != nil && *task.ParentIndex >= 0 && *task.ParentIndex < i {
+			params.ParentID = pgtype.UUID{Bytes: taskIds[*task.ParentIndex], Valid: true}
+		}
+
+		id, err := qtx.CreateTask(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		taskIds[i] = id
+
+		change := domain.NewTaskCreatedChange(id, author)
+
+		_, err = qtx.CreateTaskChange(ctx, queries.CreateTaskChangeParams{
+			TaskID:      change.TaskId,
+			UserID:      pgtype.UUID{Bytes: change.AuthorId, Valid: true},
+			Description: change.Description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -78,12 +120,17 @@ func (pr *ProjectRepository) GetById(ctx context.Context, id uuid.UUID) (*domain
 	}
 
 	project := domain.Project{
-		Id:          projectResult.ID,
-		UserId:      projectResult.UserID,
-		Name:        projectResult.Name,
-		Description: projectResult.Description,
-		CreatedAt:   projectResult.CreatedAt.Time,
-		UpdatedAt:   projectResult.UpdatedAt.Time,
+		Id:             projectResult.ID,
+		UserId:         projectResult.UserID,
+		OrganizationId: projectResult.OrganizationID,
+		Name:           projectResult.Name,
+		Description:    projectResult.Description,
+		CreatedAt:      projectResult.CreatedAt.Time,
+		UpdatedAt:      projectResult.UpdatedAt.Time,
+	}
+
+	if projectResult.ArchivedAt.Valid {
+		project.ArchivedAt = &projectResult.ArchivedAt.Time
 	}
 
 	bytes, err := json.Marshal(projectResult.Members)
@@ -98,17 +145,22 @@ func (pr *ProjectRepository) GetById(ctx context.Context, id uuid.UUID) (*domain
 	return &project, nil
 }
 
-func (pr *ProjectRepository) ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string) ([]domain.Project, error) {
+func (pr *ProjectRepository) ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string, includeArchived bool, organizationId uuid.UUID) ([]domain.Project, error) {
 	q := queries.New(pr.pool)
 
 	params := queries.ListProjectsByUserIdParams{
-		UserID: userId,
+		UserID:          userId,
+		IncludeArchived: includeArchived,
 	}
 
 	if memberRole != "" {
 		params.Role = pgtype.Text{String: memberRole, Valid: true}
 	}
 
+	if organizationId != uuid.Nil {
+		params.OrganizationID = pgtype.UUID{Bytes: organizationId, Valid: true}
+	}
+
 	projectResults, err := q.ListProjectsByUserId(ctx, params)
 	if err != nil {
 		return nil, err
@@ -118,12 +170,17 @@ func (pr *ProjectRepository) ListByUserId(ctx context.Context, userId uuid.UUID,
 
 	for i, projectResult := range projectResults {
 		projects[i] = domain.Project{
-			Id:          projectResult.ID,
-			UserId:      projectResult.UserID,
-			Name:        projectResult.Name,
-			Description: projectResult.Description,
-			CreatedAt:   projectResult.CreatedAt.Time,
-			UpdatedAt:   projectResult.UpdatedAt.Time,
+			Id:             projectResult.ID,
+			UserId:         projectResult.UserID,
+			OrganizationId: projectResult.OrganizationID,
+			Name:           projectResult.Name,
+			Description:    projectResult.Description,
+			CreatedAt:      projectResult.CreatedAt.Time,
+			UpdatedAt:      projectResult.UpdatedAt.Time,
+		}
+
+		if projectResult.ArchivedAt.Valid {
+			projects[i].ArchivedAt = &projectResult.ArchivedAt.Time
 		}
 
 		bytes, err := json.Marshal(projectResult.Members)
@@ -160,6 +217,78 @@ func (pr *ProjectRepository) Update(ctx context.Context, project *domain.Project
 	return nil
 }
 
+func (pr *ProjectRepository) UpdateArchivedAt(ctx context.Context, project *domain.Project) error {
+	q := queries.New(pr.pool)
+
+	params := queries.UpdateProjectArchivedAtParams{
+		ID: project.Id,
+	}
+
+	if project.ArchivedAt != nil {
+		params.ArchivedAt = pgtype.Timestamptz{Time: *project.ArchivedAt, Valid: true}
+	}
+
+	return q.UpdateProjectArchivedAt(ctx, params)
+}
+
+// Delete removes the project with its tasks, chats, members and invitations in a single
+// transaction and returns the ids of the deleted chats.
+func (pr *ProjectRepository) Delete(ctx context.Context, projectId uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := pr.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(pr.pool)
+	qtx := q.WithTx(tx)
+
+	deletes := []func(context.Context, uuid.UUID) error{
+		qtx.DeleteProjectTaskDependencies,
+		qtx.DeleteProjectTaskChecklistItems,
+		qtx.DeleteProjectTaskComments,
+		qtx.DeleteProjectTaskChanges,
+		qtx.DeleteProjectTasks,
+		qtx.DeleteProjectChatMessages,
+		qtx.DeleteProjectChatMembers,
+		qtx.DeleteProjectActivities,
+	}
+
+	for _, deleteRows := range deletes {
+		err = deleteRows(ctx, projectId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chatIds, err := qtx.DeleteProjectChats(ctx, pgtype.UUID{Bytes: projectId, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.DeleteProjectInvitations(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.DeleteProjectMembers(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.DeleteProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return chatIds, nil
+}
+
 func (pr *ProjectRepository) CreateMember(ctx context.Context, member *domain.ProjectMember) error {
 	q := queries.New(pr.pool)
 
@@ -204,6 +333,53 @@ func (pr *ProjectRepository) RemoveMember(ctx context.Context, projectId uuid.UU
 	return nil
 }
 
+func (pr *ProjectRepository) UpdateMemberRole(ctx context.Context, member *domain.ProjectMember) error {
+	q := queries.New(pr.pool)
+
+	params := queries.UpdateProjectMemberRoleParams{
+		Role:      string(member.Role),
+		UserID:    member.UserId,
+		ProjectID: member.ProjectId,
+	}
+
+	return q.UpdateProjectMemberRole(ctx, params)
+}
+
+// TransferOwnership swaps the roles of both members and points the project to its new owner
+// and organization in a single transaction.
+func (pr *ProjectRepository) TransferOwnership(ctx context.Context, project *domain.Project, previousOwner *domain.ProjectMember, newOwner *domain.ProjectMember) error {
+	tx, err := pr.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(pr.pool)
+	qtx := q.WithTx(tx)
+
+	err = qtx.UpdateProjectOwner(ctx, queries.UpdateProjectOwnerParams{
+		UserID:         project.UserId,
+		OrganizationID: project.OrganizationId,
+		ID:             project.Id,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, member := range []*domain.ProjectMember{previousOwner, newOwner} {
+		err = qtx.UpdateProjectMemberRole(ctx, queries.UpdateProjectMemberRoleParams{
+			Role:      string(member.Role),
+			UserID:    member.UserId,
+			ProjectID: project.Id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (pr *ProjectRepository) GetMemberByUserIdAndProjectId(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.ProjectMember, error) {
 	q := queries.New(pr.pool)
 
@@ -229,3 +405,274 @@ func (pr *ProjectRepository) GetMemberByUserIdAndProjectId(ctx context.Context,
 
 	return &member, nil
 }
+
+func (pr *ProjectRepository) CreateInvitation(ctx context.Context, invitation *domain.ProjectInvitation) error {
+	q := queries.New(pr.pool)
+
+	params := queries.CreateProjectInvitationParams{
+		ProjectID: invitation.ProjectId,
+		Email:     invitation.Email,
+		Role:      string(invitation.Role),
+		TokenHash: invitation.TokenHash,
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: pgtype.Timestamptz{Time: invitation.ExpiresAt, Valid: true},
+	}
+
+	result, err := q.CreateProjectInvitation(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	invitation.Id = result.ID
+	invitation.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (pr *ProjectRepository) GetInvitationById(ctx context.Context, id uuid.UUID) (*domain.ProjectInvitation, error) {
+	q := queries.New(pr.pool)
+
+	result, err := q.GetProjectInvitationById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("invitation not found")
+		}
+		return nil, err
+	}
+
+	return projectInvitationFromQuery(result), nil
+}
+
+func (pr *ProjectRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.ProjectInvitation, error) {
+	q := queries.New(pr.pool)
+
+	result, err := q.GetProjectInvitationByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("invitation not found")
+		}
+		return nil, err
+	}
+
+	return projectInvitationFromQuery(result), nil
+}
+
+func (pr *ProjectRepository) ListPendingInvitations(ctx context.Context, projectId uuid.UUID) ([]domain.ProjectInvitation, error) {
+	q := queries.New(pr.pool)
+
+	results, err := q.ListPendingProjectInvitationsByProjectId(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]domain.ProjectInvitation, len(results))
+	for i, result := range results {
+		invitations[i] = *projectInvitationFromQuery(result)
+	}
+
+	return invitations, nil
+}
+
+func (pr *ProjectRepository) ListPendingInvitationsByEmail(ctx context.Context, email string) ([]domain.ProjectInvitation, error) {
+	q := queries.New(pr.pool)
+
+	results, err := q.ListPendingProjectInvitationsByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]domain.ProjectInvitation, len(results))
+	for i, result := range results {
+		invitations[i] = *projectInvitationFromQuery(result)
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation marks the invitation as accepted and creates the member in the same
+// transaction, member is nil when the user already belongs to the project.
+func (pr *ProjectRepository) AcceptInvitation(ctx context.Context, invitationId uuid.UUID, member *domain.ProjectMember) error {
+	tx, err := pr.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(pr.pool)
+	qtx := q.WithTx(tx)
+
+	rows, err := qtx.AcceptProjectInvitation(ctx, invitationId)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.NotFoundError("invitation not found")
+	}
+
+	if member != nil {
+		params := queries.CreateProjectMemberParams{
+			UserID:    member.UserId,
+			ProjectID: member.ProjectId,
+			Role:      string(member.Role),
+		}
+
+		id, err := qtx.CreateProjectMember(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return domain.DuplicateEntryError("member already exists")
+			}
+			return err
+		}
+
+		member.Id = id
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (pr *ProjectRepository) RevokeInvitation(ctx context.Context, invitationId uuid.UUID) error {
+	q := queries.New(pr.pool)
+
+	rows, err := q.RevokeProjectInvitation(ctx, invitationId)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.NotFoundError("invitation not found")
+	}
+
+	return nil
+}
+
+func projectInvitationFromQuery(result queries.ProjectInvitation) *domain.ProjectInvitation {
+	invitation := domain.ProjectInvitation{
+		Id:        result.ID,
+		ProjectId: result.ProjectID,
+		Email:     result.Email,
+		Role:      domain.ProjectMemberRole(result.Role),
+		TokenHash: result.TokenHash,
+		InvitedBy: result.InvitedBy,
+		ExpiresAt: result.ExpiresAt.Time,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	if result.AcceptedAt.Valid {
+		invitation.AcceptedAt = &result.AcceptedAt.Time
+	}
+	if result.RevokedAt.Valid {
+		invitation.RevokedAt = &result.RevokedAt.Time
+	}
+
+	return &invitation
+}
+
+func (pr *ProjectRepository) ListTasks(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error) {
+	q := queries.New(pr.pool)
+
+	results, err := q.ListProjectTasks(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]domain.Task, len(results))
+	for i, result := range results {
+		tasks[i] = domain.Task{
+			Id:          result.ID,
+			ProjectId:   projectId,
+			Title:       result.Title,
+			Description: result.Description,
+			Status:      domain.TaskStatus(result.Status),
+		}
+
+		if result.ParentID.Valid {
+			parentId := uuid.UUID(result.ParentID.Bytes)
+			tasks[i].ParentId = &parentId
+		}
+	}
+
+	return tasks, nil
+}
+
+func (pr *ProjectRepository) CreateTemplate(ctx context.Context, template *domain.ProjectTemplate) error {
+	q := queries.New(pr.pool)
+
+	tasks, err := json.Marshal(template.Tasks)
+	if err != nil {
+		return err
+	}
+
+	params := queries.CreateProjectTemplateParams{
+		UserID:      template.UserId,
+		Name:        template.Name,
+		Description: template.Description,
+		Tasks:       tasks,
+	}
+
+	result, err := q.CreateProjectTemplate(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	template.Id = result.ID
+	template.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (pr *ProjectRepository) GetTemplateById(ctx context.Context, id uuid.UUID) (*domain.ProjectTemplate, error) {
+	q := queries.New(pr.pool)
+
+	result, err := q.GetProjectTemplateById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("template not found")
+		}
+		return nil, err
+	}
+
+	return projectTemplateFromQuery(result)
+}
+
+func (pr *ProjectRepository) ListTemplatesByUserId(ctx context.Context, userId uuid.UUID) ([]domain.ProjectTemplate, error) {
+	q := queries.New(pr.pool)
+
+	results, err := q.ListProjectTemplatesByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]domain.ProjectTemplate, len(results))
+	for i, result := range results {
+		template, err := projectTemplateFromQuery(result)
+		if err != nil {
+			return nil, err
+		}
+		templates[i] = *template
+	}
+
+	return templates, nil
+}
+
+func (pr *ProjectRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	q := queries.New(pr.pool)
+
+	return q.DeleteProjectTemplate(ctx, id)
+}
+
+func projectTemplateFromQuery(result queries.ProjectTemplate) (*domain.ProjectTemplate, error) {
+	template := domain.ProjectTemplate{
+		Id:          result.ID,
+		UserId:      result.UserID,
+		Name:        result.Name,
+		Description: result.Description,
+		CreatedAt:   result.CreatedAt.Time,
+	}
+
+	err := json.Unmarshal(result.Tasks, &template.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -33,6 +36,10 @@ func (tr *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 		AuthorID:    task.AuthorId,
 	}
 
+	if task.ParentId != nil {
+		params.ParentID = pgtype.UUID{Bytes: *task.ParentId, Valid: true}
+	}
+
 	id, err := q.CreateTask(ctx, params)
 	if err != nil {
 		return err
@@ -65,11 +72,17 @@ func (tr *TaskRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Ta
 		UpdatedAt:   result.TaskUpdatedAt.Time,
 	}
 
+	if result.TaskParentID.Valid {
+		parentId := uuid.UUID(result.TaskParentID.Bytes)
+		task.ParentId = &parentId
+	}
+
 	if result.TaskAuthorName.Valid {
 		task.Author = &domain.User{
 			Id:        result.TaskAuthorID,
 			Name:      result.TaskAuthorName.String,
 			Email:     result.TaskAuthorEmail.String,
+			AvatarUrl: result.TaskAuthorAvatarUrl.String,
 			CreatedAt: result.TaskAuthorCreatedAt.Time,
 		}
 	}
@@ -88,10 +101,38 @@ func (tr *TaskRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Ta
 	return &task, nil
 }
 
-func (tr *TaskRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error) {
+func (tr *TaskRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID, filter domain.TaskListFilter, params utils.PaginationAfterParams) ([]domain.Task, error) {
 	q := queries.New(tr.pool)
 
-	results, err := q.ListTasksByProjectId(ctx, projectId)
+	queryParams := queries.ListTasksByProjectIdParams{
+		ProjectID: projectId,
+		SortBy:    string(filter.SortBy),
+		SortDesc:  filter.SortDesc,
+		PageLimit: params.Limit,
+	}
+
+	if filter.Status != nil {
+		queryParams.Status = pgtype.Text{String: string(*filter.Status), Valid: true}
+	}
+
+	if filter.AuthorId != nil {
+		queryParams.AuthorID = pgtype.UUID{Bytes: *filter.AuthorId, Valid: true}
+	}
+
+	if filter.UpdatedSince != nil {
+		queryParams.UpdatedSince = pgtype.Timestamptz{Time: *filter.UpdatedSince, Valid: true}
+	}
+
+	if filter.Search != "" {
+		queryParams.Search = pgtype.Text{String: "%" + escapeLikePattern(filter.Search) + "%", Valid: true}
+	}
+
+	if !params.After.IsZero() {
+		queryParams.After = pgtype.Timestamptz{Time: params.After, Valid: true}
+		queryParams.AfterID = pgtype.UUID{Bytes: params.Id, Valid: true}
+	}
+
+	results, err := q.ListTasksByProjectId(ctx, queryParams)
 	if err != nil {
 		return nil, err
 	}
@@ -108,12 +149,20 @@ func (tr *TaskRepository) ListByProjectId(ctx context.Context, projectId uuid.UU
 			Status:      domain.TaskStatus(result.Status),
 			CreatedAt:   result.CreatedAt.Time,
 			UpdatedAt:   result.UpdatedAt.Time,
+
+			CommentCount: int(result.CommentCount),
+		}
+
+		if result.ParentID.Valid {
+			parentId := uuid.UUID(result.ParentID.Bytes)
+			task.ParentId = &parentId
 		}
 
 		if result.AuthorAuthorID.Valid {
 			user := domain.User{
-				Id:   result.AuthorID,
-				Name: result.AuthorName.String,
+				Id:        result.AuthorID,
+				Name:      result.AuthorName.String,
+				AvatarUrl: result.AuthorAvatarUrl.String,
 			}
 
 			task.Author = &user
@@ -152,7 +201,7 @@ func (tr *TaskRepository) CreateChanges(ctx context.Context, task *domain.Task,
 		params := queries.CreateTaskChangeParams{
 			TaskID:      task.Id,
 			UserID:      pgtype.UUID{Bytes: change.AuthorId, Valid: true},
-			Description: change.ChangeDescription,
+			Description: change.Description,
 		}
 
 		id, err := qtx.CreateTaskChange(ctx, params)
@@ -165,3 +214,322 @@ func (tr *TaskRepository) CreateChanges(ctx context.Context, task *domain.Task,
 
 	return tx.Commit(ctx)
 }
+
+func (tr *TaskRepository) CreateComment(ctx context.Context, comment *domain.TaskComment) error {
+	q := queries.New(tr.pool)
+
+	params := queries.CreateTaskCommentParams{
+		TaskID:  comment.TaskId,
+		UserID:  comment.AuthorId,
+		Content: comment.Content,
+	}
+
+	id, err := q.CreateTaskComment(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	comment.Id = id
+
+	return nil
+}
+
+func (tr *TaskRepository) GetCommentById(ctx context.Context, id uuid.UUID) (*domain.TaskComment, error) {
+	q := queries.New(tr.pool)
+
+	result, err := q.GetTaskCommentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("comment not found")
+		}
+		return nil, err
+	}
+
+	comment := domain.TaskComment{
+		Id:        result.ID,
+		TaskId:    result.TaskID,
+		ProjectId: result.ProjectID,
+		AuthorId:  result.UserID,
+		Content:   result.Content,
+		CreatedAt: result.CreatedAt.Time,
+		UpdatedAt: result.UpdatedAt.Time,
+	}
+
+	if result.AuthorName.Valid {
+		comment.Author = &domain.User{
+			Id:        result.UserID,
+			Name:      result.AuthorName.String,
+			AvatarUrl: result.AuthorAvatarUrl.String,
+		}
+	}
+
+	return &comment, nil
+}
+
+func (tr *TaskRepository) ListComments(ctx context.Context, taskId uuid.UUID) ([]domain.TaskComment, error) {
+	q := queries.New(tr.pool)
+
+	results, err := q.ListTaskCommentsByTaskId(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []domain.TaskComment{}
+	for _, result := range results {
+		comment := domain.TaskComment{
+			Id:        result.ID,
+			TaskId:    result.TaskID,
+			ProjectId: result.ProjectID,
+			AuthorId:  result.UserID,
+			Content:   result.Content,
+			CreatedAt: result.CreatedAt.Time,
+			UpdatedAt: result.UpdatedAt.Time,
+		}
+
+		if result.AuthorName.Valid {
+			comment.Author = &domain.User{
+				Id:        result.UserID,
+				Name:      result.AuthorName.String,
+				AvatarUrl: result.AuthorAvatarUrl.String,
+			}
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+func (tr *TaskRepository) UpdateComment(ctx context.Context, comment *domain.TaskComment) error {
+	q := queries.New(tr.pool)
+
+	params := queries.UpdateTaskCommentParams{
+		Content: comment.Content,
+		ID:      comment.Id,
+	}
+
+	return q.UpdateTaskComment(ctx, params)
+}
+
+func (tr *TaskRepository) DeleteComment(ctx context.Context, id uuid.UUID) error {
+	q := queries.New(tr.pool)
+
+	return q.DeleteTaskComment(ctx, id)
+}
+
+func (tr *TaskRepository) UpdateParent(ctx context.Context, task *domain.Task) error {
+	q := queries.New(tr.pool)
+
+	params := queries.UpdateTaskParentParams{
+		ID: task.Id,
+	}
+
+	if task.ParentId != nil {
+		params.ParentID = pgtype.UUID{Bytes: *task.ParentId, Valid: true}
+	}
+
+	return q.UpdateTaskParent(ctx, params)
+}
+
+func (tr *TaskRepository) ListSubtasks(ctx context.Context, parentId uuid.UUID) ([]domain.Task, error) {
+	q := queries.New(tr.pool)
+
+	results, err := q.ListSubtasksByParentId(ctx, pgtype.UUID{Bytes: parentId, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks := []domain.Task{}
+	for _, result := range results {
+		subtask := domain.Task{
+			Id:          result.ID,
+			ProjectId:   result.ProjectID,
+			ParentId:    &parentId,
+			AuthorId:    result.AuthorID,
+			Title:       result.Title,
+			Description: result.Description,
+			Status:      domain.TaskStatus(result.Status),
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.UpdatedAt.Time,
+		}
+
+		subtasks = append(subtasks, subtask)
+	}
+
+	return subtasks, nil
+}
+
+// ListAncestorIds returns the id of the given task followed by the ids of all of its parents.
+func (tr *TaskRepository) ListAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	q := queries.New(tr.pool)
+
+	return q.ListTaskAncestorIds(ctx, id)
+}
+
+// CreateDependency checks that the new "blocked by" edge does not close a cycle and inserts it
+// in the same transaction. Dependency changes of a project are serialized with an advisory lock
+// so two concurrent edges cannot each pass the check and form a cycle together. It reports
+// whether the edge was inserted, adding an edge that already exists is a no-op.
+func (tr *TaskRepository) CreateDependency(ctx context.Context, projectId uuid.UUID, taskId uuid.UUID, blockedByTaskId uuid.UUID) (bool, error) {
+	tx, err := tr.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(tr.pool)
+	qtx := q.WithTx(tx)
+
+	err = qtx.LockProjectTaskDependencies(ctx, projectId.String())
+	if err != nil {
+		return false, err
+	}
+
+	blockerIds, err := qtx.ListTaskTransitiveBlockerIds(ctx, blockedByTaskId)
+	if err != nil {
+		return false, err
+	}
+
+	if slices.Contains(blockerIds, taskId) {
+		return false, domain.BusinessValidationError("task dependency would create a cycle")
+	}
+
+	rows, err := qtx.CreateTaskDependency(ctx, queries.CreateTaskDependencyParams{
+		TaskID:          taskId,
+		BlockedByTaskID: blockedByTaskId,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, tx.Commit(ctx)
+}
+
+func (tr *TaskRepository) DeleteDependency(ctx context.Context, taskId uuid.UUID, blockedByTaskId uuid.UUID) error {
+	q := queries.New(tr.pool)
+
+	return q.DeleteTaskDependency(ctx, queries.DeleteTaskDependencyParams{
+		TaskID:          taskId,
+		BlockedByTaskID: blockedByTaskId,
+	})
+}
+
+func (tr *TaskRepository) ListBlockers(ctx context.Context, taskId uuid.UUID) ([]domain.Task, error) {
+	q := queries.New(tr.pool)
+
+	results, err := q.ListTaskBlockers(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := []domain.Task{}
+	for _, result := range results {
+		blocker := domain.Task{
+			Id:          result.ID,
+			ProjectId:   result.ProjectID,
+			AuthorId:    result.AuthorID,
+			Title:       result.Title,
+			Description: result.Description,
+			Status:      domain.TaskStatus(result.Status),
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.UpdatedAt.Time,
+		}
+
+		if result.ParentID.Valid {
+			parentId := uuid.UUID(result.ParentID.Bytes)
+			blocker.ParentId = &parentId
+		}
+
+		blockers = append(blockers, blocker)
+	}
+
+	return blockers, nil
+}
+
+func (tr *TaskRepository) CreateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error {
+	q := queries.New(tr.pool)
+
+	params := queries.CreateTaskChecklistItemParams{
+		TaskID: item.TaskId,
+		Title:  item.Title,
+		Done:   item.Done,
+	}
+
+	id, err := q.CreateTaskChecklistItem(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	item.Id = id
+
+	return nil
+}
+
+func (tr *TaskRepository) GetChecklistItemById(ctx context.Context, id uuid.UUID) (*domain.TaskChecklistItem, error) {
+	q := queries.New(tr.pool)
+
+	result, err := q.GetTaskChecklistItemById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("checklist item not found")
+		}
+		return nil, err
+	}
+
+	item := domain.TaskChecklistItem{
+		Id:        result.ID,
+		TaskId:    result.TaskID,
+		Title:     result.Title,
+		Done:      result.Done,
+		CreatedAt: result.CreatedAt.Time,
+		UpdatedAt: result.UpdatedAt.Time,
+	}
+
+	return &item, nil
+}
+
+func (tr *TaskRepository) ListChecklistItems(ctx context.Context, taskId uuid.UUID) ([]domain.TaskChecklistItem, error) {
+	q := queries.New(tr.pool)
+
+	results, err := q.ListTaskChecklistItemsByTaskId(ctx, taskId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []domain.TaskChecklistItem{}
+	for _, result := range results {
+		items = append(items, domain.TaskChecklistItem{
+			Id:        result.ID,
+			TaskId:    result.TaskID,
+			Title:     result.Title,
+			Done:      result.Done,
+			CreatedAt: result.CreatedAt.Time,
+			UpdatedAt: result.UpdatedAt.Time,
+		})
+	}
+
+	return items, nil
+}
+
+func (tr *TaskRepository) UpdateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error {
+	q := queries.New(tr.pool)
+
+	params := queries.UpdateTaskChecklistItemParams{
+		Title: item.Title,
+		Done:  item.Done,
+		ID:    item.Id,
+	}
+
+	return q.UpdateTaskChecklistItem(ctx, params)
+}
+
+func (tr *TaskRepository) DeleteChecklistItem(ctx context.Context, id uuid.UUID) error {
+	q := queries.New(tr.pool)
+
+	return q.DeleteTaskChecklistItem(ctx, id)
+}
+
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
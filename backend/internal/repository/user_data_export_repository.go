@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/queries"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserDataExportRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserDataExportRepository(pool *pgxpool.Pool) *UserDataExportRepository {
+	return &UserDataExportRepository{
+		pool: pool,
+	}
+}
+
+func (er *UserDataExportRepository) Create(ctx context.Context, export *domain.UserDataExport) error {
+	q := queries.New(er.pool)
+
+	params := queries.CreateUserDataExportParams{
+		UserID:    export.UserId,
+		ExpiresAt: pgtype.Timestamptz{Time: export.ExpiresAt, Valid: true},
+	}
+
+	result, err := q.CreateUserDataExport(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	export.Id = result.ID
+	export.Status = domain.UserDataExportStatus(result.Status)
+	export.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (er *UserDataExportRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.UserDataExport, error) {
+	q := queries.New(er.pool)
+
+	result, err := q.GetUserDataExportById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("export not found")
+		}
+		return nil, err
+	}
+
+	return userDataExportFromQuery(result), nil
+}
+
+// GetPending returns the latest export of the user that is still being built.
+func (er *UserDataExportRepository) GetPending(ctx context.Context, userId uuid.UUID) (*domain.UserDataExport, error) {
+	q := queries.New(er.pool)
+
+	result, err := q.GetPendingUserDataExport(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("export not found")
+		}
+		return nil, err
+	}
+
+	return userDataExportFromQuery(result), nil
+}
+
+func (er *UserDataExportRepository) Complete(ctx context.Context, id uuid.UUID, archive []byte) error {
+	q := queries.New(er.pool)
+
+	params := queries.CompleteUserDataExportParams{
+		Archive: archive,
+		ID:      id,
+	}
+
+	return q.CompleteUserDataExport(ctx, params)
+}
+
+func (er *UserDataExportRepository) Fail(ctx context.Context, id uuid.UUID) error {
+	q := queries.New(er.pool)
+
+	return q.FailUserDataExport(ctx, id)
+}
+
+// ListProjects returns the projects the user is a member of together with their role.
+func (er *UserDataExportRepository) ListProjects(ctx context.Context, userId uuid.UUID) ([]domain.UserDataExportProject, error) {
+	q := queries.New(er.pool)
+
+	results, err := q.ListUserDataExportProjects(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]domain.UserDataExportProject, 0, len(results))
+	for _, result := range results {
+		projects = append(projects, domain.UserDataExportProject{
+			Id:          result.ID,
+			Name:        result.Name,
+			Description: result.Description,
+			Role:        domain.ProjectMemberRole(result.Role),
+			CreatedAt:   result.CreatedAt.Time,
+		})
+	}
+
+	return projects, nil
+}
+
+// ListTasks returns the tasks authored by the user, without their relations.
+func (er *UserDataExportRepository) ListTasks(ctx context.Context, userId uuid.UUID) ([]domain.Task, error) {
+	q := queries.New(er.pool)
+
+	results, err := q.ListUserDataExportTasks(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]domain.Task, 0, len(results))
+	for _, result := range results {
+		tasks = append(tasks, domain.Task{
+			Id:          result.ID,
+			ProjectId:   result.ProjectID,
+			AuthorId:    userId,
+			Title:       result.Title,
+			Description: result.Description,
+			Status:      domain.TaskStatus(result.Status),
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.UpdatedAt.Time,
+		})
+	}
+
+	return tasks, nil
+}
+
+// ListChatMessages returns the messages sent by the user in every chat.
+func (er *UserDataExportRepository) ListChatMessages(ctx context.Context, userId uuid.UUID) ([]domain.ChatMessage, error) {
+	q := queries.New(er.pool)
+
+	results, err := q.ListUserDataExportChatMessages(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]domain.ChatMessage, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, domain.ChatMessage{
+			Id:          result.ID,
+			ChatId:      result.ChatID,
+			UserId:      &userId,
+			MessageType: domain.MessageType(result.MessageType),
+			Content:     result.Content,
+			CreatedAt:   result.CreatedAt.Time,
+			UpdatedAt:   result.UpdatedAt.Time,
+		})
+	}
+
+	return messages, nil
+}
+
+func userDataExportFromQuery(result queries.UserDataExport) *domain.UserDataExport {
+	export := domain.UserDataExport{
+		Id:        result.ID,
+		UserId:    result.UserID,
+		Status:    domain.UserDataExportStatus(result.Status),
+		Archive:   result.Archive,
+		ExpiresAt: result.ExpiresAt.Time,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	if result.CompletedAt.Valid {
+		export.CompletedAt = &result.CompletedAt.Time
+	}
+
+	return &export
+}
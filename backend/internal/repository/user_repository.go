@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/queries"
@@ -23,8 +25,16 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 	}
 }
 
+// Create creates the user together with their personal organization.
 func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
 
 	params := queries.CreateUserParams{
 		Name:     user.Name,
@@ -32,7 +42,7 @@ func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		Password: user.Password,
 	}
 
-	id, err := q.CreateUser(ctx, params)
+	id, err := qtx.CreateUser(ctx, params)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -52,7 +62,24 @@ func (ur *UserRepository) Create(ctx context.Context, user *domain.User) error {
 
 	user.Id = id
 
-	return nil
+	organization := domain.Organization{
+		UserId:   user.Id,
+		Name:     domain.PersonalOrganizationName,
+		Personal: true,
+		Members: []domain.OrganizationMember{
+			{
+				UserId: user.Id,
+				Role:   domain.OrganizationMemberRoleOwner,
+			},
+		},
+	}
+
+	err = createOrganization(ctx, qtx, &organization)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
@@ -66,15 +93,7 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*domain
 		return nil, err
 	}
 
-	user := domain.User{
-		Id:        userResult.ID,
-		Email:     userResult.Email,
-		Name:      userResult.Name,
-		Password:  userResult.Password,
-		CreatedAt: userResult.CreatedAt.Time,
-	}
-
-	return &user, nil
+	return userFromQuery(userResult), nil
 }
 
 func (ur *UserRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.User, error) {
@@ -88,15 +107,7 @@ func (ur *UserRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Us
 		return nil, err
 	}
 
-	user := domain.User{
-		Id:        userResult.ID,
-		Email:     userResult.Email,
-		Name:      userResult.Name,
-		Password:  userResult.Password,
-		CreatedAt: userResult.CreatedAt.Time,
-	}
-
-	return &user, nil
+	return userFromQuery(userResult), nil
 }
 
 func (ur *UserRepository) GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
@@ -113,26 +124,700 @@ func (ur *UserRepository) GetRefreshToken(ctx context.Context, token string) (*d
 	refreshToken := domain.RefreshToken{
 		Id:        tokenResult.ID,
 		UserId:    tokenResult.UserID,
+		SessionId: tokenResult.SessionID,
 		Token:     tokenResult.Token,
 		Active:    tokenResult.Active,
 		CreatedAt: tokenResult.CreatedAt.Time,
 		ExpiresAt: tokenResult.ExpiresAt.Time,
 	}
 
+	if tokenResult.RotatedAt.Valid {
+		refreshToken.RotatedAt = &tokenResult.RotatedAt.Time
+	}
+
 	return &refreshToken, nil
 }
 
-func (ur *UserRepository) CreateRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) error {
+// CreateSession starts a new session for the user together with its first refresh token.
+func (ur *UserRepository) CreateSession(ctx context.Context, session *domain.UserSession, refreshToken *domain.RefreshToken) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	params := queries.CreateUserSessionParams{
+		UserID:    session.UserId,
+		UserAgent: session.UserAgent,
+		IpAddress: session.IpAddress,
+	}
+
+	result, err := qtx.CreateUserSession(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	session.Id = result.ID
+	session.LastUsedAt = result.LastUsedAt.Time
+	session.CreatedAt = result.CreatedAt.Time
+
+	refreshToken.SessionId = session.Id
+
+	err = createRefreshToken(ctx, qtx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ur *UserRepository) GetSession(ctx context.Context, id uuid.UUID) (*domain.UserSession, error) {
+	q := queries.New(ur.pool)
+
+	result, err := q.GetUserSessionById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("session not found")
+		}
+		return nil, err
+	}
+
+	return sessionFromQuery(result), nil
+}
+
+// IsSessionActive reports whether the session exists, belongs to the user, was not revoked
+// and the user was not deleted.
+func (ur *UserRepository) IsSessionActive(ctx context.Context, id uuid.UUID, userId uuid.UUID) (bool, error) {
+	q := queries.New(ur.pool)
+
+	return q.IsUserSessionActive(ctx, queries.IsUserSessionActiveParams{
+		ID:     id,
+		UserID: userId,
+	})
+}
+
+// ListActiveSessions returns the sessions of the user that were not revoked and still have a
+// refresh token that can be used, most recently used first.
+func (ur *UserRepository) ListActiveSessions(ctx context.Context, userId uuid.UUID) ([]domain.UserSession, error) {
+	q := queries.New(ur.pool)
+
+	results, err := q.ListActiveUserSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.UserSession, 0, len(results))
+	for _, result := range results {
+		sessions = append(sessions, *sessionFromQuery(result))
+	}
+
+	return sessions, nil
+}
+
+// RotateRefreshToken replaces the refresh token with a new one of the same session in a
+// single transaction. An active token that was rotated in the meantime or a revoked session
+// returns a not found error, which means it is being reused. A token that is no longer
+// active is only passed within its grace period, the session gets one more token without
+// deactivating the one issued by the first rotation.
+func (ur *UserRepository) RotateRefreshToken(ctx context.Context, session *domain.UserSession, oldToken *domain.RefreshToken, newToken *domain.RefreshToken) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	if oldToken.Active {
+		rows, err := qtx.DeactivateRefreshToken(ctx, oldToken.Id)
+		if err != nil {
+			return err
+		}
+
+		if rows == 0 {
+			return domain.NotFoundError("refresh token not found")
+		}
+
+		// only the token rotated last keeps its grace period
+		err = qtx.ClearSessionRefreshTokenRotations(ctx, queries.ClearSessionRefreshTokenRotationsParams{
+			SessionID: oldToken.SessionId,
+			ID:        oldToken.Id,
+		})
+		if err != nil {
+			return err
+		}
+
+		oldToken.Active = false
+	}
+
+	newToken.SessionId = oldToken.SessionId
+
+	err = createRefreshToken(ctx, qtx, newToken)
+	if err != nil {
+		return err
+	}
+
+	params := queries.UpdateUserSessionLastUsedParams{
+		UserAgent: session.UserAgent,
+		IpAddress: session.IpAddress,
+		ID:        oldToken.SessionId,
+	}
+
+	rows, err := qtx.UpdateUserSessionLastUsed(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("session not found")
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeSession revokes the session and deactivates its refresh tokens, a session that was
+// already revoked returns a not found error.
+func (ur *UserRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	rows, err := qtx.RevokeUserSession(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("session not found")
+	}
+
+	err = qtx.DeactivateSessionRefreshTokens(ctx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ur *UserRepository) CreateToken(ctx context.Context, token *domain.UserToken) error {
+	q := queries.New(ur.pool)
+
+	params := queries.CreateUserTokenParams{
+		UserID:    token.UserId,
+		Purpose:   string(token.Purpose),
+		TokenHash: token.TokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: token.ExpiresAt, Valid: true},
+		Email:     pgtype.Text{String: token.Email, Valid: token.Email != ""},
+	}
+
+	result, err := q.CreateUserToken(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	token.Id = result.ID
+	token.CreatedAt = result.CreatedAt.Time
+
+	return nil
+}
+
+func (ur *UserRepository) GetToken(ctx context.Context, tokenHash string, purpose domain.UserTokenPurpose) (*domain.UserToken, error) {
+	q := queries.New(ur.pool)
+
+	params := queries.GetUserTokenByTokenHashParams{
+		TokenHash: tokenHash,
+		Purpose:   string(purpose),
+	}
+
+	result, err := q.GetUserTokenByTokenHash(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NotFoundError("token not found")
+		}
+		return nil, err
+	}
+
+	token := domain.UserToken{
+		Id:        result.ID,
+		UserId:    result.UserID,
+		Purpose:   domain.UserTokenPurpose(result.Purpose),
+		TokenHash: result.TokenHash,
+		ExpiresAt: result.ExpiresAt.Time,
+		Email:     result.Email.String,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	if result.UsedAt.Valid {
+		token.UsedAt = &result.UsedAt.Time
+	}
+
+	return &token, nil
+}
+
+// InvalidateTokens marks the unused tokens of the user for the purpose as used, it is called
+// before issuing a new token so only the latest one works.
+func (ur *UserRepository) InvalidateTokens(ctx context.Context, userId uuid.UUID, purpose domain.UserTokenPurpose) error {
+	q := queries.New(ur.pool)
+
+	params := queries.InvalidateUserTokensParams{
+		UserID:  userId,
+		Purpose: string(purpose),
+	}
+
+	return q.InvalidateUserTokens(ctx, params)
+}
+
+// VerifyEmail consumes the token and marks the email of the user as verified in a single
+// transaction, a token that was already used returns a not found error.
+func (ur *UserRepository) VerifyEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	err = useToken(ctx, qtx, token)
+	if err != nil {
+		return err
+	}
+
+	params := queries.UpdateUserEmailVerifiedAtParams{
+		EmailVerifiedAt: pgtype.Timestamptz{Time: verifiedAt, Valid: true},
+		ID:              token.UserId,
+	}
+
+	err = qtx.UpdateUserEmailVerifiedAt(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ResetPassword consumes the token, replaces the password and signs the user out of every
+// session in a single transaction. It returns the sessions that were revoked.
+func (ur *UserRepository) ResetPassword(ctx context.Context, token *domain.UserToken, password string) ([]uuid.UUID, error) {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	err = useToken(ctx, qtx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	params := queries.UpdateUserPasswordParams{
+		Password: password,
+		ID:       token.UserId,
+	}
+
+	err = qtx.UpdateUserPassword(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = qtx.DeactivateUserRefreshTokens(ctx, token.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIds, err := qtx.RevokeUserSessions(ctx, token.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionIds, nil
+}
+
+// UseToken consumes a token that does not change anything else, a token that was already
+// used returns a not found error.
+func (ur *UserRepository) UseToken(ctx context.Context, token *domain.UserToken) error {
+	q := queries.New(ur.pool)
+
+	return useToken(ctx, q, token)
+}
+
+func (ur *UserRepository) UpdateProfile(ctx context.Context, user *domain.User) error {
+	q := queries.New(ur.pool)
+
+	params := queries.UpdateUserProfileParams{
+		Name:      user.Name,
+		AvatarUrl: user.AvatarUrl,
+		Timezone:  user.Timezone,
+		Locale:    user.Locale,
+		ID:        user.Id,
+	}
+
+	return q.UpdateUserProfile(ctx, params)
+}
+
+// ChangeEmail consumes the token and replaces the email of the user with the verified address
+// the token was sent to, verification links sent to the previous address stop working.
+func (ur *UserRepository) ChangeEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	err = useToken(ctx, qtx, token)
+	if err != nil {
+		return err
+	}
+
+	params := queries.UpdateUserEmailParams{
+		Email:           token.Email,
+		EmailVerifiedAt: pgtype.Timestamptz{Time: verifiedAt, Valid: true},
+		ID:              token.UserId,
+	}
+
+	err = qtx.UpdateUserEmail(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "users_email_key" {
+			return domain.DuplicateEntryError("user email is already taken")
+		}
+		return err
+	}
+
+	invalidateParams := queries.InvalidateUserTokensParams{
+		UserID:  token.UserId,
+		Purpose: string(domain.UserTokenPurposeEmailVerification),
+	}
+
+	err = qtx.InvalidateUserTokens(ctx, invalidateParams)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ChangePassword replaces the password of the user, pending password reset links stop
+// working. Sessions are left alone, the caller decides which ones to revoke.
+func (ur *UserRepository) ChangePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	params := queries.UpdateUserPasswordParams{
+		Password: password,
+		ID:       userId,
+	}
+
+	err = qtx.UpdateUserPassword(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	invalidateParams := queries.InvalidateUserTokensParams{
+		UserID:  userId,
+		Purpose: string(domain.UserTokenPurposePasswordReset),
+	}
+
+	err = qtx.InvalidateUserTokens(ctx, invalidateParams)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListRoomIds returns the ids of the projects and chats the user is a member of, they are
+// the websocket rooms where the user is shown to other members.
+func (ur *UserRepository) ListRoomIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	q := queries.New(ur.pool)
+
+	ids, err := q.ListUserRoomIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if ids == nil {
+		return []uuid.UUID{}, nil
+	}
+
+	return ids, nil
+}
+
+// Delete anonymizes the user instead of removing the row, so the tasks, task history and
+// messages they authored keep pointing to it. The user loses every membership and every way
+// to sign in, and their name and email are removed from the task history, the activity feed,
+// the system messages of their chats and their personal organization. It returns the project
+// memberships the user lost. Users who still own projects or shared organizations cannot be
+// deleted, those must be handed over first.
+func (ur *UserRepository) Delete(ctx context.Context, user *domain.User, deletedAt time.Time) ([]domain.ProjectMember, error) {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	blockers, err := qtx.GetUserDeletionBlockers(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockers.OwnedProjects > 0 {
+		return nil, domain.BusinessValidationError("projects you own must be deleted or handed over before deleting your account")
+	}
+
+	if blockers.SharedOrganizations > 0 {
+		return nil, domain.BusinessValidationError("organizations you own must be handed over before deleting your account")
+	}
+
+	deletedEmail := fmt.Sprintf("deleted-%s@deleted.invalid", user.Id)
+
+	// the task history, system messages and activity feed keep user ids and read the names, so
+	// renaming the user is all they need
+	params := queries.AnonymizeUserParams{
+		Name:      domain.DeletedUserName,
+		Email:     deletedEmail,
+		DeletedAt: pgtype.Timestamptz{Time: deletedAt, Valid: true},
+		ID:        user.Id,
+	}
+
+	rows, err := qtx.AnonymizeUser(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows == 0 {
+		return nil, domain.NotFoundError("user not found")
+	}
+
+	organizationParams := queries.AnonymizeUserPersonalOrganizationParams{
+		Name:   domain.PersonalOrganizationName,
+		UserID: user.Id,
+	}
+
+	err = qtx.AnonymizeUserPersonalOrganization(ctx, organizationParams)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := qtx.DeleteUserProjectMemberships(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	projectMembers := make([]domain.ProjectMember, 0, len(results))
+	for _, result := range results {
+		projectMembers = append(projectMembers, domain.ProjectMember{
+			Id:        result.ID,
+			UserId:    result.UserID,
+			ProjectId: result.ProjectID,
+			Role:      domain.ProjectMemberRole(result.Role),
+		})
+	}
+
+	cleanups := []func(context.Context, uuid.UUID) error{
+		qtx.DeleteUserChatMemberships,
+		qtx.DeleteUserOrganizationMemberships,
+		qtx.DeactivateUserRefreshTokens,
+		qtx.RevokeUserPersonalAccessTokens,
+		qtx.DeleteUserIdentities,
+		qtx.DeleteUserRecoveryCodes,
+		qtx.InvalidateAllUserTokens,
+		qtx.DeleteUserDataExports,
+	}
+
+	for _, cleanup := range cleanups {
+		err = cleanup(ctx, user.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the connections of every session are closed by the user deleted event
+	_, err = qtx.RevokeUserSessions(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectMembers, nil
+}
+
+// SetTotpSecret starts a TOTP enrollment, it only takes effect once EnableTotp is called.
+func (ur *UserRepository) SetTotpSecret(ctx context.Context, userId uuid.UUID, secret string) error {
+	q := queries.New(ur.pool)
+
+	params := queries.UpdateUserTotpSecretParams{
+		TotpSecret: pgtype.Text{String: secret, Valid: true},
+		ID:         userId,
+	}
+
+	return q.UpdateUserTotpSecret(ctx, params)
+}
+
+// EnableTotp confirms the TOTP enrollment and replaces the recovery codes of the user in a
+// single transaction, step is the step of the code used to confirm it.
+func (ur *UserRepository) EnableTotp(ctx context.Context, userId uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	params := queries.EnableUserTotpParams{
+		TotpEnabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		TotpLastStep:  step,
+		ID:            userId,
+	}
+
+	err = qtx.EnableUserTotp(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	err = qtx.DeleteUserRecoveryCodes(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		err = qtx.CreateUserRecoveryCode(ctx, queries.CreateUserRecoveryCodeParams{
+			UserID:   userId,
+			CodeHash: codeHash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (ur *UserRepository) DisableTotp(ctx context.Context, userId uuid.UUID) error {
+	tx, err := ur.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := queries.New(ur.pool)
+	qtx := q.WithTx(tx)
+
+	err = qtx.DisableUserTotp(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	err = qtx.DeleteUserRecoveryCodes(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseTotpStep records the step of an accepted TOTP code, a step that is not newer than the
+// last one used returns a not found error so codes cannot be replayed.
+func (ur *UserRepository) UseTotpStep(ctx context.Context, userId uuid.UUID, step int64) error {
 	q := queries.New(ur.pool)
 
+	params := queries.UpdateUserTotpLastStepParams{
+		TotpLastStep: step,
+		ID:           userId,
+	}
+
+	rows, err := q.UpdateUserTotpLastStep(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("totp code already used")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode consumes a recovery code, unknown and used codes return a not found error.
+func (ur *UserRepository) UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) error {
+	q := queries.New(ur.pool)
+
+	params := queries.UseUserRecoveryCodeParams{
+		UserID:   userId,
+		CodeHash: codeHash,
+	}
+
+	rows, err := q.UseUserRecoveryCode(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("recovery code not found")
+	}
+
+	return nil
+}
+
+func useToken(ctx context.Context, q *queries.Queries, token *domain.UserToken) error {
+	rows, err := q.UseUserToken(ctx, token.Id)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NotFoundError("token not found")
+	}
+
+	return nil
+}
+
+func createRefreshToken(ctx context.Context, q *queries.Queries, refreshToken *domain.RefreshToken) error {
 	params := queries.CreateRefreshTokenParams{
 		UserID: refreshToken.UserId,
 		ExpiresAt: pgtype.Timestamptz{
 			Time:  refreshToken.ExpiresAt,
 			Valid: true,
 		},
-		Token:  refreshToken.Token,
-		Active: refreshToken.Active,
+		Token:     refreshToken.Token,
+		Active:    refreshToken.Active,
+		SessionID: refreshToken.SessionId,
 	}
 
 	tokenId, err := q.CreateRefreshToken(ctx, params)
@@ -145,14 +830,51 @@ func (ur *UserRepository) CreateRefreshToken(ctx context.Context, refreshToken *
 	return nil
 }
 
-func (ur *UserRepository) UpdateRefreshTokenActive(ctx context.Context, refreshToken *domain.RefreshToken) error {
-	q := queries.New(ur.pool)
+func sessionFromQuery(result queries.UserSession) *domain.UserSession {
+	session := domain.UserSession{
+		Id:         result.ID,
+		UserId:     result.UserID,
+		UserAgent:  result.UserAgent,
+		IpAddress:  result.IpAddress,
+		LastUsedAt: result.LastUsedAt.Time,
+		CreatedAt:  result.CreatedAt.Time,
+	}
+
+	if result.RevokedAt.Valid {
+		session.RevokedAt = &result.RevokedAt.Time
+	}
+
+	return &session
+}
+
+func userFromQuery(result queries.User) *domain.User {
+	user := domain.User{
+		Id:        result.ID,
+		Email:     result.Email,
+		Name:      result.Name,
+		AvatarUrl: result.AvatarUrl,
+		Timezone:  result.Timezone,
+		Locale:    result.Locale,
+		Password:  result.Password,
+		CreatedAt: result.CreatedAt.Time,
+	}
+
+	if result.EmailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &result.EmailVerifiedAt.Time
+	}
+
+	if result.TotpSecret.Valid {
+		user.TotpSecret = result.TotpSecret.String
+		user.TotpLastStep = result.TotpLastStep
+	}
+
+	if result.TotpEnabledAt.Valid {
+		user.TotpEnabledAt = &result.TotpEnabledAt.Time
+	}
 
-	params := queries.UpdateRefreshTokenParams{
-		Token:  refreshToken.Token,
-		Active: refreshToken.Active,
+	if result.DeletedAt.Valid {
+		user.DeletedAt = &result.DeletedAt.Time
 	}
 
-	err := q.UpdateRefreshToken(ctx, params)
-	return err
+	return &user
 }
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	maxAccessTokensPerUser = 50
+	// accessTokenLastUsedInterval throttles the last used updates, so a busy bot does not
+	// write to the database on every request.
+	accessTokenLastUsedInterval = time.Minute
+)
+
+type accessTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.PersonalAccessToken, error)
+	UpdateLastUsed(ctx context.Context, id uuid.UUID) error
+	Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error
+}
+
+type AccessTokenService struct {
+	accessTokenRepository accessTokenRepository
+}
+
+func NewAccessTokenService(accessTokenRepository accessTokenRepository) *AccessTokenService {
+	return &AccessTokenService{
+		accessTokenRepository: accessTokenRepository,
+	}
+}
+
+type CreateAccessTokenRequest struct {
+	UserId    uuid.UUID
+	Name      string
+	Scopes    []domain.AccessTokenScope
+	ExpiresIn time.Duration
+}
+
+// CreatedAccessToken carries the plain token, it is only returned when the token is created.
+type CreatedAccessToken struct {
+	domain.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+func (as *AccessTokenService) Create(ctx context.Context, request CreateAccessTokenRequest) (*CreatedAccessToken, error) {
+	if request.UserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	tokens, err := as.accessTokenRepository.ListByUserId(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) >= maxAccessTokensPerUser {
+		return nil, domain.BusinessValidationError("access token limit reached, revoke unused tokens first")
+	}
+
+	secret, err := GenerateRefreshToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	plainToken := domain.AccessTokenPrefix + secret
+
+	token := domain.PersonalAccessToken{
+		UserId:    request.UserId,
+		Name:      request.Name,
+		TokenHash: hashToken(plainToken),
+		Scopes:    request.Scopes,
+		ExpiresAt: time.Now().Add(request.ExpiresIn),
+	}
+
+	err = as.accessTokenRepository.Create(ctx, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedAccessToken{PersonalAccessToken: token, Token: plainToken}, nil
+}
+
+func (as *AccessTokenService) List(ctx context.Context, userId uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	return as.accessTokenRepository.ListByUserId(ctx, userId)
+}
+
+func (as *AccessTokenService) Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	if userId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	return as.accessTokenRepository.Revoke(ctx, userId, id)
+}
+
+// Authenticate resolves the token presented in the Authorization header. Unknown, revoked
+// and expired tokens are all reported the same way.
+func (as *AccessTokenService) Authenticate(ctx context.Context, plainToken string) (*domain.PersonalAccessToken, error) {
+	token, err := as.accessTokenRepository.GetByTokenHash(ctx, hashToken(plainToken))
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil, domain.UnauthorizedError("invalid token")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.IsValid(now) {
+		return nil, domain.UnauthorizedError("invalid token")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > accessTokenLastUsedInterval {
+		err = as.accessTokenRepository.UpdateLastUsed(ctx, token.Id)
+		if err != nil {
+			return nil, err
+		}
+		token.LastUsedAt = &now
+	}
+
+	return token, nil
+}
@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *mockAccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockAccessTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PersonalAccessToken), args.Error(1)
+}
+
+func (m *mockAccessTokenRepository) ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PersonalAccessToken), args.Error(1)
+}
+
+func (m *mockAccessTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockAccessTokenRepository) Revoke(ctx context.Context, userId uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, userId, id)
+	return args.Error(0)
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAccessTokenService_Create(t *testing.T) {
+	userId := uuid.New()
+	scopes := []domain.AccessTokenScope{domain.AccessTokenScopeTasksWrite, domain.AccessTokenScopeChatWrite}
+
+	type testCase struct {
+		name              string
+		request           service.CreateAccessTokenRequest
+		mockSetup         func(*mockAccessTokenRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "creates token",
+			request: service.CreateAccessTokenRequest{
+				UserId:    userId,
+				Name:      "ci bot",
+				Scopes:    scopes,
+				ExpiresIn: 30 * 24 * time.Hour,
+			},
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				repo.On("ListByUserId", mock.Anything, userId).Return([]domain.PersonalAccessToken{}, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.PersonalAccessToken")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "anonymous user",
+			request: service.CreateAccessTokenRequest{
+				Name:      "ci bot",
+				Scopes:    scopes,
+				ExpiresIn: time.Hour,
+			},
+			mockSetup:         func(repo *mockAccessTokenRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name: "token limit reached",
+			request: service.CreateAccessTokenRequest{
+				UserId:    userId,
+				Name:      "ci bot",
+				Scopes:    scopes,
+				ExpiresIn: time.Hour,
+			},
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				repo.On("ListByUserId", mock.Anything, userId).Return(make([]domain.PersonalAccessToken, 50), nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockAccessTokenRepository{}
+			tt.mockSetup(mockRepo)
+
+			accessTokenService := service.NewAccessTokenService(mockRepo)
+
+			token, err := accessTokenService.Create(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(token.Token, domain.AccessTokenPrefix))
+				assert.Equal(t, hashAccessToken(token.Token), token.TokenHash)
+				assert.Equal(t, tt.request.Scopes, token.Scopes)
+				assert.WithinDuration(t, time.Now().Add(tt.request.ExpiresIn), token.ExpiresAt, time.Minute)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessTokenService_Authenticate(t *testing.T) {
+	plainToken := domain.AccessTokenPrefix + "secret"
+	tokenHash := hashAccessToken(plainToken)
+
+	recentlyUsed := time.Now().Add(-10 * time.Second)
+	revokedAt := time.Now().Add(-time.Hour)
+
+	type testCase struct {
+		name              string
+		mockSetup         func(*mockAccessTokenRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "valid token updates last used",
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				token := &domain.PersonalAccessToken{Id: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+				repo.On("GetByTokenHash", mock.Anything, tokenHash).Return(token, nil)
+				repo.On("UpdateLastUsed", mock.Anything, token.Id).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "recently used token skips last used update",
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				token := &domain.PersonalAccessToken{Id: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: &recentlyUsed}
+				repo.On("GetByTokenHash", mock.Anything, tokenHash).Return(token, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				repo.On("GetByTokenHash", mock.Anything, tokenHash).Return(nil, domain.NotFoundError("access token not found"))
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name: "expired token",
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				token := &domain.PersonalAccessToken{Id: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}
+				repo.On("GetByTokenHash", mock.Anything, tokenHash).Return(token, nil)
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name: "revoked token",
+			mockSetup: func(repo *mockAccessTokenRepository) {
+				token := &domain.PersonalAccessToken{Id: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+				repo.On("GetByTokenHash", mock.Anything, tokenHash).Return(token, nil)
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockAccessTokenRepository{}
+			tt.mockSetup(mockRepo)
+
+			accessTokenService := service.NewAccessTokenService(mockRepo)
+
+			token, err := accessTokenService.Authenticate(context.Background(), plainToken)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.NotNil(t, token.LastUsedAt)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessTokenService_AuthenticateRepositoryError(t *testing.T) {
+	mockRepo := &mockAccessTokenRepository{}
+	mockRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+	accessTokenService := service.NewAccessTokenService(mockRepo)
+
+	_, err := accessTokenService.Authenticate(context.Background(), domain.AccessTokenPrefix+"secret")
+	require.Error(t, err)
+
+	var domainErr domain.DomainError
+	assert.False(t, errors.As(err, &domainErr), "infrastructure errors are not reported as invalid tokens")
+}
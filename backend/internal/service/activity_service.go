@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/google/uuid"
+)
+
+type activityRepository interface {
+	Create(ctx context.Context, activity *domain.ProjectActivity) error
+	ListByProjectId(ctx context.Context, projectId uuid.UUID, params utils.PaginationBeforeParams) ([]domain.ProjectActivity, error)
+}
+
+type activityProjectRepository interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Project, error)
+}
+
+type activityChatRepository interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Chat, error)
+}
+
+type ActivityService struct {
+	activityRepository activityRepository
+	projectRepository  activityProjectRepository
+	chatRepository     activityChatRepository
+}
+
+func NewActivityService(activityRepository activityRepository, projectRepository activityProjectRepository, chatRepository activityChatRepository) *ActivityService {
+	return &ActivityService{
+		activityRepository: activityRepository,
+		projectRepository:  projectRepository,
+		chatRepository:     chatRepository,
+	}
+}
+
+// CreateFromEvent records the event in the activity feed of its project. It returns nil
+// without an error for topics that are not part of the feed: deleted projects take their
+// feed with them, chat messages already live in the chat and views are not activity. The
+// actor is the user whose request published the event, when it is uuid.Nil it falls back to
+// the user the payload names, if any.
+func (as *ActivityService) CreateFromEvent(ctx context.Context, topic events.Topic, payload []byte, actorId uuid.UUID) (*domain.ProjectActivity, error) {
+	var projectId uuid.UUID
+	var payloadActorId *uuid.UUID
+	var payloadUser *domain.User
+	var activityPayload domain.ProjectActivityPayload
+
+	switch topic {
+	case events.ProjectCreated, events.ProjectUpdated, events.ProjectArchived:
+		var project domain.Project
+		err := json.Unmarshal(payload, &project)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal project", err)
+		}
+		projectId = project.Id
+		activityPayload.ProjectName = project.Name
+		if topic == events.ProjectCreated {
+			payloadActorId = &project.UserId
+		}
+	case events.ProjectMemberCreated, events.ProjectMemberRemoved, events.ProjectMemberUpdated:
+		var member domain.ProjectMember
+		err := json.Unmarshal(payload, &member)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal project member", err)
+		}
+		projectId = member.ProjectId
+		activityPayload.UserId = &member.UserId
+		activityPayload.Role = string(member.Role)
+		if member.User != nil {
+			payloadUser = &domain.User{Id: member.User.Id, Name: member.User.Name}
+		}
+	case events.ProjectOwnershipTransferred:
+		var transfer domain.ProjectOwnershipTransfer
+		err := json.Unmarshal(payload, &transfer)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal project ownership transfer", err)
+		}
+		projectId = transfer.ProjectId
+		payloadActorId = &transfer.PreviousOwnerId
+		activityPayload.PreviousOwnerId = &transfer.PreviousOwnerId
+		activityPayload.NewOwnerId = &transfer.NewOwnerId
+	case events.ChatMemberCreated:
+		var member domain.ChatMember
+		err := json.Unmarshal(payload, &member)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal chat member", err)
+		}
+		chat, err := as.chatRepository.GetById(ctx, member.ChatId)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) {
+				return nil, domainErr
+			}
+			return nil, domain.ServerError("failed to get chat", err)
+		}
+		projectId = chat.ProjectId
+		payloadActorId = &member.UserId
+		activityPayload.UserId = &member.UserId
+	case events.TaskCreated, events.TaskUpdated:
+		var task domain.Task
+		err := json.Unmarshal(payload, &task)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal task", err)
+		}
+		projectId = task.ProjectId
+		if topic == events.TaskCreated {
+			payloadActorId = &task.AuthorId
+		}
+		activityPayload.TaskId = &task.Id
+		activityPayload.TaskTitle = task.Title
+		activityPayload.TaskStatus = task.Status
+	case events.TaskCommentCreated:
+		var comment domain.TaskComment
+		err := json.Unmarshal(payload, &comment)
+		if err != nil {
+			return nil, domain.ServerError("failed to unmarshal task comment", err)
+		}
+		projectId = comment.ProjectId
+		payloadActorId = &comment.AuthorId
+		activityPayload.TaskId = &comment.TaskId
+		activityPayload.CommentId = &comment.Id
+	default:
+		return nil, nil
+	}
+
+	if actorId != uuid.Nil {
+		payloadActorId = &actorId
+	}
+
+	activityPayloadBytes, err := json.Marshal(activityPayload)
+	if err != nil {
+		return nil, domain.ServerError("failed to marshal activity payload", err)
+	}
+
+	activity := domain.ProjectActivity{
+		ProjectId: projectId,
+		ActorId:   payloadActorId,
+		Type:      topic.String(),
+		Payload:   activityPayloadBytes,
+		User:      payloadUser,
+	}
+
+	err = as.activityRepository.Create(ctx, &activity)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to create activity", err)
+	}
+
+	return &activity, nil
+}
+
+type ListActivitiesByProjectIdRequest struct {
+	ProjectId uuid.UUID
+	UserId    uuid.UUID
+	Params    utils.PaginationBeforeParams
+}
+
+// ListByProjectId lists the activity feed of a project from the newest entry.
+func (as *ActivityService) ListByProjectId(ctx context.Context, request ListActivitiesByProjectIdRequest) (*utils.CursorPaginated[domain.ProjectActivity], error) {
+	if request.UserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := as.projectRepository.GetById(ctx, request.ProjectId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get project", err)
+	}
+
+	_, err = project.Authorize(request.UserId, domain.PermissionProjectView)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := as.activityRepository.ListByProjectId(ctx, project.Id, request.Params)
+	if err != nil {
+		return nil, domain.ServerError("failed to list activities", err)
+	}
+
+	cursorPaginated := utils.CursorPaginated[domain.ProjectActivity]{
+		Data:    activities,
+		HasNext: len(activities) >= int(request.Params.Limit),
+	}
+
+	return &cursorPaginated, nil
+}
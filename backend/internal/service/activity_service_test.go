@@ -0,0 +1,266 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockActivityRepository struct {
+	mock.Mock
+}
+
+func (m *mockActivityRepository) Create(ctx context.Context, activity *domain.ProjectActivity) error {
+	args := m.Called(ctx, activity)
+	return args.Error(0)
+}
+
+func (m *mockActivityRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID, params utils.PaginationBeforeParams) ([]domain.ProjectActivity, error) {
+	args := m.Called(ctx, projectId, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProjectActivity), args.Error(1)
+}
+
+type mockActivityChatRepository struct {
+	mock.Mock
+}
+
+func (m *mockActivityChatRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Chat, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Chat), args.Error(1)
+}
+
+func TestActivityService_CreateFromEvent(t *testing.T) {
+	projectId := uuid.New()
+	userId := uuid.New()
+	chatId := uuid.New()
+
+	mustMarshal := func(v any) []byte {
+		bytes, err := json.Marshal(v)
+		require.NoError(t, err)
+		return bytes
+	}
+
+	type testCase struct {
+		name              string
+		topic             events.Topic
+		payload           []byte
+		actorId           uuid.UUID
+		mockSetup         func(*mockActivityRepository, *mockActivityChatRepository)
+		expectedActorId   *uuid.UUID
+		expectedPayload   *domain.ProjectActivityPayload
+		expectedUser      *domain.User
+		expectedErrorCode string
+		shouldRecord      bool
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:    "task created is attributed to its author",
+			topic:   events.TaskCreated,
+			payload: mustMarshal(domain.Task{Id: uuid.New(), ProjectId: projectId, AuthorId: userId}),
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(nil)
+			},
+			expectedActorId: &userId,
+			shouldRecord:    true,
+			shouldSucceed:   true,
+		},
+		{
+			name:    "project updated without actor header has no actor",
+			topic:   events.ProjectUpdated,
+			payload: mustMarshal(domain.Project{Id: projectId, UserId: userId}),
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(nil)
+			},
+			shouldRecord:  true,
+			shouldSucceed: true,
+		},
+		{
+			name:    "project updated is attributed to the actor of the event",
+			topic:   events.ProjectUpdated,
+			payload: mustMarshal(domain.Project{Id: projectId, UserId: uuid.New(), Name: "Project"}),
+			actorId: userId,
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(nil)
+			},
+			expectedActorId: &userId,
+			expectedPayload: &domain.ProjectActivityPayload{ProjectName: "Project"},
+			shouldRecord:    true,
+			shouldSucceed:   true,
+		},
+		{
+			name:  "project member keeps only the id of the user",
+			topic: events.ProjectMemberCreated,
+			payload: mustMarshal(domain.ProjectMember{
+				ProjectId: projectId,
+				UserId:    userId,
+				Role:      domain.ProjectMemberRoleMember,
+				User:      &domain.User{Id: userId, Name: "Member", Email: "member@example.com"},
+			}),
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(nil)
+			},
+			expectedPayload: &domain.ProjectActivityPayload{
+				UserId: &userId,
+				Role:   string(domain.ProjectMemberRoleMember),
+			},
+			expectedUser:  &domain.User{Id: userId, Name: "Member"},
+			shouldRecord:  true,
+			shouldSucceed: true,
+		},
+		{
+			name:    "chat member created is recorded on the chat project",
+			topic:   events.ChatMemberCreated,
+			payload: mustMarshal(domain.ChatMember{ChatId: chatId, UserId: userId}),
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				chatRepo.On("GetById", mock.Anything, chatId).Return(&domain.Chat{Id: chatId, ProjectId: projectId}, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(nil)
+			},
+			expectedActorId: &userId,
+			shouldRecord:    true,
+			shouldSucceed:   true,
+		},
+		{
+			name:          "chat messages are not recorded",
+			topic:         events.ChatMessageCreated,
+			payload:       mustMarshal(domain.ChatMessage{ChatId: chatId}),
+			mockSetup:     func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {},
+			shouldSucceed: true,
+		},
+		{
+			name:          "deleted projects are not recorded",
+			topic:         events.ProjectDeleted,
+			payload:       mustMarshal(domain.DeletedProject{Project: domain.Project{Id: projectId}}),
+			mockSetup:     func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {},
+			shouldSucceed: true,
+		},
+		{
+			name:    "project no longer exists",
+			topic:   events.TaskUpdated,
+			payload: mustMarshal(domain.Task{Id: uuid.New(), ProjectId: projectId}),
+			mockSetup: func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ProjectActivity")).Return(domain.NotFoundError("project not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name:              "invalid payload",
+			topic:             events.TaskCommentCreated,
+			payload:           []byte("{"),
+			mockSetup:         func(repo *mockActivityRepository, chatRepo *mockActivityChatRepository) {},
+			expectedErrorCode: string(domain.ServerErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockActivityRepository{}
+			mockChatRepo := &mockActivityChatRepository{}
+			tt.mockSetup(mockRepo, mockChatRepo)
+
+			activityService := service.NewActivityService(mockRepo, &mockProjectRepository{}, mockChatRepo)
+
+			activity, err := activityService.CreateFromEvent(context.Background(), tt.topic, tt.payload, tt.actorId)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				if tt.shouldRecord {
+					require.NotNil(t, activity)
+					assert.Equal(t, projectId, activity.ProjectId)
+					assert.Equal(t, tt.topic.String(), activity.Type)
+					assert.Equal(t, tt.expectedActorId, activity.ActorId)
+					assert.NotContains(t, string(activity.Payload), "@")
+					assert.Equal(t, tt.expectedUser, activity.User)
+					if tt.expectedPayload != nil {
+						assert.JSONEq(t, string(mustMarshal(tt.expectedPayload)), string(activity.Payload))
+					}
+				} else {
+					assert.Nil(t, activity)
+					mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				}
+			} else {
+				require.Error(t, err)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockChatRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActivityService_ListByProjectId(t *testing.T) {
+	projectId := uuid.New()
+	memberUserId := uuid.New()
+
+	project := &domain.Project{
+		Id: projectId,
+		Members: []domain.ProjectMember{
+			{UserId: memberUserId, Role: domain.ProjectMemberRoleViewer},
+		},
+	}
+
+	params := utils.PaginationBeforeParams{Limit: 2}
+
+	t.Run("member lists activity", func(t *testing.T) {
+		mockProjectRepo := &mockProjectRepository{}
+		mockProjectRepo.On("GetById", mock.Anything, projectId).Return(project, nil)
+
+		mockRepo := &mockActivityRepository{}
+		mockRepo.On("ListByProjectId", mock.Anything, projectId, params).Return([]domain.ProjectActivity{{ProjectId: projectId}, {ProjectId: projectId}}, nil)
+
+		activityService := service.NewActivityService(mockRepo, mockProjectRepo, &mockActivityChatRepository{})
+
+		result, err := activityService.ListByProjectId(context.Background(), service.ListActivitiesByProjectIdRequest{
+			ProjectId: projectId,
+			UserId:    memberUserId,
+			Params:    params,
+		})
+		require.NoError(t, err)
+		assert.Len(t, result.Data, 2)
+		assert.True(t, result.HasNext)
+	})
+
+	t.Run("non member cannot list activity", func(t *testing.T) {
+		mockProjectRepo := &mockProjectRepository{}
+		mockProjectRepo.On("GetById", mock.Anything, projectId).Return(project, nil)
+
+		mockRepo := &mockActivityRepository{}
+
+		activityService := service.NewActivityService(mockRepo, mockProjectRepo, &mockActivityChatRepository{})
+
+		_, err := activityService.ListByProjectId(context.Background(), service.ListActivitiesByProjectIdRequest{
+			ProjectId: projectId,
+			UserId:    uuid.New(),
+			Params:    params,
+		})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.ForbiddenErrorCode, domainErr.Code)
+		}
+
+		mockRepo.AssertNotCalled(t, "ListByProjectId", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
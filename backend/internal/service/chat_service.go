@@ -17,12 +17,17 @@ type chatRepository interface {
 	Create(ctx context.Context, chat *domain.Chat) error
 	GetByProjectId(ctx context.Context, projectId uuid.UUID) (*domain.Chat, error)
 	CreateMember(ctx context.Context, member *domain.ChatMember) error
+	RemoveMember(ctx context.Context, chatId uuid.UUID, userId uuid.UUID) error
 	CreateMessage(ctx context.Context, message *domain.ChatMessage) error
 	UpdateMemberLastSeenAt(ctx context.Context, member *domain.ChatMember) error
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Chat, error)
 	ListMessages(ctx context.Context, chatId uuid.UUID, params utils.PaginationBeforeParams) ([]domain.ChatMessage, error)
 }
 
+type chatProjectRepository interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Project, error)
+}
+
 type chatUserRepository interface {
 	GetById(ctx context.Context, id uuid.UUID) (*domain.User, error)
 }
@@ -32,16 +37,18 @@ type publisher interface {
 }
 
 type ChatService struct {
-	chatRepository chatRepository
-	userRepository chatUserRepository
-	publisher      publisher
+	chatRepository    chatRepository
+	projectRepository chatProjectRepository
+	userRepository    chatUserRepository
+	publisher         publisher
 }
 
-func NewChatService(chatRepository chatRepository, userRepository chatUserRepository, publisher publisher) *ChatService {
+func NewChatService(chatRepository chatRepository, projectRepository chatProjectRepository, userRepository chatUserRepository, publisher publisher) *ChatService {
 	return &ChatService{
-		chatRepository: chatRepository,
-		userRepository: userRepository,
-		publisher:      publisher,
+		chatRepository:    chatRepository,
+		projectRepository: projectRepository,
+		userRepository:    userRepository,
+		publisher:         publisher,
 	}
 }
 
@@ -113,9 +120,10 @@ func (cs *ChatService) CreateJoinedMessage(ctx context.Context, chatMember *doma
 		ChatId:      chatMember.ChatId,
 		MessageType: domain.MessageTypeSystem,
 		UserId:      nil,
-		Content:     fmt.Sprintf("%s has joined the chat", user.Name),
+		Content:     domain.SystemMessageJoined,
 		CreatedAt:   chatMember.JoinedAt,
 		UpdatedAt:   chatMember.JoinedAt,
+		SubjectId:   &user.Id,
 	}
 
 	err = cs.chatRepository.CreateMessage(ctx, &message)
@@ -123,6 +131,83 @@ func (cs *ChatService) CreateJoinedMessage(ctx context.Context, chatMember *doma
 		return domain.ServerError("failed to create joined message", err)
 	}
 
+	message.RenderNames("", user.Name)
+
+	err = cs.publisher.Publish(ctx, events.ChatMessageCreated, message)
+	if err != nil {
+		return domain.ServerError("failed to create publisher event", err)
+	}
+
+	return nil
+}
+
+// CreateArchivedMessage lets the chat know its project was archived and returns the chat so
+// its room can be closed.
+func (cs *ChatService) CreateArchivedMessage(ctx context.Context, project *domain.Project) (*domain.Chat, error) {
+	chat, err := cs.chatRepository.GetByProjectId(ctx, project.Id)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, err
+		}
+		return nil, domain.ServerError("failed to get chat", err)
+	}
+
+	message := domain.ChatMessage{
+		ChatId:      chat.Id,
+		MessageType: domain.MessageTypeSystem,
+		UserId:      nil,
+		Content:     "This project was archived",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err = cs.chatRepository.CreateMessage(ctx, &message)
+	if err != nil {
+		return nil, domain.ServerError("failed to create archived message", err)
+	}
+
+	return chat, nil
+}
+
+func (cs *ChatService) CreateOwnershipTransferredMessage(ctx context.Context, transfer *domain.ProjectOwnershipTransfer) error {
+	chat, err := cs.chatRepository.GetByProjectId(ctx, transfer.ProjectId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return err
+		}
+		return domain.ServerError("failed to get chat", err)
+	}
+
+	previousOwner, err := cs.userRepository.GetById(ctx, transfer.PreviousOwnerId)
+	if err != nil {
+		return domain.ServerError("failed to get user", err)
+	}
+
+	newOwner, err := cs.userRepository.GetById(ctx, transfer.NewOwnerId)
+	if err != nil {
+		return domain.ServerError("failed to get user", err)
+	}
+
+	message := domain.ChatMessage{
+		ChatId:      chat.Id,
+		MessageType: domain.MessageTypeSystem,
+		UserId:      nil,
+		Content:     domain.SystemMessageOwnershipTransferred,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		ActorId:     &previousOwner.Id,
+		SubjectId:   &newOwner.Id,
+	}
+
+	err = cs.chatRepository.CreateMessage(ctx, &message)
+	if err != nil {
+		return domain.ServerError("failed to create ownership transferred message", err)
+	}
+
+	message.RenderNames(previousOwner.Name, newOwner.Name)
+
 	err = cs.publisher.Publish(ctx, events.ChatMessageCreated, message)
 	if err != nil {
 		return domain.ServerError("failed to create publisher event", err)
@@ -131,6 +216,53 @@ func (cs *ChatService) CreateJoinedMessage(ctx context.Context, chatMember *doma
 	return nil
 }
 
+// RemoveMemberFromProjectMember removes the chat membership of a user that is no longer part
+// of the project and returns the chat they were removed from.
+func (cs *ChatService) RemoveMemberFromProjectMember(ctx context.Context, projectMember *domain.ProjectMember) (*domain.Chat, error) {
+	chat, err := cs.chatRepository.GetByProjectId(ctx, projectMember.ProjectId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, err
+		}
+		return nil, domain.ServerError("failed to get chat", err)
+	}
+
+	err = cs.chatRepository.RemoveMember(ctx, chat.Id, projectMember.UserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to remove member", err)
+	}
+
+	user, err := cs.userRepository.GetById(ctx, projectMember.UserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	message := domain.ChatMessage{
+		ChatId:      chat.Id,
+		MessageType: domain.MessageTypeSystem,
+		UserId:      nil,
+		Content:     domain.SystemMessageLeft,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		SubjectId:   &user.Id,
+	}
+
+	err = cs.chatRepository.CreateMessage(ctx, &message)
+	if err != nil {
+		return nil, domain.ServerError("failed to create left message", err)
+	}
+
+	message.RenderNames("", user.Name)
+
+	err = cs.publisher.Publish(ctx, events.ChatMessageCreated, message)
+	if err != nil {
+		return nil, domain.ServerError("failed to create publisher event", err)
+	}
+
+	return chat, nil
+}
+
 type CreateChatMessageRequest struct {
 	ChatId  uuid.UUID
 	UserId  uuid.UUID
@@ -154,18 +286,18 @@ func (cs *ChatService) CreateMessage(ctx context.Context, request CreateChatMess
 		return nil, domain.ServerError("failed to get chat", err)
 	}
 
+	err = cs.authorize(ctx, chat.ProjectId, request.UserId, domain.PermissionChatWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	var foundMember *domain.ChatMember
-	hasPermission := false
 	for _, member := range chat.Members {
 		if member.UserId == request.UserId {
-			hasPermission = true
 			foundMember = &member
 			break
 		}
 	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
-	}
 
 	message := domain.ChatMessage{
 		MessageType: domain.MessageTypeText,
@@ -207,15 +339,9 @@ func (cs *ChatService) GetByProjectId(ctx context.Context, projectId uuid.UUID,
 		return nil, domain.ServerError("failed to get chat", err)
 	}
 
-	hasPermission := false
-	for _, member := range chat.Members {
-		if member.UserId == userId {
-			hasPermission = true
-			break
-		}
-	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	err = cs.authorize(ctx, chat.ProjectId, userId, domain.PermissionChatView)
+	if err != nil {
+		return nil, err
 	}
 
 	return chat, nil
@@ -237,15 +363,9 @@ func (cs *ChatService) ListMessagesByProjectId(ctx context.Context, request List
 		return nil, domain.ServerError("failed to get chat", err)
 	}
 
-	hasPermission := false
-	for _, member := range chat.Members {
-		if member.UserId == request.UserId {
-			hasPermission = true
-			break
-		}
-	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	err = cs.authorize(ctx, chat.ProjectId, request.UserId, domain.PermissionChatView)
+	if err != nil {
+		return nil, err
 	}
 
 	chat.Messages, err = cs.chatRepository.ListMessages(ctx, chat.Id, request.Params)
@@ -273,15 +393,9 @@ func (cs *ChatService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UU
 		return nil, domain.ServerError("failed to get chat", err)
 	}
 
-	hasPermission := false
-	for _, member := range chat.Members {
-		if member.UserId == userId {
-			hasPermission = true
-			break
-		}
-	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	err = cs.authorize(ctx, chat.ProjectId, userId, domain.PermissionChatView)
+	if err != nil {
+		return nil, err
 	}
 
 	return chat, nil
@@ -305,3 +419,21 @@ func (cs *ChatService) UpdateMemberLastSeenAt(ctx context.Context, userId uuid.U
 
 	return nil
 }
+
+// authorize checks the chat permissions of a user through their role in the chat's project.
+func (cs *ChatService) authorize(ctx context.Context, projectId uuid.UUID, userId uuid.UUID, permission domain.Permission) error {
+	project, err := cs.projectRepository.GetById(ctx, projectId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return domain.NotFoundError("project not found")
+			}
+			return domainErr
+		}
+		return domain.ServerError("failed to get project", err)
+	}
+
+	_, err = project.Authorize(userId, permission)
+	return err
+}
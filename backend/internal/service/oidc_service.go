@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/logger"
+	"github.com/gabrielnakaema/project-chat/internal/oidc"
+	"github.com/google/uuid"
+)
+
+const oidcLoginStateDuration = 10 * time.Minute
+
+type oidcProvider interface {
+	Enabled() bool
+	Issuer() string
+	AuthorizationURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*oidc.Claims, error)
+}
+
+type oidcRepository interface {
+	CreateLoginState(ctx context.Context, state *domain.OidcLoginState) error
+	UseLoginState(ctx context.Context, stateHash string) (*domain.OidcLoginState, error)
+	GetIdentity(ctx context.Context, issuer string, subject string) (*domain.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity, emailVerifiedAt *time.Time) error
+}
+
+type oidcUserRepository interface {
+	Create(ctx context.Context, user *domain.User) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+type loginStarter interface {
+	StartLogin(ctx context.Context, user *domain.User, userAgent string, ipAddress string) (*LoginResult, error)
+}
+
+// OidcService signs users in through the configured OpenID Connect provider, accounts are
+// matched by the provider identity first and by verified email the first time.
+type OidcService struct {
+	provider           oidcProvider
+	oidcRepository     oidcRepository
+	userRepository     oidcUserRepository
+	invitationAccepter invitationAccepter
+	loginStarter       loginStarter
+}
+
+func NewOidcService(provider oidcProvider, oidcRepository oidcRepository, userRepository oidcUserRepository, invitationAccepter invitationAccepter, loginStarter loginStarter) *OidcService {
+	return &OidcService{
+		provider:           provider,
+		oidcRepository:     oidcRepository,
+		userRepository:     userRepository,
+		invitationAccepter: invitationAccepter,
+		loginStarter:       loginStarter,
+	}
+}
+
+// OidcAuthorization is a started login, the state has to be kept by the browser that is sent
+// to the authorization URL and given back when the login is finished.
+type OidcAuthorization struct {
+	AuthorizationURL string
+	State            string
+}
+
+// Authorize starts a login and returns the provider URL the browser has to be sent to.
+func (oidcs *OidcService) Authorize(ctx context.Context) (*OidcAuthorization, error) {
+	if !oidcs.provider.Enabled() {
+		return nil, domain.NotFoundError("single sign-on is not configured")
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, domain.ServerError("failed to generate state", err)
+	}
+
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, domain.ServerError("failed to generate nonce", err)
+	}
+
+	codeVerifier, err := oidc.RandomString()
+	if err != nil {
+		return nil, domain.ServerError("failed to generate code verifier", err)
+	}
+
+	loginState := domain.OidcLoginState{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(oidcLoginStateDuration),
+	}
+
+	err = oidcs.oidcRepository.CreateLoginState(ctx, &loginState)
+	if err != nil {
+		return nil, domain.ServerError("failed to save login state", err)
+	}
+
+	authorizationURL, err := oidcs.provider.AuthorizationURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, domain.ServerError("failed to build authorization url", err)
+	}
+
+	authorization := OidcAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+	}
+
+	return &authorization, nil
+}
+
+type OidcLoginRequest struct {
+	Code  string
+	State string
+	// BrowserState is the state kept by the browser that started the login, it has to match
+	// State so a login started in another browser cannot be finished in this one.
+	BrowserState string
+	UserAgent    string
+	IpAddress    string
+}
+
+// Login finishes the login with the code the provider sent back, it issues the same tokens
+// as a password login.
+func (oidcs *OidcService) Login(ctx context.Context, request OidcLoginRequest) (*LoginResult, error) {
+	if !oidcs.provider.Enabled() {
+		return nil, domain.NotFoundError("single sign-on is not configured")
+	}
+
+	if request.BrowserState == "" || subtle.ConstantTimeCompare([]byte(request.State), []byte(request.BrowserState)) != 1 {
+		return nil, domain.UnauthorizedError("invalid or expired login state")
+	}
+
+	loginState, err := oidcs.oidcRepository.UseLoginState(ctx, hashToken(request.State))
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil, domain.UnauthorizedError("invalid or expired login state")
+		}
+		return nil, domain.ServerError("failed to get login state", err)
+	}
+
+	claims, err := oidcs.provider.Exchange(ctx, request.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		logger.FromContext(ctx).Warn("single sign-on exchange failed", "error", err.Error())
+		return nil, domain.UnauthorizedError("single sign-on failed")
+	}
+
+	user, err := oidcs.resolveUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcs.loginStarter.StartLogin(ctx, user, request.UserAgent, request.IpAddress)
+}
+
+// resolveUser returns the user linked to the identity. Unknown identities are linked to the
+// account with the same email, or get a new account, but only when the provider verified
+// the email, otherwise anyone could claim an existing account. Accounts whose email was
+// never verified are not linked either, whoever registered them could still sign in with
+// the password next to the owner of the email.
+func (oidcs *OidcService) resolveUser(ctx context.Context, claims *oidc.Claims) (*domain.User, error) {
+	identity, err := oidcs.oidcRepository.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := oidcs.userRepository.GetById(ctx, identity.UserId)
+		if err != nil {
+			return nil, domain.ServerError("failed to get user", err)
+		}
+		return user, nil
+	}
+
+	var domainErr domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+		return nil, domain.ServerError("failed to get identity", err)
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ForbiddenError("the identity provider did not verify the email")
+	}
+
+	email := claims.Email
+
+	provisioned := false
+
+	user, err := oidcs.userRepository.GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+			return nil, domain.ServerError("failed to get user", err)
+		}
+
+		user, err = oidcs.provisionUser(ctx, email, claims.Name)
+		if err != nil {
+			return nil, err
+		}
+		provisioned = true
+	} else if !user.IsEmailVerified() {
+		return nil, domain.ForbiddenError("verify the email of your account before signing in with single sign-on")
+	}
+
+	identity = &domain.UserIdentity{
+		UserId:  user.Id,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+		Email:   email,
+	}
+
+	// only the accounts provisioned above are not verified yet
+	var emailVerifiedAt *time.Time
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		emailVerifiedAt = &now
+		user.EmailVerifiedAt = &now
+	}
+
+	err = oidcs.oidcRepository.CreateIdentity(ctx, identity, emailVerifiedAt)
+	if err != nil {
+		return nil, domain.ServerError("failed to link identity", err)
+	}
+
+	// invitations are only accepted for verified emails, which the new account has only
+	// once the identity is linked
+	if provisioned {
+		err = oidcs.invitationAccepter.AcceptPendingInvitations(ctx, user)
+		if err != nil {
+			return nil, domain.ServerError("failed to accept pending invitations", err)
+		}
+	}
+
+	return user, nil
+}
+
+// provisionUser creates the account of a first time single sign-on user. The password is
+// random, the user can still set one through the password reset.
+func (oidcs *OidcService) provisionUser(ctx context.Context, email string, name string) (*domain.User, error) {
+	if strings.TrimSpace(name) == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	password, err := GenerateRefreshToken(32)
+	if err != nil {
+		return nil, domain.ServerError("failed to generate password", err)
+	}
+
+	hashed, err := HashPassword(password)
+	if err != nil {
+		return nil, domain.ServerError("failed to hash password", err)
+	}
+
+	user := domain.User{
+		Name:      name,
+		Email:     email,
+		Password:  hashed,
+		CreatedAt: time.Now(),
+	}
+
+	err = oidcs.userRepository.Create(ctx, &user)
+	if err != nil {
+		return nil, domain.ServerError("failed to create user", err)
+	}
+
+	return &user, nil
+}
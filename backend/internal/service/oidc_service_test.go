@@ -0,0 +1,315 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/oidc"
+	"github.com/gabrielnakaema/project-chat/internal/oidc/oidctest"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOidcRepository struct {
+	mock.Mock
+	state *domain.OidcLoginState
+}
+
+func (m *mockOidcRepository) CreateLoginState(ctx context.Context, state *domain.OidcLoginState) error {
+	m.state = state
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *mockOidcRepository) UseLoginState(ctx context.Context, stateHash string) (*domain.OidcLoginState, error) {
+	args := m.Called(ctx, stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OidcLoginState), args.Error(1)
+}
+
+func (m *mockOidcRepository) GetIdentity(ctx context.Context, issuer string, subject string) (*domain.UserIdentity, error) {
+	args := m.Called(ctx, issuer, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserIdentity), args.Error(1)
+}
+
+func (m *mockOidcRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity, emailVerifiedAt *time.Time) error {
+	args := m.Called(ctx, identity, emailVerifiedAt)
+	return args.Error(0)
+}
+
+type mockLoginStarter struct {
+	mock.Mock
+}
+
+func (m *mockLoginStarter) StartLogin(ctx context.Context, user *domain.User, userAgent string, ipAddress string) (*service.LoginResult, error) {
+	args := m.Called(ctx, user, userAgent, ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.LoginResult), args.Error(1)
+}
+
+func TestOidcService_Login(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{})
+	defer mockProvider.Close()
+
+	issuer := mockProvider.Issuer()
+	existingUser := &domain.User{Id: uuid.New(), Email: "employee@example.com", Name: "Employee"}
+	verifiedAt := time.Now().Add(-time.Hour)
+	verifiedUser := &domain.User{Id: uuid.New(), Email: "verified@example.com", Name: "Verified", EmailVerifiedAt: &verifiedAt}
+
+	type testCase struct {
+		name              string
+		identity          oidctest.Identity
+		mockSetup         func(*mockOidcRepository, *mockUserRepository, *mockInvitationAccepter, *mockLoginStarter)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:     "known identity signs in its user",
+			identity: oidctest.Identity{Subject: "employee-1", Email: existingUser.Email, EmailVerified: true},
+			mockSetup: func(oidcRepo *mockOidcRepository, userRepo *mockUserRepository, accepter *mockInvitationAccepter, starter *mockLoginStarter) {
+				oidcRepo.On("GetIdentity", mock.Anything, issuer, "employee-1").Return(&domain.UserIdentity{UserId: existingUser.Id}, nil)
+				userRepo.On("GetById", mock.Anything, existingUser.Id).Return(existingUser, nil)
+				starter.On("StartLogin", mock.Anything, existingUser, "test-agent", "192.0.2.1").Return(&service.LoginResult{AccessToken: "access-token", User: existingUser}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:     "unverified account is not linked",
+			identity: oidctest.Identity{Subject: "employee-1", Email: existingUser.Email, EmailVerified: true},
+			mockSetup: func(oidcRepo *mockOidcRepository, userRepo *mockUserRepository, accepter *mockInvitationAccepter, starter *mockLoginStarter) {
+				oidcRepo.On("GetIdentity", mock.Anything, issuer, "employee-1").Return(nil, domain.NotFoundError("identity not found"))
+				userRepo.On("GetByEmail", mock.Anything, existingUser.Email).Return(existingUser, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name:     "already verified user is linked without changing the verification",
+			identity: oidctest.Identity{Subject: "employee-2", Email: verifiedUser.Email, EmailVerified: true},
+			mockSetup: func(oidcRepo *mockOidcRepository, userRepo *mockUserRepository, accepter *mockInvitationAccepter, starter *mockLoginStarter) {
+				oidcRepo.On("GetIdentity", mock.Anything, issuer, "employee-2").Return(nil, domain.NotFoundError("identity not found"))
+				userRepo.On("GetByEmail", mock.Anything, verifiedUser.Email).Return(verifiedUser, nil)
+				oidcRepo.On("CreateIdentity", mock.Anything, mock.AnythingOfType("*domain.UserIdentity"), (*time.Time)(nil)).Return(nil)
+				starter.On("StartLogin", mock.Anything, verifiedUser, "test-agent", "192.0.2.1").Return(&service.LoginResult{AccessToken: "access-token", User: verifiedUser}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:     "new verified email provisions a user",
+			identity: oidctest.Identity{Subject: "employee-3", Email: "new@example.com", EmailVerified: true, Name: "New Employee"},
+			mockSetup: func(oidcRepo *mockOidcRepository, userRepo *mockUserRepository, accepter *mockInvitationAccepter, starter *mockLoginStarter) {
+				oidcRepo.On("GetIdentity", mock.Anything, issuer, "employee-3").Return(nil, domain.NotFoundError("identity not found"))
+				userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, domain.NotFoundError("user not found"))
+				userRepo.On("Create", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+					return user.Email == "new@example.com" && user.Name == "New Employee" && user.Password != ""
+				})).Return(nil)
+				oidcRepo.On("CreateIdentity", mock.Anything, mock.AnythingOfType("*domain.UserIdentity"), mock.AnythingOfType("*time.Time")).Return(nil)
+				accepter.On("AcceptPendingInvitations", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+					return user.IsEmailVerified()
+				})).Return(nil)
+				starter.On("StartLogin", mock.Anything, mock.AnythingOfType("*domain.User"), "test-agent", "192.0.2.1").Return(&service.LoginResult{AccessToken: "access-token"}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:     "unverified email is refused",
+			identity: oidctest.Identity{Subject: "employee-4", Email: existingUser.Email, EmailVerified: false},
+			mockSetup: func(oidcRepo *mockOidcRepository, userRepo *mockUserRepository, accepter *mockInvitationAccepter, starter *mockLoginStarter) {
+				oidcRepo.On("GetIdentity", mock.Anything, issuer, "employee-4").Return(nil, domain.NotFoundError("identity not found"))
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider.SetIdentity(tt.identity)
+
+			mockOidcRepo := &mockOidcRepository{}
+			mockUserRepo := &mockUserRepository{}
+			mockAccepter := &mockInvitationAccepter{}
+			mockStarter := &mockLoginStarter{}
+
+			mockOidcRepo.On("CreateLoginState", mock.Anything, mock.AnythingOfType("*domain.OidcLoginState")).Return(nil)
+			tt.mockSetup(mockOidcRepo, mockUserRepo, mockAccepter, mockStarter)
+
+			provider := oidc.NewProvider(&config.Config{
+				OidcIssuer:       issuer,
+				OidcClientId:     oidctest.ClientId,
+				OidcClientSecret: oidctest.ClientSecret,
+				OidcRedirectURL:  "http://localhost:3000/auth/oidc/callback",
+				OidcScopes:       []string{"openid", "email"},
+			})
+
+			oidcService := service.NewOidcService(provider, mockOidcRepo, mockUserRepo, mockAccepter, mockStarter)
+
+			authorization, err := oidcService.Authorize(context.Background())
+			require.NoError(t, err)
+
+			code, state, err := mockProvider.Authorize(authorization.AuthorizationURL)
+			require.NoError(t, err)
+			assert.NotEqual(t, state, mockOidcRepo.state.StateHash, "only the hash of the state is stored")
+			mockOidcRepo.On("UseLoginState", mock.Anything, mockOidcRepo.state.StateHash).Return(mockOidcRepo.state, nil)
+
+			result, err := oidcService.Login(context.Background(), service.OidcLoginRequest{
+				Code:         code,
+				State:        state,
+				BrowserState: authorization.State,
+				UserAgent:    "test-agent",
+				IpAddress:    "192.0.2.1",
+			})
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, "access-token", result.AccessToken)
+			} else {
+				require.Error(t, err)
+				mockStarter.AssertNotCalled(t, "StartLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockOidcRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockAccepter.AssertExpectations(t)
+			mockStarter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOidcService_LoginJoinsInvitedProjects(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{Subject: "employee-5", Email: "invited@example.com", EmailVerified: true, Name: "Invited"})
+	defer mockProvider.Close()
+
+	project := &domain.Project{Id: uuid.New(), Name: "Project"}
+	invitation := domain.ProjectInvitation{
+		Id:        uuid.New(),
+		ProjectId: project.Id,
+		Email:     "invited@example.com",
+		Role:      domain.ProjectMemberRoleMember,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockOidcRepo := &mockOidcRepository{}
+	mockOidcRepo.On("CreateLoginState", mock.Anything, mock.AnythingOfType("*domain.OidcLoginState")).Return(nil)
+	mockOidcRepo.On("GetIdentity", mock.Anything, mockProvider.Issuer(), "employee-5").Return(nil, domain.NotFoundError("identity not found"))
+	mockOidcRepo.On("CreateIdentity", mock.Anything, mock.AnythingOfType("*domain.UserIdentity"), mock.AnythingOfType("*time.Time")).Return(nil)
+
+	mockUserRepo := &mockUserRepository{}
+	mockUserRepo.On("GetByEmail", mock.Anything, "invited@example.com").Return(nil, domain.NotFoundError("user not found"))
+	mockUserRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+
+	mockProjectRepo := &mockProjectRepository{}
+	mockProjectRepo.On("ListPendingInvitationsByEmail", mock.Anything, "invited@example.com").Return([]domain.ProjectInvitation{invitation}, nil)
+	mockProjectRepo.On("GetById", mock.Anything, project.Id).Return(project, nil)
+	mockProjectRepo.On("AcceptInvitation", mock.Anything, invitation.Id, mock.MatchedBy(func(member *domain.ProjectMember) bool {
+		return member.ProjectId == project.Id && member.Role == domain.ProjectMemberRoleMember
+	})).Return(nil)
+
+	mockStarter := &mockLoginStarter{}
+	mockStarter.On("StartLogin", mock.Anything, mock.AnythingOfType("*domain.User"), "test-agent", "192.0.2.1").Return(&service.LoginResult{AccessToken: "access-token"}, nil)
+
+	projectService := service.NewProjectService(mockProjectRepo, mockUserRepo, &mockPublisher{}, &mockOrganizationRepository{})
+
+	provider := oidc.NewProvider(&config.Config{
+		OidcIssuer:       mockProvider.Issuer(),
+		OidcClientId:     oidctest.ClientId,
+		OidcClientSecret: oidctest.ClientSecret,
+		OidcRedirectURL:  "http://localhost:3000/auth/oidc/callback",
+		OidcScopes:       []string{"openid", "email"},
+	})
+
+	oidcService := service.NewOidcService(provider, mockOidcRepo, mockUserRepo, projectService, mockStarter)
+
+	authorization, err := oidcService.Authorize(context.Background())
+	require.NoError(t, err)
+
+	code, state, err := mockProvider.Authorize(authorization.AuthorizationURL)
+	require.NoError(t, err)
+	mockOidcRepo.On("UseLoginState", mock.Anything, mockOidcRepo.state.StateHash).Return(mockOidcRepo.state, nil)
+
+	_, err = oidcService.Login(context.Background(), service.OidcLoginRequest{
+		Code:         code,
+		State:        state,
+		BrowserState: authorization.State,
+		UserAgent:    "test-agent",
+		IpAddress:    "192.0.2.1",
+	})
+	require.NoError(t, err)
+
+	mockProjectRepo.AssertExpectations(t)
+}
+
+func TestOidcService_LoginRejectsUnknownState(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{Subject: "employee-1"})
+	defer mockProvider.Close()
+
+	provider := oidc.NewProvider(&config.Config{OidcIssuer: mockProvider.Issuer(), OidcClientId: oidctest.ClientId})
+
+	mockOidcRepo := &mockOidcRepository{}
+	mockOidcRepo.On("UseLoginState", mock.Anything, mock.Anything).Return(nil, domain.NotFoundError("login state not found"))
+
+	oidcService := service.NewOidcService(provider, mockOidcRepo, &mockUserRepository{}, &mockInvitationAccepter{}, &mockLoginStarter{})
+
+	_, err := oidcService.Login(context.Background(), service.OidcLoginRequest{Code: "code", State: "forged-state", BrowserState: "forged-state"})
+	require.Error(t, err)
+
+	var domainErr domain.DomainError
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, domain.UnauthorizedErrorCode, domainErr.Code)
+	}
+}
+
+func TestOidcService_LoginRejectsStateOfAnotherBrowser(t *testing.T) {
+	mockProvider := oidctest.NewProvider(oidctest.Identity{Subject: "employee-1"})
+	defer mockProvider.Close()
+
+	provider := oidc.NewProvider(&config.Config{OidcIssuer: mockProvider.Issuer(), OidcClientId: oidctest.ClientId})
+
+	mockOidcRepo := &mockOidcRepository{}
+
+	oidcService := service.NewOidcService(provider, mockOidcRepo, &mockUserRepository{}, &mockInvitationAccepter{}, &mockLoginStarter{})
+
+	for _, browserState := range []string{"", "state-of-this-browser"} {
+		_, err := oidcService.Login(context.Background(), service.OidcLoginRequest{Code: "code", State: "state-of-the-victim", BrowserState: browserState})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.UnauthorizedErrorCode, domainErr.Code)
+		}
+	}
+
+	mockOidcRepo.AssertNotCalled(t, "UseLoginState", mock.Anything, mock.Anything)
+}
+
+func TestOidcService_NotConfigured(t *testing.T) {
+	provider := oidc.NewProvider(&config.Config{})
+
+	oidcService := service.NewOidcService(provider, &mockOidcRepository{}, &mockUserRepository{}, &mockInvitationAccepter{}, &mockLoginStarter{})
+
+	_, err := oidcService.Authorize(context.Background())
+	require.Error(t, err)
+
+	var domainErr domain.DomainError
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, domain.NotFoundErrorCode, domainErr.Code)
+	}
+}
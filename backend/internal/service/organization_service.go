@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/google/uuid"
+)
+
+type organizationRepository interface {
+	Create(ctx context.Context, organization *domain.Organization) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.Organization, error)
+	ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.Organization, error)
+	Update(ctx context.Context, organization *domain.Organization) error
+	CreateMember(ctx context.Context, member *domain.OrganizationMember) error
+	GetMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationId uuid.UUID, search string, limit int) ([]domain.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, member *domain.OrganizationMember) error
+	RemoveMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) error
+}
+
+type organizationServiceUserRepository interface {
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+type OrganizationService struct {
+	organizationRepository organizationRepository
+	userRepository         organizationServiceUserRepository
+}
+
+func NewOrganizationService(organizationRepository organizationRepository, userRepository organizationServiceUserRepository) *OrganizationService {
+	return &OrganizationService{
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+	}
+}
+
+type CreateOrganizationRequest struct {
+	Name   string
+	UserId uuid.UUID
+}
+
+func (ors *OrganizationService) Create(ctx context.Context, request CreateOrganizationRequest) (*domain.Organization, error) {
+	if request.UserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	organization := domain.Organization{
+		UserId: request.UserId,
+		Name:   request.Name,
+		Members: []domain.OrganizationMember{
+			{
+				UserId: request.UserId,
+				Role:   domain.OrganizationMemberRoleOwner,
+			},
+		},
+	}
+
+	err := ors.organizationRepository.Create(ctx, &organization)
+	if err != nil {
+		return nil, domain.ServerError("failed to create organization", err)
+	}
+
+	return &organization, nil
+}
+
+func (ors *OrganizationService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Organization, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	organization, _, err := ors.getOrganization(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func (ors *OrganizationService) ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.Organization, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	organizations, err := ors.organizationRepository.ListByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ServerError("failed to list organizations", err)
+	}
+
+	return organizations, nil
+}
+
+type UpdateOrganizationRequest struct {
+	OrganizationId uuid.UUID
+	Name           string
+	RequestUserId  uuid.UUID
+}
+
+func (ors *OrganizationService) Update(ctx context.Context, request UpdateOrganizationRequest) (*domain.Organization, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	organization, requestMember, err := ors.getOrganization(ctx, request.OrganizationId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestMember.Role == domain.OrganizationMemberRoleMember {
+		return nil, domain.ForbiddenError("forbidden")
+	}
+
+	organization.Name = request.Name
+
+	err = ors.organizationRepository.Update(ctx, organization)
+	if err != nil {
+		return nil, domain.ServerError("failed to update organization", err)
+	}
+
+	return organization, nil
+}
+
+type CreateOrganizationMemberRequest struct {
+	OrganizationId uuid.UUID
+	Email          string
+	Role           domain.OrganizationMemberRole
+	RequestUserId  uuid.UUID
+}
+
+func (ors *OrganizationService) CreateMember(ctx context.Context, request CreateOrganizationMemberRequest) (*domain.OrganizationMember, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	organization, requestMember, err := ors.getOrganization(ctx, request.OrganizationId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if organization.Personal {
+		return nil, domain.BusinessValidationError("personal organizations cannot have other members")
+	}
+
+	role := request.Role
+	if role == "" {
+		role = domain.OrganizationMemberRoleMember
+	}
+
+	if !requestMember.Role.CanManage(role) {
+		return nil, domain.ForbiddenError("you cannot add a member with this role")
+	}
+
+	user, err := ors.userRepository.GetByEmail(ctx, request.Email)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("user not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	member := domain.OrganizationMember{
+		OrganizationId: organization.Id,
+		UserId:         user.Id,
+		User:           user,
+		Role:           role,
+	}
+
+	err = ors.organizationRepository.CreateMember(ctx, &member)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to create organization member", err)
+	}
+
+	return &member, nil
+}
+
+type ListOrganizationMembersRequest struct {
+	OrganizationId uuid.UUID
+	Search         string
+	Limit          int
+	RequestUserId  uuid.UUID
+}
+
+// ListMembers is the organization member directory, with a search term it is used to
+// autocomplete people when adding project members.
+func (ors *OrganizationService) ListMembers(ctx context.Context, request ListOrganizationMembersRequest) ([]domain.OrganizationMember, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	_, _, err := ors.getOrganization(ctx, request.OrganizationId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := ors.organizationRepository.ListMembers(ctx, request.OrganizationId, request.Search, request.Limit)
+	if err != nil {
+		return nil, domain.ServerError("failed to list organization members", err)
+	}
+
+	return members, nil
+}
+
+type UpdateOrganizationMemberRoleRequest struct {
+	OrganizationId uuid.UUID
+	UserId         uuid.UUID
+	Role           domain.OrganizationMemberRole
+	RequestUserId  uuid.UUID
+}
+
+func (ors *OrganizationService) UpdateMemberRole(ctx context.Context, request UpdateOrganizationMemberRoleRequest) (*domain.OrganizationMember, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	_, requestMember, err := ors.getOrganization(ctx, request.OrganizationId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := ors.getMember(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requestMember.Role.CanManage(member.Role) || !requestMember.Role.CanManage(request.Role) {
+		return nil, domain.ForbiddenError("you cannot change the role of this member")
+	}
+
+	member.Role = request.Role
+
+	err = ors.organizationRepository.UpdateMemberRole(ctx, member)
+	if err != nil {
+		return nil, domain.ServerError("failed to update organization member role", err)
+	}
+
+	return member, nil
+}
+
+type RemoveOrganizationMemberRequest struct {
+	OrganizationId uuid.UUID
+	UserId         uuid.UUID
+	RequestUserId  uuid.UUID
+}
+
+// RemoveMember removes a member from the organization, anyone but the owner can remove
+// themselves. Project memberships are kept, only the organization directory changes.
+func (ors *OrganizationService) RemoveMember(ctx context.Context, request RemoveOrganizationMemberRequest) error {
+	if request.RequestUserId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	_, requestMember, err := ors.getOrganization(ctx, request.OrganizationId, request.RequestUserId)
+	if err != nil {
+		return err
+	}
+
+	member, err := ors.getMember(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == domain.OrganizationMemberRoleOwner {
+		return domain.BusinessValidationError("the owner cannot be removed from the organization")
+	}
+
+	if member.UserId != request.RequestUserId && !requestMember.Role.CanManage(member.Role) {
+		return domain.ForbiddenError("you cannot remove this member")
+	}
+
+	err = ors.organizationRepository.RemoveMember(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return domain.ServerError("failed to remove organization member", err)
+	}
+
+	return nil
+}
+
+// getOrganization loads the organization and the membership of the user, non members get
+// a not found error so the organization is not leaked.
+func (ors *OrganizationService) getOrganization(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Organization, *domain.OrganizationMember, error) {
+	organization, err := ors.organizationRepository.GetById(ctx, id)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, nil, domainErr
+		}
+		return nil, nil, domain.ServerError("failed to get organization", err)
+	}
+
+	member, err := ors.organizationRepository.GetMember(ctx, id, userId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, nil, domain.NotFoundError("organization not found")
+			}
+			return nil, nil, domainErr
+		}
+		return nil, nil, domain.ServerError("failed to get organization member", err)
+	}
+
+	return organization, member, nil
+}
+
+func (ors *OrganizationService) getMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error) {
+	member, err := ors.organizationRepository.GetMember(ctx, organizationId, userId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get organization member", err)
+	}
+
+	return member, nil
+}
@@ -0,0 +1,308 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *mockOrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	args := m.Called(ctx, organization)
+	return args.Error(0)
+}
+
+func (m *mockOrganizationRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *mockOrganizationRepository) GetPersonalByUserId(ctx context.Context, userId uuid.UUID) (*domain.Organization, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *mockOrganizationRepository) ListByUserId(ctx context.Context, userId uuid.UUID) ([]domain.Organization, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Organization), args.Error(1)
+}
+
+func (m *mockOrganizationRepository) Update(ctx context.Context, organization *domain.Organization) error {
+	args := m.Called(ctx, organization)
+	return args.Error(0)
+}
+
+func (m *mockOrganizationRepository) CreateMember(ctx context.Context, member *domain.OrganizationMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *mockOrganizationRepository) GetMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error) {
+	args := m.Called(ctx, organizationId, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrganizationMember), args.Error(1)
+}
+
+func (m *mockOrganizationRepository) ListMembers(ctx context.Context, organizationId uuid.UUID, search string, limit int) ([]domain.OrganizationMember, error) {
+	args := m.Called(ctx, organizationId, search, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OrganizationMember), args.Error(1)
+}
+
+func (m *mockOrganizationRepository) UpdateMemberRole(ctx context.Context, member *domain.OrganizationMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *mockOrganizationRepository) RemoveMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) error {
+	args := m.Called(ctx, organizationId, userId)
+	return args.Error(0)
+}
+
+func TestOrganizationService_CreateMember(t *testing.T) {
+	ownerUserId := uuid.New()
+	adminUserId := uuid.New()
+	organizationId := uuid.New()
+	newUser := &domain.User{Id: uuid.New(), Email: "new@example.com"}
+
+	organization := &domain.Organization{Id: organizationId, UserId: ownerUserId, Name: "Team"}
+	personalOrganization := &domain.Organization{Id: organizationId, UserId: ownerUserId, Name: domain.PersonalOrganizationName, Personal: true}
+
+	ownerMember := &domain.OrganizationMember{OrganizationId: organizationId, UserId: ownerUserId, Role: domain.OrganizationMemberRoleOwner}
+	adminMember := &domain.OrganizationMember{OrganizationId: organizationId, UserId: adminUserId, Role: domain.OrganizationMemberRoleAdmin}
+
+	type testCase struct {
+		name              string
+		request           service.CreateOrganizationMemberRequest
+		mockSetup         func(*mockOrganizationRepository, *mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "owner adds member",
+			request: service.CreateOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				Email:          newUser.Email,
+				RequestUserId:  ownerUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, ownerUserId).Return(ownerMember, nil)
+				userRepo.On("GetByEmail", mock.Anything, newUser.Email).Return(newUser, nil)
+				repo.On("CreateMember", mock.Anything, mock.AnythingOfType("*domain.OrganizationMember")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "admin cannot add another admin",
+			request: service.CreateOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				Email:          newUser.Email,
+				Role:           domain.OrganizationMemberRoleAdmin,
+				RequestUserId:  adminUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, adminUserId).Return(adminMember, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "personal organization cannot be shared",
+			request: service.CreateOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				Email:          newUser.Email,
+				RequestUserId:  ownerUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(personalOrganization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, ownerUserId).Return(ownerMember, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "non member gets not found",
+			request: service.CreateOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				Email:          newUser.Email,
+				RequestUserId:  newUser.Id,
+			},
+			mockSetup: func(repo *mockOrganizationRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, newUser.Id).Return(nil, domain.NotFoundError("organization member not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name: "user not found",
+			request: service.CreateOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				Email:          "missing@example.com",
+				RequestUserId:  ownerUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, ownerUserId).Return(ownerMember, nil)
+				userRepo.On("GetByEmail", mock.Anything, "missing@example.com").Return(nil, domain.NotFoundError("user not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockOrganizationRepository{}
+			mockUserRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo, mockUserRepo)
+
+			organizationService := service.NewOrganizationService(mockRepo, mockUserRepo)
+
+			member, err := organizationService.CreateMember(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, newUser.Id, member.UserId)
+				assert.Equal(t, domain.OrganizationMemberRoleMember, member.Role)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "CreateMember", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	ownerUserId := uuid.New()
+	memberUserId := uuid.New()
+	otherMemberUserId := uuid.New()
+	organizationId := uuid.New()
+
+	organization := &domain.Organization{Id: organizationId, UserId: ownerUserId, Name: "Team"}
+
+	ownerMember := &domain.OrganizationMember{OrganizationId: organizationId, UserId: ownerUserId, Role: domain.OrganizationMemberRoleOwner}
+	member := &domain.OrganizationMember{OrganizationId: organizationId, UserId: memberUserId, Role: domain.OrganizationMemberRoleMember}
+	otherMember := &domain.OrganizationMember{OrganizationId: organizationId, UserId: otherMemberUserId, Role: domain.OrganizationMemberRoleMember}
+
+	type testCase struct {
+		name              string
+		request           service.RemoveOrganizationMemberRequest
+		mockSetup         func(*mockOrganizationRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "owner removes member",
+			request: service.RemoveOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				UserId:         memberUserId,
+				RequestUserId:  ownerUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, ownerUserId).Return(ownerMember, nil)
+				repo.On("GetMember", mock.Anything, organizationId, memberUserId).Return(member, nil)
+				repo.On("RemoveMember", mock.Anything, organizationId, memberUserId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "member leaves organization",
+			request: service.RemoveOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				UserId:         memberUserId,
+				RequestUserId:  memberUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, memberUserId).Return(member, nil)
+				repo.On("RemoveMember", mock.Anything, organizationId, memberUserId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "member cannot remove another member",
+			request: service.RemoveOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				UserId:         otherMemberUserId,
+				RequestUserId:  memberUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, memberUserId).Return(member, nil)
+				repo.On("GetMember", mock.Anything, organizationId, otherMemberUserId).Return(otherMember, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "owner cannot be removed",
+			request: service.RemoveOrganizationMemberRequest{
+				OrganizationId: organizationId,
+				UserId:         ownerUserId,
+				RequestUserId:  ownerUserId,
+			},
+			mockSetup: func(repo *mockOrganizationRepository) {
+				repo.On("GetById", mock.Anything, organizationId).Return(organization, nil)
+				repo.On("GetMember", mock.Anything, organizationId, ownerUserId).Return(ownerMember, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockOrganizationRepository{}
+			tt.mockSetup(mockRepo)
+
+			organizationService := service.NewOrganizationService(mockRepo, &mockUserRepository{})
+
+			err := organizationService.RemoveMember(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
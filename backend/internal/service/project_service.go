@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
@@ -12,40 +13,70 @@ import (
 
 type projectRepository interface {
 	Create(ctx context.Context, project *domain.Project) error
+	CreateWithTasks(ctx context.Context, project *domain.Project, tasks []domain.ProjectTemplateTask, author *domain.User) error
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Project, error)
-	ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string) ([]domain.Project, error)
+	ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string, includeArchived bool, organizationId uuid.UUID) ([]domain.Project, error)
 	Update(ctx context.Context, project *domain.Project) error
+	UpdateArchivedAt(ctx context.Context, project *domain.Project) error
+	Delete(ctx context.Context, projectId uuid.UUID) ([]uuid.UUID, error)
 	CreateMember(ctx context.Context, member *domain.ProjectMember) error
 	RemoveMember(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) error
+	UpdateMemberRole(ctx context.Context, member *domain.ProjectMember) error
+	TransferOwnership(ctx context.Context, project *domain.Project, previousOwner *domain.ProjectMember, newOwner *domain.ProjectMember) error
 	GetMemberByUserIdAndProjectId(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.ProjectMember, error)
+	CreateInvitation(ctx context.Context, invitation *domain.ProjectInvitation) error
+	GetInvitationById(ctx context.Context, id uuid.UUID) (*domain.ProjectInvitation, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.ProjectInvitation, error)
+	ListPendingInvitations(ctx context.Context, projectId uuid.UUID) ([]domain.ProjectInvitation, error)
+	ListPendingInvitationsByEmail(ctx context.Context, email string) ([]domain.ProjectInvitation, error)
+	AcceptInvitation(ctx context.Context, invitationId uuid.UUID, member *domain.ProjectMember) error
+	RevokeInvitation(ctx context.Context, invitationId uuid.UUID) error
+	ListTasks(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error)
+	CreateTemplate(ctx context.Context, template *domain.ProjectTemplate) error
+	GetTemplateById(ctx context.Context, id uuid.UUID) (*domain.ProjectTemplate, error)
+	ListTemplatesByUserId(ctx context.Context, userId uuid.UUID) ([]domain.ProjectTemplate, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
 }
 
 type projectServiceUserRepository interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 }
 
+type projectServiceOrganizationRepository interface {
+	GetPersonalByUserId(ctx context.Context, userId uuid.UUID) (*domain.Organization, error)
+	GetMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error)
+}
+
+const invitationDuration = 7 * 24 * time.Hour
+
 type projectServicePublisher interface {
 	Publish(ctx context.Context, topic events.Topic, payload interface{}) error
 }
 
 type ProjectService struct {
-	projectRepository projectRepository
-	userRepository    projectServiceUserRepository
-	publisher         projectServicePublisher
+	projectRepository      projectRepository
+	userRepository         projectServiceUserRepository
+	publisher              projectServicePublisher
+	organizationRepository projectServiceOrganizationRepository
 }
 
-func NewProjectService(projectRepository projectRepository, userRepository projectServiceUserRepository, publisher projectServicePublisher) *ProjectService {
+func NewProjectService(projectRepository projectRepository, userRepository projectServiceUserRepository, publisher projectServicePublisher, organizationRepository projectServiceOrganizationRepository) *ProjectService {
 	return &ProjectService{
-		projectRepository: projectRepository,
-		userRepository:    userRepository,
-		publisher:         publisher,
+		projectRepository:      projectRepository,
+		userRepository:         userRepository,
+		publisher:              publisher,
+		organizationRepository: organizationRepository,
 	}
 }
 
+// CreateProjectRequest creates the project in the given organization, when OrganizationId
+// is empty the project goes to the personal organization of the user.
 type CreateProjectRequest struct {
-	Name        string
-	Description string
-	UserId      uuid.UUID
+	Name           string
+	Description    string
+	UserId         uuid.UUID
+	OrganizationId uuid.UUID
 }
 
 func (ps *ProjectService) Create(ctx context.Context, request CreateProjectRequest) (*domain.Project, error) {
@@ -53,6 +84,11 @@ func (ps *ProjectService) Create(ctx context.Context, request CreateProjectReque
 		return nil, domain.UnauthorizedError("unauthorized")
 	}
 
+	organizationId, err := ps.resolveOrganizationId(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
 	project := domain.Project{
 		Name:        request.Name,
 		Description: request.Description,
@@ -61,13 +97,14 @@ func (ps *ProjectService) Create(ctx context.Context, request CreateProjectReque
 		Members: []domain.ProjectMember{
 			{
 				UserId: request.UserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 		},
-		UserId: request.UserId,
+		UserId:         request.UserId,
+		OrganizationId: organizationId,
 	}
 
-	err := ps.projectRepository.Create(ctx, &project)
+	err = ps.projectRepository.Create(ctx, &project)
 	if err != nil {
 		return nil, domain.ServerError("failed to create project", err)
 	}
@@ -80,6 +117,252 @@ func (ps *ProjectService) Create(ctx context.Context, request CreateProjectReque
 	return &project, nil
 }
 
+type CreateTemplateRequest struct {
+	ProjectId     uuid.UUID
+	Name          string
+	Description   string
+	RequestUserId uuid.UUID
+}
+
+// CreateTemplate saves the tasks of a project as a template owned by the request user.
+func (ps *ProjectService) CreateTemplate(ctx context.Context, request CreateTemplateRequest) (*domain.ProjectTemplate, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	tasks, err := ps.getProjectTemplateTasks(ctx, request.ProjectId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	template := domain.ProjectTemplate{
+		UserId:      request.RequestUserId,
+		Name:        request.Name,
+		Description: request.Description,
+		Tasks:       tasks,
+	}
+
+	err = ps.projectRepository.CreateTemplate(ctx, &template)
+	if err != nil {
+		return nil, domain.ServerError("failed to create template", err)
+	}
+
+	return &template, nil
+}
+
+func (ps *ProjectService) ListTemplates(ctx context.Context, userId uuid.UUID) ([]domain.ProjectTemplate, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	templates, err := ps.projectRepository.ListTemplatesByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ServerError("failed to list templates", err)
+	}
+
+	return templates, nil
+}
+
+func (ps *ProjectService) DeleteTemplate(ctx context.Context, templateId uuid.UUID, userId uuid.UUID) error {
+	template, err := ps.getTemplate(ctx, templateId, userId)
+	if err != nil {
+		return err
+	}
+
+	err = ps.projectRepository.DeleteTemplate(ctx, template.Id)
+	if err != nil {
+		return domain.ServerError("failed to delete template", err)
+	}
+
+	return nil
+}
+
+type CreateFromTemplateRequest struct {
+	TemplateId     uuid.UUID
+	Name           string
+	Description    string
+	UserId         uuid.UUID
+	OrganizationId uuid.UUID
+}
+
+func (ps *ProjectService) CreateFromTemplate(ctx context.Context, request CreateFromTemplateRequest) (*domain.Project, error) {
+	template, err := ps.getTemplate(ctx, request.TemplateId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	organizationId, err := ps.resolveOrganizationId(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	createRequest := CreateProjectRequest{
+		Name:           request.Name,
+		Description:    request.Description,
+		UserId:         request.UserId,
+		OrganizationId: organizationId,
+	}
+
+	return ps.createWithTasks(ctx, createRequest, template.Tasks)
+}
+
+type CloneProjectRequest struct {
+	ProjectId      uuid.UUID
+	Name           string
+	Description    string
+	UserId         uuid.UUID
+	OrganizationId uuid.UUID
+}
+
+// Clone creates a new project owned by the request user with a copy of the source tasks,
+// members, chat and comments are not copied.
+func (ps *ProjectService) Clone(ctx context.Context, request CloneProjectRequest) (*domain.Project, error) {
+	if request.UserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	// the copy must be allowed in the target organization before anything is read from
+	// the source project
+	organizationId, err := ps.resolveOrganizationId(ctx, request.OrganizationId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := ps.getProjectTemplateTasks(ctx, request.ProjectId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	createRequest := CreateProjectRequest{
+		Name:           request.Name,
+		Description:    request.Description,
+		UserId:         request.UserId,
+		OrganizationId: organizationId,
+	}
+
+	return ps.createWithTasks(ctx, createRequest, tasks)
+}
+
+// createWithTasks expects the organization of the request to be already resolved with
+// resolveOrganizationId.
+func (ps *ProjectService) createWithTasks(ctx context.Context, request CreateProjectRequest, tasks []domain.ProjectTemplateTask) (*domain.Project, error) {
+	project := domain.Project{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Members: []domain.ProjectMember{
+			{
+				UserId: request.UserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+		UserId:         request.UserId,
+		OrganizationId: request.OrganizationId,
+	}
+
+	author, err := ps.userRepository.GetById(ctx, request.UserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	err = ps.projectRepository.CreateWithTasks(ctx, &project, tasks, author)
+	if err != nil {
+		return nil, domain.ServerError("failed to create project", err)
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectCreated, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project created event", err)
+	}
+
+	return &project, nil
+}
+
+func (ps *ProjectService) getProjectTemplateTasks(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) ([]domain.ProjectTemplateTask, error) {
+	project, err := ps.getProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = project.Authorize(userId, domain.PermissionProjectView)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := ps.projectRepository.ListTasks(ctx, project.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list tasks", err)
+	}
+
+	return domain.NewProjectTemplateTasks(tasks), nil
+}
+
+// resolveOrganizationId returns the organization a new project goes to, defaulting to the
+// personal organization of the user, the user must be allowed to create projects in it.
+func (ps *ProjectService) resolveOrganizationId(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (uuid.UUID, error) {
+	if organizationId == uuid.Nil {
+		organization, err := ps.organizationRepository.GetPersonalByUserId(ctx, userId)
+		if err != nil {
+			return uuid.Nil, domain.ServerError("failed to get personal organization", err)
+		}
+		return organization.Id, nil
+	}
+
+	member, err := ps.getOrganizationMember(ctx, organizationId, userId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if !member.Role.CanCreateProjects() {
+		return uuid.Nil, domain.ForbiddenError("you cannot create projects in this organization")
+	}
+
+	return organizationId, nil
+}
+
+func (ps *ProjectService) checkOrganizationMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) error {
+	_, err := ps.getOrganizationMember(ctx, organizationId, userId)
+	return err
+}
+
+func (ps *ProjectService) getOrganizationMember(ctx context.Context, organizationId uuid.UUID, userId uuid.UUID) (*domain.OrganizationMember, error) {
+	member, err := ps.organizationRepository.GetMember(ctx, organizationId, userId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("organization not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get organization member", err)
+	}
+
+	return member, nil
+}
+
+func (ps *ProjectService) getTemplate(ctx context.Context, templateId uuid.UUID, userId uuid.UUID) (*domain.ProjectTemplate, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	template, err := ps.projectRepository.GetTemplateById(ctx, templateId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get template", err)
+	}
+
+	if template.UserId != userId {
+		return nil, domain.NotFoundError("template not found")
+	}
+
+	return template, nil
+}
+
 func (ps *ProjectService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Project, error) {
 	if userId == uuid.Nil {
 		return nil, domain.UnauthorizedError("unauthorized")
@@ -98,15 +381,9 @@ func (ps *ProjectService) GetById(ctx context.Context, id uuid.UUID, userId uuid
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	hasPermission := false
-	for _, member := range project.Members {
-		if member.UserId == userId {
-			hasPermission = true
-			break
-		}
-	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	_, err = project.Authorize(userId, domain.PermissionProjectView)
+	if err != nil {
+		return nil, err
 	}
 
 	return project, nil
@@ -116,6 +393,8 @@ type ListProjectsByUserIdRequest struct {
 	UserId             uuid.UUID
 	MemberRole         domain.ProjectMemberRole
 	ShouldFilterByRole bool
+	IncludeArchived    bool
+	OrganizationId     uuid.UUID
 }
 
 func (ps *ProjectService) ListByUserId(ctx context.Context, request ListProjectsByUserIdRequest) ([]domain.Project, error) {
@@ -128,7 +407,14 @@ func (ps *ProjectService) ListByUserId(ctx context.Context, request ListProjects
 		strRole = string(request.MemberRole)
 	}
 
-	projects, err := ps.projectRepository.ListByUserId(ctx, request.UserId, strRole)
+	if request.OrganizationId != uuid.Nil {
+		err := ps.checkOrganizationMember(ctx, request.OrganizationId, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	projects, err := ps.projectRepository.ListByUserId(ctx, request.UserId, strRole, request.IncludeArchived, request.OrganizationId)
 	if err != nil {
 		return nil, domain.ServerError("failed to list projects", err)
 	}
@@ -161,8 +447,9 @@ func (ps *ProjectService) Update(ctx context.Context, request UpdateProjectReque
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	if project.UserId != request.UserId {
-		return nil, domain.ForbiddenError("forbidden")
+	_, err = project.Authorize(request.UserId, domain.PermissionProjectUpdate)
+	if err != nil {
+		return nil, err
 	}
 
 	project.Name = request.Name
@@ -182,9 +469,89 @@ func (ps *ProjectService) Update(ctx context.Context, request UpdateProjectReque
 	return project, nil
 }
 
+// Archive makes the project read-only and hides it from the default project list.
+func (ps *ProjectService) Archive(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.Project, error) {
+	project, err := ps.getOwnedProject(ctx, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.IsArchived() {
+		return nil, domain.BusinessValidationError("project is already archived")
+	}
+
+	now := time.Now()
+	project.ArchivedAt = &now
+	project.UpdatedAt = now
+
+	err = ps.projectRepository.UpdateArchivedAt(ctx, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to archive project", err)
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectArchived, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project archived event", err)
+	}
+
+	return project, nil
+}
+
+func (ps *ProjectService) Unarchive(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.Project, error) {
+	project, err := ps.getOwnedProject(ctx, projectId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !project.IsArchived() {
+		return nil, domain.BusinessValidationError("project is not archived")
+	}
+
+	project.ArchivedAt = nil
+	project.UpdatedAt = time.Now()
+
+	err = ps.projectRepository.UpdateArchivedAt(ctx, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to unarchive project", err)
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectUpdated, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project updated event", err)
+	}
+
+	return project, nil
+}
+
+// Delete permanently removes the project and everything that belongs to it.
+func (ps *ProjectService) Delete(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) error {
+	project, err := ps.getOwnedProject(ctx, projectId, userId)
+	if err != nil {
+		return err
+	}
+
+	chatIds, err := ps.projectRepository.Delete(ctx, project.Id)
+	if err != nil {
+		return domain.ServerError("failed to delete project", err)
+	}
+
+	deleted := domain.DeletedProject{
+		Project: *project,
+		ChatIds: chatIds,
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectDeleted, deleted)
+	if err != nil {
+		return domain.ServerError("failed to publish project deleted event", err)
+	}
+
+	return nil
+}
+
 type CreateMemberRequest struct {
 	ProjectId     uuid.UUID
 	Email         string
+	Role          domain.ProjectMemberRole
 	RequestUserId uuid.UUID
 }
 
@@ -221,8 +588,18 @@ func (ps *ProjectService) CreateMember(ctx context.Context, request CreateMember
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	if project.UserId != request.RequestUserId {
-		return nil, domain.ForbiddenError("forbidden")
+	requestMember, err := project.Authorize(request.RequestUserId, domain.PermissionProjectManageMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	role := request.Role
+	if role == "" {
+		role = domain.ProjectMemberRoleMember
+	}
+
+	if !requestMember.Role.CanManage(role) {
+		return nil, domain.ForbiddenError("you cannot add a member with this role")
 	}
 
 	alreadyMember := false
@@ -239,7 +616,7 @@ func (ps *ProjectService) CreateMember(ctx context.Context, request CreateMember
 	member := domain.ProjectMember{
 		ProjectId: request.ProjectId,
 		UserId:    user.Id,
-		Role:      domain.ProjectMemberRoleMember,
+		Role:      role,
 	}
 
 	err = ps.projectRepository.CreateMember(ctx, &member)
@@ -261,3 +638,494 @@ func (ps *ProjectService) CreateMember(ctx context.Context, request CreateMember
 
 	return &member, nil
 }
+
+type RemoveMemberRequest struct {
+	ProjectId     uuid.UUID
+	UserId        uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+// RemoveMember removes a member from the project, members that can manage the target's role
+// can remove them while anyone but the owner can remove themselves.
+func (ps *ProjectService) RemoveMember(ctx context.Context, request RemoveMemberRequest) error {
+	if request.RequestUserId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, request.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	requestMember, ok := project.GetMember(request.RequestUserId)
+	if !ok {
+		return domain.ForbiddenError("forbidden")
+	}
+
+	member, ok := project.GetMember(request.UserId)
+	if !ok {
+		return domain.NotFoundError("member not found")
+	}
+
+	if member.Role == domain.ProjectMemberRoleOwner {
+		return domain.BusinessValidationError("the project owner cannot leave the project")
+	}
+
+	if member.UserId != requestMember.UserId && !requestMember.Role.CanManage(member.Role) {
+		return domain.ForbiddenError("forbidden")
+	}
+
+	err = ps.projectRepository.RemoveMember(ctx, project.Id, member.UserId)
+	if err != nil {
+		return domain.ServerError("failed to remove member", err)
+	}
+
+	member.ProjectId = project.Id
+
+	err = ps.publisher.Publish(ctx, events.ProjectMemberRemoved, member)
+	if err != nil {
+		return domain.ServerError("failed to publish project member removed event", err)
+	}
+
+	return nil
+}
+
+func (ps *ProjectService) Leave(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) error {
+	return ps.RemoveMember(ctx, RemoveMemberRequest{
+		ProjectId:     projectId,
+		UserId:        userId,
+		RequestUserId: userId,
+	})
+}
+
+type UpdateMemberRoleRequest struct {
+	ProjectId     uuid.UUID
+	UserId        uuid.UUID
+	Role          domain.ProjectMemberRole
+	RequestUserId uuid.UUID
+}
+
+func (ps *ProjectService) UpdateMemberRole(ctx context.Context, request UpdateMemberRoleRequest) (*domain.ProjectMember, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, request.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestMember, err := project.Authorize(request.RequestUserId, domain.PermissionProjectManageMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok := project.GetMember(request.UserId)
+	if !ok {
+		return nil, domain.NotFoundError("member not found")
+	}
+
+	if member.UserId == requestMember.UserId {
+		return nil, domain.BusinessValidationError("you cannot change your own role")
+	}
+
+	if !requestMember.Role.CanManage(member.Role) || !requestMember.Role.CanManage(request.Role) {
+		return nil, domain.ForbiddenError("you cannot change this member to this role")
+	}
+
+	member.ProjectId = project.Id
+	member.Role = request.Role
+
+	err = ps.projectRepository.UpdateMemberRole(ctx, member)
+	if err != nil {
+		return nil, domain.ServerError("failed to update member role", err)
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectMemberUpdated, member)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project member updated event", err)
+	}
+
+	return member, nil
+}
+
+type TransferOwnershipRequest struct {
+	ProjectId     uuid.UUID
+	UserId        uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+// TransferOwnership hands the project to another member, the previous owner becomes an admin.
+func (ps *ProjectService) TransferOwnership(ctx context.Context, request TransferOwnershipRequest) (*domain.Project, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, request.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	previousOwner, ok := project.GetMember(request.RequestUserId)
+	if !ok || previousOwner.Role != domain.ProjectMemberRoleOwner {
+		return nil, domain.ForbiddenError("only the project owner can transfer ownership")
+	}
+
+	newOwner, ok := project.GetMember(request.UserId)
+	if !ok {
+		return nil, domain.NotFoundError("member not found")
+	}
+
+	if newOwner.UserId == previousOwner.UserId {
+		return nil, domain.BusinessValidationError("you already own this project")
+	}
+
+	// personal organizations cannot be shared, a project kept there follows its owner to
+	// the personal organization of the new owner
+	previousOrganization, err := ps.organizationRepository.GetPersonalByUserId(ctx, previousOwner.UserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get personal organization", err)
+	}
+
+	if project.OrganizationId == previousOrganization.Id {
+		newOrganization, err := ps.organizationRepository.GetPersonalByUserId(ctx, newOwner.UserId)
+		if err != nil {
+			return nil, domain.ServerError("failed to get personal organization", err)
+		}
+		project.OrganizationId = newOrganization.Id
+	}
+
+	previousOwner.Role = domain.ProjectMemberRoleAdmin
+	previousOwner.ProjectId = project.Id
+	newOwner.Role = domain.ProjectMemberRoleOwner
+	newOwner.ProjectId = project.Id
+	project.UserId = newOwner.UserId
+
+	err = ps.projectRepository.TransferOwnership(ctx, project, previousOwner, newOwner)
+	if err != nil {
+		return nil, domain.ServerError("failed to transfer ownership", err)
+	}
+
+	project.UpdatedAt = time.Now()
+
+	err = ps.publisher.Publish(ctx, events.ProjectUpdated, project)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project updated event", err)
+	}
+
+	transfer := domain.ProjectOwnershipTransfer{
+		ProjectId:       project.Id,
+		PreviousOwnerId: previousOwner.UserId,
+		NewOwnerId:      newOwner.UserId,
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectOwnershipTransferred, transfer)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project ownership transferred event", err)
+	}
+
+	return project, nil
+}
+
+type CreateInvitationRequest struct {
+	ProjectId     uuid.UUID
+	Email         string
+	Role          domain.ProjectMemberRole
+	RequestUserId uuid.UUID
+}
+
+// CreateInvitation creates a single use invitation for the email, the returned invitation
+// is the only place where the plain token is available.
+func (ps *ProjectService) CreateInvitation(ctx context.Context, request CreateInvitationRequest) (*domain.ProjectInvitation, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, request.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	requestMember, err := project.Authorize(request.RequestUserId, domain.PermissionProjectManageMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	role := request.Role
+	if role == "" {
+		role = domain.ProjectMemberRoleMember
+	}
+
+	if !requestMember.Role.CanManage(role) {
+		return nil, domain.ForbiddenError("you cannot invite a member with this role")
+	}
+
+	email := strings.TrimSpace(request.Email)
+
+	for _, member := range project.Members {
+		if member.User != nil && strings.EqualFold(member.User.Email, email) {
+			return nil, domain.DuplicateEntryError("member already exists")
+		}
+	}
+
+	pending, err := ps.projectRepository.ListPendingInvitations(ctx, project.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list invitations", err)
+	}
+
+	for _, invitation := range pending {
+		if strings.EqualFold(invitation.Email, email) {
+			return nil, domain.DuplicateEntryError("a pending invitation already exists for this email")
+		}
+	}
+
+	token, err := GenerateRefreshToken(32)
+	if err != nil {
+		return nil, domain.ServerError("failed to generate invitation token", err)
+	}
+
+	invitation := domain.ProjectInvitation{
+		ProjectId: project.Id,
+		Email:     email,
+		Role:      role,
+		Token:     token,
+		TokenHash: hashToken(token),
+		InvitedBy: request.RequestUserId,
+		ExpiresAt: time.Now().Add(invitationDuration),
+	}
+
+	err = ps.projectRepository.CreateInvitation(ctx, &invitation)
+	if err != nil {
+		return nil, domain.ServerError("failed to create invitation", err)
+	}
+
+	return &invitation, nil
+}
+
+func (ps *ProjectService) ListInvitations(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) ([]domain.ProjectInvitation, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = project.Authorize(userId, domain.PermissionProjectManageMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := ps.projectRepository.ListPendingInvitations(ctx, project.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list invitations", err)
+	}
+
+	return invitations, nil
+}
+
+type RevokeInvitationRequest struct {
+	ProjectId     uuid.UUID
+	InvitationId  uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+func (ps *ProjectService) RevokeInvitation(ctx context.Context, request RevokeInvitationRequest) error {
+	if request.RequestUserId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, request.ProjectId)
+	if err != nil {
+		return err
+	}
+
+	_, err = project.Authorize(request.RequestUserId, domain.PermissionProjectManageMembers)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := ps.projectRepository.GetInvitationById(ctx, request.InvitationId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return domainErr
+		}
+		return domain.ServerError("failed to get invitation", err)
+	}
+
+	if invitation.ProjectId != project.Id {
+		return domain.NotFoundError("invitation not found")
+	}
+
+	err = ps.projectRepository.RevokeInvitation(ctx, invitation.Id)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return domain.BusinessValidationError("invitation is no longer pending")
+		}
+		return domain.ServerError("failed to revoke invitation", err)
+	}
+
+	return nil
+}
+
+type AcceptInvitationRequest struct {
+	Token  string
+	UserId uuid.UUID
+}
+
+// AcceptInvitation lets an existing user join the project with an invitation link, the
+// invitation must have been sent to the user's email.
+func (ps *ProjectService) AcceptInvitation(ctx context.Context, request AcceptInvitationRequest) (*domain.ProjectMember, error) {
+	if request.UserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	invitation, err := ps.projectRepository.GetInvitationByTokenHash(ctx, hashToken(request.Token))
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get invitation", err)
+	}
+
+	if !invitation.IsPending(time.Now()) {
+		return nil, domain.BusinessValidationError("invitation is no longer valid")
+	}
+
+	user, err := ps.userRepository.GetById(ctx, request.UserId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ForbiddenError("this invitation was sent to another email")
+	}
+
+	// matching the address only proves the invitation is for the user once they own it
+	if !user.IsEmailVerified() {
+		return nil, domain.ForbiddenError("verify your email before accepting invitations")
+	}
+
+	return ps.joinWithInvitation(ctx, invitation, user)
+}
+
+// AcceptPendingInvitations adds a user to every project they have a pending invitation for,
+// users who did not verify their email yet are left out until they do.
+func (ps *ProjectService) AcceptPendingInvitations(ctx context.Context, user *domain.User) error {
+	if !user.IsEmailVerified() {
+		return nil
+	}
+
+	invitations, err := ps.projectRepository.ListPendingInvitationsByEmail(ctx, user.Email)
+	if err != nil {
+		return domain.ServerError("failed to list invitations", err)
+	}
+
+	for i := range invitations {
+		_, err := ps.joinWithInvitation(ctx, &invitations[i], user)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) && (domainErr.Code == domain.BusinessValidationErrorCode || domainErr.Code == domain.DuplicateEntryErrorCode) {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ps *ProjectService) joinWithInvitation(ctx context.Context, invitation *domain.ProjectInvitation, user *domain.User) (*domain.ProjectMember, error) {
+	project, err := ps.getProject(ctx, invitation.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.IsArchived() {
+		return nil, domain.BusinessValidationError("project is archived")
+	}
+
+	if member, ok := project.GetMember(user.Id); ok {
+		err = ps.projectRepository.AcceptInvitation(ctx, invitation.Id, nil)
+		if err != nil {
+			return nil, acceptInvitationError(err)
+		}
+
+		member.ProjectId = project.Id
+		return member, nil
+	}
+
+	member := domain.ProjectMember{
+		ProjectId: project.Id,
+		UserId:    user.Id,
+		Role:      invitation.Role,
+	}
+
+	err = ps.projectRepository.AcceptInvitation(ctx, invitation.Id, &member)
+	if err != nil {
+		return nil, acceptInvitationError(err)
+	}
+
+	err = ps.publisher.Publish(ctx, events.ProjectMemberCreated, member)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish project member created event", err)
+	}
+
+	return &member, nil
+}
+
+func acceptInvitationError(err error) error {
+	var domainErr domain.DomainError
+	if errors.As(err, &domainErr) {
+		switch domainErr.Code {
+		case domain.NotFoundErrorCode:
+			return domain.BusinessValidationError("invitation is no longer valid")
+		case domain.DuplicateEntryErrorCode:
+			return domain.DuplicateEntryError("member already exists")
+		}
+		return domainErr
+	}
+	return domain.ServerError("failed to accept invitation", err)
+}
+
+func (ps *ProjectService) getOwnedProject(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.Project, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	project, err := ps.getProject(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok := project.GetMember(userId)
+	if !ok || member.Role != domain.ProjectMemberRoleOwner {
+		return nil, domain.ForbiddenError("only the project owner can do this")
+	}
+
+	return project, nil
+}
+
+func (ps *ProjectService) getProject(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	project, err := ps.projectRepository.GetById(ctx, id)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("project not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get project", err)
+	}
+
+	return project, nil
+}
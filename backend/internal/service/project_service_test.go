@@ -35,6 +35,45 @@ func (m *mockProjectRepository) Create(ctx context.Context, project *domain.Proj
 	return args.Error(0)
 }
 
+func (m *mockProjectRepository) CreateWithTasks(ctx context.Context, project *domain.Project, tasks []domain.ProjectTemplateTask, author *domain.User) error {
+	args := m.Called(ctx, project, tasks, author)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) ListTasks(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error) {
+	args := m.Called(ctx, projectId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *mockProjectRepository) CreateTemplate(ctx context.Context, template *domain.ProjectTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) GetTemplateById(ctx context.Context, id uuid.UUID) (*domain.ProjectTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProjectTemplate), args.Error(1)
+}
+
+func (m *mockProjectRepository) ListTemplatesByUserId(ctx context.Context, userId uuid.UUID) ([]domain.ProjectTemplate, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProjectTemplate), args.Error(1)
+}
+
+func (m *mockProjectRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *mockProjectRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -43,8 +82,8 @@ func (m *mockProjectRepository) GetById(ctx context.Context, id uuid.UUID) (*dom
 	return args.Get(0).(*domain.Project), args.Error(1)
 }
 
-func (m *mockProjectRepository) ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string) ([]domain.Project, error) {
-	args := m.Called(ctx, userId, memberRole)
+func (m *mockProjectRepository) ListByUserId(ctx context.Context, userId uuid.UUID, memberRole string, includeArchived bool, organizationId uuid.UUID) ([]domain.Project, error) {
+	args := m.Called(ctx, userId, memberRole, includeArchived, organizationId)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -59,6 +98,19 @@ func (m *mockProjectRepository) Update(ctx context.Context, project *domain.Proj
 	return args.Error(0)
 }
 
+func (m *mockProjectRepository) UpdateArchivedAt(ctx context.Context, project *domain.Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) Delete(ctx context.Context, projectId uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, projectId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 func (m *mockProjectRepository) CreateMember(ctx context.Context, member *domain.ProjectMember) error {
 	args := m.Called(ctx, member)
 	if args.Get(0) == nil {
@@ -75,6 +127,16 @@ func (m *mockProjectRepository) RemoveMember(ctx context.Context, projectId uuid
 	return args.Error(0)
 }
 
+func (m *mockProjectRepository) UpdateMemberRole(ctx context.Context, member *domain.ProjectMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) TransferOwnership(ctx context.Context, project *domain.Project, previousOwner *domain.ProjectMember, newOwner *domain.ProjectMember) error {
+	args := m.Called(ctx, project, previousOwner, newOwner)
+	return args.Error(0)
+}
+
 func (m *mockProjectRepository) GetMemberByUserIdAndProjectId(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) (*domain.ProjectMember, error) {
 	args := m.Called(ctx, projectId, userId)
 	if args.Get(0) == nil {
@@ -83,13 +145,62 @@ func (m *mockProjectRepository) GetMemberByUserIdAndProjectId(ctx context.Contex
 	return args.Get(0).(*domain.ProjectMember), args.Error(1)
 }
 
+func (m *mockProjectRepository) CreateInvitation(ctx context.Context, invitation *domain.ProjectInvitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) GetInvitationById(ctx context.Context, id uuid.UUID) (*domain.ProjectInvitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProjectInvitation), args.Error(1)
+}
+
+func (m *mockProjectRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.ProjectInvitation, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProjectInvitation), args.Error(1)
+}
+
+func (m *mockProjectRepository) ListPendingInvitations(ctx context.Context, projectId uuid.UUID) ([]domain.ProjectInvitation, error) {
+	args := m.Called(ctx, projectId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProjectInvitation), args.Error(1)
+}
+
+func (m *mockProjectRepository) ListPendingInvitationsByEmail(ctx context.Context, email string) ([]domain.ProjectInvitation, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProjectInvitation), args.Error(1)
+}
+
+func (m *mockProjectRepository) AcceptInvitation(ctx context.Context, invitationId uuid.UUID, member *domain.ProjectMember) error {
+	args := m.Called(ctx, invitationId, member)
+	return args.Error(0)
+}
+
+func (m *mockProjectRepository) RevokeInvitation(ctx context.Context, invitationId uuid.UUID) error {
+	args := m.Called(ctx, invitationId)
+	return args.Error(0)
+}
+
 func TestProjectService_Create(t *testing.T) {
 	validUserId := uuid.New()
+	personalOrganization := &domain.Organization{Id: uuid.New(), UserId: validUserId, Personal: true}
+	teamOrganizationId := uuid.New()
 
 	type testCase struct {
 		name              string
 		request           service.CreateProjectRequest
-		mockSetup         func(*mockProjectRepository, *mockUserRepository)
+		mockSetup         func(*mockProjectRepository, *mockOrganizationRepository)
 		expectedProject   *domain.Project
 		expectedError     error
 		expectedErrorCode string
@@ -104,7 +215,8 @@ func TestProjectService_Create(t *testing.T) {
 				Description: "Test Description",
 				UserId:      validUserId,
 			},
-			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+			mockSetup: func(repo *mockProjectRepository, organizationRepo *mockOrganizationRepository) {
+				organizationRepo.On("GetPersonalByUserId", mock.Anything, validUserId).Return(personalOrganization, nil)
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Project")).Return(nil)
 			},
 			expectedProject: &domain.Project{
@@ -116,14 +228,49 @@ func TestProjectService_Create(t *testing.T) {
 				Members: []domain.ProjectMember{
 					{
 						UserId: validUserId,
-						Role:   domain.ProjectMemberRoleCreator,
+						Role:   domain.ProjectMemberRoleOwner,
 					},
 				},
-				UserId: validUserId,
+				UserId:         validUserId,
+				OrganizationId: personalOrganization.Id,
 			},
 			shouldSucceed: true,
 			expectedError: nil,
 		},
+		{
+			name: "creates project in organization of the user",
+			request: service.CreateProjectRequest{
+				Name:           "Test Project",
+				Description:    "Test Description",
+				UserId:         validUserId,
+				OrganizationId: teamOrganizationId,
+			},
+			mockSetup: func(repo *mockProjectRepository, organizationRepo *mockOrganizationRepository) {
+				organizationRepo.On("GetMember", mock.Anything, teamOrganizationId, validUserId).Return(&domain.OrganizationMember{OrganizationId: teamOrganizationId, UserId: validUserId, Role: domain.OrganizationMemberRoleMember}, nil)
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Project")).Return(nil)
+			},
+			expectedProject: &domain.Project{
+				Name:           "Test Project",
+				Description:    "Test Description",
+				UserId:         validUserId,
+				OrganizationId: teamOrganizationId,
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "not a member of the organization",
+			request: service.CreateProjectRequest{
+				Name:           "Test Project",
+				Description:    "Test Description",
+				UserId:         validUserId,
+				OrganizationId: teamOrganizationId,
+			},
+			mockSetup: func(repo *mockProjectRepository, organizationRepo *mockOrganizationRepository) {
+				organizationRepo.On("GetMember", mock.Anything, teamOrganizationId, validUserId).Return(nil, domain.NotFoundError("organization member not found"))
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
 		{
 			name: "unauthorized error",
 			request: service.CreateProjectRequest{
@@ -131,7 +278,7 @@ func TestProjectService_Create(t *testing.T) {
 				Description: "Test Description",
 				UserId:      uuid.Nil,
 			},
-			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+			mockSetup: func(repo *mockProjectRepository, organizationRepo *mockOrganizationRepository) {
 			},
 			shouldSucceed:     false,
 			expectedErrorCode: string(domain.UnauthorizedErrorCode),
@@ -144,7 +291,8 @@ func TestProjectService_Create(t *testing.T) {
 				Description: "Test Description",
 				UserId:      validUserId,
 			},
-			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+			mockSetup: func(repo *mockProjectRepository, organizationRepo *mockOrganizationRepository) {
+				organizationRepo.On("GetPersonalByUserId", mock.Anything, validUserId).Return(personalOrganization, nil)
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Project")).Return(errors.New("error"))
 			},
 			shouldSucceed:     false,
@@ -158,15 +306,17 @@ func TestProjectService_Create(t *testing.T) {
 			mockRepo := &mockProjectRepository{}
 			mockUserRepo := &mockUserRepository{}
 			mockPublisher := &mockPublisher{}
-			tt.mockSetup(mockRepo, mockUserRepo)
-			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher)
+			mockOrganizationRepo := &mockOrganizationRepository{}
+			tt.mockSetup(mockRepo, mockOrganizationRepo)
+			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher, mockOrganizationRepo)
 			ctx := context.Background()
 
 			project, err := service.Create(ctx, tt.request)
 
 			if tt.shouldSucceed {
 				assert.NoError(t, err)
-				assert.NotNil(t, project)
+				require.NotNil(t, project)
+				assert.Equal(t, tt.expectedProject.OrganizationId, project.OrganizationId)
 			} else {
 				require.Error(t, err)
 				require.Nil(t, project)
@@ -195,7 +345,7 @@ func TestProjectService_GetById(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validMemberUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 			{
 				UserId: validUserId,
@@ -269,7 +419,7 @@ func TestProjectService_GetById(t *testing.T) {
 			mockUserRepo := &mockUserRepository{}
 			mockPublisher := &mockPublisher{}
 			tt.mockSetup(mockRepo, mockUserRepo)
-			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher)
+			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher, &mockOrganizationRepository{})
 			ctx := context.Background()
 
 			project, err := service.GetById(ctx, tt.id, tt.userId)
@@ -307,7 +457,7 @@ func TestProjectService_ListByUserId(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validMemberUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 		},
 		UserId: validUserId,
@@ -327,11 +477,11 @@ func TestProjectService_ListByUserId(t *testing.T) {
 			name: "successful project list by user id",
 			request: service.ListProjectsByUserIdRequest{
 				UserId:             validUserId,
-				MemberRole:         domain.ProjectMemberRoleCreator,
+				MemberRole:         domain.ProjectMemberRoleOwner,
 				ShouldFilterByRole: true,
 			},
 			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
-				repo.On("ListByUserId", mock.Anything, validUserId, "creator").Return([]domain.Project{validProject}, nil)
+				repo.On("ListByUserId", mock.Anything, validUserId, "owner", false, uuid.Nil).Return([]domain.Project{validProject}, nil)
 			},
 			shouldSucceed: true,
 			expectedError: nil,
@@ -340,11 +490,11 @@ func TestProjectService_ListByUserId(t *testing.T) {
 			name: "throws server error",
 			request: service.ListProjectsByUserIdRequest{
 				UserId:             validUserId,
-				MemberRole:         domain.ProjectMemberRoleCreator,
+				MemberRole:         domain.ProjectMemberRoleOwner,
 				ShouldFilterByRole: true,
 			},
 			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
-				repo.On("ListByUserId", mock.Anything, validUserId, "creator").Return(nil, errors.New("server error"))
+				repo.On("ListByUserId", mock.Anything, validUserId, "owner", false, uuid.Nil).Return(nil, errors.New("server error"))
 			},
 			shouldSucceed:     false,
 			expectedErrorCode: string(domain.ServerErrorCode),
@@ -359,7 +509,7 @@ func TestProjectService_ListByUserId(t *testing.T) {
 			mockPublisher := &mockPublisher{}
 			tt.mockSetup(mockRepo, mockUserRepo)
 
-			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher)
+			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher, &mockOrganizationRepository{})
 			ctx := context.Background()
 
 			projects, err := service.ListByUserId(ctx, tt.request)
@@ -395,7 +545,7 @@ func TestProjectService_Update(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 		},
 		UserId: validUserId,
@@ -483,7 +633,7 @@ func TestProjectService_Update(t *testing.T) {
 			mockPublisher := &mockPublisher{}
 			tt.mockSetup(mockRepo, mockUserRepo)
 
-			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher)
+			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher, &mockOrganizationRepository{})
 			ctx := context.Background()
 
 			project, err := service.Update(ctx, tt.request)
@@ -525,7 +675,7 @@ func TestProjectService_CreateMember(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 			{
 				UserId: existingMemberUserId,
@@ -695,7 +845,7 @@ func TestProjectService_CreateMember(t *testing.T) {
 			mockPublisher := &mockPublisher{}
 			tt.mockSetup(mockRepo, mockUserRepo)
 
-			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher)
+			service := service.NewProjectService(mockRepo, mockUserRepo, mockPublisher, &mockOrganizationRepository{})
 			ctx := context.Background()
 
 			member, err := service.CreateMember(ctx, tt.request)
@@ -720,3 +870,1101 @@ func TestProjectService_CreateMember(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectService_RemoveMember(t *testing.T) {
+	creatorUserId := uuid.New()
+	memberUserId := uuid.New()
+	otherMemberUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validProject := &domain.Project{
+		Id:          validProjectId,
+		Name:        "Test Project",
+		Description: "Test Description",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Members: []domain.ProjectMember{
+			{
+				UserId: creatorUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: memberUserId,
+				Role:   domain.ProjectMemberRoleMember,
+			},
+			{
+				UserId: otherMemberUserId,
+				Role:   domain.ProjectMemberRoleMember,
+			},
+		},
+		UserId: creatorUserId,
+	}
+
+	type testCase struct {
+		name              string
+		request           service.RemoveMemberRequest
+		mockSetup         func(*mockProjectRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "creator removes member",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: creatorUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("RemoveMember", mock.Anything, validProjectId, memberUserId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "member leaves project",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: memberUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("RemoveMember", mock.Anything, validProjectId, memberUserId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "forbidden - member removes another member",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        otherMemberUserId,
+				RequestUserId: memberUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "creator cannot leave",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        creatorUserId,
+				RequestUserId: creatorUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "member not found",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        uuid.New(),
+				RequestUserId: creatorUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name: "unauthorized error",
+			request: service.RemoveMemberRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: uuid.Nil,
+			},
+			mockSetup:         func(repo *mockProjectRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			tt.mockSetup(mockRepo)
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+			err := projectService.RemoveMember(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_UpdateMemberRole(t *testing.T) {
+	ownerUserId := uuid.New()
+	adminUserId := uuid.New()
+	memberUserId := uuid.New()
+	otherAdminUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	newProject := func() *domain.Project {
+		return &domain.Project{
+			Id:     validProjectId,
+			Name:   "Test Project",
+			UserId: ownerUserId,
+			Members: []domain.ProjectMember{
+				{UserId: ownerUserId, Role: domain.ProjectMemberRoleOwner},
+				{UserId: adminUserId, Role: domain.ProjectMemberRoleAdmin},
+				{UserId: otherAdminUserId, Role: domain.ProjectMemberRoleAdmin},
+				{UserId: memberUserId, Role: domain.ProjectMemberRoleMember},
+			},
+		}
+	}
+
+	type testCase struct {
+		name              string
+		request           service.UpdateMemberRoleRequest
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "owner promotes member to admin",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				Role:          domain.ProjectMemberRoleAdmin,
+				RequestUserId: ownerUserId,
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "admin downgrades member to viewer",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				Role:          domain.ProjectMemberRoleViewer,
+				RequestUserId: adminUserId,
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "forbidden - admin promotes member to admin",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				Role:          domain.ProjectMemberRoleAdmin,
+				RequestUserId: adminUserId,
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "forbidden - admin changes another admin",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        otherAdminUserId,
+				Role:          domain.ProjectMemberRoleMember,
+				RequestUserId: adminUserId,
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "forbidden - owner role cannot be given",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				Role:          domain.ProjectMemberRoleOwner,
+				RequestUserId: ownerUserId,
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "forbidden - member cannot manage members",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        adminUserId,
+				Role:          domain.ProjectMemberRoleViewer,
+				RequestUserId: memberUserId,
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "cannot change own role",
+			request: service.UpdateMemberRoleRequest{
+				ProjectId:     validProjectId,
+				UserId:        adminUserId,
+				Role:          domain.ProjectMemberRoleMember,
+				RequestUserId: adminUserId,
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			mockRepo.On("GetById", mock.Anything, validProjectId).Return(newProject(), nil)
+			mockRepo.On("UpdateMemberRole", mock.Anything, mock.AnythingOfType("*domain.ProjectMember")).Return(nil)
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+			member, err := projectService.UpdateMemberRole(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, tt.request.Role, member.Role)
+				assert.Equal(t, tt.request.UserId, member.UserId)
+				mockRepo.AssertCalled(t, "UpdateMemberRole", mock.Anything, mock.AnythingOfType("*domain.ProjectMember"))
+			} else {
+				require.Error(t, err)
+				require.Nil(t, member)
+				mockRepo.AssertNotCalled(t, "UpdateMemberRole", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+		})
+	}
+}
+
+func TestProjectService_CreateInvitation(t *testing.T) {
+	ownerUserId := uuid.New()
+	adminUserId := uuid.New()
+	memberUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validProject := &domain.Project{
+		Id:     validProjectId,
+		Name:   "Test Project",
+		UserId: ownerUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: ownerUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: adminUserId,
+				Role:   domain.ProjectMemberRoleAdmin,
+			},
+			{
+				UserId: memberUserId,
+				Role:   domain.ProjectMemberRoleMember,
+				User:   &domain.User{Id: memberUserId, Email: "member@example.com"},
+			},
+		},
+	}
+
+	type testCase struct {
+		name              string
+		request           service.CreateInvitationRequest
+		mockSetup         func(*mockProjectRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "owner invites new email",
+			request: service.CreateInvitationRequest{
+				ProjectId:     validProjectId,
+				Email:         "new@example.com",
+				RequestUserId: ownerUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("ListPendingInvitations", mock.Anything, validProjectId).Return([]domain.ProjectInvitation{}, nil)
+				repo.On("CreateInvitation", mock.Anything, mock.AnythingOfType("*domain.ProjectInvitation")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "pending invitation already exists",
+			request: service.CreateInvitationRequest{
+				ProjectId:     validProjectId,
+				Email:         "New@example.com",
+				RequestUserId: ownerUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("ListPendingInvitations", mock.Anything, validProjectId).Return([]domain.ProjectInvitation{
+					{Email: "new@example.com"},
+				}, nil)
+			},
+			expectedErrorCode: string(domain.DuplicateEntryErrorCode),
+		},
+		{
+			name: "email is already a member",
+			request: service.CreateInvitationRequest{
+				ProjectId:     validProjectId,
+				Email:         "member@example.com",
+				RequestUserId: ownerUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.DuplicateEntryErrorCode),
+		},
+		{
+			name: "admin cannot invite an admin",
+			request: service.CreateInvitationRequest{
+				ProjectId:     validProjectId,
+				Email:         "new@example.com",
+				Role:          domain.ProjectMemberRoleAdmin,
+				RequestUserId: adminUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "member cannot invite",
+			request: service.CreateInvitationRequest{
+				ProjectId:     validProjectId,
+				Email:         "new@example.com",
+				RequestUserId: memberUserId,
+			},
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			tt.mockSetup(mockRepo)
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+			invitation, err := projectService.CreateInvitation(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, domain.ProjectMemberRoleMember, invitation.Role)
+				assert.NotEmpty(t, invitation.Token)
+				assert.NotEqual(t, invitation.Token, invitation.TokenHash)
+				assert.True(t, invitation.IsPending(time.Now()))
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "CreateInvitation", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_AcceptInvitation(t *testing.T) {
+	ownerUserId := uuid.New()
+	invitedUserId := uuid.New()
+	validProjectId := uuid.New()
+	invitationId := uuid.New()
+
+	validProject := &domain.Project{
+		Id:     validProjectId,
+		Name:   "Test Project",
+		UserId: ownerUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: ownerUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	verifiedAt := time.Now()
+	invitedUser := &domain.User{Id: invitedUserId, Email: "invited@example.com", EmailVerifiedAt: &verifiedAt}
+	unverifiedUser := &domain.User{Id: invitedUserId, Email: "invited@example.com"}
+
+	pendingInvitation := func() *domain.ProjectInvitation {
+		return &domain.ProjectInvitation{
+			Id:        invitationId,
+			ProjectId: validProjectId,
+			Email:     "Invited@example.com",
+			Role:      domain.ProjectMemberRoleViewer,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	type testCase struct {
+		name              string
+		mockSetup         func(*mockProjectRepository, *mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "invited user joins with the invitation role",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(pendingInvitation(), nil)
+				userRepo.On("GetById", mock.Anything, invitedUserId).Return(invitedUser, nil)
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("AcceptInvitation", mock.Anything, invitationId, mock.AnythingOfType("*domain.ProjectMember")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "invitation sent to another email",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				invitation := pendingInvitation()
+				invitation.Email = "someone@example.com"
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(invitation, nil)
+				userRepo.On("GetById", mock.Anything, invitedUserId).Return(invitedUser, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "user with unverified email",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(pendingInvitation(), nil)
+				userRepo.On("GetById", mock.Anything, invitedUserId).Return(unverifiedUser, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "expired invitation",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				invitation := pendingInvitation()
+				invitation.ExpiresAt = time.Now().Add(-time.Minute)
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(invitation, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "already used invitation",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				acceptedAt := time.Now()
+				invitation := pendingInvitation()
+				invitation.AcceptedAt = &acceptedAt
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(invitation, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "invitation accepted concurrently",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(pendingInvitation(), nil)
+				userRepo.On("GetById", mock.Anything, invitedUserId).Return(invitedUser, nil)
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("AcceptInvitation", mock.Anything, invitationId, mock.AnythingOfType("*domain.ProjectMember")).Return(domain.NotFoundError("invitation not found"))
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "unknown token",
+			mockSetup: func(repo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetInvitationByTokenHash", mock.Anything, mock.AnythingOfType("string")).Return(nil, domain.NotFoundError("invitation not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			mockUserRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo, mockUserRepo)
+
+			projectService := service.NewProjectService(mockRepo, mockUserRepo, &mockPublisher{}, &mockOrganizationRepository{})
+
+			member, err := projectService.AcceptInvitation(context.Background(), service.AcceptInvitationRequest{
+				Token:  "token",
+				UserId: invitedUserId,
+			})
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, invitedUserId, member.UserId)
+				assert.Equal(t, validProjectId, member.ProjectId)
+				assert.Equal(t, domain.ProjectMemberRoleViewer, member.Role)
+			} else {
+				require.Error(t, err)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_TransferOwnership(t *testing.T) {
+	ownerUserId := uuid.New()
+	adminUserId := uuid.New()
+	memberUserId := uuid.New()
+	validProjectId := uuid.New()
+	ownerOrganizationId := uuid.New()
+	memberOrganizationId := uuid.New()
+	sharedOrganizationId := uuid.New()
+
+	newProject := func(organizationId uuid.UUID) *domain.Project {
+		return &domain.Project{
+			Id:             validProjectId,
+			Name:           "Test Project",
+			UserId:         ownerUserId,
+			OrganizationId: organizationId,
+			Members: []domain.ProjectMember{
+				{
+					UserId: ownerUserId,
+					Role:   domain.ProjectMemberRoleOwner,
+				},
+				{
+					UserId: adminUserId,
+					Role:   domain.ProjectMemberRoleAdmin,
+				},
+				{
+					UserId: memberUserId,
+					Role:   domain.ProjectMemberRoleMember,
+				},
+			},
+		}
+	}
+
+	type testCase struct {
+		name                   string
+		organizationId         uuid.UUID
+		request                service.TransferOwnershipRequest
+		expectedOrganizationId uuid.UUID
+		expectedErrorCode      string
+		shouldSucceed          bool
+	}
+
+	tests := []testCase{
+		{
+			name:           "owner transfers to member",
+			organizationId: sharedOrganizationId,
+			request: service.TransferOwnershipRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: ownerUserId,
+			},
+			expectedOrganizationId: sharedOrganizationId,
+			shouldSucceed:          true,
+		},
+		{
+			name:           "project in personal organization moves to the new owner",
+			organizationId: ownerOrganizationId,
+			request: service.TransferOwnershipRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: ownerUserId,
+			},
+			expectedOrganizationId: memberOrganizationId,
+			shouldSucceed:          true,
+		},
+		{
+			name: "admin cannot transfer ownership",
+			request: service.TransferOwnershipRequest{
+				ProjectId:     validProjectId,
+				UserId:        memberUserId,
+				RequestUserId: adminUserId,
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "new owner must be a member",
+			request: service.TransferOwnershipRequest{
+				ProjectId:     validProjectId,
+				UserId:        uuid.New(),
+				RequestUserId: ownerUserId,
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name: "owner cannot transfer to themselves",
+			request: service.TransferOwnershipRequest{
+				ProjectId:     validProjectId,
+				UserId:        ownerUserId,
+				RequestUserId: ownerUserId,
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			mockRepo.On("GetById", mock.Anything, validProjectId).Return(newProject(tt.organizationId), nil)
+			if tt.shouldSucceed {
+				mockRepo.On("TransferOwnership", mock.Anything, mock.AnythingOfType("*domain.Project"), mock.AnythingOfType("*domain.ProjectMember"), mock.AnythingOfType("*domain.ProjectMember")).Return(nil)
+			}
+
+			mockOrganizationRepo := &mockOrganizationRepository{}
+			mockOrganizationRepo.On("GetPersonalByUserId", mock.Anything, ownerUserId).Return(&domain.Organization{Id: ownerOrganizationId, UserId: ownerUserId, Personal: true}, nil).Maybe()
+			mockOrganizationRepo.On("GetPersonalByUserId", mock.Anything, memberUserId).Return(&domain.Organization{Id: memberOrganizationId, UserId: memberUserId, Personal: true}, nil).Maybe()
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, mockOrganizationRepo)
+
+			project, err := projectService.TransferOwnership(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, tt.request.UserId, project.UserId)
+				assert.Equal(t, tt.expectedOrganizationId, project.OrganizationId)
+
+				previousOwner, _ := project.GetMember(ownerUserId)
+				newOwner, _ := project.GetMember(tt.request.UserId)
+				assert.Equal(t, domain.ProjectMemberRoleAdmin, previousOwner.Role)
+				assert.Equal(t, domain.ProjectMemberRoleOwner, newOwner.Role)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "TransferOwnership", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_Archive(t *testing.T) {
+	ownerUserId := uuid.New()
+	adminUserId := uuid.New()
+	validProjectId := uuid.New()
+	archivedAt := time.Now()
+
+	newProject := func(archivedAt *time.Time) *domain.Project {
+		return &domain.Project{
+			Id:         validProjectId,
+			Name:       "Test Project",
+			UserId:     ownerUserId,
+			ArchivedAt: archivedAt,
+			Members: []domain.ProjectMember{
+				{
+					UserId: ownerUserId,
+					Role:   domain.ProjectMemberRoleOwner,
+				},
+				{
+					UserId: adminUserId,
+					Role:   domain.ProjectMemberRoleAdmin,
+				},
+			},
+		}
+	}
+
+	type testCase struct {
+		name              string
+		project           *domain.Project
+		userId            uuid.UUID
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:          "owner archives project",
+			project:       newProject(nil),
+			userId:        ownerUserId,
+			shouldSucceed: true,
+		},
+		{
+			name:              "admin cannot archive project",
+			project:           newProject(nil),
+			userId:            adminUserId,
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name:              "project is already archived",
+			project:           newProject(&archivedAt),
+			userId:            ownerUserId,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			mockRepo.On("GetById", mock.Anything, validProjectId).Return(tt.project, nil)
+			if tt.shouldSucceed {
+				mockRepo.On("UpdateArchivedAt", mock.Anything, tt.project).Return(nil)
+			}
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+			project, err := projectService.Archive(context.Background(), validProjectId, tt.userId)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.True(t, project.IsArchived())
+			} else {
+				require.Error(t, err)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("archived project is read-only", func(t *testing.T) {
+		mockRepo := &mockProjectRepository{}
+		mockRepo.On("GetById", mock.Anything, validProjectId).Return(newProject(&archivedAt), nil)
+
+		projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+		_, err := projectService.Update(context.Background(), service.UpdateProjectRequest{
+			Id:          validProjectId,
+			Name:        "Updated Project",
+			Description: "Updated Description",
+			UserId:      ownerUserId,
+		})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.BusinessValidationErrorCode, domainErr.Code)
+		}
+
+		project, err := projectService.GetById(context.Background(), validProjectId, adminUserId)
+		require.NoError(t, err)
+		assert.True(t, project.IsArchived())
+	})
+}
+
+func TestProjectService_Delete(t *testing.T) {
+	ownerUserId := uuid.New()
+	memberUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validProject := &domain.Project{
+		Id:     validProjectId,
+		Name:   "Test Project",
+		UserId: ownerUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: ownerUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: memberUserId,
+				Role:   domain.ProjectMemberRoleMember,
+			},
+		},
+	}
+
+	type testCase struct {
+		name              string
+		userId            uuid.UUID
+		mockSetup         func(*mockProjectRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:   "owner deletes project",
+			userId: ownerUserId,
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("Delete", mock.Anything, validProjectId).Return([]uuid.UUID{uuid.New()}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:   "member cannot delete project",
+			userId: memberUserId,
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name:   "throws server error",
+			userId: ownerUserId,
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+				repo.On("Delete", mock.Anything, validProjectId).Return(nil, errors.New("server error"))
+			},
+			expectedErrorCode: string(domain.ServerErrorCode),
+		},
+		{
+			name:              "unauthorized error",
+			userId:            uuid.Nil,
+			mockSetup:         func(repo *mockProjectRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			tt.mockSetup(mockRepo)
+
+			projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, &mockOrganizationRepository{})
+
+			err := projectService.Delete(context.Background(), validProjectId, tt.userId)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_Clone(t *testing.T) {
+	ownerUserId := uuid.New()
+	viewerUserId := uuid.New()
+	validProjectId := uuid.New()
+	parentTaskId := uuid.New()
+	subtaskId := uuid.New()
+
+	validProject := &domain.Project{
+		Id:     validProjectId,
+		Name:   "Test Project",
+		UserId: ownerUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: ownerUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: viewerUserId,
+				Role:   domain.ProjectMemberRoleViewer,
+			},
+		},
+	}
+
+	viewerUser := &domain.User{Id: viewerUserId, Name: "Viewer"}
+
+	// the subtask was created first and moved under its parent later
+	sourceTasks := []domain.Task{
+		{
+			Id:       subtaskId,
+			ParentId: &parentTaskId,
+			Title:    "Subtask",
+			Status:   domain.TaskStatusDone,
+		},
+		{
+			Id:     parentTaskId,
+			Title:  "Parent",
+			Status: domain.TaskStatusDoing,
+		},
+	}
+
+	t.Run("viewer clones project with its tasks", func(t *testing.T) {
+		mockRepo := &mockProjectRepository{}
+		mockRepo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+		mockRepo.On("ListTasks", mock.Anything, validProjectId).Return(sourceTasks, nil)
+
+		var createdTasks []domain.ProjectTemplateTask
+		mockRepo.On("CreateWithTasks", mock.Anything, mock.AnythingOfType("*domain.Project"), mock.AnythingOfType("[]domain.ProjectTemplateTask"), viewerUser).Return(nil).Run(func(args mock.Arguments) {
+			createdTasks = args.Get(2).([]domain.ProjectTemplateTask)
+		})
+
+		mockOrganizationRepo := &mockOrganizationRepository{}
+		mockOrganizationRepo.On("GetPersonalByUserId", mock.Anything, viewerUserId).Return(&domain.Organization{Id: uuid.New(), UserId: viewerUserId, Personal: true}, nil)
+
+		mockUserRepo := &mockUserRepository{}
+		mockUserRepo.On("GetById", mock.Anything, viewerUserId).Return(viewerUser, nil)
+
+		projectService := service.NewProjectService(mockRepo, mockUserRepo, &mockPublisher{}, mockOrganizationRepo)
+
+		project, err := projectService.Clone(context.Background(), service.CloneProjectRequest{
+			ProjectId:   validProjectId,
+			Name:        "Cloned Project",
+			Description: "Cloned Description",
+			UserId:      viewerUserId,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Cloned Project", project.Name)
+		assert.Equal(t, viewerUserId, project.UserId)
+		require.Len(t, project.Members, 1)
+		assert.Equal(t, domain.ProjectMemberRoleOwner, project.Members[0].Role)
+
+		require.Len(t, createdTasks, 2)
+		assert.Equal(t, "Parent", createdTasks[0].Title)
+		assert.Nil(t, createdTasks[0].ParentIndex)
+		assert.Equal(t, "Subtask", createdTasks[1].Title)
+		assert.Equal(t, domain.TaskStatusDone, createdTasks[1].Status)
+		require.NotNil(t, createdTasks[1].ParentIndex)
+		assert.Equal(t, 0, *createdTasks[1].ParentIndex)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("non member cannot clone project", func(t *testing.T) {
+		mockRepo := &mockProjectRepository{}
+		mockRepo.On("GetById", mock.Anything, validProjectId).Return(validProject, nil)
+
+		mockOrganizationRepo := &mockOrganizationRepository{}
+		mockOrganizationRepo.On("GetPersonalByUserId", mock.Anything, mock.Anything).Return(&domain.Organization{Id: uuid.New(), Personal: true}, nil)
+
+		projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, mockOrganizationRepo)
+
+		_, err := projectService.Clone(context.Background(), service.CloneProjectRequest{
+			ProjectId: validProjectId,
+			Name:      "Cloned Project",
+			UserId:    uuid.New(),
+		})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.ForbiddenErrorCode, domainErr.Code)
+		}
+
+		mockRepo.AssertNotCalled(t, "CreateWithTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("viewer cannot clone into an organization they are not a member of", func(t *testing.T) {
+		organizationId := uuid.New()
+
+		mockRepo := &mockProjectRepository{}
+
+		mockOrganizationRepo := &mockOrganizationRepository{}
+		mockOrganizationRepo.On("GetMember", mock.Anything, organizationId, viewerUserId).Return(nil, domain.NotFoundError("organization member not found"))
+
+		projectService := service.NewProjectService(mockRepo, &mockUserRepository{}, &mockPublisher{}, mockOrganizationRepo)
+
+		_, err := projectService.Clone(context.Background(), service.CloneProjectRequest{
+			ProjectId:      validProjectId,
+			Name:           "Cloned Project",
+			UserId:         viewerUserId,
+			OrganizationId: organizationId,
+		})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.NotFoundErrorCode, domainErr.Code)
+		}
+
+		mockRepo.AssertNotCalled(t, "ListTasks", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CreateWithTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProjectService_CreateFromTemplate(t *testing.T) {
+	userId := uuid.New()
+	templateId := uuid.New()
+
+	template := &domain.ProjectTemplate{
+		Id:     templateId,
+		UserId: userId,
+		Name:   "Template",
+		Tasks: []domain.ProjectTemplateTask{
+			{Title: "First task", Status: domain.TaskStatusPending},
+		},
+	}
+
+	type testCase struct {
+		name              string
+		userId            uuid.UUID
+		mockSetup         func(*mockProjectRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:   "creates project from own template",
+			userId: userId,
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetTemplateById", mock.Anything, templateId).Return(template, nil)
+				repo.On("CreateWithTasks", mock.Anything, mock.AnythingOfType("*domain.Project"), template.Tasks, mock.AnythingOfType("*domain.User")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:   "template of another user",
+			userId: uuid.New(),
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetTemplateById", mock.Anything, templateId).Return(template, nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name:   "template not found",
+			userId: userId,
+			mockSetup: func(repo *mockProjectRepository) {
+				repo.On("GetTemplateById", mock.Anything, templateId).Return(nil, domain.NotFoundError("template not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name:              "unauthorized error",
+			userId:            uuid.Nil,
+			mockSetup:         func(repo *mockProjectRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockProjectRepository{}
+			tt.mockSetup(mockRepo)
+
+			mockOrganizationRepo := &mockOrganizationRepository{}
+			mockOrganizationRepo.On("GetPersonalByUserId", mock.Anything, tt.userId).Return(&domain.Organization{Id: uuid.New(), UserId: tt.userId, Personal: true}, nil).Maybe()
+
+			mockUserRepo := &mockUserRepository{}
+			mockUserRepo.On("GetById", mock.Anything, tt.userId).Return(&domain.User{Id: tt.userId, Name: "Test User"}, nil).Maybe()
+
+			projectService := service.NewProjectService(mockRepo, mockUserRepo, &mockPublisher{}, mockOrganizationRepo)
+
+			project, err := projectService.CreateFromTemplate(context.Background(), service.CreateFromTemplateRequest{
+				TemplateId:  templateId,
+				Name:        "New Project",
+				Description: "New Description",
+				UserId:      tt.userId,
+			})
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				assert.Equal(t, "New Project", project.Name)
+				assert.Equal(t, tt.userId, project.UserId)
+			} else {
+				require.Error(t, err)
+				mockRepo.AssertNotCalled(t, "CreateWithTasks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
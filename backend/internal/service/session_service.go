@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/google/uuid"
+)
+
+type sessionRepository interface {
+	IsSessionActive(ctx context.Context, id uuid.UUID, userId uuid.UUID) (bool, error)
+}
+
+// SessionService checks the session access tokens were issued for. It runs on every
+// authenticated request and is not cached, a revoked session must stop working right away
+// on every instance.
+type SessionService struct {
+	sessionRepository sessionRepository
+}
+
+func NewSessionService(sessionRepository sessionRepository) *SessionService {
+	return &SessionService{
+		sessionRepository: sessionRepository,
+	}
+}
+
+// CheckSession returns an unauthorized error when the session does not exist, belongs to
+// another user, was revoked or its user was deleted.
+func (ss *SessionService) CheckSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	const INVALID_SESSION_ERROR_MESSAGE = "invalid session"
+
+	if userId == uuid.Nil || sessionId == uuid.Nil {
+		return domain.UnauthorizedError(INVALID_SESSION_ERROR_MESSAGE)
+	}
+
+	active, err := ss.sessionRepository.IsSessionActive(ctx, sessionId, userId)
+	if err != nil {
+		return domain.ServerError("failed to get session", err)
+	}
+
+	if !active {
+		return domain.UnauthorizedError(INVALID_SESSION_ERROR_MESSAGE)
+	}
+
+	return nil
+}
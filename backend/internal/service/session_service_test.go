@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSessionService_CheckSession(t *testing.T) {
+	userId := uuid.New()
+	sessionId := uuid.New()
+
+	type testCase struct {
+		name              string
+		userId            uuid.UUID
+		sessionId         uuid.UUID
+		mockSetup         func(*mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:      "active session",
+			userId:    userId,
+			sessionId: sessionId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("IsSessionActive", mock.Anything, sessionId, userId).Return(true, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:      "revoked, unknown or deleted user session",
+			userId:    userId,
+			sessionId: sessionId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("IsSessionActive", mock.Anything, sessionId, userId).Return(false, nil)
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name:              "token without session",
+			userId:            userId,
+			sessionId:         uuid.Nil,
+			mockSetup:         func(repo *mockUserRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name:      "repository error",
+			userId:    userId,
+			sessionId: sessionId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("IsSessionActive", mock.Anything, sessionId, userId).Return(false, errors.New("database error"))
+			},
+			expectedErrorCode: string(domain.ServerErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo)
+
+			sessionService := service.NewSessionService(mockRepo)
+
+			err := sessionService.CheckSession(context.Background(), tt.userId, tt.sessionId)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("revoked sessions are rejected on the next check", func(t *testing.T) {
+		mockRepo := &mockUserRepository{}
+		mockRepo.On("IsSessionActive", mock.Anything, sessionId, userId).Return(true, nil).Once()
+		mockRepo.On("IsSessionActive", mock.Anything, sessionId, userId).Return(false, nil).Once()
+
+		sessionService := service.NewSessionService(mockRepo)
+
+		assert.NoError(t, sessionService.CheckSession(context.Background(), userId, sessionId))
+		assert.Error(t, sessionService.CheckSession(context.Background(), userId, sessionId))
+	})
+}
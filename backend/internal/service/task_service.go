@@ -4,20 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
 	"github.com/google/uuid"
 )
 
 type taskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
 	GetById(ctx context.Context, id uuid.UUID) (*domain.Task, error)
-	ListByProjectId(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error)
+	ListByProjectId(ctx context.Context, projectId uuid.UUID, filter domain.TaskListFilter, params utils.PaginationAfterParams) ([]domain.Task, error)
 	Update(ctx context.Context, task *domain.Task) error
 
 	CreateChanges(ctx context.Context, task *domain.Task, changes []domain.TaskChange) error
+
+	UpdateParent(ctx context.Context, task *domain.Task) error
+	ListSubtasks(ctx context.Context, parentId uuid.UUID) ([]domain.Task, error)
+	ListAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+
+	CreateDependency(ctx context.Context, projectId uuid.UUID, taskId uuid.UUID, blockedByTaskId uuid.UUID) (bool, error)
+	DeleteDependency(ctx context.Context, taskId uuid.UUID, blockedByTaskId uuid.UUID) error
+	ListBlockers(ctx context.Context, taskId uuid.UUID) ([]domain.Task, error)
+
+	CreateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error
+	GetChecklistItemById(ctx context.Context, id uuid.UUID) (*domain.TaskChecklistItem, error)
+	ListChecklistItems(ctx context.Context, taskId uuid.UUID) ([]domain.TaskChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, id uuid.UUID) error
+
+	CreateComment(ctx context.Context, comment *domain.TaskComment) error
+	GetCommentById(ctx context.Context, id uuid.UUID) (*domain.TaskComment, error)
+	ListComments(ctx context.Context, taskId uuid.UUID) ([]domain.TaskComment, error)
+	UpdateComment(ctx context.Context, comment *domain.TaskComment) error
+	DeleteComment(ctx context.Context, id uuid.UUID) error
 }
 
 type taskServiceProjectRepository interface {
@@ -50,6 +72,7 @@ func NewTaskService(taskRepository taskRepository, projectRepository taskService
 
 type CreateTaskRequest struct {
 	ProjectId     uuid.UUID
+	ParentId      *uuid.UUID
 	Title         string
 	Description   string
 	RequestUserId uuid.UUID
@@ -72,15 +95,31 @@ func (ts *TaskService) Create(ctx context.Context, request CreateTaskRequest) (*
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	hasPermission := false
-	for _, member := range project.Members {
-		if member.UserId == request.RequestUserId {
-			hasPermission = true
-			break
-		}
+	_, err = project.Authorize(request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
 	}
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+
+	if request.ParentId != nil {
+		parent, err := ts.taskRepository.GetById(ctx, *request.ParentId)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) {
+				if domainErr.Code == domain.NotFoundErrorCode {
+					return nil, domain.NotFoundError("parent task not found")
+				}
+				return nil, domainErr
+			}
+			return nil, domain.ServerError("failed to get parent task", err)
+		}
+
+		if parent.ProjectId != request.ProjectId {
+			return nil, domain.BusinessValidationError("parent task must belong to the same project")
+		}
+
+		if !parent.IsOpen() {
+			return nil, domain.BusinessValidationError("cannot add a subtask to a closed task")
+		}
 	}
 
 	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
@@ -90,6 +129,7 @@ func (ts *TaskService) Create(ctx context.Context, request CreateTaskRequest) (*
 
 	task := domain.Task{
 		ProjectId:   request.ProjectId,
+		ParentId:    request.ParentId,
 		Title:       request.Title,
 		Description: request.Description,
 		AuthorId:    request.RequestUserId,
@@ -105,12 +145,7 @@ func (ts *TaskService) Create(ctx context.Context, request CreateTaskRequest) (*
 		return nil, domain.ServerError("failed to create task", err)
 	}
 
-	taskChange := domain.TaskChange{
-		TaskId:            task.Id,
-		AuthorId:          request.RequestUserId,
-		CreatedAt:         time.Now(),
-		ChangeDescription: fmt.Sprintf("Task created by %s", user.Name),
-	}
+	taskChange := domain.NewTaskCreatedChange(task.Id, user)
 
 	err = ts.taskRepository.CreateChanges(ctx, &task, []domain.TaskChange{taskChange})
 	if err != nil {
@@ -164,21 +199,15 @@ func (ts *TaskService) Update(ctx context.Context, request UpdateTaskRequest) (*
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	hasPermission := false
-	for _, member := range project.Members {
-		if member.UserId == request.RequestUserId {
-			hasPermission = true
-			break
-		}
-	}
-
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	_, err = project.Authorize(request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
 	}
 
 	updatedTask := domain.Task{
 		Id:          task.Id,
 		ProjectId:   task.ProjectId,
+		ParentId:    task.ParentId,
 		Title:       request.Title,
 		Description: request.Description,
 		Status:      task.Status,
@@ -194,6 +223,41 @@ func (ts *TaskService) Update(ctx context.Context, request UpdateTaskRequest) (*
 		return nil, err
 	}
 
+	// a closed task cannot have open subtasks, the same rule SetParent enforces
+	// when linking tasks, so it is checked whenever a task is closed or reopened.
+	if task.IsOpen() && !updatedTask.IsOpen() {
+		updatedTask.Subtasks, err = ts.taskRepository.ListSubtasks(ctx, task.Id)
+		if err != nil {
+			return nil, domain.ServerError("failed to list subtasks", err)
+		}
+
+		if updatedTask.HasOpenSubtasks() {
+			return nil, domain.BusinessValidationError("task cannot be closed while it has open subtasks")
+		}
+	}
+
+	if !task.IsOpen() && updatedTask.IsOpen() && task.ParentId != nil {
+		parent, err := ts.taskRepository.GetById(ctx, *task.ParentId)
+		if err != nil {
+			return nil, domain.ServerError("failed to get parent task", err)
+		}
+
+		if !parent.IsOpen() {
+			return nil, domain.BusinessValidationError("subtask cannot be reopened while its parent task is closed")
+		}
+	}
+
+	if updatedTask.Status == domain.TaskStatusDone && task.Status != domain.TaskStatusDone {
+		updatedTask.Blockers, err = ts.taskRepository.ListBlockers(ctx, task.Id)
+		if err != nil {
+			return nil, domain.ServerError("failed to list task blockers", err)
+		}
+
+		if updatedTask.HasOpenBlockers() {
+			return nil, domain.BusinessValidationError("task cannot be done while it is blocked by open tasks")
+		}
+	}
+
 	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
 	if err != nil {
 		var domainErr domain.DomainError
@@ -228,38 +292,57 @@ func (ts *TaskService) Update(ctx context.Context, request UpdateTaskRequest) (*
 	return &updatedTask, nil
 }
 
-func (ts *TaskService) List(ctx context.Context, projectId uuid.UUID, userId uuid.UUID) ([]domain.Task, error) {
-	if projectId == uuid.Nil {
+type ListTasksRequest struct {
+	ProjectId uuid.UUID
+	UserId    uuid.UUID
+	Filter    domain.TaskListFilter
+	Params    utils.PaginationAfterParams
+}
+
+func (ts *TaskService) List(ctx context.Context, request ListTasksRequest) (*utils.CursorPaginated[domain.Task], error) {
+	if request.ProjectId == uuid.Nil {
 		return nil, domain.BusinessValidationError("project_id is required")
 	}
 
-	if userId == uuid.Nil {
+	if request.UserId == uuid.Nil {
 		return nil, domain.UnauthorizedError("unauthorized")
 	}
 
-	project, err := ts.projectRepository.GetById(ctx, projectId)
+	project, err := ts.projectRepository.GetById(ctx, request.ProjectId)
 	if err != nil {
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	hasPermission := false
-	for _, member := range project.Members {
-		if member.UserId == userId {
-			hasPermission = true
-			break
-		}
+	_, err = project.Authorize(request.UserId, domain.PermissionTaskView)
+	if err != nil {
+		return nil, err
 	}
 
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
-	}
+	// one extra task is requested to know whether there is a next page
+	params := request.Params
+	params.Limit++
 
-	tasks, err := ts.taskRepository.ListByProjectId(ctx, projectId)
+	tasks, err := ts.taskRepository.ListByProjectId(ctx, request.ProjectId, request.Filter, params)
 	if err != nil {
 		return nil, domain.ServerError("failed to list tasks", err)
 	}
 
-	return tasks, nil
+	hasNext := len(tasks) > int(request.Params.Limit)
+	if hasNext {
+		tasks = tasks[:request.Params.Limit]
+	}
+
+	cursorPaginated := utils.CursorPaginated[domain.Task]{
+		Data:    tasks,
+		HasNext: hasNext,
+	}
+
+	if hasNext {
+		last := tasks[len(tasks)-1]
+		cursorPaginated.NextCursor = utils.EncodeAfterCursor(last.SortValue(request.Filter.SortBy), last.Id)
+	}
+
+	return &cursorPaginated, nil
 }
 
 func (ts *TaskService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Task, error) {
@@ -267,7 +350,169 @@ func (ts *TaskService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UU
 		return nil, domain.UnauthorizedError("unauthorized")
 	}
 
-	project, err := ts.projectRepository.GetById(ctx, id)
+	task, err := ts.getTaskForMember(ctx, id, userId, domain.PermissionTaskView)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Subtasks, err = ts.taskRepository.ListSubtasks(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list subtasks", err)
+	}
+
+	task.Blockers, err = ts.taskRepository.ListBlockers(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list task blockers", err)
+	}
+
+	task.Checklist, err = ts.taskRepository.ListChecklistItems(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list checklist items", err)
+	}
+
+	task.ComputeProgress()
+
+	return task, nil
+}
+
+type CreateTaskCommentRequest struct {
+	TaskId        uuid.UUID
+	Content       string
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) CreateComment(ctx context.Context, request CreateTaskCommentRequest) (*domain.TaskComment, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	task, err := ts.getTaskForMember(ctx, request.TaskId, request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	comment := domain.TaskComment{
+		TaskId:    task.Id,
+		ProjectId: task.ProjectId,
+		AuthorId:  request.RequestUserId,
+		Content:   request.Content,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Author:    user,
+	}
+
+	err = ts.taskRepository.CreateComment(ctx, &comment)
+	if err != nil {
+		return nil, domain.ServerError("failed to create task comment", err)
+	}
+
+	err = ts.publisher.Publish(ctx, events.TaskCommentCreated, comment)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish task comment created event", err)
+	}
+
+	return &comment, nil
+}
+
+func (ts *TaskService) ListComments(ctx context.Context, taskId uuid.UUID, userId uuid.UUID) ([]domain.TaskComment, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	task, err := ts.getTaskForMember(ctx, taskId, userId, domain.PermissionTaskView)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := ts.taskRepository.ListComments(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list task comments", err)
+	}
+
+	return comments, nil
+}
+
+type UpdateTaskCommentRequest struct {
+	TaskId        uuid.UUID
+	CommentId     uuid.UUID
+	Content       string
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) UpdateComment(ctx context.Context, request UpdateTaskCommentRequest) (*domain.TaskComment, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	comment, err := ts.getCommentForAuthor(ctx, request.TaskId, request.CommentId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Content = request.Content
+	comment.UpdatedAt = time.Now()
+
+	err = ts.taskRepository.UpdateComment(ctx, comment)
+	if err != nil {
+		return nil, domain.ServerError("failed to update task comment", err)
+	}
+
+	return comment, nil
+}
+
+type DeleteTaskCommentRequest struct {
+	TaskId        uuid.UUID
+	CommentId     uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) DeleteComment(ctx context.Context, request DeleteTaskCommentRequest) error {
+	if request.RequestUserId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	comment, err := ts.getCommentForAuthor(ctx, request.TaskId, request.CommentId, request.RequestUserId)
+	if err != nil {
+		return err
+	}
+
+	err = ts.taskRepository.DeleteComment(ctx, comment.Id)
+	if err != nil {
+		return domain.ServerError("failed to delete task comment", err)
+	}
+
+	return nil
+}
+
+// getCommentForAuthor loads a comment of the given task and makes sure it can only be
+// changed by its author while they are still a member of the project.
+func (ts *TaskService) getCommentForAuthor(ctx context.Context, taskId uuid.UUID, commentId uuid.UUID, userId uuid.UUID) (*domain.TaskComment, error) {
+	comment, err := ts.taskRepository.GetCommentById(ctx, commentId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("comment not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get task comment", err)
+	}
+
+	if comment.TaskId != taskId {
+		return nil, domain.NotFoundError("comment not found")
+	}
+
+	if comment.AuthorId != userId {
+		return nil, domain.ForbiddenError("only the author can change this comment")
+	}
+
+	project, err := ts.projectRepository.GetById(ctx, comment.ProjectId)
 	if err != nil {
 		var domainErr domain.DomainError
 		if errors.As(err, &domainErr) {
@@ -279,19 +524,338 @@ func (ts *TaskService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UU
 		return nil, domain.ServerError("failed to get project", err)
 	}
 
-	hasPermission := false
-	for _, member := range project.Members {
-		if member.UserId == userId {
-			hasPermission = true
-			break
+	_, err = project.Authorize(userId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+type SetTaskParentRequest struct {
+	TaskId        uuid.UUID
+	ParentId      *uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+// SetParent links a task to a parent task of the same project, or detaches it
+// when ParentId is nil.
+func (ts *TaskService) SetParent(ctx context.Context, request SetTaskParentRequest) (*domain.Task, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	task, err := ts.getTaskForMember(ctx, request.TaskId, request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	taskChange := domain.NewTaskChange(task.Id, user, "Parent task removed")
+
+	if request.ParentId != nil {
+		if *request.ParentId == task.Id {
+			return nil, domain.BusinessValidationError("a task cannot be its own parent")
 		}
+
+		parent, err := ts.taskRepository.GetById(ctx, *request.ParentId)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) {
+				if domainErr.Code == domain.NotFoundErrorCode {
+					return nil, domain.NotFoundError("parent task not found")
+				}
+				return nil, domainErr
+			}
+			return nil, domain.ServerError("failed to get parent task", err)
+		}
+
+		if parent.ProjectId != task.ProjectId {
+			return nil, domain.BusinessValidationError("parent task must belong to the same project")
+		}
+
+		if !parent.IsOpen() && task.IsOpen() {
+			return nil, domain.BusinessValidationError("cannot add an open subtask to a closed task")
+		}
+
+		ancestorIds, err := ts.taskRepository.ListAncestorIds(ctx, parent.Id)
+		if err != nil {
+			return nil, domain.ServerError("failed to list parent task ancestors", err)
+		}
+
+		if slices.Contains(ancestorIds, task.Id) {
+			return nil, domain.BusinessValidationError("parent task would create a cycle")
+		}
+
+		taskChange = domain.NewTaskChange(task.Id, user, fmt.Sprintf("Parent task set to %s", parent.Title))
 	}
 
-	if !hasPermission {
-		return nil, domain.ForbiddenError("forbidden")
+	task.ParentId = request.ParentId
+	task.UpdatedAt = time.Now()
+
+	err = ts.taskRepository.UpdateParent(ctx, task)
+	if err != nil {
+		return nil, domain.ServerError("failed to update parent task", err)
+	}
+
+	err = ts.taskRepository.CreateChanges(ctx, task, []domain.TaskChange{taskChange})
+	if err != nil {
+		return nil, domain.ServerError("failed to create task changes", err)
+	}
+
+	task.Changes = append(task.Changes, taskChange)
+
+	err = ts.publisher.Publish(ctx, events.TaskUpdated, task)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish task updated event", err)
+	}
+
+	return task, nil
+}
+
+type TaskBlockerRequest struct {
+	TaskId          uuid.UUID
+	BlockedByTaskId uuid.UUID
+	RequestUserId   uuid.UUID
+}
+
+// AddBlocker marks the task as blocked by another task of the same project, adding an edge
+// that already exists is a no-op.
+func (ts *TaskService) AddBlocker(ctx context.Context, request TaskBlockerRequest) (*domain.Task, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	if request.TaskId == request.BlockedByTaskId {
+		return nil, domain.BusinessValidationError("a task cannot block itself")
+	}
+
+	task, err := ts.getTaskForMember(ctx, request.TaskId, request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	blocker, err := ts.taskRepository.GetById(ctx, request.BlockedByTaskId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("blocking task not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get blocking task", err)
+	}
+
+	if blocker.ProjectId != task.ProjectId {
+		return nil, domain.BusinessValidationError("blocking task must belong to the same project")
+	}
+
+	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	created, err := ts.taskRepository.CreateDependency(ctx, task.ProjectId, task.Id, blocker.Id)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to create task dependency", err)
+	}
+
+	if !created {
+		task.Blockers, err = ts.taskRepository.ListBlockers(ctx, task.Id)
+		if err != nil {
+			return nil, domain.ServerError("failed to list task blockers", err)
+		}
+
+		return task, nil
+	}
+
+	taskChange := domain.NewTaskChange(task.Id, user, fmt.Sprintf("Marked as blocked by %s", blocker.Title))
+
+	return ts.saveDependencyChange(ctx, task, taskChange)
+}
+
+// RemoveBlocker removes a "blocked by" edge, removing an edge that does not exist is a no-op.
+func (ts *TaskService) RemoveBlocker(ctx context.Context, request TaskBlockerRequest) (*domain.Task, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	task, err := ts.getTaskForMember(ctx, request.TaskId, request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers, err := ts.taskRepository.ListBlockers(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list task blockers", err)
+	}
+
+	blockerIndex := slices.IndexFunc(blockers, func(blocker domain.Task) bool {
+		return blocker.Id == request.BlockedByTaskId
+	})
+	if blockerIndex == -1 {
+		return nil, domain.NotFoundError("task dependency not found")
+	}
+
+	user, err := ts.userRepository.GetById(ctx, request.RequestUserId)
+	if err != nil {
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	err = ts.taskRepository.DeleteDependency(ctx, task.Id, request.BlockedByTaskId)
+	if err != nil {
+		return nil, domain.ServerError("failed to delete task dependency", err)
+	}
+
+	taskChange := domain.NewTaskChange(task.Id, user, fmt.Sprintf("No longer blocked by %s", blockers[blockerIndex].Title))
+
+	return ts.saveDependencyChange(ctx, task, taskChange)
+}
+
+func (ts *TaskService) saveDependencyChange(ctx context.Context, task *domain.Task, taskChange domain.TaskChange) (*domain.Task, error) {
+	err := ts.taskRepository.CreateChanges(ctx, task, []domain.TaskChange{taskChange})
+	if err != nil {
+		return nil, domain.ServerError("failed to create task changes", err)
+	}
+
+	task.Changes = append(task.Changes, taskChange)
+
+	task.Blockers, err = ts.taskRepository.ListBlockers(ctx, task.Id)
+	if err != nil {
+		return nil, domain.ServerError("failed to list task blockers", err)
+	}
+
+	err = ts.publisher.Publish(ctx, events.TaskUpdated, task)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish task updated event", err)
+	}
+
+	return task, nil
+}
+
+type CreateChecklistItemRequest struct {
+	TaskId        uuid.UUID
+	Title         string
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) CreateChecklistItem(ctx context.Context, request CreateChecklistItemRequest) (*domain.TaskChecklistItem, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	task, err := ts.getTaskForMember(ctx, request.TaskId, request.RequestUserId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	item := domain.TaskChecklistItem{
+		TaskId:    task.Id,
+		Title:     request.Title,
+		Done:      false,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err = ts.taskRepository.CreateChecklistItem(ctx, &item)
+	if err != nil {
+		return nil, domain.ServerError("failed to create checklist item", err)
+	}
+
+	return &item, nil
+}
+
+type UpdateChecklistItemRequest struct {
+	TaskId        uuid.UUID
+	ItemId        uuid.UUID
+	Title         string
+	Done          bool
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) UpdateChecklistItem(ctx context.Context, request UpdateChecklistItemRequest) (*domain.TaskChecklistItem, error) {
+	if request.RequestUserId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	item, err := ts.getChecklistItemForMember(ctx, request.TaskId, request.ItemId, request.RequestUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	item.Title = request.Title
+	item.Done = request.Done
+	item.UpdatedAt = time.Now()
+
+	err = ts.taskRepository.UpdateChecklistItem(ctx, item)
+	if err != nil {
+		return nil, domain.ServerError("failed to update checklist item", err)
+	}
+
+	return item, nil
+}
+
+type DeleteChecklistItemRequest struct {
+	TaskId        uuid.UUID
+	ItemId        uuid.UUID
+	RequestUserId uuid.UUID
+}
+
+func (ts *TaskService) DeleteChecklistItem(ctx context.Context, request DeleteChecklistItemRequest) error {
+	if request.RequestUserId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	item, err := ts.getChecklistItemForMember(ctx, request.TaskId, request.ItemId, request.RequestUserId)
+	if err != nil {
+		return err
+	}
+
+	err = ts.taskRepository.DeleteChecklistItem(ctx, item.Id)
+	if err != nil {
+		return domain.ServerError("failed to delete checklist item", err)
+	}
+
+	return nil
+}
+
+func (ts *TaskService) getChecklistItemForMember(ctx context.Context, taskId uuid.UUID, itemId uuid.UUID, userId uuid.UUID) (*domain.TaskChecklistItem, error) {
+	task, err := ts.getTaskForMember(ctx, taskId, userId, domain.PermissionTaskEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := ts.taskRepository.GetChecklistItemById(ctx, itemId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("checklist item not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get checklist item", err)
 	}
 
-	task, err := ts.taskRepository.GetById(ctx, id)
+	if item.TaskId != task.Id {
+		return nil, domain.NotFoundError("checklist item not found")
+	}
+
+	return item, nil
+}
+
+// getTaskForMember loads a task and makes sure the user has the given permission in its project.
+func (ts *TaskService) getTaskForMember(ctx context.Context, taskId uuid.UUID, userId uuid.UUID, permission domain.Permission) (*domain.Task, error) {
+	task, err := ts.taskRepository.GetById(ctx, taskId)
 	if err != nil {
 		var domainErr domain.DomainError
 		if errors.As(err, &domainErr) {
@@ -303,5 +867,22 @@ func (ts *TaskService) GetById(ctx context.Context, id uuid.UUID, userId uuid.UU
 		return nil, domain.ServerError("failed to get task", err)
 	}
 
+	project, err := ts.projectRepository.GetById(ctx, task.ProjectId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			if domainErr.Code == domain.NotFoundErrorCode {
+				return nil, domain.NotFoundError("project not found")
+			}
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get project", err)
+	}
+
+	_, err = project.Authorize(userId, permission)
+	if err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
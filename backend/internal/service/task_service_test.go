@@ -7,6 +7,7 @@ import (
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,8 +31,8 @@ func (m *mockTaskRepository) GetById(ctx context.Context, id uuid.UUID) (*domain
 	return args.Get(0).(*domain.Task), args.Error(1)
 }
 
-func (m *mockTaskRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID) ([]domain.Task, error) {
-	args := m.Called(ctx, projectId)
+func (m *mockTaskRepository) ListByProjectId(ctx context.Context, projectId uuid.UUID, filter domain.TaskListFilter, params utils.PaginationAfterParams) ([]domain.Task, error) {
+	args := m.Called(ctx, projectId, filter, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -54,10 +55,112 @@ func (m *mockTaskRepository) CreateChanges(ctx context.Context, task *domain.Tas
 	return args.Error(0)
 }
 
+func (m *mockTaskRepository) CreateComment(ctx context.Context, comment *domain.TaskComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) GetCommentById(ctx context.Context, id uuid.UUID) (*domain.TaskComment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TaskComment), args.Error(1)
+}
+
+func (m *mockTaskRepository) ListComments(ctx context.Context, taskId uuid.UUID) ([]domain.TaskComment, error) {
+	args := m.Called(ctx, taskId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TaskComment), args.Error(1)
+}
+
+func (m *mockTaskRepository) UpdateComment(ctx context.Context, comment *domain.TaskComment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) DeleteComment(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) UpdateParent(ctx context.Context, task *domain.Task) error {
+	args := m.Called(ctx, task)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) ListSubtasks(ctx context.Context, parentId uuid.UUID) ([]domain.Task, error) {
+	args := m.Called(ctx, parentId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) ListAncestorIds(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *mockTaskRepository) CreateDependency(ctx context.Context, projectId uuid.UUID, taskId uuid.UUID, blockedByTaskId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, projectId, taskId, blockedByTaskId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockTaskRepository) DeleteDependency(ctx context.Context, taskId uuid.UUID, blockedByTaskId uuid.UUID) error {
+	args := m.Called(ctx, taskId, blockedByTaskId)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) ListBlockers(ctx context.Context, taskId uuid.UUID) ([]domain.Task, error) {
+	args := m.Called(ctx, taskId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepository) CreateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) GetChecklistItemById(ctx context.Context, id uuid.UUID) (*domain.TaskChecklistItem, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TaskChecklistItem), args.Error(1)
+}
+
+func (m *mockTaskRepository) ListChecklistItems(ctx context.Context, taskId uuid.UUID) ([]domain.TaskChecklistItem, error) {
+	args := m.Called(ctx, taskId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TaskChecklistItem), args.Error(1)
+}
+
+func (m *mockTaskRepository) UpdateChecklistItem(ctx context.Context, item *domain.TaskChecklistItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *mockTaskRepository) DeleteChecklistItem(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestTaskService_Create(t *testing.T) {
 	validUserId := uuid.New()
 	validProjectId := uuid.New()
 	validTaskId := uuid.New()
+	viewerUserId := uuid.New()
 
 	validProject := domain.Project{
 		Id:          validProjectId,
@@ -68,7 +171,11 @@ func TestTaskService_Create(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: viewerUserId,
+				Role:   domain.ProjectMemberRoleViewer,
 			},
 		},
 		UserId: validUserId,
@@ -122,6 +229,21 @@ func TestTaskService_Create(t *testing.T) {
 			shouldSucceed: true,
 			expectedError: nil,
 		},
+		{
+			name: "forbidden - viewer cannot create tasks",
+			request: service.CreateTaskRequest{
+				ProjectId:     validProjectId,
+				Title:         "Test Task",
+				Description:   "Test Description",
+				RequestUserId: viewerUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+			expectedError:     domain.ForbiddenError("forbidden"),
+		},
 		{
 			name: "unauthorized error",
 			request: service.CreateTaskRequest{
@@ -186,6 +308,12 @@ func TestTaskService_Create(t *testing.T) {
 				assert.Equal(t, tt.expectedTask.Title, task.Title)
 				assert.Equal(t, tt.expectedTask.Description, task.Description)
 				assert.Equal(t, tt.expectedTask.Status, domain.TaskStatusPending)
+
+				// the stored description leaves the author out, their name is added when read
+				if assert.Len(t, task.Changes, 1) {
+					assert.Equal(t, "Task created", task.Changes[0].Description)
+					assert.Equal(t, "Task created by "+validUser.Name, task.Changes[0].ChangeDescription)
+				}
 			} else {
 				require.Error(t, err)
 				require.Nil(t, task)
@@ -223,7 +351,7 @@ func TestTaskService_Update(t *testing.T) {
 		Members: []domain.ProjectMember{
 			{
 				UserId: validUserId,
-				Role:   domain.ProjectMemberRoleCreator,
+				Role:   domain.ProjectMemberRoleOwner,
 			},
 		},
 		UserId: validUserId,
@@ -242,6 +370,24 @@ func TestTaskService_Update(t *testing.T) {
 		Changes:     []domain.TaskChange{},
 	}
 
+	doneTask := validTask
+	doneTask.Status = domain.TaskStatusDone
+
+	doneSubtaskId := uuid.New()
+	doneSubtask := domain.Task{
+		Id:          doneSubtaskId,
+		ProjectId:   validProjectId,
+		ParentId:    &validTaskId,
+		AuthorId:    validUserId,
+		Author:      &validUser,
+		Title:       "Test Subtask",
+		Description: "Test Description",
+		Status:      domain.TaskStatusDone,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Changes:     []domain.TaskChange{},
+	}
+
 	type testCase struct {
 		name                      string
 		request                   service.UpdateTaskRequest
@@ -324,6 +470,128 @@ func TestTaskService_Update(t *testing.T) {
 			expectedErrorCode: string(domain.ForbiddenErrorCode),
 			expectedError:     domain.ForbiddenError("forbidden"),
 		},
+		{
+			name: "task with open subtasks cannot be done",
+			request: service.UpdateTaskRequest{
+				TaskId:        validTaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusDone,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("ListSubtasks", mock.Anything, validTaskId).Return([]domain.Task{
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDone},
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDoing},
+				}, nil)
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "task with closed subtasks can be done",
+			request: service.UpdateTaskRequest{
+				TaskId:        validTaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusDone,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("ListSubtasks", mock.Anything, validTaskId).Return([]domain.Task{
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDone},
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusArchived},
+				}, nil)
+				repo.On("ListBlockers", mock.Anything, validTaskId).Return([]domain.Task{
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDone},
+				}, nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+				repo.On("CreateChanges", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.AnythingOfType("[]domain.TaskChange")).Return(nil)
+				userRepo.On("GetById", mock.Anything, validUserId).Return(&validUser, nil)
+			},
+			shouldSucceed:             true,
+			expectedTaskChangesLength: 3,
+		},
+		{
+			name: "task blocked by open tasks cannot be done",
+			request: service.UpdateTaskRequest{
+				TaskId:        validTaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusDone,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("ListSubtasks", mock.Anything, validTaskId).Return([]domain.Task{}, nil)
+				repo.On("ListBlockers", mock.Anything, validTaskId).Return([]domain.Task{
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusPending},
+				}, nil)
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "task with open subtasks cannot be archived",
+			request: service.UpdateTaskRequest{
+				TaskId:        validTaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusArchived,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("ListSubtasks", mock.Anything, validTaskId).Return([]domain.Task{
+					{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusPending},
+				}, nil)
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "subtask cannot be reopened while its parent is closed",
+			request: service.UpdateTaskRequest{
+				TaskId:        doneSubtaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusDoing,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, doneSubtaskId).Return(&doneSubtask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("GetById", mock.Anything, validTaskId).Return(&doneTask, nil)
+			},
+			shouldSucceed:     false,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name: "subtask can be reopened while its parent is open",
+			request: service.UpdateTaskRequest{
+				TaskId:        doneSubtaskId,
+				Title:         "Updated Task",
+				Description:   "Updated Description",
+				Status:        domain.TaskStatusDoing,
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, doneSubtaskId).Return(&doneSubtask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				repo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+				repo.On("CreateChanges", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.AnythingOfType("[]domain.TaskChange")).Return(nil)
+				userRepo.On("GetById", mock.Anything, validUserId).Return(&validUser, nil)
+			},
+			shouldSucceed:             true,
+			expectedTaskChangesLength: 3,
+		},
 		{
 			name: "task not found",
 			request: service.UpdateTaskRequest{
@@ -378,3 +646,755 @@ func TestTaskService_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskService_CreateComment(t *testing.T) {
+	validUserId := uuid.New()
+	validProjectId := uuid.New()
+	validTaskId := uuid.New()
+
+	validUser := domain.User{
+		Id:    validUserId,
+		Name:  "Test User",
+		Email: "user@example.com",
+	}
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: validUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: validUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	validTask := domain.Task{
+		Id:        validTaskId,
+		ProjectId: validProjectId,
+		AuthorId:  validUserId,
+		Title:     "Test Task",
+		Status:    domain.TaskStatusPending,
+	}
+
+	type testCase struct {
+		name              string
+		request           service.CreateTaskCommentRequest
+		mockSetup         func(*mockTaskRepository, *mockProjectRepository, *mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "successful comment creation",
+			request: service.CreateTaskCommentRequest{
+				TaskId:        validTaskId,
+				Content:       "Looks good",
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				userRepo.On("GetById", mock.Anything, validUserId).Return(&validUser, nil)
+				repo.On("CreateComment", mock.Anything, mock.AnythingOfType("*domain.TaskComment")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "unauthorized error",
+			request: service.CreateTaskCommentRequest{
+				TaskId:        validTaskId,
+				Content:       "Looks good",
+				RequestUserId: uuid.Nil,
+			},
+			mockSetup:         func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+		},
+		{
+			name: "task not found",
+			request: service.CreateTaskCommentRequest{
+				TaskId:        validTaskId,
+				Content:       "Looks good",
+				RequestUserId: validUserId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(nil, domain.NotFoundError("task not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name: "forbidden error",
+			request: service.CreateTaskCommentRequest{
+				TaskId:        validTaskId,
+				Content:       "Looks good",
+				RequestUserId: uuid.New(),
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository, userRepo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTaskRepository{}
+			mockProjectRepo := &mockProjectRepository{}
+			mockUserRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo, mockProjectRepo, mockUserRepo)
+			service := service.NewTaskService(mockRepo, mockProjectRepo, mockUserRepo, &mockPublisher{})
+
+			comment, err := service.CreateComment(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				require.NotNil(t, comment)
+
+				assert.Equal(t, tt.request.Content, comment.Content)
+				assert.Equal(t, validTaskId, comment.TaskId)
+				assert.Equal(t, validProjectId, comment.ProjectId)
+				assert.Equal(t, validUserId, comment.AuthorId)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, comment)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockProjectRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskService_UpdateComment(t *testing.T) {
+	authorId := uuid.New()
+	otherMemberId := uuid.New()
+	validProjectId := uuid.New()
+	validTaskId := uuid.New()
+	validCommentId := uuid.New()
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: authorId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: authorId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+			{
+				UserId: otherMemberId,
+				Role:   domain.ProjectMemberRoleMember,
+			},
+		},
+	}
+
+	newComment := func() *domain.TaskComment {
+		return &domain.TaskComment{
+			Id:        validCommentId,
+			TaskId:    validTaskId,
+			ProjectId: validProjectId,
+			AuthorId:  authorId,
+			Content:   "Original content",
+		}
+	}
+
+	type testCase struct {
+		name              string
+		request           service.UpdateTaskCommentRequest
+		mockSetup         func(*mockTaskRepository, *mockProjectRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name: "author updates comment",
+			request: service.UpdateTaskCommentRequest{
+				TaskId:        validTaskId,
+				CommentId:     validCommentId,
+				Content:       "Edited content",
+				RequestUserId: authorId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository) {
+				repo.On("GetCommentById", mock.Anything, validCommentId).Return(newComment(), nil)
+				projectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+				repo.On("UpdateComment", mock.Anything, mock.AnythingOfType("*domain.TaskComment")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "other member cannot update comment",
+			request: service.UpdateTaskCommentRequest{
+				TaskId:        validTaskId,
+				CommentId:     validCommentId,
+				Content:       "Edited content",
+				RequestUserId: otherMemberId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository) {
+				repo.On("GetCommentById", mock.Anything, validCommentId).Return(newComment(), nil)
+			},
+			expectedErrorCode: string(domain.ForbiddenErrorCode),
+		},
+		{
+			name: "comment belongs to another task",
+			request: service.UpdateTaskCommentRequest{
+				TaskId:        uuid.New(),
+				CommentId:     validCommentId,
+				Content:       "Edited content",
+				RequestUserId: authorId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository) {
+				repo.On("GetCommentById", mock.Anything, validCommentId).Return(newComment(), nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+		{
+			name: "comment not found",
+			request: service.UpdateTaskCommentRequest{
+				TaskId:        validTaskId,
+				CommentId:     validCommentId,
+				Content:       "Edited content",
+				RequestUserId: authorId,
+			},
+			mockSetup: func(repo *mockTaskRepository, projectRepo *mockProjectRepository) {
+				repo.On("GetCommentById", mock.Anything, validCommentId).Return(nil, domain.NotFoundError("comment not found"))
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTaskRepository{}
+			mockProjectRepo := &mockProjectRepository{}
+			tt.mockSetup(mockRepo, mockProjectRepo)
+			service := service.NewTaskService(mockRepo, mockProjectRepo, &mockUserRepository{}, &mockPublisher{})
+
+			comment, err := service.UpdateComment(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				require.NotNil(t, comment)
+				assert.Equal(t, tt.request.Content, comment.Content)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, comment)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockProjectRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskService_DeleteComment(t *testing.T) {
+	authorId := uuid.New()
+	validProjectId := uuid.New()
+	validTaskId := uuid.New()
+	validCommentId := uuid.New()
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: authorId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: authorId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	comment := domain.TaskComment{
+		Id:        validCommentId,
+		TaskId:    validTaskId,
+		ProjectId: validProjectId,
+		AuthorId:  authorId,
+		Content:   "Original content",
+	}
+
+	t.Run("author deletes comment", func(t *testing.T) {
+		mockRepo := &mockTaskRepository{}
+		mockProjectRepo := &mockProjectRepository{}
+		mockRepo.On("GetCommentById", mock.Anything, validCommentId).Return(&comment, nil)
+		mockProjectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+		mockRepo.On("DeleteComment", mock.Anything, validCommentId).Return(nil)
+
+		taskService := service.NewTaskService(mockRepo, mockProjectRepo, &mockUserRepository{}, &mockPublisher{})
+
+		err := taskService.DeleteComment(context.Background(), service.DeleteTaskCommentRequest{
+			TaskId:        validTaskId,
+			CommentId:     validCommentId,
+			RequestUserId: authorId,
+		})
+		require.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("non author cannot delete comment", func(t *testing.T) {
+		mockRepo := &mockTaskRepository{}
+		mockRepo.On("GetCommentById", mock.Anything, validCommentId).Return(&comment, nil)
+
+		taskService := service.NewTaskService(mockRepo, &mockProjectRepository{}, &mockUserRepository{}, &mockPublisher{})
+
+		err := taskService.DeleteComment(context.Background(), service.DeleteTaskCommentRequest{
+			TaskId:        validTaskId,
+			CommentId:     validCommentId,
+			RequestUserId: uuid.New(),
+		})
+		require.Error(t, err)
+
+		var domainErr domain.DomainError
+		if assert.ErrorAs(t, err, &domainErr) {
+			assert.Equal(t, domain.ForbiddenErrorCode, domainErr.Code)
+		}
+
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "DeleteComment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskService_GetById(t *testing.T) {
+	validUserId := uuid.New()
+	validProjectId := uuid.New()
+	validTaskId := uuid.New()
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: validUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: validUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	validTask := domain.Task{
+		Id:        validTaskId,
+		ProjectId: validProjectId,
+		AuthorId:  validUserId,
+		Title:     "Test Task",
+		Status:    domain.TaskStatusDoing,
+	}
+
+	mockRepo := &mockTaskRepository{}
+	mockProjectRepo := &mockProjectRepository{}
+
+	mockRepo.On("GetById", mock.Anything, validTaskId).Return(&validTask, nil)
+	mockProjectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+	mockRepo.On("ListSubtasks", mock.Anything, validTaskId).Return([]domain.Task{
+		{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDone},
+		{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusPending},
+		{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusArchived},
+	}, nil)
+	mockRepo.On("ListBlockers", mock.Anything, validTaskId).Return([]domain.Task{
+		{Id: uuid.New(), ProjectId: validProjectId, Status: domain.TaskStatusDoing},
+	}, nil)
+	mockRepo.On("ListChecklistItems", mock.Anything, validTaskId).Return([]domain.TaskChecklistItem{
+		{Id: uuid.New(), TaskId: validTaskId, Title: "First", Done: true},
+		{Id: uuid.New(), TaskId: validTaskId, Title: "Second", Done: false},
+	}, nil)
+
+	taskService := service.NewTaskService(mockRepo, mockProjectRepo, &mockUserRepository{}, &mockPublisher{})
+
+	task, err := taskService.GetById(context.Background(), validTaskId, validUserId)
+	require.NoError(t, err)
+	require.NotNil(t, task.Progress)
+
+	assert.Equal(t, 2, task.Progress.Done)
+	assert.Equal(t, 4, task.Progress.Total)
+	assert.Len(t, task.Subtasks, 3)
+	assert.Len(t, task.Checklist, 2)
+	assert.Len(t, task.Blockers, 1)
+
+	_, err = taskService.GetById(context.Background(), validTaskId, uuid.New())
+	var domainErr domain.DomainError
+	if assert.ErrorAs(t, err, &domainErr) {
+		assert.Equal(t, domain.ForbiddenErrorCode, domainErr.Code)
+	}
+}
+
+func TestTaskService_SetParent(t *testing.T) {
+	validUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validUser := domain.User{
+		Id:   validUserId,
+		Name: "Test User",
+	}
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: validUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: validUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	childId := uuid.New()
+	parentId := uuid.New()
+	otherProjectTaskId := uuid.New()
+	doneTaskId := uuid.New()
+
+	newTask := func(id uuid.UUID, projectId uuid.UUID, status domain.TaskStatus) *domain.Task {
+		return &domain.Task{
+			Id:        id,
+			ProjectId: projectId,
+			AuthorId:  validUserId,
+			Title:     "Task",
+			Status:    status,
+		}
+	}
+
+	type testCase struct {
+		name              string
+		parentId          *uuid.UUID
+		mockSetup         func(*mockTaskRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}
+
+	tests := []testCase{
+		{
+			name:     "links task to parent",
+			parentId: &parentId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, parentId).Return(newTask(parentId, validProjectId, domain.TaskStatusPending), nil)
+				repo.On("ListAncestorIds", mock.Anything, parentId).Return([]uuid.UUID{parentId}, nil)
+				repo.On("UpdateParent", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+				repo.On("CreateChanges", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.AnythingOfType("[]domain.TaskChange")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:     "detaches task from parent",
+			parentId: nil,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("UpdateParent", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+				repo.On("CreateChanges", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.AnythingOfType("[]domain.TaskChange")).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:              "task cannot be its own parent",
+			parentId:          &childId,
+			mockSetup:         func(repo *mockTaskRepository) {},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name:     "parent link would create a cycle",
+			parentId: &parentId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, parentId).Return(newTask(parentId, validProjectId, domain.TaskStatusPending), nil)
+				repo.On("ListAncestorIds", mock.Anything, parentId).Return([]uuid.UUID{parentId, childId}, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name:     "parent from another project",
+			parentId: &otherProjectTaskId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, otherProjectTaskId).Return(newTask(otherProjectTaskId, uuid.New(), domain.TaskStatusPending), nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name:     "open task cannot be added to a done parent",
+			parentId: &doneTaskId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, doneTaskId).Return(newTask(doneTaskId, validProjectId, domain.TaskStatusDone), nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTaskRepository{}
+			mockProjectRepo := &mockProjectRepository{}
+			mockUserRepo := &mockUserRepository{}
+
+			mockRepo.On("GetById", mock.Anything, childId).Return(newTask(childId, validProjectId, domain.TaskStatusPending), nil)
+			mockProjectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+			mockUserRepo.On("GetById", mock.Anything, validUserId).Return(&validUser, nil)
+			tt.mockSetup(mockRepo)
+
+			taskService := service.NewTaskService(mockRepo, mockProjectRepo, mockUserRepo, &mockPublisher{})
+
+			task, err := taskService.SetParent(context.Background(), service.SetTaskParentRequest{
+				TaskId:        childId,
+				ParentId:      tt.parentId,
+				RequestUserId: validUserId,
+			})
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				require.NotNil(t, task)
+				assert.Equal(t, tt.parentId, task.ParentId)
+				mockRepo.AssertExpectations(t)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, task)
+				mockRepo.AssertNotCalled(t, "UpdateParent", mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+		})
+	}
+}
+
+func TestTaskService_AddBlocker(t *testing.T) {
+	validUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validUser := domain.User{
+		Id:   validUserId,
+		Name: "Test User",
+	}
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: validUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: validUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	taskId := uuid.New()
+	blockerId := uuid.New()
+	otherProjectTaskId := uuid.New()
+
+	newTask := func(id uuid.UUID, projectId uuid.UUID) *domain.Task {
+		return &domain.Task{
+			Id:        id,
+			ProjectId: projectId,
+			AuthorId:  validUserId,
+			Title:     "Task",
+			Status:    domain.TaskStatusPending,
+		}
+	}
+
+	type testCase struct {
+		name              string
+		blockedByTaskId   uuid.UUID
+		mockSetup         func(*mockTaskRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+		expectedChanges   int
+	}
+
+	tests := []testCase{
+		{
+			name:            "adds blocker",
+			blockedByTaskId: blockerId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, blockerId).Return(newTask(blockerId, validProjectId), nil)
+				repo.On("CreateDependency", mock.Anything, validProjectId, taskId, blockerId).Return(true, nil)
+				repo.On("CreateChanges", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.AnythingOfType("[]domain.TaskChange")).Return(nil)
+				repo.On("ListBlockers", mock.Anything, taskId).Return([]domain.Task{*newTask(blockerId, validProjectId)}, nil)
+			},
+			shouldSucceed:   true,
+			expectedChanges: 1,
+		},
+		{
+			name:            "existing blocker is not recorded again",
+			blockedByTaskId: blockerId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, blockerId).Return(newTask(blockerId, validProjectId), nil)
+				repo.On("CreateDependency", mock.Anything, validProjectId, taskId, blockerId).Return(false, nil)
+				repo.On("ListBlockers", mock.Anything, taskId).Return([]domain.Task{*newTask(blockerId, validProjectId)}, nil)
+			},
+			shouldSucceed:   true,
+			expectedChanges: 0,
+		},
+		{
+			name:              "task cannot block itself",
+			blockedByTaskId:   taskId,
+			mockSetup:         func(repo *mockTaskRepository) {},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name:            "blocker would create a cycle",
+			blockedByTaskId: blockerId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, blockerId).Return(newTask(blockerId, validProjectId), nil)
+				repo.On("CreateDependency", mock.Anything, validProjectId, taskId, blockerId).Return(false, domain.BusinessValidationError("task dependency would create a cycle"))
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+		{
+			name:            "blocker from another project",
+			blockedByTaskId: otherProjectTaskId,
+			mockSetup: func(repo *mockTaskRepository) {
+				repo.On("GetById", mock.Anything, otherProjectTaskId).Return(newTask(otherProjectTaskId, uuid.New()), nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTaskRepository{}
+			mockProjectRepo := &mockProjectRepository{}
+			mockUserRepo := &mockUserRepository{}
+
+			mockRepo.On("GetById", mock.Anything, taskId).Return(newTask(taskId, validProjectId), nil)
+			mockProjectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+			mockUserRepo.On("GetById", mock.Anything, validUserId).Return(&validUser, nil)
+			tt.mockSetup(mockRepo)
+
+			taskService := service.NewTaskService(mockRepo, mockProjectRepo, mockUserRepo, &mockPublisher{})
+
+			task, err := taskService.AddBlocker(context.Background(), service.TaskBlockerRequest{
+				TaskId:          taskId,
+				BlockedByTaskId: tt.blockedByTaskId,
+				RequestUserId:   validUserId,
+			})
+
+			if tt.shouldSucceed {
+				require.NoError(t, err)
+				require.NotNil(t, task)
+				assert.Len(t, task.Blockers, 1)
+				assert.Len(t, task.Changes, tt.expectedChanges)
+				mockRepo.AssertExpectations(t)
+			} else {
+				require.Error(t, err)
+				require.Nil(t, task)
+				mockRepo.AssertNotCalled(t, "CreateChanges", mock.Anything, mock.Anything, mock.Anything)
+
+				var domainErr domain.DomainError
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+		})
+	}
+}
+
+func TestTaskService_List(t *testing.T) {
+	validUserId := uuid.New()
+	validProjectId := uuid.New()
+
+	validProject := domain.Project{
+		Id:     validProjectId,
+		UserId: validUserId,
+		Members: []domain.ProjectMember{
+			{
+				UserId: validUserId,
+				Role:   domain.ProjectMemberRoleOwner,
+			},
+		},
+	}
+
+	status := domain.TaskStatusDoing
+	filter := domain.TaskListFilter{
+		Status:   &status,
+		Search:   "bug",
+		SortBy:   domain.TaskSortFieldUpdatedAt,
+		SortDesc: true,
+	}
+
+	newTasks := func(count int) []domain.Task {
+		tasks := []domain.Task{}
+		for range count {
+			tasks = append(tasks, domain.Task{Id: uuid.New(), ProjectId: validProjectId, Status: status, UpdatedAt: time.Now()})
+		}
+		return tasks
+	}
+
+	type testCase struct {
+		name            string
+		userId          uuid.UUID
+		returnedTasks   []domain.Task
+		expectedLength  int
+		expectedHasNext bool
+		expectedError   domain.ErrorCode
+	}
+
+	tests := []testCase{
+		{
+			name:            "has next page",
+			userId:          validUserId,
+			returnedTasks:   newTasks(3),
+			expectedLength:  2,
+			expectedHasNext: true,
+		},
+		{
+			name:            "last page",
+			userId:          validUserId,
+			returnedTasks:   newTasks(2),
+			expectedLength:  2,
+			expectedHasNext: false,
+		},
+		{
+			name:          "not a member",
+			userId:        uuid.New(),
+			expectedError: domain.ForbiddenErrorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockTaskRepository{}
+			mockProjectRepo := &mockProjectRepository{}
+
+			mockProjectRepo.On("GetById", mock.Anything, validProjectId).Return(&validProject, nil)
+			mockRepo.On("ListByProjectId", mock.Anything, validProjectId, filter, utils.PaginationAfterParams{Limit: 3}).Return(tt.returnedTasks, nil)
+
+			taskService := service.NewTaskService(mockRepo, mockProjectRepo, &mockUserRepository{}, &mockPublisher{})
+
+			result, err := taskService.List(context.Background(), service.ListTasksRequest{
+				ProjectId: validProjectId,
+				UserId:    tt.userId,
+				Filter:    filter,
+				Params:    utils.PaginationAfterParams{Limit: 2},
+			})
+
+			if tt.expectedError != "" {
+				var domainErr domain.DomainError
+				require.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tt.expectedError, domainErr.Code)
+				mockRepo.AssertNotCalled(t, "ListByProjectId", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, result.Data, tt.expectedLength)
+			assert.Equal(t, tt.expectedHasNext, result.HasNext)
+
+			if !tt.expectedHasNext {
+				assert.Empty(t, result.NextCursor)
+				return
+			}
+
+			next, err := utils.DecodeAfterCursor(result.NextCursor, 2)
+			require.NoError(t, err)
+			last := result.Data[len(result.Data)-1]
+			assert.Equal(t, last.Id, next.Id)
+			assert.True(t, last.UpdatedAt.Equal(next.After))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/logger"
+	"github.com/gabrielnakaema/project-chat/internal/mailer"
+	"github.com/google/uuid"
+)
+
+// userDataExportDuration is how long a built archive can be downloaded.
+const userDataExportDuration = 7 * 24 * time.Hour
+
+type userDataExportRepository interface {
+	Create(ctx context.Context, export *domain.UserDataExport) error
+	GetById(ctx context.Context, id uuid.UUID) (*domain.UserDataExport, error)
+	GetPending(ctx context.Context, userId uuid.UUID) (*domain.UserDataExport, error)
+	Complete(ctx context.Context, id uuid.UUID, archive []byte) error
+	Fail(ctx context.Context, id uuid.UUID) error
+	ListProjects(ctx context.Context, userId uuid.UUID) ([]domain.UserDataExportProject, error)
+	ListTasks(ctx context.Context, userId uuid.UUID) ([]domain.Task, error)
+	ListChatMessages(ctx context.Context, userId uuid.UUID) ([]domain.ChatMessage, error)
+}
+
+type userGetter interface {
+	GetById(ctx context.Context, id uuid.UUID) (*domain.User, error)
+}
+
+type UserDataExportService struct {
+	exportRepository userDataExportRepository
+	userGetter       userGetter
+	mailer           userMailer
+	publisher        publisher
+	appURL           string
+}
+
+func NewUserDataExportService(exportRepository userDataExportRepository, userGetter userGetter, mailer userMailer, publisher publisher, appURL string) *UserDataExportService {
+	return &UserDataExportService{
+		exportRepository: exportRepository,
+		userGetter:       userGetter,
+		mailer:           mailer,
+		publisher:        publisher,
+		appURL:           appURL,
+	}
+}
+
+// Request starts an export of the data of the user, the archive is built in the background
+// and the user is emailed once it can be downloaded. An export that is still being built is
+// returned instead of starting another one.
+func (es *UserDataExportService) Request(ctx context.Context, userId uuid.UUID) (*domain.UserDataExport, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	pending, err := es.exportRepository.GetPending(ctx, userId)
+	if err == nil {
+		return pending, nil
+	}
+
+	var domainErr domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+		return nil, domain.ServerError("failed to get pending export", err)
+	}
+
+	export := domain.UserDataExport{
+		UserId:    userId,
+		ExpiresAt: time.Now().Add(userDataExportDuration),
+	}
+
+	err = es.exportRepository.Create(ctx, &export)
+	if err != nil {
+		return nil, domain.ServerError("failed to create export", err)
+	}
+
+	err = es.publisher.Publish(ctx, events.UserDataExportRequested, export)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish export requested event", err)
+	}
+
+	return &export, nil
+}
+
+// Get returns an export of the user, exports of other users are reported as not found.
+func (es *UserDataExportService) Get(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*domain.UserDataExport, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	export, err := es.exportRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.UserId != userId {
+		return nil, domain.NotFoundError("export not found")
+	}
+
+	return export, nil
+}
+
+// Download returns the export together with its archive, only exports that are ready and
+// have not expired can be downloaded.
+func (es *UserDataExportService) Download(ctx context.Context, userId uuid.UUID, id uuid.UUID) (*domain.UserDataExport, error) {
+	export, err := es.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !export.IsDownloadable(time.Now()) {
+		return nil, domain.BusinessValidationError("export is not available for download")
+	}
+
+	return export, nil
+}
+
+// Build gathers the data of the user into the archive of a pending export. Exports that
+// were already built are skipped, so a redelivered event does nothing.
+func (es *UserDataExportService) Build(ctx context.Context, id uuid.UUID) error {
+	export, err := es.exportRepository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if export.Status != domain.UserDataExportStatusPending {
+		return nil
+	}
+
+	user, err := es.userGetter.GetById(ctx, export.UserId)
+	if err != nil {
+		return err
+	}
+
+	archive, err := es.buildArchive(ctx, user)
+	if err != nil {
+		failErr := es.exportRepository.Fail(ctx, export.Id)
+		if failErr != nil {
+			logger.FromContext(ctx).Error("failed to mark export as failed", "export_id", export.Id, "error", failErr.Error())
+		}
+		return domain.ServerError("failed to build export", err)
+	}
+
+	err = es.exportRepository.Complete(ctx, export.Id, archive)
+	if err != nil {
+		return domain.ServerError("failed to complete export", err)
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: "Your data export is ready",
+		Body: fmt.Sprintf("Hi %s,\n\nThe export of your data is ready, download it from the link below before %s:\n\n%s/settings/exports/%s",
+			user.Name, export.ExpiresAt.UTC().Format(time.RFC1123), es.appURL, export.Id),
+	}
+
+	err = es.mailer.Send(ctx, message)
+	if err != nil {
+		return domain.ServerError("failed to send export email", err)
+	}
+
+	return nil
+}
+
+// buildArchive writes the data of the user to a ZIP archive with one JSON file per kind of
+// data.
+func (es *UserDataExportService) buildArchive(ctx context.Context, user *domain.User) ([]byte, error) {
+	projects, err := es.exportRepository.ListProjects(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := es.exportRepository.ListTasks(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := es.exportRepository.ListChatMessages(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := domain.UserData{
+		Profile:      *user,
+		Projects:     projects,
+		Tasks:        tasks,
+		ChatMessages: messages,
+	}
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{name: "profile.json", content: data.Profile},
+		{name: "projects.json", content: data.Projects},
+		{name: "tasks.json", content: data.Tasks},
+		{name: "chat_messages.json", content: data.ChatMessages},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(file.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
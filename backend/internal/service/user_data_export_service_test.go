@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUserDataExportRepository struct {
+	mock.Mock
+}
+
+func (m *mockUserDataExportRepository) Create(ctx context.Context, export *domain.UserDataExport) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *mockUserDataExportRepository) GetById(ctx context.Context, id uuid.UUID) (*domain.UserDataExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserDataExport), args.Error(1)
+}
+
+func (m *mockUserDataExportRepository) GetPending(ctx context.Context, userId uuid.UUID) (*domain.UserDataExport, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserDataExport), args.Error(1)
+}
+
+func (m *mockUserDataExportRepository) Complete(ctx context.Context, id uuid.UUID, archive []byte) error {
+	args := m.Called(ctx, id, archive)
+	return args.Error(0)
+}
+
+func (m *mockUserDataExportRepository) Fail(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockUserDataExportRepository) ListProjects(ctx context.Context, userId uuid.UUID) ([]domain.UserDataExportProject, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.UserDataExportProject), args.Error(1)
+}
+
+func (m *mockUserDataExportRepository) ListTasks(ctx context.Context, userId uuid.UUID) ([]domain.Task, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *mockUserDataExportRepository) ListChatMessages(ctx context.Context, userId uuid.UUID) ([]domain.ChatMessage, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ChatMessage), args.Error(1)
+}
+
+func TestUserDataExportService_Request(t *testing.T) {
+	userId := uuid.New()
+	pending := &domain.UserDataExport{Id: uuid.New(), UserId: userId, Status: domain.UserDataExportStatusPending}
+
+	tests := []struct {
+		name              string
+		userId            uuid.UUID
+		mockSetup         func(*mockUserDataExportRepository)
+		expectedId        uuid.UUID
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:   "creates a new export",
+			userId: userId,
+			mockSetup: func(repo *mockUserDataExportRepository) {
+				repo.On("GetPending", mock.Anything, userId).Return(nil, domain.NotFoundError("export not found"))
+				repo.On("Create", mock.Anything, mock.MatchedBy(func(export *domain.UserDataExport) bool {
+					return export.UserId == userId && export.ExpiresAt.After(time.Now())
+				})).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:   "returns the export being built",
+			userId: userId,
+			mockSetup: func(repo *mockUserDataExportRepository) {
+				repo.On("GetPending", mock.Anything, userId).Return(pending, nil)
+			},
+			expectedId:    pending.Id,
+			shouldSucceed: true,
+		},
+		{
+			name:              "anonymous user",
+			userId:            uuid.Nil,
+			mockSetup:         func(repo *mockUserDataExportRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserDataExportRepository{}
+			tt.mockSetup(mockRepo)
+
+			exportService := service.NewUserDataExportService(mockRepo, &mockUserRepository{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			export, err := exportService.Request(context.Background(), tt.userId)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedId, export.Id)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserDataExportService_Download(t *testing.T) {
+	userId := uuid.New()
+	ready := &domain.UserDataExport{Id: uuid.New(), UserId: userId, Status: domain.UserDataExportStatusReady, Archive: []byte("zip"), ExpiresAt: time.Now().Add(time.Hour)}
+	pending := &domain.UserDataExport{Id: uuid.New(), UserId: userId, Status: domain.UserDataExportStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	expired := &domain.UserDataExport{Id: uuid.New(), UserId: userId, Status: domain.UserDataExportStatusReady, Archive: []byte("zip"), ExpiresAt: time.Now().Add(-time.Hour)}
+
+	tests := []struct {
+		name              string
+		userId            uuid.UUID
+		export            *domain.UserDataExport
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:          "ready export",
+			userId:        userId,
+			export:        ready,
+			shouldSucceed: true,
+		},
+		{
+			name:              "export of another user",
+			userId:            uuid.New(),
+			export:            ready,
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:              "export still being built",
+			userId:            userId,
+			export:            pending,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:              "expired export",
+			userId:            userId,
+			export:            expired,
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserDataExportRepository{}
+			mockRepo.On("GetById", mock.Anything, tt.export.Id).Return(tt.export, nil)
+
+			exportService := service.NewUserDataExportService(mockRepo, &mockUserRepository{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			export, err := exportService.Download(context.Background(), tt.userId, tt.export.Id)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.export.Archive, export.Archive)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserDataExportService_Build(t *testing.T) {
+	user := &domain.User{Id: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	export := &domain.UserDataExport{Id: uuid.New(), UserId: user.Id, Status: domain.UserDataExportStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockRepo := &mockUserDataExportRepository{}
+	mockRepo.On("GetById", mock.Anything, export.Id).Return(export, nil)
+	mockRepo.On("ListProjects", mock.Anything, user.Id).Return([]domain.UserDataExportProject{{Id: uuid.New(), Name: "Project"}}, nil)
+	mockRepo.On("ListTasks", mock.Anything, user.Id).Return([]domain.Task{{Id: uuid.New(), Title: "Task"}}, nil)
+	mockRepo.On("ListChatMessages", mock.Anything, user.Id).Return([]domain.ChatMessage{{Id: uuid.New(), Content: "Hello"}}, nil)
+
+	var archive []byte
+	mockRepo.On("Complete", mock.Anything, export.Id, mock.Anything).Run(func(args mock.Arguments) {
+		archive = args.Get(2).([]byte)
+	}).Return(nil)
+
+	mockUsers := &mockUserRepository{}
+	mockUsers.On("GetById", mock.Anything, user.Id).Return(user, nil)
+
+	mockMail := &mockMailer{}
+	mockMail.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	exportService := service.NewUserDataExportService(mockRepo, mockUsers, mockMail, &mockPublisher{}, "http://localhost:5173")
+
+	err := exportService.Build(context.Background(), export.Id)
+	assert.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if assert.NoError(t, err) {
+		names := []string{}
+		for _, file := range reader.File {
+			names = append(names, file.Name)
+		}
+		assert.ElementsMatch(t, []string{"profile.json", "projects.json", "tasks.json", "chat_messages.json"}, names)
+	}
+
+	mockRepo.AssertExpectations(t)
+	mockUsers.AssertExpectations(t)
+	mockMail.AssertExpectations(t)
+}
+
+func TestUserDataExportService_Build_Failure(t *testing.T) {
+	user := &domain.User{Id: uuid.New(), Name: "John Doe", Email: "john@example.com"}
+	export := &domain.UserDataExport{Id: uuid.New(), UserId: user.Id, Status: domain.UserDataExportStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockRepo := &mockUserDataExportRepository{}
+	mockRepo.On("GetById", mock.Anything, export.Id).Return(export, nil)
+	mockRepo.On("ListProjects", mock.Anything, user.Id).Return(nil, errors.New("database error"))
+	mockRepo.On("Fail", mock.Anything, export.Id).Return(nil)
+
+	mockUsers := &mockUserRepository{}
+	mockUsers.On("GetById", mock.Anything, user.Id).Return(user, nil)
+
+	exportService := service.NewUserDataExportService(mockRepo, mockUsers, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+	err := exportService.Build(context.Background(), export.Id)
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
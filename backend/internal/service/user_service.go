@@ -3,12 +3,22 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/logger"
+	"github.com/gabrielnakaema/project-chat/internal/mailer"
+	"github.com/gabrielnakaema/project-chat/internal/totp"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -17,24 +27,100 @@ type userRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetById(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
-	CreateRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) error
 	GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error)
-	UpdateRefreshTokenActive(ctx context.Context, refreshToken *domain.RefreshToken) error
+	CreateSession(ctx context.Context, session *domain.UserSession, refreshToken *domain.RefreshToken) error
+	GetSession(ctx context.Context, id uuid.UUID) (*domain.UserSession, error)
+	ListActiveSessions(ctx context.Context, userId uuid.UUID) ([]domain.UserSession, error)
+	RotateRefreshToken(ctx context.Context, session *domain.UserSession, oldToken *domain.RefreshToken, newToken *domain.RefreshToken) error
+	RevokeSession(ctx context.Context, sessionId uuid.UUID) error
+	CreateToken(ctx context.Context, token *domain.UserToken) error
+	GetToken(ctx context.Context, tokenHash string, purpose domain.UserTokenPurpose) (*domain.UserToken, error)
+	InvalidateTokens(ctx context.Context, userId uuid.UUID, purpose domain.UserTokenPurpose) error
+	VerifyEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error
+	ResetPassword(ctx context.Context, token *domain.UserToken, password string) ([]uuid.UUID, error)
+	UseToken(ctx context.Context, token *domain.UserToken) error
+	SetTotpSecret(ctx context.Context, userId uuid.UUID, secret string) error
+	EnableTotp(ctx context.Context, userId uuid.UUID, step int64, recoveryCodeHashes []string) error
+	DisableTotp(ctx context.Context, userId uuid.UUID) error
+	UseTotpStep(ctx context.Context, userId uuid.UUID, step int64) error
+	UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) error
+	UpdateProfile(ctx context.Context, user *domain.User) error
+	ChangeEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error
+	ChangePassword(ctx context.Context, userId uuid.UUID, password string) error
+	ListRoomIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	Delete(ctx context.Context, user *domain.User, deletedAt time.Time) ([]domain.ProjectMember, error)
 }
 
 type jwtProvider interface {
 	Generate(sub string, exp time.Time, claims map[string]string) (string, error)
 }
 
+type invitationAccepter interface {
+	AcceptPendingInvitations(ctx context.Context, user *domain.User) error
+}
+
+type loginAttemptRepository interface {
+	Create(ctx context.Context, attempt *domain.LoginAttempt, since time.Time) (*domain.LoginFailures, *domain.LoginFailures, error)
+	UpdateResult(ctx context.Context, attempt *domain.LoginAttempt) error
+}
+
+type userMailer interface {
+	Send(ctx context.Context, message mailer.Message) error
+}
+
+const (
+	emailVerificationDuration = 24 * time.Hour
+	passwordResetDuration     = time.Hour
+	mfaChallengeDuration      = 5 * time.Minute
+	emailChangeDuration       = 24 * time.Hour
+)
+
+// refreshTokenReuseGracePeriod lets the token the session rotated last be used again for a
+// moment, so concurrent refreshes of the same client do not look like a stolen token.
+const refreshTokenReuseGracePeriod = 30 * time.Second
+
+const (
+	totpIssuer        = "Project Chat"
+	recoveryCodeCount = 10
+)
+
+// Failed logins lock the account after accountLockoutThreshold failures and the address
+// after ipLockoutThreshold failures across accounts, every failure after that doubles the
+// lockout.
+const (
+	loginFailureWindow        = 24 * time.Hour
+	accountLockoutThreshold   = 5
+	ipAddressLockoutThreshold = 20
+	loginLockoutBase          = time.Minute
+	loginLockoutMax           = time.Hour
+)
+
+// dummyPasswordHash is compared against when the email has no account so the response takes
+// as long as a wrong password would.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := HashPassword("dummy-password")
+	return hash
+})
+
 type UserService struct {
-	jwtProvider    jwtProvider
-	userRepository userRepository
+	jwtProvider            jwtProvider
+	userRepository         userRepository
+	loginAttemptRepository loginAttemptRepository
+	invitationAccepter     invitationAccepter
+	mailer                 userMailer
+	publisher              publisher
+	appURL                 string
 }
 
-func NewUserService(jwtProvider jwtProvider, userRepository userRepository) *UserService {
+func NewUserService(jwtProvider jwtProvider, userRepository userRepository, loginAttemptRepository loginAttemptRepository, invitationAccepter invitationAccepter, mailer userMailer, publisher publisher, appURL string) *UserService {
 	return &UserService{
-		jwtProvider:    jwtProvider,
-		userRepository: userRepository,
+		jwtProvider:            jwtProvider,
+		userRepository:         userRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		invitationAccepter:     invitationAccepter,
+		mailer:                 mailer,
+		publisher:              publisher,
+		appURL:                 appURL,
 	}
 }
 
@@ -69,44 +155,327 @@ func (us *UserService) Create(ctx context.Context, request CreateUserRequest) (*
 		return nil, domain.ServerError("failed to create user", err)
 	}
 
+	// the account is already created, the user can ask for a new email if this one fails
+	err = us.sendEmailVerification(ctx, &user)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to send email verification", "user_id", user.Id, "error", err.Error())
+	}
+
 	return &user, nil
 }
 
-type LoginRequest struct {
-	Email    string
+// RequestEmailVerification sends a new verification email, the links sent before stop working.
+func (us *UserService) RequestEmailVerification(ctx context.Context, userId uuid.UUID) error {
+	if userId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	user, err := us.userRepository.GetById(ctx, userId)
+	if err != nil {
+		return domain.ServerError("failed to get user", err)
+	}
+
+	if user.IsEmailVerified() {
+		return domain.BusinessValidationError("email is already verified")
+	}
+
+	err = us.sendEmailVerification(ctx, user)
+	if err != nil {
+		return domain.ServerError("failed to send email verification", err)
+	}
+
+	return nil
+}
+
+type VerifyEmailRequest struct {
+	Token string
+}
+
+func (us *UserService) VerifyEmail(ctx context.Context, request VerifyEmailRequest) error {
+	token, err := us.getValidToken(ctx, request.Token, domain.UserTokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	err = us.userRepository.VerifyEmail(ctx, token, time.Now())
+	if err != nil {
+		return userTokenError(err)
+	}
+
+	us.acceptPendingInvitations(ctx, token.UserId)
+
+	return nil
+}
+
+// acceptPendingInvitations adds the user to the projects their email was invited to, it is
+// only called once the user proved they own the address. The address is verified by then and
+// the invitations can still be accepted with their links, so failures are only logged.
+func (us *UserService) acceptPendingInvitations(ctx context.Context, userId uuid.UUID) {
+	log := logger.FromContext(ctx)
+
+	user, err := us.userRepository.GetById(ctx, userId)
+	if err != nil {
+		log.Error("failed to get user to accept pending invitations", "user_id", userId, "error", err.Error())
+		return
+	}
+
+	err = us.invitationAccepter.AcceptPendingInvitations(ctx, user)
+	if err != nil {
+		log.Error("failed to accept pending invitations", "user_id", userId, "error", err.Error())
+	}
+}
+
+type RequestPasswordResetRequest struct {
+	Email string
+}
+
+// RequestPasswordReset emails a password reset link, unknown emails succeed silently so the
+// endpoint cannot be used to find out which emails have an account.
+func (us *UserService) RequestPasswordReset(ctx context.Context, request RequestPasswordResetRequest) error {
+	user, err := us.userRepository.GetByEmail(ctx, request.Email)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil
+		}
+		return domain.ServerError("failed to get user", err)
+	}
+
+	// the email is sent in the background and its failures are only logged, answering known
+	// emails the same way and as fast as unknown ones does not tell which emails have an
+	// account
+	go us.sendPasswordReset(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+func (us *UserService) sendPasswordReset(ctx context.Context, user *domain.User) {
+	log := logger.FromContext(ctx)
+
+	token, err := us.createToken(ctx, user.Id, domain.UserTokenPurposePasswordReset, passwordResetDuration)
+	if err != nil {
+		log.Error("failed to create password reset token", "user_id", user.Id, "error", err.Error())
+		return
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password, it expires in 1 hour:\n\n%s/reset-password?token=%s\n\nIf you did not ask to reset your password you can ignore this email.",
+			user.Name, us.appURL, token.Token),
+	}
+
+	err = us.mailer.Send(ctx, message)
+	if err != nil {
+		log.Error("failed to send password reset email", "user_id", user.Id, "error", err.Error())
+	}
+}
+
+type ResetPasswordRequest struct {
+	Token    string
 	Password string
 }
 
+// ResetPassword sets the new password and signs the user out of every session.
+func (us *UserService) ResetPassword(ctx context.Context, request ResetPasswordRequest) error {
+	token, err := us.getValidToken(ctx, request.Token, domain.UserTokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := HashPassword(request.Password)
+	if err != nil {
+		return domain.ServerError("failed to hash password", err)
+	}
+
+	sessionIds, err := us.userRepository.ResetPassword(ctx, token, hashed)
+	if err != nil {
+		return userTokenError(err)
+	}
+
+	// the websocket connections of the revoked sessions are closed like a single sign out
+	for _, sessionId := range sessionIds {
+		session := domain.UserSession{
+			Id:     sessionId,
+			UserId: token.UserId,
+		}
+
+		err = us.publisher.Publish(ctx, events.UserSessionRevoked, session)
+		if err != nil {
+			return domain.ServerError("failed to publish session revoked event", err)
+		}
+	}
+
+	return nil
+}
+
+func (us *UserService) sendEmailVerification(ctx context.Context, user *domain.User) error {
+	token, err := us.createToken(ctx, user.Id, domain.UserTokenPurposeEmailVerification, emailVerificationDuration)
+	if err != nil {
+		return err
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening the link below:\n\n%s/verify-email?token=%s",
+			user.Name, us.appURL, token.Token),
+	}
+
+	return us.mailer.Send(ctx, message)
+}
+
+// createToken invalidates the previous tokens of the user for the purpose and creates a new
+// one, the plain token is only kept in the returned value.
+func (us *UserService) createToken(ctx context.Context, userId uuid.UUID, purpose domain.UserTokenPurpose, duration time.Duration) (*domain.UserToken, error) {
+	return us.issueToken(ctx, domain.UserToken{UserId: userId, Purpose: purpose}, duration)
+}
+
+// issueToken works like createToken for a token that carries more than the user and the
+// purpose, such as the new address of an email change.
+func (us *UserService) issueToken(ctx context.Context, token domain.UserToken, duration time.Duration) (*domain.UserToken, error) {
+	plain, err := GenerateRefreshToken(32)
+	if err != nil {
+		return nil, domain.ServerError("failed to generate token", err)
+	}
+
+	err = us.userRepository.InvalidateTokens(ctx, token.UserId, token.Purpose)
+	if err != nil {
+		return nil, domain.ServerError("failed to invalidate tokens", err)
+	}
+
+	token.Token = plain
+	token.TokenHash = hashToken(plain)
+	token.ExpiresAt = time.Now().Add(duration)
+
+	err = us.userRepository.CreateToken(ctx, &token)
+	if err != nil {
+		return nil, domain.ServerError("failed to create token", err)
+	}
+
+	return &token, nil
+}
+
+func (us *UserService) getValidToken(ctx context.Context, plain string, purpose domain.UserTokenPurpose) (*domain.UserToken, error) {
+	token, err := us.userRepository.GetToken(ctx, hashToken(plain), purpose)
+	if err != nil {
+		return nil, userTokenError(err)
+	}
+
+	if !token.IsValid(time.Now()) {
+		return nil, domain.BusinessValidationError("token is invalid or expired")
+	}
+
+	return token, nil
+}
+
+// userTokenError hides whether a token does not exist or was used in the meantime.
+func userTokenError(err error) error {
+	var domainErr domain.DomainError
+	if errors.As(err, &domainErr) {
+		if domainErr.Code == domain.NotFoundErrorCode {
+			return domain.BusinessValidationError("token is invalid or expired")
+		}
+		return domainErr
+	}
+	return domain.ServerError("failed to use token", err)
+}
+
+type LoginRequest struct {
+	Email     string
+	Password  string
+	UserAgent string
+	IpAddress string
+}
+
+// LoginResult holds the tokens of the new session, when the user has two-factor
+// authentication enabled only MfaToken is set and has to be exchanged with VerifyMfa.
 type LoginResult struct {
 	AccessToken  string
 	RefreshToken string
+	MfaToken     string
 	User         *domain.User
 }
 
+// Login checks the credentials of the user, unknown emails and wrong passwords get the same
+// response. Every attempt is recorded and too many failures lock the email or the address.
 func (us *UserService) Login(ctx context.Context, request LoginRequest) (*LoginResult, error) {
 	const INVALID_CREDENTIALS_ERROR_MESSAGE = "invalid credentials"
 
-	user, err := us.userRepository.GetByEmail(ctx, request.Email)
+	// the attempt counts as a failure until it is known to be something else, so concurrent
+	// attempts cannot all pass the lockout check before any of them is recorded
+	attempt := domain.LoginAttempt{
+		Email:         strings.ToLower(strings.TrimSpace(request.Email)),
+		IpAddress:     request.IpAddress,
+		FailureReason: domain.LoginFailureReasonInvalidCredentials,
+	}
+
+	lockedUntil, err := us.startLoginAttempt(ctx, &attempt)
 	if err != nil {
-		return nil, domain.UnauthorizedError(INVALID_CREDENTIALS_ERROR_MESSAGE)
+		return nil, err
 	}
 
-	equal, err := CompareHash(request.Password, user.Password)
+	if lockedUntil.After(time.Now()) {
+		attempt.FailureReason = domain.LoginFailureReasonLocked
+		us.finishLoginAttempt(ctx, &attempt)
+		return nil, domain.TooManyRequestsError("too many failed login attempts, try again later", time.Until(lockedUntil))
+	}
+
+	user, err := us.userRepository.GetByEmail(ctx, attempt.Email)
 	if err != nil {
+		var domainErr domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+			return nil, domain.ServerError("error while getting user", err)
+		}
+
+		CompareHash(request.Password, dummyPasswordHash())
+
+		us.finishLoginAttempt(ctx, &attempt)
 		return nil, domain.UnauthorizedError(INVALID_CREDENTIALS_ERROR_MESSAGE)
 	}
 
+	attempt.UserId = &user.Id
+
+	equal, _ := CompareHash(request.Password, user.Password)
 	if !equal {
+		us.finishLoginAttempt(ctx, &attempt)
 		return nil, domain.UnauthorizedError(INVALID_CREDENTIALS_ERROR_MESSAGE)
 	}
 
-	claims := make(map[string]string)
+	// the attempt only counts as successful once the second factor is verified, otherwise a
+	// correct password would reset the failures of the mfa codes
+	if user.IsTotpEnabled() {
+		attempt.FailureReason = domain.LoginFailureReasonMfaRequired
+	} else {
+		attempt.Success = true
+		attempt.FailureReason = ""
+	}
+	us.finishLoginAttempt(ctx, &attempt)
+
+	return us.StartLogin(ctx, user, request.UserAgent, request.IpAddress)
+}
 
-	token, err := us.jwtProvider.Generate(user.Id.String(), time.Now().Add(30*time.Minute), claims)
-	if err != nil {
-		return nil, domain.ServerError("error while generating token", err)
+// StartLogin signs in a user whose identity was already checked. Users with two-factor
+// authentication get an MFA challenge instead of the session.
+func (us *UserService) StartLogin(ctx context.Context, user *domain.User, userAgent string, ipAddress string) (*LoginResult, error) {
+	if user.IsTotpEnabled() {
+		challenge, err := us.createToken(ctx, user.Id, domain.UserTokenPurposeMfaChallenge, mfaChallengeDuration)
+		if err != nil {
+			return nil, err
+		}
+
+		result := LoginResult{
+			MfaToken: challenge.Token,
+			User:     user,
+		}
+
+		return &result, nil
 	}
 
+	return us.startSession(ctx, user, userAgent, ipAddress)
+}
+
+func (us *UserService) startSession(ctx context.Context, user *domain.User, userAgent string, ipAddress string) (*LoginResult, error) {
 	refreshTokenToken, err := GenerateRefreshToken(48)
 	if err != nil {
 		return nil, domain.ServerError("error while generating refresh token", err)
@@ -119,11 +488,22 @@ func (us *UserService) Login(ctx context.Context, request LoginRequest) (*LoginR
 		Active:    true,
 	}
 
-	err = us.userRepository.CreateRefreshToken(ctx, &refreshToken)
+	session := domain.UserSession{
+		UserId:    user.Id,
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+	}
+
+	err = us.userRepository.CreateSession(ctx, &session, &refreshToken)
 	if err != nil {
 		return nil, domain.ServerError("error while saving refresh token", err)
 	}
 
+	token, err := us.jwtProvider.Generate(user.Id.String(), time.Now().Add(30*time.Minute), sessionClaims(session.Id))
+	if err != nil {
+		return nil, domain.ServerError("error while generating token", err)
+	}
+
 	result := LoginResult{
 		AccessToken:  token,
 		RefreshToken: refreshToken.Token,
@@ -134,42 +514,386 @@ func (us *UserService) Login(ctx context.Context, request LoginRequest) (*LoginR
 }
 
 func (us *UserService) GetMe(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	return us.userRepository.GetById(ctx, id)
+	user, err := us.userRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDeleted() {
+		return nil, domain.NotFoundError("user not found")
+	}
+
+	return user, nil
 }
 
-type RefreshTokenRequest struct {
+// UpdateProfileRequest only changes the fields that are set.
+type UpdateProfileRequest struct {
+	UserId    uuid.UUID
+	Name      *string
+	AvatarUrl *string
+	Timezone  *string
+	Locale    *string
+}
+
+// UpdateProfile changes the profile of the user, the other members of their projects and
+// chats are notified so the name and avatar they show are refreshed.
+func (us *UserService) UpdateProfile(ctx context.Context, request UpdateProfileRequest) (*domain.User, error) {
+	user, err := us.getUser(ctx, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		user.Name = *request.Name
+	}
+	if request.AvatarUrl != nil {
+		user.AvatarUrl = *request.AvatarUrl
+	}
+	if request.Timezone != nil {
+		user.Timezone = *request.Timezone
+	}
+	if request.Locale != nil {
+		user.Locale = *request.Locale
+	}
+
+	err = us.userRepository.UpdateProfile(ctx, user)
+	if err != nil {
+		return nil, domain.ServerError("failed to update profile", err)
+	}
+
+	err = us.publisher.Publish(ctx, events.UserProfileUpdated, user)
+	if err != nil {
+		return nil, domain.ServerError("failed to publish profile updated event", err)
+	}
+
+	return user, nil
+}
+
+// ListRoomIds returns the projects and chats where the user is shown to other members.
+func (us *UserService) ListRoomIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	roomIds, err := us.userRepository.ListRoomIds(ctx, userId)
+	if err != nil {
+		return nil, domain.ServerError("failed to list user rooms", err)
+	}
+
+	return roomIds, nil
+}
+
+type RequestEmailChangeRequest struct {
+	UserId   uuid.UUID
+	Email    string
+	Password string
+}
+
+// RequestEmailChange sends a confirmation link to the new address, the email of the user only
+// changes once the link is opened so a typo cannot lock them out of their account.
+func (us *UserService) RequestEmailChange(ctx context.Context, request RequestEmailChangeRequest) error {
+	user, err := us.getUser(ctx, request.UserId)
+	if err != nil {
+		return err
+	}
+
+	err = us.verifyPassword(user, request.Password)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(user.Email, request.Email) {
+		return domain.BusinessValidationError("email is the same as the current one")
+	}
+
+	_, err = us.userRepository.GetByEmail(ctx, request.Email)
+	if err == nil {
+		return domain.DuplicateEntryError("user email is already taken")
+	}
+	var domainErr domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+		return domain.ServerError("failed to get user", err)
+	}
+
+	token, err := us.issueToken(ctx, domain.UserToken{UserId: user.Id, Purpose: domain.UserTokenPurposeEmailChange, Email: request.Email}, emailChangeDuration)
+	if err != nil {
+		return err
+	}
+
+	message := mailer.Message{
+		To:      request.Email,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm that you want to use this address for your account by opening the link below:\n\n%s/confirm-email?token=%s\n\nIf you did not ask to change your email you can ignore this email.",
+			user.Name, us.appURL, token.Token),
+	}
+
+	err = us.mailer.Send(ctx, message)
+	if err != nil {
+		return domain.ServerError("failed to send email change confirmation", err)
+	}
+
+	return nil
+}
+
+type ConfirmEmailChangeRequest struct {
 	Token string
 }
 
+// ConfirmEmailChange switches the user to the address the token was sent to, opening the link
+// proves the address so it is verified as well. The previous address is told about the change.
+func (us *UserService) ConfirmEmailChange(ctx context.Context, request ConfirmEmailChangeRequest) error {
+	token, err := us.getValidToken(ctx, request.Token, domain.UserTokenPurposeEmailChange)
+	if err != nil {
+		return err
+	}
+
+	user, err := us.getUser(ctx, token.UserId)
+	if err != nil {
+		return err
+	}
+
+	err = us.userRepository.ChangeEmail(ctx, token, time.Now())
+	if err != nil {
+		return userTokenError(err)
+	}
+
+	previousEmail := user.Email
+	user.Email = token.Email
+
+	err = us.publisher.Publish(ctx, events.UserProfileUpdated, user)
+	if err != nil {
+		return domain.ServerError("failed to publish profile updated event", err)
+	}
+
+	us.acceptPendingInvitations(ctx, user.Id)
+
+	// the email already changed, a failed notice must not make the confirmation fail
+	message := mailer.Message{
+		To:      previousEmail,
+		Subject: "Your email was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email of your account was changed to %s.\n\nIf you did not make this change, reset your password and contact support.",
+			user.Name, user.Email),
+	}
+
+	err = us.mailer.Send(ctx, message)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to send email changed notice", "user_id", user.Id, "error", err.Error())
+	}
+
+	return nil
+}
+
+type ChangePasswordRequest struct {
+	UserId          uuid.UUID
+	SessionId       uuid.UUID
+	CurrentPassword string
+	NewPassword     string
+	IpAddress       string
+}
+
+// ChangePassword replaces the password after checking the current one, every other session
+// of the user is signed out so a leaked password stops working everywhere. Wrong current
+// passwords count towards the login lockout, a stolen access token must not be a way to
+// guess the password without it.
+func (us *UserService) ChangePassword(ctx context.Context, request ChangePasswordRequest) error {
+	user, err := us.getUser(ctx, request.UserId)
+	if err != nil {
+		return err
+	}
+
+	attempt := domain.LoginAttempt{
+		Email:         strings.ToLower(strings.TrimSpace(user.Email)),
+		IpAddress:     request.IpAddress,
+		UserId:        &user.Id,
+		FailureReason: domain.LoginFailureReasonInvalidCredentials,
+	}
+
+	lockedUntil, err := us.startLoginAttempt(ctx, &attempt)
+	if err != nil {
+		return err
+	}
+
+	if lockedUntil.After(time.Now()) {
+		attempt.FailureReason = domain.LoginFailureReasonLocked
+		us.finishLoginAttempt(ctx, &attempt)
+		return domain.TooManyRequestsError("too many failed login attempts, try again later", time.Until(lockedUntil))
+	}
+
+	err = us.verifyPassword(user, request.CurrentPassword)
+	if err != nil {
+		us.finishLoginAttempt(ctx, &attempt)
+		return err
+	}
+
+	attempt.Success = true
+	attempt.FailureReason = ""
+	us.finishLoginAttempt(ctx, &attempt)
+
+	hashed, err := HashPassword(request.NewPassword)
+	if err != nil {
+		return domain.ServerError("failed to hash password", err)
+	}
+
+	err = us.userRepository.ChangePassword(ctx, user.Id, hashed)
+	if err != nil {
+		return domain.ServerError("failed to change password", err)
+	}
+
+	sessions, err := us.userRepository.ListActiveSessions(ctx, user.Id)
+	if err != nil {
+		return domain.ServerError("failed to list sessions", err)
+	}
+
+	for _, session := range sessions {
+		if session.Id == request.SessionId {
+			continue
+		}
+
+		err = us.revokeSession(ctx, user.Id, session.Id)
+		if err != nil {
+			var domainErr domain.DomainError
+			if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+type DeleteAccountRequest struct {
+	UserId   uuid.UUID
+	Password string
+	Code     string
+}
+
+// DeleteAccount anonymizes the user after checking their password, and their second factor
+// when it is enabled. The user is signed out everywhere and their websocket connections are
+// closed once the deletion is published.
+func (us *UserService) DeleteAccount(ctx context.Context, request DeleteAccountRequest) error {
+	user, err := us.getUser(ctx, request.UserId)
+	if err != nil {
+		return err
+	}
+
+	err = us.verifyPassword(user, request.Password)
+	if err != nil {
+		return err
+	}
+
+	if user.IsTotpEnabled() {
+		err = us.verifySecondFactor(ctx, user, request.Code)
+		if err != nil {
+			return err
+		}
+	}
+
+	projectMembers, err := us.userRepository.Delete(ctx, user, time.Now())
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return domainErr
+		}
+		return domain.ServerError("failed to delete user", err)
+	}
+
+	// the other members see the user leave, and the project chats and rooms drop them
+	for _, projectMember := range projectMembers {
+		err = us.publisher.Publish(ctx, events.ProjectMemberRemoved, projectMember)
+		if err != nil {
+			return domain.ServerError("failed to publish project member removed event", err)
+		}
+	}
+
+	deleted := domain.User{
+		Id: user.Id,
+	}
+
+	err = us.publisher.Publish(ctx, events.UserDeleted, deleted)
+	if err != nil {
+		return domain.ServerError("failed to publish user deleted event", err)
+	}
+
+	return nil
+}
+
+func (us *UserService) verifyPassword(user *domain.User, password string) error {
+	ok, _ := CompareHash(password, user.Password)
+	if !ok {
+		return domain.BusinessValidationError("current password is incorrect")
+	}
+
+	return nil
+}
+
+// startLoginAttempt records the attempt and returns the end of the longest lockout that
+// applies to it, the zero time when logins are allowed. The failures are counted in the same
+// transaction the attempt is recorded in.
+func (us *UserService) startLoginAttempt(ctx context.Context, attempt *domain.LoginAttempt) (time.Time, error) {
+	since := time.Now().Add(-loginFailureWindow)
+
+	emailFailures, ipAddressFailures, err := us.loginAttemptRepository.Create(ctx, attempt, since)
+	if err != nil {
+		return time.Time{}, domain.ServerError("error while recording login attempt", err)
+	}
+
+	lockedUntil := emailFailures.LockedUntil(accountLockoutThreshold, loginLockoutBase, loginLockoutMax)
+
+	ipAddressLockedUntil := ipAddressFailures.LockedUntil(ipAddressLockoutThreshold, loginLockoutBase, loginLockoutMax)
+	if ipAddressLockedUntil.After(lockedUntil) {
+		lockedUntil = ipAddressLockedUntil
+	}
+
+	return lockedUntil, nil
+}
+
+// finishLoginAttempt stores the outcome of the attempt, failing to store it does not change
+// the outcome of the login.
+func (us *UserService) finishLoginAttempt(ctx context.Context, attempt *domain.LoginAttempt) {
+	log := logger.FromContext(ctx)
+
+	if !attempt.Success && attempt.FailureReason != domain.LoginFailureReasonMfaRequired {
+		log.Warn("failed login attempt", "email", attempt.Email, "ip_address", attempt.IpAddress, "reason", attempt.FailureReason)
+	}
+
+	err := us.loginAttemptRepository.UpdateResult(ctx, attempt)
+	if err != nil {
+		log.Error("failed to record login attempt", "email", attempt.Email, "error", err.Error())
+	}
+}
+
+type RefreshTokenRequest struct {
+	Token     string
+	UserAgent string
+	IpAddress string
+}
+
+// RefreshToken rotates the refresh token. Presenting a token that was already rotated means
+// it leaked, so the whole session is revoked and every token issued from it stops working.
 func (us *UserService) RefreshToken(ctx context.Context, request RefreshTokenRequest) (*LoginResult, error) {
+	const INVALID_REFRESH_TOKEN_ERROR_MESSAGE = "invalid refresh token"
+
 	refreshToken, err := us.userRepository.GetRefreshToken(ctx, request.Token)
 	if err != nil {
 		var domainErr domain.DomainError
 		if errors.As(err, &domainErr) {
 			if domainErr.Code == domain.NotFoundErrorCode {
-				return nil, domain.UnauthorizedError("invalid refresh token")
+				return nil, domain.UnauthorizedError(INVALID_REFRESH_TOKEN_ERROR_MESSAGE)
 			}
 			return nil, domainErr
 		}
 		return nil, domain.ServerError("error while getting refresh token", err)
 	}
 
-	if !refreshToken.Active {
-		return nil, domain.UnauthorizedError("invalid refresh token")
+	if !refreshToken.Active && !refreshToken.WasJustRotated(time.Now(), refreshTokenReuseGracePeriod) {
+		us.revokeReusedSession(ctx, refreshToken)
+		return nil, domain.UnauthorizedError(INVALID_REFRESH_TOKEN_ERROR_MESSAGE)
 	}
 
 	if refreshToken.ExpiresAt.Before(time.Now()) {
-		return nil, domain.UnauthorizedError("invalid refresh token")
+		return nil, domain.UnauthorizedError(INVALID_REFRESH_TOKEN_ERROR_MESSAGE)
 	}
 
 	user, err := us.userRepository.GetById(ctx, refreshToken.UserId)
 	if err != nil {
-		return nil, domain.UnauthorizedError("invalid refresh token")
-	}
-
-	accessToken, err := us.jwtProvider.Generate(user.Id.String(), time.Now().Add(30*time.Minute), make(map[string]string))
-	if err != nil {
-		return nil, domain.ServerError("error while generating token", err)
+		return nil, domain.UnauthorizedError(INVALID_REFRESH_TOKEN_ERROR_MESSAGE)
 	}
 
 	newRefreshTokenToken, err := GenerateRefreshToken(48)
@@ -185,14 +909,28 @@ func (us *UserService) RefreshToken(ctx context.Context, request RefreshTokenReq
 		Active:    true,
 	}
 
-	err = us.userRepository.CreateRefreshToken(ctx, &newRefreshToken)
-	if err != nil {
-		return nil, domain.ServerError("error while creating new refresh token", err)
+	session := domain.UserSession{
+		Id:        refreshToken.SessionId,
+		UserId:    user.Id,
+		UserAgent: request.UserAgent,
+		IpAddress: request.IpAddress,
 	}
 
-	refreshToken.Active = false
+	err = us.userRepository.RotateRefreshToken(ctx, &session, refreshToken, &newRefreshToken)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			// another request rotated the token in the meantime
+			us.revokeReusedSession(ctx, refreshToken)
+			return nil, domain.UnauthorizedError(INVALID_REFRESH_TOKEN_ERROR_MESSAGE)
+		}
+		return nil, domain.ServerError("error while rotating refresh token", err)
+	}
 
-	_ = us.userRepository.UpdateRefreshTokenActive(ctx, refreshToken)
+	accessToken, err := us.jwtProvider.Generate(user.Id.String(), time.Now().Add(30*time.Minute), sessionClaims(session.Id))
+	if err != nil {
+		return nil, domain.ServerError("error while generating token", err)
+	}
 
 	result := LoginResult{
 		User:         user,
@@ -213,9 +951,350 @@ func (us *UserService) Logout(ctx context.Context, userId uuid.UUID, token strin
 		return domain.ForbiddenError("invalid refresh token")
 	}
 
-	refreshToken.Active = false
+	err = us.revokeSession(ctx, userId, refreshToken.SessionId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil
+		}
+		return err
+	}
 
-	return us.userRepository.UpdateRefreshTokenActive(ctx, refreshToken)
+	return nil
+}
+
+// ListSessions returns the active sessions of the user, currentSessionId marks the session
+// making the request.
+func (us *UserService) ListSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) ([]domain.UserSession, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	sessions, err := us.userRepository.ListActiveSessions(ctx, userId)
+	if err != nil {
+		return nil, domain.ServerError("failed to list sessions", err)
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentSessionId
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs the user out of one of their sessions, its websocket connections are
+// closed as well.
+func (us *UserService) RevokeSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	if userId == uuid.Nil {
+		return domain.UnauthorizedError("unauthorized")
+	}
+
+	session, err := us.userRepository.GetSession(ctx, sessionId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return domainErr
+		}
+		return domain.ServerError("failed to get session", err)
+	}
+
+	if session.UserId != userId || session.IsRevoked() {
+		return domain.NotFoundError("session not found")
+	}
+
+	return us.revokeSession(ctx, userId, sessionId)
+}
+
+func (us *UserService) revokeSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	err := us.userRepository.RevokeSession(ctx, sessionId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return domainErr
+		}
+		return domain.ServerError("failed to revoke session", err)
+	}
+
+	session := domain.UserSession{
+		Id:     sessionId,
+		UserId: userId,
+	}
+
+	err = us.publisher.Publish(ctx, events.UserSessionRevoked, session)
+	if err != nil {
+		return domain.ServerError("failed to publish session revoked event", err)
+	}
+
+	return nil
+}
+
+// revokeReusedSession is called when a rotated refresh token is presented again, the caller
+// answers with an unauthorized error regardless so failures are only logged.
+func (us *UserService) revokeReusedSession(ctx context.Context, refreshToken *domain.RefreshToken) {
+	log := logger.FromContext(ctx)
+
+	log.Warn("refresh token reused, revoking session", "user_id", refreshToken.UserId, "session_id", refreshToken.SessionId)
+
+	err := us.revokeSession(ctx, refreshToken.UserId, refreshToken.SessionId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return
+		}
+		log.Error("failed to revoke reused session", "session_id", refreshToken.SessionId, "error", err.Error())
+	}
+}
+
+// sessionClaims ties the access token to its session so websocket connections opened with
+// it can be closed when the session is revoked.
+func sessionClaims(sessionId uuid.UUID) map[string]string {
+	return map[string]string{
+		"sid": sessionId.String(),
+	}
+}
+
+type TotpEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// EnrollTotp generates a new TOTP secret for the user, logins keep working without a code
+// until the enrollment is confirmed with ConfirmTotp.
+func (us *UserService) EnrollTotp(ctx context.Context, userId uuid.UUID) (*TotpEnrollment, error) {
+	user, err := us.getUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTotpEnabled() {
+		return nil, domain.BusinessValidationError("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, domain.ServerError("failed to generate totp secret", err)
+	}
+
+	err = us.userRepository.SetTotpSecret(ctx, user.Id, secret)
+	if err != nil {
+		return nil, domain.ServerError("failed to save totp secret", err)
+	}
+
+	enrollment := TotpEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, totpIssuer, user.Email),
+	}
+
+	return &enrollment, nil
+}
+
+// ConfirmTotp enables two-factor authentication once the user proves their device generates
+// valid codes, it returns the recovery codes which are not stored in plain text.
+func (us *UserService) ConfirmTotp(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	user, err := us.getUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTotpEnabled() {
+		return nil, domain.BusinessValidationError("two-factor authentication is already enabled")
+	}
+
+	if user.TotpSecret == "" {
+		return nil, domain.BusinessValidationError("two-factor authentication enrollment was not started")
+	}
+
+	step, ok := totp.Validate(user.TotpSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, domain.BusinessValidationError("invalid code")
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodeHashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, domain.ServerError("failed to generate recovery code", err)
+		}
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		recoveryCodeHashes = append(recoveryCodeHashes, hashRecoveryCode(recoveryCode))
+	}
+
+	err = us.userRepository.EnableTotp(ctx, user.Id, step, recoveryCodeHashes)
+	if err != nil {
+		return nil, domain.ServerError("failed to enable totp", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTotp turns two-factor authentication off, it requires a current TOTP or recovery
+// code so a stolen access token is not enough.
+func (us *UserService) DisableTotp(ctx context.Context, userId uuid.UUID, code string) error {
+	user, err := us.getUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsTotpEnabled() {
+		return domain.BusinessValidationError("two-factor authentication is not enabled")
+	}
+
+	err = us.verifySecondFactor(ctx, user, code)
+	if err != nil {
+		return err
+	}
+
+	err = us.userRepository.DisableTotp(ctx, user.Id)
+	if err != nil {
+		return domain.ServerError("failed to disable totp", err)
+	}
+
+	return nil
+}
+
+type VerifyMfaRequest struct {
+	Token     string
+	Code      string
+	UserAgent string
+	IpAddress string
+}
+
+// VerifyMfa completes a login that returned an MFA challenge, the code can be a TOTP code or
+// one of the recovery codes. Wrong codes count as failed logins of the account.
+func (us *UserService) VerifyMfa(ctx context.Context, request VerifyMfaRequest) (*LoginResult, error) {
+	const INVALID_MFA_TOKEN_ERROR_MESSAGE = "invalid mfa token"
+
+	challenge, err := us.userRepository.GetToken(ctx, hashToken(request.Token), domain.UserTokenPurposeMfaChallenge)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil, domain.UnauthorizedError(INVALID_MFA_TOKEN_ERROR_MESSAGE)
+		}
+		return nil, domain.ServerError("failed to get mfa token", err)
+	}
+
+	if !challenge.IsValid(time.Now()) {
+		return nil, domain.UnauthorizedError(INVALID_MFA_TOKEN_ERROR_MESSAGE)
+	}
+
+	user, err := us.getUser(ctx, challenge.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := domain.LoginAttempt{
+		Email:         strings.ToLower(strings.TrimSpace(user.Email)),
+		IpAddress:     request.IpAddress,
+		UserId:        &user.Id,
+		FailureReason: domain.LoginFailureReasonInvalidCredentials,
+	}
+
+	lockedUntil, err := us.startLoginAttempt(ctx, &attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedUntil.After(time.Now()) {
+		attempt.FailureReason = domain.LoginFailureReasonLocked
+		us.finishLoginAttempt(ctx, &attempt)
+		return nil, domain.TooManyRequestsError("too many failed login attempts, try again later", time.Until(lockedUntil))
+	}
+
+	err = us.verifySecondFactor(ctx, user, request.Code)
+	if err != nil {
+		var domainErr domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.UnauthorizedErrorCode {
+			// only wrong codes count towards the lockout
+			attempt.FailureReason = domain.LoginFailureReasonMfaRequired
+		}
+		us.finishLoginAttempt(ctx, &attempt)
+		return nil, err
+	}
+
+	err = us.userRepository.UseToken(ctx, challenge)
+	if err != nil {
+		attempt.FailureReason = domain.LoginFailureReasonMfaRequired
+		us.finishLoginAttempt(ctx, &attempt)
+
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return nil, domain.UnauthorizedError(INVALID_MFA_TOKEN_ERROR_MESSAGE)
+		}
+		return nil, domain.ServerError("failed to use mfa token", err)
+	}
+
+	attempt.Success = true
+	attempt.FailureReason = ""
+	us.finishLoginAttempt(ctx, &attempt)
+
+	return us.startSession(ctx, user, request.UserAgent, request.IpAddress)
+}
+
+// verifySecondFactor accepts a TOTP code that was not used before or an unused recovery
+// code, anything else returns an unauthorized error.
+func (us *UserService) verifySecondFactor(ctx context.Context, user *domain.User, code string) error {
+	const INVALID_CODE_ERROR_MESSAGE = "invalid code"
+
+	code = strings.TrimSpace(code)
+
+	var err error
+	if step, ok := totp.Validate(user.TotpSecret, code, time.Now()); ok {
+		err = us.userRepository.UseTotpStep(ctx, user.Id, step)
+	} else if len(code) == totp.Digits {
+		return domain.UnauthorizedError(INVALID_CODE_ERROR_MESSAGE)
+	} else {
+		err = us.userRepository.UseRecoveryCode(ctx, user.Id, hashRecoveryCode(code))
+	}
+
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			return domain.UnauthorizedError(INVALID_CODE_ERROR_MESSAGE)
+		}
+		return domain.ServerError("failed to verify code", err)
+	}
+
+	return nil
+}
+
+func (us *UserService) getUser(ctx context.Context, userId uuid.UUID) (*domain.User, error) {
+	if userId == uuid.Nil {
+		return nil, domain.UnauthorizedError("unauthorized")
+	}
+
+	user, err := us.userRepository.GetById(ctx, userId)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, domainErr
+		}
+		return nil, domain.ServerError("failed to get user", err)
+	}
+
+	if user.IsDeleted() {
+		return nil, domain.NotFoundError("user not found")
+	}
+
+	return user, nil
+}
+
+// generateRecoveryCode returns a code formatted as two groups of five characters so it is
+// easy to copy by hand.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return hashToken(normalized)
 }
 
 func GenerateRefreshToken(length int) (string, error) {
@@ -226,6 +1305,13 @@ func GenerateRefreshToken(length int) (string, error) {
 	return base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b), nil
 }
 
+// hashToken returns the value stored for tokens sent to users, tokens are random so a plain
+// sha256 is enough to look them up without keeping them in the database.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func HashPassword(plaintext string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(plaintext), 10)
 	if err != nil {
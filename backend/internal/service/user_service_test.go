@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/mailer"
 	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/gabrielnakaema/project-chat/internal/totp"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,21 +38,177 @@ func (m *mockUserRepository) GetByEmail(ctx context.Context, email string) (*dom
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *mockUserRepository) CreateRefreshToken(ctx context.Context, refreshToken *domain.RefreshToken) error {
-	args := m.Called(ctx, refreshToken)
+func (m *mockUserRepository) GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+}
+
+func (m *mockUserRepository) CreateSession(ctx context.Context, session *domain.UserSession, refreshToken *domain.RefreshToken) error {
+	args := m.Called(ctx, session, refreshToken)
 	return args.Error(0)
 }
 
-func (m *mockUserRepository) GetRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+func (m *mockUserRepository) GetSession(ctx context.Context, id uuid.UUID) (*domain.UserSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserSession), args.Error(1)
+}
+
+func (m *mockUserRepository) IsSessionActive(ctx context.Context, id uuid.UUID, userId uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, userId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) ListActiveSessions(ctx context.Context, userId uuid.UUID) ([]domain.UserSession, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.UserSession), args.Error(1)
+}
+
+func (m *mockUserRepository) RotateRefreshToken(ctx context.Context, session *domain.UserSession, oldToken *domain.RefreshToken, newToken *domain.RefreshToken) error {
+	args := m.Called(ctx, session, oldToken, newToken)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	args := m.Called(ctx, sessionId)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) CreateToken(ctx context.Context, token *domain.UserToken) error {
 	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) GetToken(ctx context.Context, tokenHash string, purpose domain.UserTokenPurpose) (*domain.UserToken, error) {
+	args := m.Called(ctx, tokenHash, purpose)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*domain.RefreshToken), args.Error(1)
+	return args.Get(0).(*domain.UserToken), args.Error(1)
+}
+
+func (m *mockUserRepository) InvalidateTokens(ctx context.Context, userId uuid.UUID, purpose domain.UserTokenPurpose) error {
+	args := m.Called(ctx, userId, purpose)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) VerifyEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error {
+	args := m.Called(ctx, token, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ResetPassword(ctx context.Context, token *domain.UserToken, password string) ([]uuid.UUID, error) {
+	args := m.Called(ctx, token, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *mockUserRepository) UseToken(ctx context.Context, token *domain.UserToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) SetTotpSecret(ctx context.Context, userId uuid.UUID, secret string) error {
+	args := m.Called(ctx, userId, secret)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) EnableTotp(ctx context.Context, userId uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, userId, step, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) DisableTotp(ctx context.Context, userId uuid.UUID) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) UseTotpStep(ctx context.Context, userId uuid.UUID, step int64) error {
+	args := m.Called(ctx, userId, step)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) error {
+	args := m.Called(ctx, userId, codeHash)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) UpdateProfile(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ChangeEmail(ctx context.Context, token *domain.UserToken, verifiedAt time.Time) error {
+	args := m.Called(ctx, token, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ChangePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	args := m.Called(ctx, userId, password)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ListRoomIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *mockUserRepository) Delete(ctx context.Context, user *domain.User, deletedAt time.Time) ([]domain.ProjectMember, error) {
+	args := m.Called(ctx, user, deletedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProjectMember), args.Error(1)
+}
+
+// recordingPublisher keeps the topics it was asked to publish, in order.
+type recordingPublisher struct {
+	topics   []events.Topic
+	payloads []interface{}
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, topic events.Topic, payload interface{}) error {
+	p.topics = append(p.topics, topic)
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+type mockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *mockLoginAttemptRepository) Create(ctx context.Context, attempt *domain.LoginAttempt, since time.Time) (*domain.LoginFailures, *domain.LoginFailures, error) {
+	args := m.Called(ctx, attempt, since)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.LoginFailures), args.Get(1).(*domain.LoginFailures), args.Error(2)
+}
+
+func (m *mockLoginAttemptRepository) UpdateResult(ctx context.Context, attempt *domain.LoginAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
 }
 
-func (m *mockUserRepository) UpdateRefreshTokenActive(ctx context.Context, refreshToken *domain.RefreshToken) error {
-	args := m.Called(ctx, refreshToken)
+type mockMailer struct {
+	mock.Mock
+}
+
+func (m *mockMailer) Send(ctx context.Context, message mailer.Message) error {
+	args := m.Called(ctx, message)
 	return args.Error(0)
 }
 
@@ -62,11 +221,20 @@ func (m *mockJWTProvider) Generate(sub string, exp time.Time, claims map[string]
 	return args.String(0), args.Error(1)
 }
 
+type mockInvitationAccepter struct {
+	mock.Mock
+}
+
+func (m *mockInvitationAccepter) AcceptPendingInvitations(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
 func TestUserService_Create(t *testing.T) {
 	tests := []struct {
 		name          string
 		request       service.CreateUserRequest
-		mockSetup     func(*mockUserRepository)
+		mockSetup     func(*mockUserRepository, *mockInvitationAccepter, *mockMailer)
 		expectedUser  *domain.User
 		expectedError error
 		shouldSucceed bool
@@ -78,11 +246,31 @@ func TestUserService_Create(t *testing.T) {
 				Email:    "john@example.com",
 				Password: "password123",
 			},
-			mockSetup: func(repo *mockUserRepository) {
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter, mail *mockMailer) {
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Run(func(args mock.Arguments) {
 					user := args.Get(1).(*domain.User)
 					user.Id = uuid.New()
 				})
+				repo.On("InvalidateTokens", mock.Anything, mock.AnythingOfType("uuid.UUID"), domain.UserTokenPurposeEmailVerification).Return(nil)
+				repo.On("CreateToken", mock.Anything, mock.AnythingOfType("*domain.UserToken")).Return(nil)
+				mail.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					return message.To == "john@example.com"
+				})).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "verification email error does not fail creation",
+			request: service.CreateUserRequest{
+				Name:     "John Doe",
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter, mail *mockMailer) {
+				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+				repo.On("InvalidateTokens", mock.Anything, mock.AnythingOfType("uuid.UUID"), domain.UserTokenPurposeEmailVerification).Return(nil)
+				repo.On("CreateToken", mock.Anything, mock.AnythingOfType("*domain.UserToken")).Return(nil)
+				mail.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).Return(errors.New("smtp error"))
 			},
 			shouldSucceed: true,
 		},
@@ -93,7 +281,7 @@ func TestUserService_Create(t *testing.T) {
 				Email:    "john@example.com",
 				Password: "password123",
 			},
-			mockSetup: func(repo *mockUserRepository) {
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter, mail *mockMailer) {
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.DuplicateEntryError("user email is already taken"))
 			},
 			expectedError: domain.DuplicateEntryError("user email is already taken"),
@@ -106,7 +294,7 @@ func TestUserService_Create(t *testing.T) {
 				Email:    "john@example.com",
 				Password: "password123",
 			},
-			mockSetup: func(repo *mockUserRepository) {
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter, mail *mockMailer) {
 				repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
 			},
 			shouldSucceed: false,
@@ -117,9 +305,11 @@ func TestUserService_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockUserRepository{}
 			mockJWT := &mockJWTProvider{}
-			tt.mockSetup(mockRepo)
+			mockAccepter := &mockInvitationAccepter{}
+			mockMail := &mockMailer{}
+			tt.mockSetup(mockRepo, mockAccepter, mockMail)
 
-			service := service.NewUserService(mockJWT, mockRepo)
+			service := service.NewUserService(mockJWT, mockRepo, &mockLoginAttemptRepository{}, mockAccepter, mockMail, &mockPublisher{}, "http://localhost:5173")
 			ctx := context.Background()
 
 			user, err := service.Create(ctx, tt.request)
@@ -139,6 +329,9 @@ func TestUserService_Create(t *testing.T) {
 			}
 
 			mockRepo.AssertExpectations(t)
+			mockMail.AssertExpectations(t)
+			// invitations are only accepted once the email is verified
+			mockAccepter.AssertNotCalled(t, "AcceptPendingInvitations", mock.Anything, mock.Anything)
 		})
 	}
 }
@@ -154,12 +347,13 @@ func TestUserService_Login(t *testing.T) {
 	}
 
 	tests := []struct {
-		name          string
-		request       service.LoginRequest
-		mockUserSetup func(*mockUserRepository)
-		mockJWTSetup  func(*mockJWTProvider)
-		shouldSucceed bool
-		expectedError string
+		name              string
+		request           service.LoginRequest
+		mockUserSetup     func(*mockUserRepository)
+		mockJWTSetup      func(*mockJWTProvider)
+		mockAttemptsSetup func(*mockLoginAttemptRepository)
+		shouldSucceed     bool
+		expectedError     string
 	}{
 		{
 			name: "successful login",
@@ -169,9 +363,12 @@ func TestUserService_Login(t *testing.T) {
 			},
 			mockUserSetup: func(repo *mockUserRepository) {
 				repo.On("GetByEmail", mock.Anything, "john@example.com").Return(validUser, nil)
-				repo.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Run(func(args mock.Arguments) {
-					token := args.Get(1).(*domain.RefreshToken)
+				repo.On("CreateSession", mock.Anything, mock.AnythingOfType("*domain.UserSession"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Run(func(args mock.Arguments) {
+					session := args.Get(1).(*domain.UserSession)
+					session.Id = uuid.New()
+					token := args.Get(2).(*domain.RefreshToken)
 					token.Id = uuid.New()
+					token.SessionId = session.Id
 				})
 			},
 			mockJWTSetup: func(jwt *mockJWTProvider) {
@@ -179,6 +376,29 @@ func TestUserService_Login(t *testing.T) {
 			},
 			shouldSucceed: true,
 		},
+		{
+			name: "email is looked up the way attempts are recorded",
+			request: service.LoginRequest{
+				Email:    " John@Example.com ",
+				Password: "password123",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				repo.On("GetByEmail", mock.Anything, "john@example.com").Return(validUser, nil)
+				repo.On("CreateSession", mock.Anything, mock.AnythingOfType("*domain.UserSession"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+			},
+			mockJWTSetup: func(jwt *mockJWTProvider) {
+				jwt.On("Generate", validUser.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("jwt-token", nil)
+			},
+			mockAttemptsSetup: func(attempts *mockLoginAttemptRepository) {
+				attempts.On("Create", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return attempt.Email == "john@example.com"
+				}), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return attempt.Success && attempt.Email == "john@example.com"
+				})).Return(nil)
+			},
+			shouldSucceed: true,
+		},
 		{
 			name: "user not found",
 			request: service.LoginRequest{
@@ -205,16 +425,94 @@ func TestUserService_Login(t *testing.T) {
 			shouldSucceed: false,
 			expectedError: "invalid credentials",
 		},
+		{
+			name: "locked account",
+			request: service.LoginRequest{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			mockAttemptsSetup: func(attempts *mockLoginAttemptRepository) {
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{Count: 5, LastFailedAt: time.Now()}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return !attempt.Success && attempt.FailureReason == domain.LoginFailureReasonLocked
+				})).Return(nil)
+			},
+			shouldSucceed: false,
+			expectedError: "too many failed login attempts",
+		},
+		{
+			name: "lockout doubles with every failure",
+			request: service.LoginRequest{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			mockAttemptsSetup: func(attempts *mockLoginAttemptRepository) {
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{Count: 7, LastFailedAt: time.Now().Add(-3 * time.Minute)}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+			},
+			shouldSucceed: false,
+			expectedError: "too many failed login attempts",
+		},
+		{
+			name: "expired lockout",
+			request: service.LoginRequest{
+				Email:    "john@example.com",
+				Password: "password123",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				repo.On("GetByEmail", mock.Anything, "john@example.com").Return(validUser, nil)
+				repo.On("CreateSession", mock.Anything, mock.AnythingOfType("*domain.UserSession"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+			},
+			mockJWTSetup: func(jwt *mockJWTProvider) {
+				jwt.On("Generate", validUser.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("jwt-token", nil)
+			},
+			mockAttemptsSetup: func(attempts *mockLoginAttemptRepository) {
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{Count: 6, LastFailedAt: time.Now().Add(-3 * time.Minute)}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return attempt.Success
+				})).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "locked ip address",
+			request: service.LoginRequest{
+				Email:     "john@example.com",
+				Password:  "password123",
+				IpAddress: "10.0.0.1",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			mockAttemptsSetup: func(attempts *mockLoginAttemptRepository) {
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{Count: 20, LastFailedAt: time.Now()}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+			},
+			shouldSucceed: false,
+			expectedError: "too many failed login attempts",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockUserRepository{}
 			mockJWT := &mockJWTProvider{}
+			mockAttempts := &mockLoginAttemptRepository{}
 			tt.mockUserSetup(mockRepo)
 			tt.mockJWTSetup(mockJWT)
+			if tt.mockAttemptsSetup != nil {
+				tt.mockAttemptsSetup(mockAttempts)
+			} else {
+				mockAttempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				mockAttempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return attempt.Success == tt.shouldSucceed
+				})).Return(nil)
+			}
 
-			service := service.NewUserService(mockJWT, mockRepo)
+			service := service.NewUserService(mockJWT, mockRepo, mockAttempts, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
 			ctx := context.Background()
 
 			result, err := service.Login(ctx, tt.request)
@@ -229,15 +527,13 @@ func TestUserService_Login(t *testing.T) {
 				assert.Error(t, err)
 				assert.Nil(t, result)
 				if tt.expectedError != "" {
-					var domainErr domain.DomainError
-					if errors.As(err, &domainErr) {
-						assert.Contains(t, domainErr.Error(), tt.expectedError)
-					}
+					assert.Contains(t, err.Error(), tt.expectedError)
 				}
 			}
 
 			mockRepo.AssertExpectations(t)
 			mockJWT.AssertExpectations(t)
+			mockAttempts.AssertExpectations(t)
 		})
 	}
 }
@@ -254,6 +550,7 @@ func TestUserService_RefreshToken(t *testing.T) {
 	validRefreshToken := &domain.RefreshToken{
 		Id:        uuid.New(),
 		UserId:    validUser.Id,
+		SessionId: uuid.New(),
 		Token:     "valid-refresh-token",
 		Active:    true,
 		ExpiresAt: time.Now().Add(time.Hour),
@@ -271,16 +568,16 @@ func TestUserService_RefreshToken(t *testing.T) {
 		{
 			name: "successful token refresh",
 			request: service.RefreshTokenRequest{
-				Token: "valid-refresh-token",
+				Token:     "valid-refresh-token",
+				UserAgent: "Firefox",
+				IpAddress: "127.0.0.1",
 			},
 			mockUserSetup: func(repo *mockUserRepository) {
 				repo.On("GetRefreshToken", mock.Anything, "valid-refresh-token").Return(validRefreshToken, nil)
 				repo.On("GetById", mock.Anything, validUser.Id).Return(validUser, nil)
-				repo.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Run(func(args mock.Arguments) {
-					token := args.Get(1).(*domain.RefreshToken)
-					token.Id = uuid.New()
-				})
-				repo.On("UpdateRefreshTokenActive", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+				repo.On("RotateRefreshToken", mock.Anything, mock.MatchedBy(func(session *domain.UserSession) bool {
+					return session.Id == validRefreshToken.SessionId && session.UserAgent == "Firefox"
+				}), validRefreshToken, mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
 			},
 			mockJWTSetup: func(jwt *mockJWTProvider) {
 				jwt.On("Generate", validUser.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("new-jwt-token", nil)
@@ -307,17 +604,119 @@ func TestUserService_RefreshToken(t *testing.T) {
 				inactiveToken := &domain.RefreshToken{
 					Id:        uuid.New(),
 					UserId:    validUser.Id,
+					SessionId: uuid.New(),
 					Token:     "inactive-token",
 					Active:    false,
 					ExpiresAt: time.Now().Add(time.Hour),
 					CreatedAt: time.Now(),
 				}
 				repo.On("GetRefreshToken", mock.Anything, "inactive-token").Return(inactiveToken, nil)
+				repo.On("RevokeSession", mock.Anything, inactiveToken.SessionId).Return(nil)
+			},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			shouldSucceed: false,
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "token rotated last is accepted within the grace period",
+			request: service.RefreshTokenRequest{
+				Token: "just-rotated-token",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				rotatedAt := time.Now().Add(-5 * time.Second)
+				rotatedToken := &domain.RefreshToken{
+					Id:        uuid.New(),
+					UserId:    validUser.Id,
+					SessionId: uuid.New(),
+					Token:     "just-rotated-token",
+					Active:    false,
+					ExpiresAt: time.Now().Add(time.Hour),
+					CreatedAt: time.Now(),
+					RotatedAt: &rotatedAt,
+				}
+				repo.On("GetRefreshToken", mock.Anything, "just-rotated-token").Return(rotatedToken, nil)
+				repo.On("GetById", mock.Anything, validUser.Id).Return(validUser, nil)
+				repo.On("RotateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.UserSession"), rotatedToken, mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+			},
+			mockJWTSetup: func(jwt *mockJWTProvider) {
+				jwt.On("Generate", validUser.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("new-jwt-token", nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name: "token rotated after the grace period revokes the session",
+			request: service.RefreshTokenRequest{
+				Token: "old-rotated-token",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				rotatedAt := time.Now().Add(-time.Minute)
+				rotatedToken := &domain.RefreshToken{
+					Id:        uuid.New(),
+					UserId:    validUser.Id,
+					SessionId: uuid.New(),
+					Token:     "old-rotated-token",
+					Active:    false,
+					ExpiresAt: time.Now().Add(time.Hour),
+					CreatedAt: time.Now(),
+					RotatedAt: &rotatedAt,
+				}
+				repo.On("GetRefreshToken", mock.Anything, "old-rotated-token").Return(rotatedToken, nil)
+				repo.On("RevokeSession", mock.Anything, rotatedToken.SessionId).Return(nil)
+			},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			shouldSucceed: false,
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "reused refresh token of revoked session",
+			request: service.RefreshTokenRequest{
+				Token: "revoked-token",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				revokedToken := &domain.RefreshToken{
+					Id:        uuid.New(),
+					UserId:    validUser.Id,
+					SessionId: uuid.New(),
+					Token:     "revoked-token",
+					Active:    false,
+					ExpiresAt: time.Now().Add(time.Hour),
+					CreatedAt: time.Now(),
+				}
+				repo.On("GetRefreshToken", mock.Anything, "revoked-token").Return(revokedToken, nil)
+				repo.On("RevokeSession", mock.Anything, revokedToken.SessionId).Return(domain.NotFoundError("session not found"))
+			},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			shouldSucceed: false,
+			expectedError: "invalid refresh token",
+		},
+		{
+			name: "refresh token rotated concurrently",
+			request: service.RefreshTokenRequest{
+				Token: "valid-refresh-token",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				repo.On("GetRefreshToken", mock.Anything, "valid-refresh-token").Return(validRefreshToken, nil)
+				repo.On("GetById", mock.Anything, validUser.Id).Return(validUser, nil)
+				repo.On("RotateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.UserSession"), validRefreshToken, mock.AnythingOfType("*domain.RefreshToken")).Return(domain.NotFoundError("refresh token not found"))
+				repo.On("RevokeSession", mock.Anything, validRefreshToken.SessionId).Return(nil)
 			},
 			mockJWTSetup:  func(jwt *mockJWTProvider) {},
 			shouldSucceed: false,
 			expectedError: "invalid refresh token",
 		},
+		{
+			name: "rotation error",
+			request: service.RefreshTokenRequest{
+				Token: "valid-refresh-token",
+			},
+			mockUserSetup: func(repo *mockUserRepository) {
+				repo.On("GetRefreshToken", mock.Anything, "valid-refresh-token").Return(validRefreshToken, nil)
+				repo.On("GetById", mock.Anything, validUser.Id).Return(validUser, nil)
+				repo.On("RotateRefreshToken", mock.Anything, mock.AnythingOfType("*domain.UserSession"), validRefreshToken, mock.AnythingOfType("*domain.RefreshToken")).Return(errors.New("database error"))
+			},
+			mockJWTSetup:  func(jwt *mockJWTProvider) {},
+			shouldSucceed: false,
+		},
 		{
 			name: "expired refresh token",
 			request: service.RefreshTokenRequest{
@@ -347,7 +746,7 @@ func TestUserService_RefreshToken(t *testing.T) {
 			tt.mockUserSetup(mockRepo)
 			tt.mockJWTSetup(mockJWT)
 
-			service := service.NewUserService(mockJWT, mockRepo)
+			service := service.NewUserService(mockJWT, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
 			ctx := context.Background()
 
 			result, err := service.RefreshToken(ctx, tt.request)
@@ -375,6 +774,992 @@ func TestUserService_RefreshToken(t *testing.T) {
 	}
 }
 
+func TestUserService_VerifyEmail(t *testing.T) {
+	userId := uuid.New()
+	verifiedAt := time.Now()
+	verifiedUser := &domain.User{
+		Id:              userId,
+		Email:           "john@example.com",
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	tests := []struct {
+		name              string
+		request           service.VerifyEmailRequest
+		mockSetup         func(*mockUserRepository, *mockInvitationAccepter)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:    "successful verification",
+			request: service.VerifyEmailRequest{Token: "valid-token"},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter) {
+				token := &domain.UserToken{
+					Id:        uuid.New(),
+					UserId:    userId,
+					Purpose:   domain.UserTokenPurposeEmailVerification,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailVerification).Return(token, nil)
+				repo.On("VerifyEmail", mock.Anything, token, mock.AnythingOfType("time.Time")).Return(nil)
+				repo.On("GetById", mock.Anything, userId).Return(verifiedUser, nil)
+				accepter.On("AcceptPendingInvitations", mock.Anything, verifiedUser).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "pending invitations error does not fail verification",
+			request: service.VerifyEmailRequest{Token: "valid-token"},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter) {
+				token := &domain.UserToken{
+					Id:        uuid.New(),
+					UserId:    userId,
+					Purpose:   domain.UserTokenPurposeEmailVerification,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailVerification).Return(token, nil)
+				repo.On("VerifyEmail", mock.Anything, token, mock.AnythingOfType("time.Time")).Return(nil)
+				repo.On("GetById", mock.Anything, userId).Return(verifiedUser, nil)
+				accepter.On("AcceptPendingInvitations", mock.Anything, verifiedUser).Return(errors.New("database error"))
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "unknown token",
+			request: service.VerifyEmailRequest{Token: "unknown-token"},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailVerification).Return(nil, domain.NotFoundError("token not found"))
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "expired token",
+			request: service.VerifyEmailRequest{Token: "expired-token"},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter) {
+				token := &domain.UserToken{
+					Id:        uuid.New(),
+					UserId:    userId,
+					Purpose:   domain.UserTokenPurposeEmailVerification,
+					ExpiresAt: time.Now().Add(-time.Hour),
+				}
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailVerification).Return(token, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "token used concurrently",
+			request: service.VerifyEmailRequest{Token: "valid-token"},
+			mockSetup: func(repo *mockUserRepository, accepter *mockInvitationAccepter) {
+				token := &domain.UserToken{
+					Id:        uuid.New(),
+					UserId:    userId,
+					Purpose:   domain.UserTokenPurposeEmailVerification,
+					ExpiresAt: time.Now().Add(time.Hour),
+				}
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailVerification).Return(token, nil)
+				repo.On("VerifyEmail", mock.Anything, token, mock.AnythingOfType("time.Time")).Return(domain.NotFoundError("token not found"))
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			mockAccepter := &mockInvitationAccepter{}
+			tt.mockSetup(mockRepo, mockAccepter)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, mockAccepter, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.VerifyEmail(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockAccepter.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_RequestPasswordReset(t *testing.T) {
+	validUser := &domain.User{
+		Id:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+
+	tests := []struct {
+		name       string
+		request    service.RequestPasswordResetRequest
+		mockSetup  func(*mockUserRepository, *mockMailer, chan struct{})
+		shouldSend bool
+	}{
+		{
+			name:    "sends reset email",
+			request: service.RequestPasswordResetRequest{Email: "john@example.com"},
+			mockSetup: func(repo *mockUserRepository, mail *mockMailer, sent chan struct{}) {
+				repo.On("GetByEmail", mock.Anything, "john@example.com").Return(validUser, nil)
+				repo.On("InvalidateTokens", mock.Anything, validUser.Id, domain.UserTokenPurposePasswordReset).Return(nil)
+				repo.On("CreateToken", mock.Anything, mock.MatchedBy(func(token *domain.UserToken) bool {
+					return token.UserId == validUser.Id && token.TokenHash != "" && token.TokenHash != token.Token
+				})).Return(nil)
+				mail.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					return message.To == validUser.Email
+				})).Return(nil).Run(func(args mock.Arguments) { close(sent) })
+			},
+			shouldSend: true,
+		},
+		{
+			name:    "unknown email succeeds without sending",
+			request: service.RequestPasswordResetRequest{Email: "unknown@example.com"},
+			mockSetup: func(repo *mockUserRepository, mail *mockMailer, sent chan struct{}) {
+				repo.On("GetByEmail", mock.Anything, "unknown@example.com").Return(nil, domain.NotFoundError("user not found"))
+			},
+		},
+		{
+			name:    "mailer error is not reported",
+			request: service.RequestPasswordResetRequest{Email: "john@example.com"},
+			mockSetup: func(repo *mockUserRepository, mail *mockMailer, sent chan struct{}) {
+				repo.On("GetByEmail", mock.Anything, "john@example.com").Return(validUser, nil)
+				repo.On("InvalidateTokens", mock.Anything, validUser.Id, domain.UserTokenPurposePasswordReset).Return(nil)
+				repo.On("CreateToken", mock.Anything, mock.AnythingOfType("*domain.UserToken")).Return(nil)
+				mail.On("Send", mock.Anything, mock.AnythingOfType("mailer.Message")).Return(errors.New("smtp error")).Run(func(args mock.Arguments) { close(sent) })
+			},
+			shouldSend: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			mockMail := &mockMailer{}
+			sent := make(chan struct{})
+			tt.mockSetup(mockRepo, mockMail, sent)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, mockMail, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.RequestPasswordReset(context.Background(), tt.request)
+			assert.NoError(t, err)
+
+			if tt.shouldSend {
+				select {
+				case <-sent:
+				case <-time.After(time.Second):
+					t.Fatal("password reset email was not sent")
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockMail.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	token := &domain.UserToken{
+		Id:        uuid.New(),
+		UserId:    uuid.New(),
+		Purpose:   domain.UserTokenPurposePasswordReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	usedAt := time.Now().Add(-time.Minute)
+	usedToken := &domain.UserToken{
+		Id:        uuid.New(),
+		UserId:    uuid.New(),
+		Purpose:   domain.UserTokenPurposePasswordReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+
+	sessionIds := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name            string
+		request         service.ResetPasswordRequest
+		mockSetup       func(*mockUserRepository)
+		expectedRevoked []uuid.UUID
+		shouldSucceed   bool
+	}{
+		{
+			name:    "successful reset",
+			request: service.ResetPasswordRequest{Token: "valid-token", Password: "newpassword"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposePasswordReset).Return(token, nil)
+				repo.On("ResetPassword", mock.Anything, token, mock.MatchedBy(func(password string) bool {
+					ok, _ := service.CompareHash("newpassword", password)
+					return ok
+				})).Return(sessionIds, nil)
+			},
+			expectedRevoked: sessionIds,
+			shouldSucceed:   true,
+		},
+		{
+			name:    "used token",
+			request: service.ResetPasswordRequest{Token: "used-token", Password: "newpassword"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposePasswordReset).Return(usedToken, nil)
+			},
+			shouldSucceed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo)
+
+			publisher := &recordingPublisher{}
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, publisher, "http://localhost:5173")
+
+			err := userService.ResetPassword(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+
+			// every revoked session is published so its websocket connections are closed
+			var revoked []uuid.UUID
+			for i, topic := range publisher.topics {
+				if assert.Equal(t, events.UserSessionRevoked, topic) {
+					session := publisher.payloads[i].(domain.UserSession)
+					assert.Equal(t, token.UserId, session.UserId)
+					revoked = append(revoked, session.Id)
+				}
+			}
+			assert.Equal(t, tt.expectedRevoked, revoked)
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_RevokeSession(t *testing.T) {
+	userId := uuid.New()
+	sessionId := uuid.New()
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name              string
+		userId            uuid.UUID
+		mockSetup         func(*mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:   "successful revocation",
+			userId: userId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetSession", mock.Anything, sessionId).Return(&domain.UserSession{Id: sessionId, UserId: userId}, nil)
+				repo.On("RevokeSession", mock.Anything, sessionId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:   "session of another user",
+			userId: userId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetSession", mock.Anything, sessionId).Return(&domain.UserSession{Id: sessionId, UserId: uuid.New()}, nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:   "session already revoked",
+			userId: userId,
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetSession", mock.Anything, sessionId).Return(&domain.UserSession{Id: sessionId, UserId: userId, RevokedAt: &revokedAt}, nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:              "anonymous user",
+			userId:            uuid.Nil,
+			mockSetup:         func(repo *mockUserRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.RevokeSession(context.Background(), tt.userId, sessionId)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ListSessions(t *testing.T) {
+	userId := uuid.New()
+	currentSessionId := uuid.New()
+
+	mockRepo := &mockUserRepository{}
+	mockRepo.On("ListActiveSessions", mock.Anything, userId).Return([]domain.UserSession{
+		{Id: uuid.New(), UserId: userId},
+		{Id: currentSessionId, UserId: userId},
+	}, nil)
+
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+	sessions, err := userService.ListSessions(context.Background(), userId, currentSessionId)
+
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_LoginWithTotp(t *testing.T) {
+	hashedPassword, _ := service.HashPassword("password123")
+	enabledAt := time.Now()
+	user := &domain.User{
+		Id:            uuid.New(),
+		Email:         "john@example.com",
+		Password:      hashedPassword,
+		TotpSecret:    "JBSWY3DPEHPK3PXP",
+		TotpEnabledAt: &enabledAt,
+	}
+
+	mockRepo := &mockUserRepository{}
+	mockAttempts := &mockLoginAttemptRepository{}
+
+	mockAttempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+	mockAttempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+		return !attempt.Success && attempt.FailureReason == domain.LoginFailureReasonMfaRequired
+	})).Return(nil)
+	mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(user, nil)
+	mockRepo.On("InvalidateTokens", mock.Anything, user.Id, domain.UserTokenPurposeMfaChallenge).Return(nil)
+	mockRepo.On("CreateToken", mock.Anything, mock.MatchedBy(func(token *domain.UserToken) bool {
+		return token.Purpose == domain.UserTokenPurposeMfaChallenge && token.ExpiresAt.Before(time.Now().Add(10*time.Minute))
+	})).Return(nil)
+
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, mockAttempts, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+	result, err := userService.Login(context.Background(), service.LoginRequest{Email: "john@example.com", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.MfaToken)
+	assert.Empty(t, result.AccessToken)
+	assert.Empty(t, result.RefreshToken)
+	mockRepo.AssertExpectations(t)
+	mockAttempts.AssertExpectations(t)
+}
+
+func TestUserService_VerifyMfa(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+
+	enabledAt := time.Now()
+	user := &domain.User{
+		Id:            uuid.New(),
+		Email:         "john@example.com",
+		TotpSecret:    secret,
+		TotpEnabledAt: &enabledAt,
+	}
+
+	challenge := &domain.UserToken{
+		Id:        uuid.New(),
+		UserId:    user.Id,
+		Purpose:   domain.UserTokenPurposeMfaChallenge,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	tests := []struct {
+		name              string
+		request           service.VerifyMfaRequest
+		mockSetup         func(*mockUserRepository, *mockLoginAttemptRepository, *mockJWTProvider)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:    "valid totp code",
+			request: service.VerifyMfaRequest{Token: "mfa-token", Code: code},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(challenge, nil)
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				repo.On("UseTotpStep", mock.Anything, user.Id, step).Return(nil)
+				repo.On("UseToken", mock.Anything, challenge).Return(nil)
+				attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return attempt.Success
+				})).Return(nil)
+				repo.On("CreateSession", mock.Anything, mock.AnythingOfType("*domain.UserSession"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+				jwt.On("Generate", user.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("jwt-token", nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "valid recovery code",
+			request: service.VerifyMfaRequest{Token: "mfa-token", Code: "ABCDE-FGHIJ"},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(challenge, nil)
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				repo.On("UseRecoveryCode", mock.Anything, user.Id, mock.AnythingOfType("string")).Return(nil)
+				repo.On("UseToken", mock.Anything, challenge).Return(nil)
+				attempts.On("UpdateResult", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+				repo.On("CreateSession", mock.Anything, mock.AnythingOfType("*domain.UserSession"), mock.AnythingOfType("*domain.RefreshToken")).Return(nil)
+				jwt.On("Generate", user.Id.String(), mock.AnythingOfType("time.Time"), mock.AnythingOfType("map[string]string")).Return("jwt-token", nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "wrong code is recorded as a failed login",
+			request: service.VerifyMfaRequest{Token: "mfa-token", Code: wrongCode},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(challenge, nil)
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+					return !attempt.Success && attempt.FailureReason == domain.LoginFailureReasonInvalidCredentials
+				})).Return(nil)
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "replayed totp code",
+			request: service.VerifyMfaRequest{Token: "mfa-token", Code: code},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(challenge, nil)
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{}, &domain.LoginFailures{}, nil)
+				repo.On("UseTotpStep", mock.Anything, user.Id, step).Return(domain.NotFoundError("totp code already used"))
+				attempts.On("UpdateResult", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "unknown mfa token",
+			request: service.VerifyMfaRequest{Token: "unknown", Code: code},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(nil, domain.NotFoundError("token not found"))
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "locked account",
+			request: service.VerifyMfaRequest{Token: "mfa-token", Code: code},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository, jwt *mockJWTProvider) {
+				repo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeMfaChallenge).Return(challenge, nil)
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				attempts.On("Create", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt"), mock.AnythingOfType("time.Time")).Return(&domain.LoginFailures{Count: 5, LastFailedAt: time.Now()}, &domain.LoginFailures{}, nil)
+				attempts.On("UpdateResult", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+			},
+			expectedErrorCode: string(domain.TooManyRequestsErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			mockAttempts := &mockLoginAttemptRepository{}
+			mockJWT := &mockJWTProvider{}
+			tt.mockSetup(mockRepo, mockAttempts, mockJWT)
+
+			userService := service.NewUserService(mockJWT, mockRepo, mockAttempts, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			result, err := userService.VerifyMfa(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.AccessToken)
+				assert.NotEmpty(t, result.RefreshToken)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockAttempts.AssertExpectations(t)
+			mockJWT.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ConfirmTotp(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+
+	user := &domain.User{
+		Id:         uuid.New(),
+		Email:      "john@example.com",
+		TotpSecret: secret,
+	}
+
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	mockRepo := &mockUserRepository{}
+	mockRepo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+	mockRepo.On("EnableTotp", mock.Anything, user.Id, step, mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+	recoveryCodes, err := userService.ConfirmTotp(context.Background(), user.Id, code)
+
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	hashes := mockRepo.Calls[1].Arguments.Get(3).([]string)
+	for i, recoveryCode := range recoveryCodes {
+		assert.NotEqual(t, recoveryCode, hashes[i])
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	userId := uuid.New()
+	name := "Jane Doe"
+	timezone := "America/Sao_Paulo"
+
+	mockRepo := &mockUserRepository{}
+	mockRepo.On("GetById", mock.Anything, userId).Return(&domain.User{Id: userId, Name: "John Doe", AvatarUrl: "https://example.com/john.png", Timezone: "UTC", Locale: "en"}, nil)
+	mockRepo.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+		return user.Name == name && user.Timezone == timezone && user.AvatarUrl == "https://example.com/john.png" && user.Locale == "en"
+	})).Return(nil)
+
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+	user, err := userService.UpdateProfile(context.Background(), service.UpdateProfileRequest{UserId: userId, Name: &name, Timezone: &timezone})
+
+	assert.NoError(t, err)
+	assert.Equal(t, name, user.Name)
+	assert.Equal(t, timezone, user.Timezone)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_RequestEmailChange(t *testing.T) {
+	hashed, _ := service.HashPassword("password123")
+	user := &domain.User{
+		Id:       uuid.New(),
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: hashed,
+	}
+
+	tests := []struct {
+		name              string
+		request           service.RequestEmailChangeRequest
+		mockSetup         func(*mockUserRepository, *mockMailer)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:    "successful request",
+			request: service.RequestEmailChangeRequest{UserId: user.Id, Email: "new@example.com", Password: "password123"},
+			mockSetup: func(repo *mockUserRepository, mailerMock *mockMailer) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				repo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, domain.NotFoundError("user not found"))
+				repo.On("InvalidateTokens", mock.Anything, user.Id, domain.UserTokenPurposeEmailChange).Return(nil)
+				repo.On("CreateToken", mock.Anything, mock.MatchedBy(func(token *domain.UserToken) bool {
+					return token.Purpose == domain.UserTokenPurposeEmailChange && token.Email == "new@example.com"
+				})).Return(nil)
+				mailerMock.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+					return message.To == "new@example.com"
+				})).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "wrong password",
+			request: service.RequestEmailChangeRequest{UserId: user.Id, Email: "new@example.com", Password: "wrongpassword"},
+			mockSetup: func(repo *mockUserRepository, mailerMock *mockMailer) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "email taken",
+			request: service.RequestEmailChangeRequest{UserId: user.Id, Email: "taken@example.com", Password: "password123"},
+			mockSetup: func(repo *mockUserRepository, mailerMock *mockMailer) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				repo.On("GetByEmail", mock.Anything, "taken@example.com").Return(&domain.User{Id: uuid.New(), Email: "taken@example.com"}, nil)
+			},
+			expectedErrorCode: string(domain.DuplicateEntryErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "same email",
+			request: service.RequestEmailChangeRequest{UserId: user.Id, Email: "John@example.com", Password: "password123"},
+			mockSetup: func(repo *mockUserRepository, mailerMock *mockMailer) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			mockMailer := &mockMailer{}
+			tt.mockSetup(mockRepo, mockMailer)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, mockMailer, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.RequestEmailChange(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockMailer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ConfirmEmailChange(t *testing.T) {
+	user := &domain.User{
+		Id:    uuid.New(),
+		Name:  "John Doe",
+		Email: "john@example.com",
+	}
+
+	token := &domain.UserToken{
+		Id:        uuid.New(),
+		UserId:    user.Id,
+		Purpose:   domain.UserTokenPurposeEmailChange,
+		Email:     "new@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockRepo := &mockUserRepository{}
+	mockRepo.On("GetToken", mock.Anything, mock.AnythingOfType("string"), domain.UserTokenPurposeEmailChange).Return(token, nil)
+	mockRepo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+	mockRepo.On("ChangeEmail", mock.Anything, token, mock.AnythingOfType("time.Time")).Return(nil)
+
+	mockMailer := &mockMailer{}
+	mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(message mailer.Message) bool {
+		return message.To == "john@example.com"
+	})).Return(nil)
+
+	mockAccepter := &mockInvitationAccepter{}
+	mockAccepter.On("AcceptPendingInvitations", mock.Anything, user).Return(nil)
+
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, mockAccepter, mockMailer, &mockPublisher{}, "http://localhost:5173")
+
+	err := userService.ConfirmEmailChange(context.Background(), service.ConfirmEmailChangeRequest{Token: "valid-token"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockMailer.AssertExpectations(t)
+	mockAccepter.AssertExpectations(t)
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	hashed, _ := service.HashPassword("password123")
+	user := &domain.User{
+		Id:       uuid.New(),
+		Email:    "John@Example.com",
+		Password: hashed,
+	}
+
+	currentSessionId := uuid.New()
+	otherSessionId := uuid.New()
+
+	startAttempt := func(attempts *mockLoginAttemptRepository, emailFailures *domain.LoginFailures) {
+		attempts.On("Create", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+			return attempt.Email == "john@example.com" && attempt.IpAddress == "192.0.2.1" && *attempt.UserId == user.Id
+		}), mock.AnythingOfType("time.Time")).Return(emailFailures, &domain.LoginFailures{}, nil)
+	}
+
+	finishAttempt := func(attempts *mockLoginAttemptRepository, success bool, reason domain.LoginFailureReason) {
+		attempts.On("UpdateResult", mock.Anything, mock.MatchedBy(func(attempt *domain.LoginAttempt) bool {
+			return attempt.Success == success && attempt.FailureReason == reason
+		})).Return(nil)
+	}
+
+	tests := []struct {
+		name              string
+		request           service.ChangePasswordRequest
+		mockSetup         func(*mockUserRepository, *mockLoginAttemptRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:    "successful change signs out the other sessions",
+			request: service.ChangePasswordRequest{UserId: user.Id, SessionId: currentSessionId, CurrentPassword: "password123", NewPassword: "newpassword", IpAddress: "192.0.2.1"},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				startAttempt(attempts, &domain.LoginFailures{})
+				finishAttempt(attempts, true, "")
+				repo.On("ChangePassword", mock.Anything, user.Id, mock.MatchedBy(func(password string) bool {
+					ok, _ := service.CompareHash("newpassword", password)
+					return ok
+				})).Return(nil)
+				repo.On("ListActiveSessions", mock.Anything, user.Id).Return([]domain.UserSession{{Id: currentSessionId, UserId: user.Id}, {Id: otherSessionId, UserId: user.Id}}, nil)
+				repo.On("RevokeSession", mock.Anything, otherSessionId).Return(nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "wrong current password is recorded as a failed attempt",
+			request: service.ChangePasswordRequest{UserId: user.Id, SessionId: currentSessionId, CurrentPassword: "wrongpassword", NewPassword: "newpassword", IpAddress: "192.0.2.1"},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				startAttempt(attempts, &domain.LoginFailures{})
+				finishAttempt(attempts, false, domain.LoginFailureReasonInvalidCredentials)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "locked account does not check the password",
+			request: service.ChangePasswordRequest{UserId: user.Id, SessionId: currentSessionId, CurrentPassword: "password123", NewPassword: "newpassword", IpAddress: "192.0.2.1"},
+			mockSetup: func(repo *mockUserRepository, attempts *mockLoginAttemptRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				startAttempt(attempts, &domain.LoginFailures{Count: 5, LastFailedAt: time.Now()})
+				finishAttempt(attempts, false, domain.LoginFailureReasonLocked)
+			},
+			expectedErrorCode: string(domain.TooManyRequestsErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:              "anonymous user",
+			request:           service.ChangePasswordRequest{UserId: uuid.Nil, CurrentPassword: "password123", NewPassword: "newpassword"},
+			mockSetup:         func(repo *mockUserRepository, attempts *mockLoginAttemptRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			mockAttempts := &mockLoginAttemptRepository{}
+			tt.mockSetup(mockRepo, mockAttempts)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, mockAttempts, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.ChangePassword(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockAttempts.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+
+	hashed, _ := service.HashPassword("password123")
+	user := &domain.User{
+		Id:       uuid.New(),
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: hashed,
+	}
+
+	enabledAt := time.Now()
+	totpUser := &domain.User{
+		Id:            uuid.New(),
+		Name:          "Jane Doe",
+		Email:         "jane@example.com",
+		Password:      hashed,
+		TotpSecret:    secret,
+		TotpEnabledAt: &enabledAt,
+	}
+
+	deletedAt := time.Now()
+	deletedUser := &domain.User{
+		Id:        uuid.New(),
+		Name:      domain.DeletedUserName,
+		DeletedAt: &deletedAt,
+	}
+
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	tests := []struct {
+		name              string
+		request           service.DeleteAccountRequest
+		mockSetup         func(*mockUserRepository)
+		expectedErrorCode string
+		shouldSucceed     bool
+	}{
+		{
+			name:    "successful deletion",
+			request: service.DeleteAccountRequest{UserId: user.Id, Password: "password123"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				repo.On("Delete", mock.Anything, user, mock.AnythingOfType("time.Time")).Return([]domain.ProjectMember{}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "wrong password",
+			request: service.DeleteAccountRequest{UserId: user.Id, Password: "wrongpassword"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "two-factor code is required when enabled",
+			request: service.DeleteAccountRequest{UserId: totpUser.Id, Password: "password123", Code: "12345"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, totpUser.Id).Return(totpUser, nil)
+				repo.On("UseRecoveryCode", mock.Anything, totpUser.Id, mock.Anything).Return(domain.NotFoundError("recovery code not found"))
+			},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "successful deletion with two-factor code",
+			request: service.DeleteAccountRequest{UserId: totpUser.Id, Password: "password123", Code: code},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, totpUser.Id).Return(totpUser, nil)
+				repo.On("UseTotpStep", mock.Anything, totpUser.Id, step).Return(nil)
+				repo.On("Delete", mock.Anything, totpUser, mock.AnythingOfType("time.Time")).Return([]domain.ProjectMember{}, nil)
+			},
+			shouldSucceed: true,
+		},
+		{
+			name:    "user still owns projects",
+			request: service.DeleteAccountRequest{UserId: user.Id, Password: "password123"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+				repo.On("Delete", mock.Anything, user, mock.AnythingOfType("time.Time")).Return(nil, domain.BusinessValidationError("projects you own must be deleted or handed over before deleting your account"))
+			},
+			expectedErrorCode: string(domain.BusinessValidationErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:    "user already deleted",
+			request: service.DeleteAccountRequest{UserId: deletedUser.Id, Password: "password123"},
+			mockSetup: func(repo *mockUserRepository) {
+				repo.On("GetById", mock.Anything, deletedUser.Id).Return(deletedUser, nil)
+			},
+			expectedErrorCode: string(domain.NotFoundErrorCode),
+			shouldSucceed:     false,
+		},
+		{
+			name:              "anonymous user",
+			request:           service.DeleteAccountRequest{UserId: uuid.Nil, Password: "password123"},
+			mockSetup:         func(repo *mockUserRepository) {},
+			expectedErrorCode: string(domain.UnauthorizedErrorCode),
+			shouldSucceed:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepository{}
+			tt.mockSetup(mockRepo)
+
+			userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, &mockPublisher{}, "http://localhost:5173")
+
+			err := userService.DeleteAccount(context.Background(), tt.request)
+
+			if tt.shouldSucceed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				var domainErr domain.DomainError
+				if assert.True(t, errors.As(err, &domainErr)) {
+					assert.Equal(t, tt.expectedErrorCode, string(domainErr.Code))
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_DeleteAccountRemovesProjectMemberships(t *testing.T) {
+	hashed, _ := service.HashPassword("password123")
+	user := &domain.User{
+		Id:       uuid.New(),
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: hashed,
+	}
+
+	projectMembers := []domain.ProjectMember{
+		{Id: uuid.New(), UserId: user.Id, ProjectId: uuid.New(), Role: domain.ProjectMemberRoleMember},
+		{Id: uuid.New(), UserId: user.Id, ProjectId: uuid.New(), Role: domain.ProjectMemberRoleViewer},
+	}
+
+	mockRepo := &mockUserRepository{}
+	mockRepo.On("GetById", mock.Anything, user.Id).Return(user, nil)
+	mockRepo.On("Delete", mock.Anything, user, mock.AnythingOfType("time.Time")).Return(projectMembers, nil)
+
+	publisher := &recordingPublisher{}
+	userService := service.NewUserService(&mockJWTProvider{}, mockRepo, &mockLoginAttemptRepository{}, &mockInvitationAccepter{}, &mockMailer{}, publisher, "http://localhost:5173")
+
+	err := userService.DeleteAccount(context.Background(), service.DeleteAccountRequest{UserId: user.Id, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []events.Topic{events.ProjectMemberRemoved, events.ProjectMemberRemoved, events.UserDeleted}, publisher.topics)
+	assert.Equal(t, projectMembers[0], publisher.payloads[0])
+	assert.Equal(t, projectMembers[1], publisher.payloads[1])
+	mockRepo.AssertExpectations(t)
+}
+
 func TestHashPassword(t *testing.T) {
 	tests := []struct {
 		name      string
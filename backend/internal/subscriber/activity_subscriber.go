@@ -0,0 +1,74 @@
+package subscriber
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+)
+
+type ActivityNotifier interface {
+	SendProjectActivity(ctx context.Context, activity *domain.ProjectActivity) error
+}
+
+// ActivitySubscriber consumes every topic to build the project activity feed and streams
+// the new entries to the project room.
+type ActivitySubscriber struct {
+	logger          *slog.Logger
+	subscriber      *Subscriber
+	activityService *service.ActivityService
+	notifier        ActivityNotifier
+}
+
+func NewActivitySubscriber(config *config.Config, logger *slog.Logger, activityService *service.ActivityService, notifier ActivityNotifier) (*ActivitySubscriber, error) {
+	subscriber, err := NewSubscriber(config, "activity.subscriber")
+	if err != nil {
+		return nil, domain.ServerError("failed to create activity subscriber", err)
+	}
+
+	activitySubscriber := &ActivitySubscriber{
+		logger:          logger,
+		subscriber:      subscriber,
+		activityService: activityService,
+		notifier:        notifier,
+	}
+
+	err = subscriber.Subscribe(context.Background(), events.AllTopics, activitySubscriber.handleEvents, activitySubscriber.logger)
+	if err != nil {
+		return nil, domain.ServerError("failed to subscribe to activity events", err)
+	}
+
+	return activitySubscriber, nil
+}
+
+func (as *ActivitySubscriber) handleEvents(ctx context.Context, message Message) error {
+	// events published outside of a request have no actor header and parse to uuid.Nil
+	actorId, _ := uuid.Parse(message.Metadata[events.ActorHeader])
+
+	activity, err := as.activityService.CreateFromEvent(ctx, message.Topic, message.Value, actorId)
+	if err != nil {
+		// events of a project that was deleted in the meantime have nowhere to go
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			as.logger.Warn("skipping activity of missing project", "topic", message.Topic.String(), "error", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	if activity == nil {
+		return nil
+	}
+
+	err = as.notifier.SendProjectActivity(ctx, activity)
+	if err != nil {
+		return domain.ServerError("failed to send project activity to ws server", err)
+	}
+
+	return nil
+}
@@ -10,10 +10,13 @@ import (
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/events"
 	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
 )
 
 type MessageNotifier interface {
 	SendMessages(ctx context.Context, message *domain.ChatMessage) error
+	RemoveUserFromRooms(ctx context.Context, userId uuid.UUID, roomIds ...uuid.UUID) error
+	CloseRooms(ctx context.Context, roomIds ...uuid.UUID) error
 }
 
 type ChatSubscriber struct {
@@ -36,7 +39,7 @@ func NewChatSubscriber(config *config.Config, logger *slog.Logger, chatService *
 		notifier:    notifier,
 	}
 
-	topics := []events.Topic{events.ProjectCreated, events.ProjectMemberCreated, events.ChatMemberCreated, events.ChatMessageCreated, events.ChatMemberViewed}
+	topics := []events.Topic{events.ProjectCreated, events.ProjectArchived, events.ProjectDeleted, events.ProjectMemberCreated, events.ProjectMemberRemoved, events.ProjectOwnershipTransferred, events.ChatMemberCreated, events.ChatMessageCreated, events.ChatMemberViewed}
 
 	err = subscriber.Subscribe(context.Background(), topics, chatSubscriber.handleChatEvents, chatSubscriber.logger)
 	if err != nil {
@@ -50,8 +53,16 @@ func (cs *ChatSubscriber) handleChatEvents(ctx context.Context, message Message)
 	switch message.Topic {
 	case events.ProjectCreated:
 		return cs.handleProjectCreated(ctx, message)
+	case events.ProjectArchived:
+		return cs.handleProjectArchived(ctx, message)
+	case events.ProjectDeleted:
+		return cs.handleProjectDeleted(ctx, message)
 	case events.ProjectMemberCreated:
 		return cs.handleProjectMemberCreated(ctx, message)
+	case events.ProjectMemberRemoved:
+		return cs.handleProjectMemberRemoved(ctx, message)
+	case events.ProjectOwnershipTransferred:
+		return cs.handleProjectOwnershipTransferred(ctx, message)
 	case events.ChatMemberCreated:
 		return cs.handleChatMemberCreated(ctx, message)
 	case events.ChatMessageCreated:
@@ -102,6 +113,52 @@ func (cs *ChatSubscriber) handleProjectCreated(ctx context.Context, message Mess
 	return nil
 }
 
+func (cs *ChatSubscriber) handleProjectArchived(ctx context.Context, message Message) error {
+	var project domain.Project
+	err := json.Unmarshal(message.Value, &project)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal project", err)
+	}
+
+	roomIds := []uuid.UUID{project.Id}
+
+	chat, err := cs.chatService.CreateArchivedMessage(ctx, &project)
+	if err != nil {
+		var domainErr domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+			cs.logger.Error("failed to create archived message", "error", err)
+			return err
+		}
+		cs.logger.Info("chat not found, skipping archived message", "project_id", project.Id)
+	} else {
+		roomIds = append(roomIds, chat.Id)
+	}
+
+	err = cs.notifier.CloseRooms(ctx, roomIds...)
+	if err != nil {
+		return domain.ServerError("failed to close rooms", err)
+	}
+
+	return nil
+}
+
+func (cs *ChatSubscriber) handleProjectDeleted(ctx context.Context, message Message) error {
+	var project domain.DeletedProject
+	err := json.Unmarshal(message.Value, &project)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal deleted project", err)
+	}
+
+	roomIds := append([]uuid.UUID{project.Id}, project.ChatIds...)
+
+	err = cs.notifier.CloseRooms(ctx, roomIds...)
+	if err != nil {
+		return domain.ServerError("failed to close rooms", err)
+	}
+
+	return nil
+}
+
 func (cs *ChatSubscriber) handleProjectMemberCreated(ctx context.Context, message Message) error {
 	var projectMember domain.ProjectMember
 	err := json.Unmarshal(message.Value, &projectMember)
@@ -127,6 +184,58 @@ func (cs *ChatSubscriber) handleProjectMemberCreated(ctx context.Context, messag
 	return nil
 }
 
+func (cs *ChatSubscriber) handleProjectMemberRemoved(ctx context.Context, message Message) error {
+	var projectMember domain.ProjectMember
+	err := json.Unmarshal(message.Value, &projectMember)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal project member", err)
+	}
+
+	// the project room is left first so a failure below does not keep the user listening
+	err = cs.notifier.RemoveUserFromRooms(ctx, projectMember.UserId, projectMember.ProjectId)
+	if err != nil {
+		return domain.ServerError("failed to remove user from rooms", err)
+	}
+
+	chat, err := cs.chatService.RemoveMemberFromProjectMember(ctx, &projectMember)
+	if err != nil {
+		var domainErr domain.DomainError
+		if !errors.As(err, &domainErr) || domainErr.Code != domain.NotFoundErrorCode {
+			cs.logger.Error("failed to remove member from project member", "error", err)
+			return err
+		}
+		cs.logger.Info("chat not found, skipping removal of chat member from project member", "project_member", projectMember)
+		return nil
+	}
+
+	err = cs.notifier.RemoveUserFromRooms(ctx, projectMember.UserId, chat.Id)
+	if err != nil {
+		return domain.ServerError("failed to remove user from rooms", err)
+	}
+
+	return nil
+}
+
+func (cs *ChatSubscriber) handleProjectOwnershipTransferred(ctx context.Context, message Message) error {
+	var transfer domain.ProjectOwnershipTransfer
+	err := json.Unmarshal(message.Value, &transfer)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal project ownership transfer", err)
+	}
+
+	err = cs.chatService.CreateOwnershipTransferredMessage(ctx, &transfer)
+	if err != nil {
+		var domainErr domain.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == domain.NotFoundErrorCode {
+			cs.logger.Info("chat not found, skipping ownership transferred message", "transfer", transfer)
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (cs *ChatSubscriber) handleChatMemberCreated(ctx context.Context, message Message) error {
 	var chatMember domain.ChatMember
 	err := json.Unmarshal(message.Value, &chatMember)
@@ -0,0 +1,51 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+)
+
+// ExportSubscriber builds the archives of the data exports requested by users.
+type ExportSubscriber struct {
+	logger        *slog.Logger
+	subscriber    *Subscriber
+	exportService *service.UserDataExportService
+}
+
+func NewExportSubscriber(config *config.Config, logger *slog.Logger, exportService *service.UserDataExportService) (*ExportSubscriber, error) {
+	subscriber, err := NewSubscriber(config, "export.subscriber")
+	if err != nil {
+		return nil, err
+	}
+
+	exportSubscriber := &ExportSubscriber{
+		logger:        logger,
+		subscriber:    subscriber,
+		exportService: exportService,
+	}
+
+	topics := []events.Topic{events.UserDataExportRequested}
+
+	err = subscriber.Subscribe(context.Background(), topics, exportSubscriber.handleExportRequested, exportSubscriber.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return exportSubscriber, nil
+}
+
+func (es *ExportSubscriber) handleExportRequested(ctx context.Context, message Message) error {
+	var export domain.UserDataExport
+	err := json.Unmarshal(message.Value, &export)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal export", err)
+	}
+
+	return es.exportService.Build(ctx, export.Id)
+}
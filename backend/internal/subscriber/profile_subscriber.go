@@ -0,0 +1,69 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/service"
+	"github.com/google/uuid"
+)
+
+type ProfileNotifier interface {
+	SendUserUpdated(ctx context.Context, user *domain.User, roomIds ...uuid.UUID) error
+}
+
+// ProfileSubscriber sends profile changes to the project and chat rooms of the user so the
+// member info cached by the clients is refreshed.
+type ProfileSubscriber struct {
+	logger      *slog.Logger
+	subscriber  *Subscriber
+	userService *service.UserService
+	notifier    ProfileNotifier
+}
+
+func NewProfileSubscriber(config *config.Config, logger *slog.Logger, userService *service.UserService, notifier ProfileNotifier) (*ProfileSubscriber, error) {
+	subscriber, err := NewSubscriber(config, "profile.subscriber")
+	if err != nil {
+		return nil, err
+	}
+
+	profileSubscriber := &ProfileSubscriber{
+		logger:      logger,
+		subscriber:  subscriber,
+		userService: userService,
+		notifier:    notifier,
+	}
+
+	topics := []events.Topic{events.UserProfileUpdated}
+
+	err = subscriber.Subscribe(context.Background(), topics, profileSubscriber.handleProfileUpdated, profileSubscriber.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return profileSubscriber, nil
+}
+
+func (ps *ProfileSubscriber) handleProfileUpdated(ctx context.Context, message Message) error {
+	var user domain.User
+	err := json.Unmarshal(message.Value, &user)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal user", err)
+	}
+
+	roomIds, err := ps.userService.ListRoomIds(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	err = ps.notifier.SendUserUpdated(ctx, &user, roomIds...)
+	if err != nil {
+		return domain.ServerError("failed to send updated user to ws server", err)
+	}
+
+	return nil
+}
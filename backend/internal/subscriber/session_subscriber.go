@@ -0,0 +1,87 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/gabrielnakaema/project-chat/internal/config"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/google/uuid"
+)
+
+type SessionNotifier interface {
+	CloseSession(context.Context, uuid.UUID) error
+	CloseUser(context.Context, uuid.UUID) error
+}
+
+// SessionSubscriber closes the websocket connections of revoked sessions and deleted users.
+type SessionSubscriber struct {
+	logger     *slog.Logger
+	subscriber *Subscriber
+	notifier   SessionNotifier
+}
+
+func NewSessionSubscriber(config *config.Config, logger *slog.Logger, notifier SessionNotifier) (*SessionSubscriber, error) {
+	subscriber, err := NewSubscriber(config, "session.subscriber")
+	if err != nil {
+		return nil, err
+	}
+
+	sessionSubscriber := &SessionSubscriber{
+		logger:     logger,
+		subscriber: subscriber,
+		notifier:   notifier,
+	}
+
+	topics := []events.Topic{events.UserSessionRevoked, events.UserDeleted}
+
+	err = subscriber.Subscribe(context.Background(), topics, sessionSubscriber.handleEvent, sessionSubscriber.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionSubscriber, nil
+}
+
+func (ss *SessionSubscriber) handleEvent(ctx context.Context, message Message) error {
+	switch message.Topic {
+	case events.UserSessionRevoked:
+		return ss.handleSessionRevoked(ctx, message)
+	case events.UserDeleted:
+		return ss.handleUserDeleted(ctx, message)
+	}
+
+	return nil
+}
+
+func (ss *SessionSubscriber) handleSessionRevoked(ctx context.Context, message Message) error {
+	var session domain.UserSession
+	err := json.Unmarshal(message.Value, &session)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal session", err)
+	}
+
+	err = ss.notifier.CloseSession(ctx, session.Id)
+	if err != nil {
+		return domain.ServerError("failed to close session connections", err)
+	}
+
+	return nil
+}
+
+func (ss *SessionSubscriber) handleUserDeleted(ctx context.Context, message Message) error {
+	var user domain.User
+	err := json.Unmarshal(message.Value, &user)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal user", err)
+	}
+
+	err = ss.notifier.CloseUser(ctx, user.Id)
+	if err != nil {
+		return domain.ServerError("failed to close user connections", err)
+	}
+
+	return nil
+}
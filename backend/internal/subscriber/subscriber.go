@@ -3,15 +3,20 @@ package subscriber
 import (
 	"context"
 	"log/slog"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/gabrielnakaema/project-chat/internal/config"
 	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 type Subscriber struct {
 	consumer sarama.ConsumerGroup
+	groupId  string
 }
 
 func NewSubscriber(config *config.Config, groupId string) (*Subscriber, error) {
@@ -24,6 +29,7 @@ func NewSubscriber(config *config.Config, groupId string) (*Subscriber, error) {
 
 	return &Subscriber{
 		consumer: consumer,
+		groupId:  groupId,
 	}, nil
 }
 
@@ -45,7 +51,7 @@ func (s *Subscriber) Subscribe(ctx context.Context, topic []events.Topic, handle
 
 	go func() {
 		for {
-			err := s.consumer.Consume(ctx, topics, &consumerGroupHandler{handler: handler})
+			err := s.consumer.Consume(ctx, topics, &consumerGroupHandler{handler: handler, groupId: s.groupId})
 			if err != nil {
 				logger.Error("error consuming topic", "error", err.Error())
 			}
@@ -55,8 +61,20 @@ func (s *Subscriber) Subscribe(ctx context.Context, topic []events.Topic, handle
 	return nil
 }
 
+// consumerLag is how many messages of a partition a group has yet to handle. It is sampled
+// while the partition is claimed, so the lag of a stuck handler keeps growing as messages
+// arrive instead of freezing at the value of the last handled message.
+var consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "subscriber_consumer_lag",
+	Help: "Messages a consumer group is behind the newest message of a partition.",
+}, []string{"group", "topic", "partition"})
+
+// lagSampleInterval is how often the lag of a claimed partition is sampled.
+var lagSampleInterval = 15 * time.Second
+
 type consumerGroupHandler struct {
 	handler MessageHandler
+	groupId string
 }
 
 func (h *consumerGroupHandler) Setup(sarama.ConsumerGroupSession) error {
@@ -68,12 +86,25 @@ func (h *consumerGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
 }
 
 func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	// nextOffset is the offset of the next message to handle, it starts negative when the
+	// group has no committed offset until the first message arrives.
+	var nextOffset atomic.Int64
+	nextOffset.Store(claim.InitialOffset())
+
+	stopSampling := h.sampleLag(claim, &nextOffset)
+	defer stopSampling()
+
 	for message := range claim.Messages() {
 		m := Message{
 			Topic:     events.Topic(message.Topic),
 			Key:       message.Key,
 			Value:     message.Value,
 			Timestamp: message.Timestamp,
+			Metadata:  make(map[string]string, len(message.Headers)),
+		}
+
+		for _, header := range message.Headers {
+			m.Metadata[string(header.Key)] = string(header.Value)
 		}
 
 		err := h.handler(session.Context(), m)
@@ -82,7 +113,47 @@ func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 		}
 
 		session.MarkMessage(message, "")
+		nextOffset.Store(message.Offset + 1)
 	}
 
 	return nil
 }
+
+// sampleLag updates the lag of the claimed partition every lagSampleInterval until the
+// returned function is called. The partition may be claimed by another instance next, so
+// stopping removes its lag instead of leaving the last sample behind.
+func (h *consumerGroupHandler) sampleLag(claim sarama.ConsumerGroupClaim, nextOffset *atomic.Int64) func() {
+	labels := prometheus.Labels{
+		"group":     h.groupId,
+		"topic":     claim.Topic(),
+		"partition": strconv.Itoa(int(claim.Partition())),
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(lagSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			if offset := nextOffset.Load(); offset >= 0 {
+				consumerLag.With(labels).Set(float64(max(claim.HighWaterMarkOffset()-offset, 0)))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		consumerLag.Delete(labels)
+	}
+}
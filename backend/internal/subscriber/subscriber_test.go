@@ -0,0 +1,65 @@
+package subscriber
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClaim struct {
+	highWaterMark atomic.Int64
+}
+
+func (f *fakeClaim) Topic() string                            { return "test.topic" }
+func (f *fakeClaim) Partition() int32                         { return 3 }
+func (f *fakeClaim) InitialOffset() int64                     { return 0 }
+func (f *fakeClaim) HighWaterMarkOffset() int64               { return f.highWaterMark.Load() }
+func (f *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return nil }
+
+func TestSampleLag(t *testing.T) {
+	previousInterval := lagSampleInterval
+	lagSampleInterval = 10 * time.Millisecond
+	defer func() { lagSampleInterval = previousInterval }()
+
+	handler := &consumerGroupHandler{groupId: "test-group"}
+	claim := &fakeClaim{}
+	claim.highWaterMark.Store(5)
+
+	var nextOffset atomic.Int64
+	nextOffset.Store(2)
+
+	stopSampling := handler.sampleLag(claim, &nextOffset)
+
+	gauge := consumerLag.WithLabelValues("test-group", "test.topic", "3")
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(gauge) == 3 }, time.Second, 5*time.Millisecond)
+
+	claim.highWaterMark.Store(9)
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(gauge) == 7 }, time.Second, 5*time.Millisecond, "lag grows without new handled messages")
+
+	nextOffset.Store(9)
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(gauge) == 0 }, time.Second, 5*time.Millisecond)
+
+	stopSampling()
+
+	assert.Equal(t, 0, testutil.CollectAndCount(consumerLag), "stopping removes the lag of the partition")
+}
+
+func TestSampleLagWaitsForAnOffset(t *testing.T) {
+	handler := &consumerGroupHandler{groupId: "test-group"}
+	claim := &fakeClaim{}
+	claim.highWaterMark.Store(5)
+
+	var nextOffset atomic.Int64
+	nextOffset.Store(sarama.OffsetNewest)
+
+	stopSampling := handler.sampleLag(claim, &nextOffset)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Equal(t, 0, testutil.CollectAndCount(consumerLag))
+
+	stopSampling()
+}
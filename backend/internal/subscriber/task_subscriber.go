@@ -13,6 +13,7 @@ import (
 type TaskNotifier interface {
 	SendCreatedTask(context.Context, *domain.Task) error
 	SendUpdatedTask(context.Context, *domain.Task) error
+	SendCreatedTaskComment(context.Context, *domain.TaskComment) error
 }
 
 type TaskSubscriber struct {
@@ -33,7 +34,7 @@ func NewTaskSubscriber(config *config.Config, logger *slog.Logger, notifier Task
 		notifier:   notifier,
 	}
 
-	topics := []events.Topic{events.TaskCreated, events.TaskUpdated}
+	topics := []events.Topic{events.TaskCreated, events.TaskUpdated, events.TaskCommentCreated}
 
 	err = subscriber.Subscribe(context.Background(), topics, taskSubscriber.handleTaskEvents, taskSubscriber.logger)
 	if err != nil {
@@ -50,6 +51,8 @@ func (ts *TaskSubscriber) handleTaskEvents(ctx context.Context, message Message)
 		return ts.handleTaskCreated(ctx, message)
 	case events.TaskUpdated:
 		return ts.handleTaskUpdated(ctx, message)
+	case events.TaskCommentCreated:
+		return ts.handleTaskCommentCreated(ctx, message)
 	default:
 		return nil
 
@@ -85,3 +88,18 @@ func (ts *TaskSubscriber) handleTaskUpdated(ctx context.Context, message Message
 
 	return nil
 }
+
+func (ts *TaskSubscriber) handleTaskCommentCreated(ctx context.Context, message Message) error {
+	var comment domain.TaskComment
+	err := json.Unmarshal(message.Value, &comment)
+	if err != nil {
+		return domain.ServerError("failed to unmarshal task comment", err)
+	}
+
+	err = ts.notifier.SendCreatedTaskComment(ctx, &comment)
+	if err != nil {
+		return domain.ServerError("failed to send created task comment to ws server", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+type verificationKey struct {
+	id     string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+type signingKey struct {
+	verificationKey
+	private crypto.PrivateKey
+}
+
+// JWK is the public part of a verification key, as described in RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the keys other services can use to validate our tokens. It is empty when
+// signing with a shared secret, which must never be published.
+func (tp *TokenProvider) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(tp.verificationKeys))}
+
+	for _, key := range tp.verificationKeys {
+		jwk := publicJWK(key.public)
+		jwk.Use = "sig"
+		jwk.Alg = key.method.Alg()
+		jwk.Kid = key.id
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func loadSigningKey(path string) (*signingKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt signing key %s: unexpected pem block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt signing key %s: %w", path, err)
+	}
+
+	var public crypto.PublicKey
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		public = k.Public()
+	case ed25519.PrivateKey:
+		public = k.Public()
+	default:
+		return nil, fmt.Errorf("jwt signing key %s: unsupported key type %T", path, private)
+	}
+
+	key, err := newVerificationKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("jwt signing key %s: %w", path, err)
+	}
+
+	return &signingKey{verificationKey: *key, private: private}, nil
+}
+
+func loadVerificationKey(path string) (*verificationKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("jwt verification key %s: unexpected pem block %q", path, block.Type)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwt verification key %s: %w", path, err)
+	}
+
+	key, err := newVerificationKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("jwt verification key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s does not contain a pem encoded key", path)
+	}
+
+	return block, nil
+}
+
+func newVerificationKey(public crypto.PublicKey) (*verificationKey, error) {
+	var method jwt.SigningMethod
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key must have at least %d bits", minRSAKeyBits)
+		}
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only rsa and ed25519 keys are supported")
+	}
+
+	id, err := thumbprint(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &verificationKey{id: id, method: method, public: public}, nil
+}
+
+// thumbprint derives the key id from the key itself (RFC 7638), so the same key always
+// gets the same kid no matter which instance loaded it.
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk := publicJWK(public)
+
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func publicJWK(public crypto.PublicKey) JWK {
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(k),
+		}
+	}
+
+	return JWK{}
+}
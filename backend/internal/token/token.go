@@ -1,6 +1,8 @@
 package token
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/config"
@@ -8,13 +10,53 @@ import (
 )
 
 type TokenProvider struct {
-	config *config.Config
+	method jwt.SigningMethod
+
+	// signingKey is the HMAC secret or the private key new tokens are signed with.
+	signingKey any
+	keyId      string
+
+	// verificationKeys holds every public key accepted by Verify, indexed by kid,
+	// so tokens signed with a previous key stay valid while it is being rotated out.
+	verificationKeys map[string]*verificationKey
 }
 
-func NewTokenProvider(config *config.Config) *TokenProvider {
-	return &TokenProvider{
-		config: config,
+// NewTokenProvider signs with the private key in JwtSigningKeyFile when set, and falls
+// back to HS256 with JwtSecret otherwise.
+func NewTokenProvider(config *config.Config) (*TokenProvider, error) {
+	if config.JwtSigningKeyFile == "" {
+		if config.JwtSecret == "" {
+			return nil, errors.New("jwt secret is empty")
+		}
+
+		return &TokenProvider{
+			method:     jwt.SigningMethodHS256,
+			signingKey: []byte(config.JwtSecret),
+		}, nil
 	}
+
+	signingKey, err := loadSigningKey(config.JwtSigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := &TokenProvider{
+		method:           signingKey.method,
+		signingKey:       signingKey.private,
+		keyId:            signingKey.id,
+		verificationKeys: map[string]*verificationKey{signingKey.id: &signingKey.verificationKey},
+	}
+
+	for _, path := range config.JwtVerificationKeyFiles {
+		key, err := loadVerificationKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		tp.verificationKeys[key.id] = key
+	}
+
+	return tp, nil
 }
 
 func (tp *TokenProvider) Generate(sub string, exp time.Time, claims map[string]string) (string, error) {
@@ -31,11 +73,12 @@ func (tp *TokenProvider) Generate(sub string, exp time.Time, claims map[string]s
 		}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
-
-	secret := []byte(tp.config.JwtSecret)
+	token := jwt.NewWithClaims(tp.method, mapClaims)
+	if tp.keyId != "" {
+		token.Header["kid"] = tp.keyId
+	}
 
-	tokenString, err := token.SignedString(secret)
+	tokenString, err := token.SignedString(tp.signingKey)
 	if err != nil {
 		return "", err
 	}
@@ -44,13 +87,45 @@ func (tp *TokenProvider) Generate(sub string, exp time.Time, claims map[string]s
 }
 
 func (tp *TokenProvider) Verify(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (any, error) {
-		return []byte(tp.config.JwtSecret), nil
-	})
-
+	token, err := jwt.Parse(tokenString, tp.keyFunc, jwt.WithValidMethods(tp.validMethods()))
 	if err != nil {
 		return nil, err
 	}
 
 	return token, nil
 }
+
+func (tp *TokenProvider) keyFunc(t *jwt.Token) (any, error) {
+	if tp.verificationKeys == nil {
+		return tp.signingKey, nil
+	}
+
+	kid, ok := t.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("token has no key id")
+	}
+
+	key, ok := tp.verificationKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", t.Method.Alg(), kid)
+	}
+
+	return key.public, nil
+}
+
+func (tp *TokenProvider) validMethods() []string {
+	if tp.verificationKeys == nil {
+		return []string{tp.method.Alg()}
+	}
+
+	methods := make([]string, 0, len(tp.verificationKeys))
+	for _, key := range tp.verificationKeys {
+		methods = append(methods, key.method.Alg())
+	}
+
+	return methods
+}
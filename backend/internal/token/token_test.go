@@ -1,12 +1,22 @@
 package token
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/config"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -19,7 +29,8 @@ func TestTokenProvider_Generate(t *testing.T) {
 	cfg := &config.Config{
 		JwtSecret: testJwtSecret,
 	}
-	provider := NewTokenProvider(cfg)
+	provider, err := NewTokenProvider(cfg)
+	require.NoError(t, err)
 
 	tests := []struct {
 		name      string
@@ -101,11 +112,13 @@ func TestTokenProvider_Verify(t *testing.T) {
 	cfg := &config.Config{
 		JwtSecret: testJwtSecret,
 	}
-	provider := NewTokenProvider(cfg)
+	provider, err := NewTokenProvider(cfg)
+	require.NoError(t, err)
 
 	validToken, _ := provider.Generate("user-123", time.Now().Add(time.Hour), map[string]string{"role": "user"})
 
-	wrongSecretProvider := NewTokenProvider(&config.Config{JwtSecret: wrongSecret})
+	wrongSecretProvider, err := NewTokenProvider(&config.Config{JwtSecret: wrongSecret})
+	require.NoError(t, err)
 	wrongSecretToken, _ := wrongSecretProvider.Generate("user-456", time.Now().Add(time.Hour), nil)
 
 	expiredToken, _ := provider.Generate("user-expired", time.Now().Add(-time.Hour), nil)
@@ -175,7 +188,8 @@ func TestTokenProvider_RoundTrip(t *testing.T) {
 	cfg := &config.Config{
 		JwtSecret: testJwtSecret,
 	}
-	provider := NewTokenProvider(cfg)
+	provider, err := NewTokenProvider(cfg)
+	require.NoError(t, err)
 
 	testCases := []struct {
 		sub    string
@@ -223,3 +237,208 @@ func TestTokenProvider_RoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func writePrivateKey(t *testing.T, key crypto.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return writePEM(t, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return writePEM(t, "PUBLIC KEY", der)
+}
+
+func TestTokenProvider_AsymmetricKeys(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		key         crypto.PrivateKey
+		expectedAlg string
+	}{
+		{
+			name:        "ed25519 key signs with EdDSA",
+			key:         edKey,
+			expectedAlg: "EdDSA",
+		},
+		{
+			name:        "rsa key signs with RS256",
+			key:         rsaKey,
+			expectedAlg: "RS256",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewTokenProvider(&config.Config{JwtSigningKeyFile: writePrivateKey(t, tt.key)})
+			require.NoError(t, err)
+
+			tokenString, err := provider.Generate("user-123", time.Now().Add(time.Hour), map[string]string{"sid": "session-1"})
+			require.NoError(t, err)
+
+			token, err := provider.Verify(tokenString)
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, tt.expectedAlg, token.Method.Alg())
+			assert.Equal(t, provider.keyId, token.Header["kid"])
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			assert.True(t, ok)
+			assert.Equal(t, "user-123", claims["sub"])
+			assert.Equal(t, "session-1", claims["sid"])
+		})
+	}
+}
+
+func TestTokenProvider_KeyRotation(t *testing.T) {
+	oldPublic, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	oldProvider, err := NewTokenProvider(&config.Config{JwtSigningKeyFile: writePrivateKey(t, oldKey)})
+	require.NoError(t, err)
+
+	oldToken, err := oldProvider.Generate("user-123", time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	rotatedProvider, err := NewTokenProvider(&config.Config{
+		JwtSigningKeyFile:       writePrivateKey(t, newKey),
+		JwtVerificationKeyFiles: []string{writePublicKey(t, oldPublic)},
+	})
+	require.NoError(t, err)
+
+	newOnlyProvider, err := NewTokenProvider(&config.Config{JwtSigningKeyFile: writePrivateKey(t, newKey)})
+	require.NoError(t, err)
+
+	newToken, err := rotatedProvider.Generate("user-456", time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	_, err = rotatedProvider.Verify(oldToken)
+	assert.NoError(t, err, "tokens signed with a verification key are still accepted")
+
+	_, err = rotatedProvider.Verify(newToken)
+	assert.NoError(t, err)
+
+	_, err = newOnlyProvider.Verify(oldToken)
+	assert.Error(t, err, "tokens signed with a removed key are rejected")
+
+	_, err = oldProvider.Verify(newToken)
+	assert.Error(t, err)
+}
+
+func TestTokenProvider_VerifyRejectsUnexpectedSigningMethod(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	provider, err := NewTokenProvider(&config.Config{JwtSigningKeyFile: writePrivateKey(t, private)})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "user-123", "exp": time.Now().Add(time.Hour).Unix()}
+
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacToken.Header["kid"] = provider.keyId
+	hmacTokenString, err := hmacToken.SignedString([]byte(public))
+	require.NoError(t, err)
+
+	noneToken := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	noneToken.Header["kid"] = provider.keyId
+	noneTokenString, err := noneToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	secretProvider, err := NewTokenProvider(&config.Config{JwtSecret: testJwtSecret})
+	require.NoError(t, err)
+
+	secretToken, err := secretProvider.Generate("user-123", time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	asymmetricToken, err := provider.Generate("user-123", time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		provider *TokenProvider
+		token    string
+	}{
+		{name: "hmac token signed with the public key", provider: provider, token: hmacTokenString},
+		{name: "unsigned token", provider: provider, token: noneTokenString},
+		{name: "shared secret token against key provider", provider: provider, token: secretToken},
+		{name: "key signed token against shared secret provider", provider: secretProvider, token: asymmetricToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.provider.Verify(tt.token)
+			assert.Error(t, err)
+			assert.Nil(t, token)
+		})
+	}
+}
+
+func TestTokenProvider_JWKS(t *testing.T) {
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider, err := NewTokenProvider(&config.Config{
+		JwtSigningKeyFile:       writePrivateKey(t, edKey),
+		JwtVerificationKeyFiles: []string{writePublicKey(t, &rsaKey.PublicKey)},
+	})
+	require.NoError(t, err)
+
+	set := provider.JWKS()
+	assert.Len(t, set.Keys, 2)
+
+	keys := map[string]JWK{}
+	for _, key := range set.Keys {
+		assert.Equal(t, "sig", key.Use)
+		assert.NotEmpty(t, key.Kid)
+		keys[key.Kty] = key
+	}
+
+	assert.Equal(t, provider.keyId, keys["OKP"].Kid)
+	assert.Equal(t, "EdDSA", keys["OKP"].Alg)
+	assert.Equal(t, "Ed25519", keys["OKP"].Crv)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(edPublic), keys["OKP"].X)
+
+	assert.Equal(t, "RS256", keys["RSA"].Alg)
+	assert.Equal(t, "AQAB", keys["RSA"].E)
+	assert.NotEmpty(t, keys["RSA"].N)
+
+	secretProvider, err := NewTokenProvider(&config.Config{JwtSecret: testJwtSecret})
+	require.NoError(t, err)
+	assert.Empty(t, secretProvider.JWKS().Keys, "shared secrets are never published")
+}
+
+func TestNewTokenProvider_RejectsWeakRSAKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	_, err = NewTokenProvider(&config.Config{JwtSigningKeyFile: writePrivateKey(t, rsaKey)})
+	assert.Error(t, err)
+}
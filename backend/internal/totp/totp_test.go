@@ -0,0 +1,92 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     int64
+		expected string
+	}{
+		{"59 seconds", 59, "287082"},
+		{"1111111109 seconds", 1111111109, "081804"},
+		{"1111111111 seconds", 1111111111, "050471"},
+		{"1234567890 seconds", 1234567890, "005924"},
+		{"2000000000 seconds", 2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tt.time, 0)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	currentStep := Step(now)
+
+	previous, _ := Code(rfcSecret, currentStep-1)
+	next, _ := Code(rfcSecret, currentStep+1)
+	old, _ := Code(rfcSecret, currentStep-2)
+
+	tests := []struct {
+		name         string
+		code         string
+		expectedStep int64
+		expectedOk   bool
+	}{
+		{"current step", "005924", currentStep, true},
+		{"previous step", previous, currentStep - 1, true},
+		{"next step", next, currentStep + 1, true},
+		{"step outside skew", old, 0, false},
+		{"wrong code", "123456", 0, false},
+		{"wrong length", "12345", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now)
+
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedStep, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	_, err = Code(secret, 1)
+	assert.NoError(t, err)
+
+	other, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("SECRET", "Project Chat", "john@example.com")
+
+	parsed, err := url.Parse(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/Project Chat:john@example.com", parsed.Path)
+	assert.Equal(t, "SECRET", parsed.Query().Get("secret"))
+	assert.Equal(t, "Project Chat", parsed.Query().Get("issuer"))
+}
@@ -1,14 +1,18 @@
 package utils
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CursorPaginated[T any] struct {
-	Data    []T  `json:"data"`
-	HasNext bool `json:"has_next"`
+	Data       []T    `json:"data"`
+	HasNext    bool   `json:"has_next"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type PaginationBeforeParams struct {
@@ -16,3 +20,45 @@ type PaginationBeforeParams struct {
 	Id     uuid.UUID `json:"id"`
 	Limit  int32     `json:"limit"`
 }
+
+// PaginationAfterParams points at the last item of the previous page, After holds
+// the value of the field the list is sorted by and is zero for the first page.
+type PaginationAfterParams struct {
+	After time.Time `json:"after"`
+	Id    uuid.UUID `json:"id"`
+	Limit int32     `json:"limit"`
+}
+
+// EncodeAfterCursor builds the opaque cursor of the next page from the sort value and id
+// of the last item of the current page.
+func EncodeAfterCursor(after time.Time, id uuid.UUID) string {
+	value := after.UTC().Format(time.RFC3339Nano) + "," + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// DecodeAfterCursor reads a cursor built by EncodeAfterCursor back into pagination params.
+func DecodeAfterCursor(cursor string, limit int32) (PaginationAfterParams, error) {
+	params := PaginationAfterParams{Limit: limit}
+
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return params, errors.New("invalid cursor")
+	}
+
+	after, id, found := strings.Cut(string(value), ",")
+	if !found {
+		return params, errors.New("invalid cursor")
+	}
+
+	params.After, err = time.Parse(time.RFC3339Nano, after)
+	if err != nil {
+		return params, errors.New("invalid cursor")
+	}
+
+	params.Id, err = uuid.Parse(id)
+	if err != nil {
+		return params, errors.New("invalid cursor")
+	}
+
+	return params, nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAfterCursor(t *testing.T) {
+	after := time.Date(2025, 10, 18, 12, 30, 15, 123456000, time.UTC)
+	id := uuid.New()
+
+	cursor := EncodeAfterCursor(after, id)
+
+	params, err := DecodeAfterCursor(cursor, 50)
+	require.NoError(t, err)
+	assert.True(t, after.Equal(params.After))
+	assert.Equal(t, id, params.Id)
+	assert.Equal(t, int32(50), params.Limit)
+
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	invalid := []string{
+		"not base64!",
+		encode("missing separator"),
+		encode("yesterday," + id.String()),
+		encode(after.Format(time.RFC3339Nano) + ",not-an-id"),
+	}
+
+	for _, cursor := range invalid {
+		_, err := DecodeAfterCursor(cursor, 50)
+		assert.Error(t, err, cursor)
+	}
+}
@@ -41,3 +41,16 @@ func GetQueryFloat(r *http.Request, key string, defaultValue float64) float64 {
 	}
 	return parsedValue
 }
+
+func GetQueryBool(r *http.Request, key string, defaultValue bool) bool {
+	query := r.URL.Query()
+	value := query.Get(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsedValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return parsedValue
+}
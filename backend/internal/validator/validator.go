@@ -1,6 +1,10 @@
 package validator
 
-import "net/mail"
+import (
+	"net/mail"
+	"net/url"
+	"unicode/utf8"
+)
 
 type Validator struct {
 	Errors map[string][]string
@@ -48,3 +52,16 @@ func ValidEmail(value string) bool {
 func MinLength(value string, min int) bool {
 	return len(value) >= min
 }
+
+func MaxLength(value string, max int) bool {
+	return utf8.RuneCountInString(value) <= max
+}
+
+// ValidURL reports whether the value is an absolute http or https URL.
+func ValidURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -9,13 +9,13 @@ import (
 
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
 	"github.com/google/uuid"
 )
 
 func (ws *Server) Handler(w http.ResponseWriter, r *http.Request) {
 	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		OriginPatterns: []string{"*"},
+		OriginPatterns: ws.originPatterns,
 	})
 	if err != nil {
 		ws.logger.Error("failed to accept websocket", "error", err.Error())
@@ -23,48 +23,49 @@ func (ws *Server) Handler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer c.Close(websocket.StatusNormalClosure, "close")
 
-	authorization := r.URL.Query().Get("jwt")
-	if authorization == "" {
-		WriteErrorAndClose(r.Context(), c, "jwt is required")
+	plainTicket := r.URL.Query().Get("ticket")
+	if plainTicket == "" {
+		WriteErrorAndClose(r.Context(), c, "ticket is required")
 		return
 	}
 
-	token, err := ws.tokenProvider.Verify(authorization)
-	if err != nil {
-		WriteErrorAndClose(r.Context(), c, "invalid token")
+	ticket, ok := ws.tickets.redeem(plainTicket)
+	if !ok {
+		WriteErrorAndClose(r.Context(), c, "invalid ticket")
 		return
 	}
 
-	sub := token.Claims.(jwt.MapClaims)["sub"].(string)
-	userId, err := uuid.Parse(sub)
-	if err != nil {
-		WriteErrorAndClose(r.Context(), c, "invalid user_id")
-		return
-	}
+	userId := ticket.userId
+	connectionId := uuid.New()
 
 	writerChannel := make(chan interface{})
 	readerChannel := make(chan interface{})
-	expiresAt := token.Claims.(jwt.MapClaims)["exp"].(float64)
 
-	roomUser := &WsUser{
+	ctx, cancel := context.WithCancel(context.Background())
+
+	connection := &WsUser{
 		id:             userId,
-		tokenExpiresAt: time.Unix(int64(expiresAt), 0),
+		connectionId:   connectionId,
+		sessionId:      ticket.sessionId,
+		tokenExpiresAt: ticket.tokenExpiresAt,
 		writer:         writerChannel,
 		reader:         readerChannel,
 		rooms:          make(map[uuid.UUID]bool),
 		lastPong:       time.Now(),
 		awaitingPong:   false,
+		close: func(code websocket.StatusCode, reason string) {
+			c.Close(code, reason)
+			cancel()
+		},
 	}
 
 	ws.mutex.Lock()
-	ws.users[userId] = roomUser
+	ws.connections[connectionId] = connection
 	ws.mutex.Unlock()
 
-	ctx, cancel := context.WithCancel(context.Background())
-
 	cleanUp := func() {
 		c.Close(websocket.StatusNormalClosure, "close")
-		ws.disconnectUser(userId)
+		ws.disconnect(connectionId)
 		cancel()
 	}
 
@@ -73,19 +74,21 @@ func (ws *Server) Handler(w http.ResponseWriter, r *http.Request) {
 	var wg sync.WaitGroup
 
 	wg.Add(1)
-	go ws.writerLoop(ctx, c, userId, writerChannel, &wg, cancel)
+	go ws.writerLoop(ctx, c, connectionId, userId, writerChannel, &wg, cancel)
 
 	wg.Add(1)
-	go ws.readerLoop(ctx, c, userId, writerChannel, &wg, cancel)
+	go ws.readerLoop(ctx, c, connectionId, userId, writerChannel, &wg, cancel)
 
 	wg.Wait()
 }
 
-func (ws *Server) writerLoop(ctx context.Context, c *websocket.Conn, userId uuid.UUID, writerChannel chan interface{}, wg *sync.WaitGroup, cancel context.CancelFunc) {
+func (ws *Server) writerLoop(ctx context.Context, c *websocket.Conn, connectionId uuid.UUID, userId uuid.UUID, writerChannel chan interface{}, wg *sync.WaitGroup, cancel context.CancelFunc) {
 	pingTicker := time.NewTicker(pingInterval)
+	tokenTicker := time.NewTicker(tokenCheckInterval)
 
 	defer func() {
 		pingTicker.Stop()
+		tokenTicker.Stop()
 		wg.Done()
 		cancel()
 	}()
@@ -104,18 +107,18 @@ func (ws *Server) writerLoop(ctx context.Context, c *websocket.Conn, userId uuid
 			}
 		case <-pingTicker.C:
 			ws.mutex.Lock()
-			user := ws.users[userId]
-			if user == nil {
+			connection := ws.connections[connectionId]
+			if connection == nil {
 				ws.mutex.Unlock()
 				return
 			}
 
-			if user.awaitingPong && time.Since(user.lastPong) > pongTimeout {
+			if connection.awaitingPong && time.Since(connection.lastPong) > pongTimeout {
 				ws.mutex.Unlock()
 				return
 			}
 
-			user.awaitingPong = true
+			connection.awaitingPong = true
 			ws.mutex.Unlock()
 
 			pingMessage := WebsocketMessage{
@@ -127,18 +130,54 @@ func (ws *Server) writerLoop(ctx context.Context, c *websocket.Conn, userId uuid
 				ws.logger.Error("failed to send ping", "error", err.Error(), "user_id", userId)
 				return
 			}
+		case <-tokenTicker.C:
+			ws.mutex.Lock()
+			connection := ws.connections[connectionId]
+			if connection == nil {
+				ws.mutex.Unlock()
+				return
+			}
+
+			expiresAt := connection.tokenExpiresAt
+			notify := !connection.expiryNotified && time.Until(expiresAt) <= tokenExpiryNotice
+			if notify {
+				connection.expiryNotified = true
+			}
+			ws.mutex.Unlock()
+
+			if !time.Now().Before(expiresAt) {
+				c.Close(websocket.StatusPolicyViolation, "token expired")
+				return
+			}
+
+			if notify {
+				expiringMessage := WebsocketMessage{
+					Type: WebsocketMessageTypeTokenExpiring,
+					Data: TokenExpiryData{ExpiresAt: expiresAt},
+				}
+				err := WriteWebsocketMessage(ctx, c, expiringMessage)
+				if err != nil {
+					ws.logger.Error("failed to send token expiring", "error", err.Error(), "user_id", userId)
+					return
+				}
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func (ws *Server) readerLoop(ctx context.Context, c *websocket.Conn, userId uuid.UUID, writerChannel chan interface{}, wg *sync.WaitGroup, cancel context.CancelFunc) {
+func (ws *Server) readerLoop(ctx context.Context, c *websocket.Conn, connectionId uuid.UUID, userId uuid.UUID, writerChannel chan interface{}, wg *sync.WaitGroup, cancel context.CancelFunc) {
 	defer func() {
 		cancel()
 		wg.Done()
 	}()
 
+	var bucket *ratelimit.Bucket
+	if ws.messageLimit.Enabled() {
+		bucket = ratelimit.NewBucket(ws.messageLimit, time.Now())
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -158,15 +197,32 @@ func (ws *Server) readerLoop(ctx context.Context, c *websocket.Conn, userId uuid
 
 			if message.Type == WebsocketMessageTypePong {
 				ws.mutex.Lock()
-				user := ws.users[userId]
-				if user != nil {
-					user.lastPong = time.Now()
-					user.awaitingPong = false
+				connection := ws.connections[connectionId]
+				if connection != nil {
+					connection.lastPong = time.Now()
+					connection.awaitingPong = false
 				}
 				ws.mutex.Unlock()
 				continue
 			}
 
+			// Pongs answer our own pings so they are never limited, dropping them would get
+			// the connection closed.
+			if bucket != nil {
+				result := bucket.Take(time.Now())
+				if !result.Allowed {
+					errorMessage := WebsocketMessage{
+						Type: WebsocketMessageTypeError,
+						Data: ErrorMessage{Message: "rate limit exceeded"},
+					}
+					select {
+					case writerChannel <- errorMessage:
+					default:
+					}
+					continue
+				}
+			}
+
 			if message.Type == WebsocketMessageTypePing {
 				pongMessage := WebsocketMessage{
 					Type: WebsocketMessageTypePong,
@@ -179,7 +235,7 @@ func (ws *Server) readerLoop(ctx context.Context, c *websocket.Conn, userId uuid
 				continue
 			}
 
-			ws.handleMessage(ctx, userId, message, writerChannel)
+			ws.handleMessage(ctx, connectionId, userId, message, writerChannel)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -3,20 +3,25 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-func (ws *Server) handleMessage(ctx context.Context, userId uuid.UUID, message WebsocketMessage, writerChannel chan interface{}) {
+func (ws *Server) handleMessage(ctx context.Context, connectionId uuid.UUID, userId uuid.UUID, message WebsocketMessage, writerChannel chan interface{}) {
 	switch message.Type {
 	case WebsocketMessageTypeConnectUserToRoom:
-		ws.handleConnectUserToRoom(ctx, userId, message)
+		ws.handleConnectUserToRoom(ctx, connectionId, userId, message)
 	case WebsocketMessageTypeDisconnectUserFromRoom:
-		ws.handleDisconnectUserFromRoom(ctx, userId, message)
+		ws.handleDisconnectUserFromRoom(ctx, connectionId, userId, message)
+	case WebsocketMessageTypeAuthenticate:
+		ws.handleAuthenticate(ctx, connectionId, userId, message)
 	}
 }
 
-func (ws *Server) handleConnectUserToRoom(ctx context.Context, userId uuid.UUID, message WebsocketMessage) {
+func (ws *Server) handleConnectUserToRoom(ctx context.Context, connectionId uuid.UUID, userId uuid.UUID, message WebsocketMessage) {
 	bytes, err := json.Marshal(message.Data)
 	if err != nil {
 		ws.logger.Error("failed to marshal message", "error", err.Error(), "user_id", userId)
@@ -30,7 +35,7 @@ func (ws *Server) handleConnectUserToRoom(ctx context.Context, userId uuid.UUID,
 		return
 	}
 
-	err = ws.connectUserToRoom(userId, data.RoomId, WsRoomType(data.Type))
+	err = ws.connectUserToRoom(connectionId, userId, data.RoomId, WsRoomType(data.Type))
 	if err != nil {
 		return
 	}
@@ -45,7 +50,7 @@ func (ws *Server) handleConnectUserToRoom(ctx context.Context, userId uuid.UUID,
 	})
 }
 
-func (ws *Server) handleDisconnectUserFromRoom(ctx context.Context, userId uuid.UUID, message WebsocketMessage) {
+func (ws *Server) handleDisconnectUserFromRoom(ctx context.Context, connectionId uuid.UUID, userId uuid.UUID, message WebsocketMessage) {
 	bytes, err := json.Marshal(message.Data)
 	if err != nil {
 		ws.logger.Error("failed to marshal message", "error", err.Error(), "user_id", userId)
@@ -59,7 +64,11 @@ func (ws *Server) handleDisconnectUserFromRoom(ctx context.Context, userId uuid.
 		return
 	}
 
-	ws.disconnectUserFromRoom(userId, data.RoomId)
+	// the user is still in the room while another of their connections is
+	if ws.disconnectConnectionFromRoom(connectionId, data.RoomId) {
+		return
+	}
+
 	ws.sendMessageToRoom(ctx, data.RoomId, WebsocketMessage{
 		Type:   WebsocketMessageTypeUserDisconnected,
 		RoomId: data.RoomId,
@@ -68,4 +77,82 @@ func (ws *Server) handleDisconnectUserFromRoom(ctx context.Context, userId uuid.
 			RoomId: data.RoomId,
 		},
 	})
-}
\ No newline at end of file
+}
+
+// handleAuthenticate extends the connection with a fresh access token, the token must belong
+// to the same user and session the connection was opened for.
+func (ws *Server) handleAuthenticate(ctx context.Context, connectionId uuid.UUID, userId uuid.UUID, message WebsocketMessage) {
+	bytes, err := json.Marshal(message.Data)
+	if err != nil {
+		ws.logger.Error("failed to marshal message", "error", err.Error(), "user_id", userId)
+		return
+	}
+
+	var data AuthenticateData
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		ws.logger.Error("failed to unmarshal message", "error", err.Error(), "user_id", userId)
+		return
+	}
+
+	expiresAt, err := ws.verifyToken(ctx, connectionId, userId, data.Token)
+	if err != nil {
+		ws.sendMessageToConnection(ctx, connectionId, WebsocketMessage{
+			Type: WebsocketMessageTypeError,
+			Data: ErrorMessage{Message: "invalid token"},
+		})
+		return
+	}
+
+	ws.sendMessageToConnection(ctx, connectionId, WebsocketMessage{
+		Type: WebsocketMessageTypeAuthenticated,
+		Data: TokenExpiryData{ExpiresAt: expiresAt},
+	})
+}
+
+func (ws *Server) verifyToken(ctx context.Context, connectionId uuid.UUID, userId uuid.UUID, plain string) (time.Time, error) {
+	token, err := ws.tokenProvider.Verify(plain)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, errors.New("unexpected claims")
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil || sub != userId.String() {
+		return time.Time{}, errors.New("token belongs to another user")
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil || !exp.Time.After(time.Now()) {
+		return time.Time{}, errors.New("token expired")
+	}
+
+	sid, _ := claims["sid"].(string)
+	sessionId, _ := uuid.Parse(sid)
+
+	err = ws.sessionChecker.CheckSession(ctx, userId, sessionId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	connection, ok := ws.connections[connectionId]
+	if !ok {
+		return time.Time{}, errors.New("connection is closed")
+	}
+
+	if connection.sessionId != uuid.Nil && sessionId != connection.sessionId {
+		return time.Time{}, errors.New("token belongs to another session")
+	}
+
+	connection.tokenExpiresAt = exp.Time
+	connection.expiryNotified = false
+
+	return exp.Time, nil
+}
@@ -2,6 +2,7 @@ package ws
 
 import (
 	"context"
+	"time"
 
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
@@ -27,6 +28,13 @@ const (
 	WebsocketMessageTypeTaskCreated            WebsocketMessageType = "task_created"
 	WebsocketMessageTypeTaskUpdated            WebsocketMessageType = "task_updated"
 	WebsocketMessageTypeUsersOnline            WebsocketMessageType = "users_online"
+	WebsocketMessageTypeTaskCommentCreated     WebsocketMessageType = "task_comment_created"
+	WebsocketMessageTypeRemovedFromRoom        WebsocketMessageType = "removed_from_room"
+	WebsocketMessageTypeProjectActivity        WebsocketMessageType = "project_activity"
+	WebsocketMessageTypeUserUpdated            WebsocketMessageType = "user_updated"
+	WebsocketMessageTypeAuthenticate           WebsocketMessageType = "authenticate"
+	WebsocketMessageTypeAuthenticated          WebsocketMessageType = "authenticated"
+	WebsocketMessageTypeTokenExpiring          WebsocketMessageType = "token_expiring"
 )
 
 type WebsocketMessage struct {
@@ -79,6 +87,26 @@ type UserDisconnectedData struct {
 	RoomId uuid.UUID `json:"room_id"`
 }
 
+// UserUpdatedData is the member info shown next to messages, tasks and comments, clients
+// replace what they cached for the user with it.
+type UserUpdatedData struct {
+	UserId    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	AvatarUrl string    `json:"avatar_url"`
+}
+
+// AuthenticateData carries a fresh access token, clients send it when they are told their
+// token is expiring so the connection stays open.
+type AuthenticateData struct {
+	Token string `json:"token"`
+}
+
+// TokenExpiryData tells when the connection is closed unless the client authenticates again.
+type TokenExpiryData struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 func MapChatMessage(message *domain.ChatMessage) WebsocketMessage {
 	return WebsocketMessage{
 		Type:   WebsocketMessageTypeMessage,
@@ -102,3 +130,19 @@ func MapTaskUpdated(task *domain.Task) WebsocketMessage {
 		Data:   task,
 	}
 }
+
+func MapTaskCommentCreated(comment *domain.TaskComment) WebsocketMessage {
+	return WebsocketMessage{
+		Type:   WebsocketMessageTypeTaskCommentCreated,
+		RoomId: comment.ProjectId,
+		Data:   comment,
+	}
+}
+
+func MapProjectActivity(activity *domain.ProjectActivity) WebsocketMessage {
+	return WebsocketMessage{
+		Type:   WebsocketMessageTypeProjectActivity,
+		RoomId: activity.ProjectId,
+		Data:   activity,
+	}
+}
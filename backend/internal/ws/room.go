@@ -2,14 +2,23 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/events"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// droppedMessages counts messages not delivered because the writer of the connection was full.
+var droppedMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "websocket_dropped_messages_total",
+	Help: "Websocket messages dropped because the connection could not keep up.",
+}, []string{"target"})
+
 func (ws *Server) sendMessageToRoom(ctx context.Context, roomId uuid.UUID, message WebsocketMessage) error {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
@@ -19,18 +28,19 @@ func (ws *Server) sendMessageToRoom(ctx context.Context, roomId uuid.UUID, messa
 		return nil
 	}
 
-	for userId := range room.users {
-		user, ok := ws.users[userId]
+	for connectionId := range room.connections {
+		connection, ok := ws.connections[connectionId]
 		if !ok {
 			continue
 		}
 
 		select {
-		case user.writer <- message:
+		case connection.writer <- message:
 		case <-ctx.Done():
 			return nil
 		default:
-			ws.logger.Debug("failed to send message", "error", "channel is full", "user_id", user.id, "room_id", roomId)
+			droppedMessages.WithLabelValues("room").Inc()
+			ws.logger.Debug("failed to send message", "error", "channel is full", "user_id", connection.id, "room_id", roomId)
 			return nil
 		}
 	}
@@ -38,55 +48,116 @@ func (ws *Server) sendMessageToRoom(ctx context.Context, roomId uuid.UUID, messa
 	return nil
 }
 
-func (ws *Server) disconnectUserFromRoom(userId uuid.UUID, roomId uuid.UUID) {
+// sendMessageToUser sends the message to every connection the user has open.
+func (ws *Server) sendMessageToUser(ctx context.Context, userId uuid.UUID, message WebsocketMessage) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	for _, connection := range ws.connections {
+		if connection.id == userId {
+			ws.writeToConnection(ctx, connection, message)
+		}
+	}
+}
+
+func (ws *Server) sendMessageToConnection(ctx context.Context, connectionId uuid.UUID, message WebsocketMessage) {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	user, ok := ws.users[userId]
+	connection, ok := ws.connections[connectionId]
 	if !ok {
 		return
 	}
 
-	delete(user.rooms, roomId)
+	ws.writeToConnection(ctx, connection, message)
+}
+
+// writeToConnection must be called with the mutex held.
+func (ws *Server) writeToConnection(ctx context.Context, connection *WsUser, message WebsocketMessage) {
+	select {
+	case connection.writer <- message:
+	case <-ctx.Done():
+	default:
+		droppedMessages.WithLabelValues("user").Inc()
+		ws.logger.Debug("failed to send message", "error", "channel is full", "user_id", connection.id)
+	}
+}
+
+// disconnectUserFromRoom removes every connection of the user from the room.
+func (ws *Server) disconnectUserFromRoom(userId uuid.UUID, roomId uuid.UUID) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	for connectionId, connection := range ws.connections {
+		if connection.id == userId {
+			ws.removeConnectionFromRoom(connectionId, roomId)
+		}
+	}
+}
+
+// disconnectConnectionFromRoom removes the connection from the room and reports whether the
+// user is still in it through another connection.
+func (ws *Server) disconnectConnectionFromRoom(connectionId uuid.UUID, roomId uuid.UUID) bool {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	connection, ok := ws.connections[connectionId]
+	if !ok {
+		return false
+	}
+
+	ws.removeConnectionFromRoom(connectionId, roomId)
+
+	room, ok := ws.rooms[roomId]
+	if !ok {
+		return false
+	}
+
+	for otherId := range room.connections {
+		if other, ok := ws.connections[otherId]; ok && other.id == connection.id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeConnectionFromRoom must be called with the mutex held.
+func (ws *Server) removeConnectionFromRoom(connectionId uuid.UUID, roomId uuid.UUID) {
+	if connection, ok := ws.connections[connectionId]; ok {
+		delete(connection.rooms, roomId)
+	}
 
 	room, ok := ws.rooms[roomId]
 	if !ok {
 		return
 	}
 
-	delete(room.users, userId)
-	if len(room.users) == 0 {
+	delete(room.connections, connectionId)
+	if len(room.connections) == 0 {
 		delete(ws.rooms, roomId)
 	}
 }
 
-func (ws *Server) disconnectUser(userId uuid.UUID) {
+func (ws *Server) disconnect(connectionId uuid.UUID) {
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	user, ok := ws.users[userId]
+	connection, ok := ws.connections[connectionId]
 	if !ok {
 		return
 	}
 
-	for roomId := range user.rooms {
-		room, ok := ws.rooms[roomId]
-		if !ok {
-			continue
-		}
-
-		delete(room.users, userId)
-		if len(room.users) == 0 {
-			delete(ws.rooms, room.id)
-		}
+	for roomId := range connection.rooms {
+		ws.removeConnectionFromRoom(connectionId, roomId)
 	}
 
-	close(user.reader)
-	close(user.writer)
-	delete(ws.users, userId)
+	close(connection.reader)
+	close(connection.writer)
+	delete(ws.connections, connectionId)
 }
 
-func (ws *Server) connectUserToRoom(userId uuid.UUID, roomId uuid.UUID, roomType WsRoomType) error {
+func (ws *Server) connectUserToRoom(connectionId uuid.UUID, userId uuid.UUID, roomId uuid.UUID, roomType WsRoomType) error {
 	if roomType == WsRoomTypeChat {
 		_, err := ws.chatService.GetById(context.Background(), roomId, userId)
 		if err != nil {
@@ -105,31 +176,37 @@ func (ws *Server) connectUserToRoom(userId uuid.UUID, roomId uuid.UUID, roomType
 	}
 
 	if roomType == WsRoomTypeProject {
-		_, err := ws.projectService.GetById(context.Background(), roomId, userId)
+		project, err := ws.projectService.GetById(context.Background(), roomId, userId)
 		if err != nil {
 			return err
 		}
+
+		if project.IsArchived() {
+			return domain.BusinessValidationError("project is archived")
+		}
 	}
 
 	ws.mutex.Lock()
 	defer ws.mutex.Unlock()
 
-	room, ok := ws.rooms[roomId]
-	if ok {
-		room.users[userId] = true
-		ws.users[userId].rooms[roomId] = true
-		return nil
+	connection, ok := ws.connections[connectionId]
+	if !ok {
+		return errors.New("connection is closed")
 	}
 
-	room = &WsRoom{
-		id:       roomId,
-		users:    make(map[uuid.UUID]bool),
-		mutex:    sync.Mutex{},
-		roomType: roomType,
+	room, ok := ws.rooms[roomId]
+	if !ok {
+		room = &WsRoom{
+			id:          roomId,
+			connections: make(map[uuid.UUID]bool),
+			mutex:       sync.Mutex{},
+			roomType:    roomType,
+		}
+		ws.rooms[roomId] = room
 	}
-	ws.rooms[roomId] = room
-	room.users[userId] = true
-	ws.users[userId].rooms[roomId] = true
+
+	room.connections[connectionId] = true
+	connection.rooms[roomId] = true
 
 	return nil
-}
\ No newline at end of file
+}
@@ -6,20 +6,30 @@ import (
 	"sync"
 	"time"
 
+	"github.com/coder/websocket"
 	"github.com/gabrielnakaema/project-chat/internal/domain"
 	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/metrics"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// WsUser is a connection of a user, a user can have many of them open at once, one per tab or
+// device, and each one keeps the session and token expiry it was opened with.
 type WsUser struct {
 	id             uuid.UUID
+	connectionId   uuid.UUID
+	sessionId      uuid.UUID
 	tokenExpiresAt time.Time
 	writer         chan any
 	reader         chan any
 	rooms          map[uuid.UUID]bool
 	lastPong       time.Time
 	awaitingPong   bool
+	expiryNotified bool
+	close          func(code websocket.StatusCode, reason string)
 }
 
 type WsRoomType string
@@ -35,17 +45,29 @@ const (
 	usersOnlineInterval = 10 * time.Second
 )
 
+// Connections are closed once the access token they were opened with expires, clients are
+// told tokenExpiryNotice before that so they can authenticate again with a fresh token.
+const tokenExpiryNotice = time.Minute
+
+// tokenCheckInterval is how often the expiry of the token of a connection is checked, it is a
+// variable so tests do not wait for it.
+var tokenCheckInterval = 5 * time.Second
+
 type WsRoom struct {
-	id       uuid.UUID
-	users    map[uuid.UUID]bool
-	mutex    sync.Mutex
-	roomType WsRoomType
+	id          uuid.UUID
+	connections map[uuid.UUID]bool
+	mutex       sync.Mutex
+	roomType    WsRoomType
 }
 
 type tokenProvider interface {
 	Verify(token string) (*jwt.Token, error)
 }
 
+type sessionChecker interface {
+	CheckSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error
+}
+
 type chatService interface {
 	GetById(ctx context.Context, id uuid.UUID, userId uuid.UUID) (*domain.Chat, error)
 }
@@ -60,46 +82,52 @@ type publisher interface {
 
 type Server struct {
 	rooms          map[uuid.UUID]*WsRoom
-	users          map[uuid.UUID]*WsUser
+	connections    map[uuid.UUID]*WsUser
 	logger         *slog.Logger
 	mutex          sync.Mutex
 	tokenProvider  tokenProvider
+	sessionChecker sessionChecker
 	chatService    chatService
 	projectService projectService
 	publisher      publisher
+	tickets        *ticketStore
+	originPatterns []string
+	messageLimit   ratelimit.Limit
 }
 
-func NewServer(tokenProvider tokenProvider, logger *slog.Logger, chatService chatService, projectService projectService, publisher publisher) *Server {
+// NewServer creates the websocket server, originPatterns are the origins allowed to open
+// connections from a browser, the same ones allowed by CORS. messageLimit is applied to the
+// messages of every connection on its own, messages over it are dropped.
+func NewServer(tokenProvider tokenProvider, sessionChecker sessionChecker, logger *slog.Logger, chatService chatService, projectService projectService, publisher publisher, originPatterns []string, messageLimit ratelimit.Limit) *Server {
 	ws := &Server{
 		rooms:          make(map[uuid.UUID]*WsRoom),
 		logger:         logger,
 		mutex:          sync.Mutex{},
 		tokenProvider:  tokenProvider,
+		sessionChecker: sessionChecker,
 		chatService:    chatService,
 		projectService: projectService,
 		publisher:      publisher,
-		users:          make(map[uuid.UUID]*WsUser),
+		connections:    make(map[uuid.UUID]*WsUser),
+		tickets:        newTicketStore(),
+		originPatterns: originPatterns,
+		messageLimit:   messageLimit,
 	}
 
 	go func() {
 		usersOnlineTicker := time.NewTicker(usersOnlineInterval)
 
 		for range usersOnlineTicker.C {
-			for _, room := range ws.rooms {
+			for roomId, userIds := range ws.usersOnline() {
 				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 
-				userIds := []uuid.UUID{}
-				for userId := range room.users {
-					userIds = append(userIds, userId)
-				}
-
 				message := WebsocketMessage{
 					Type:   WebsocketMessageTypeUsersOnline,
-					RoomId: room.id,
+					RoomId: roomId,
 					Data:   userIds,
 				}
 
-				ws.sendMessageToRoom(ctx, room.id, message)
+				ws.sendMessageToRoom(ctx, roomId, message)
 
 				cancel()
 			}
@@ -109,6 +137,53 @@ func NewServer(tokenProvider tokenProvider, logger *slog.Logger, chatService cha
 	return ws
 }
 
+// usersOnline lists the users of every room once, no matter how many connections they have
+// open in it.
+func (ws *Server) usersOnline() map[uuid.UUID][]uuid.UUID {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	online := make(map[uuid.UUID][]uuid.UUID, len(ws.rooms))
+	for _, room := range ws.rooms {
+		seen := make(map[uuid.UUID]bool)
+		userIds := []uuid.UUID{}
+		for connectionId := range room.connections {
+			connection, ok := ws.connections[connectionId]
+			if !ok || seen[connection.id] {
+				continue
+			}
+			seen[connection.id] = true
+			userIds = append(userIds, connection.id)
+		}
+		online[room.id] = userIds
+	}
+
+	return online
+}
+
+// RegisterMetrics exposes the open connections and rooms, they are read from the server on
+// every scrape.
+func (ws *Server) RegisterMetrics() {
+	metrics.Register(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "websocket_connections",
+			Help: "Open websocket connections.",
+		}, func() float64 {
+			ws.mutex.Lock()
+			defer ws.mutex.Unlock()
+			return float64(len(ws.connections))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "websocket_rooms",
+			Help: "Websocket rooms with at least one user.",
+		}, func() float64 {
+			ws.mutex.Lock()
+			defer ws.mutex.Unlock()
+			return float64(len(ws.rooms))
+		}),
+	)
+}
+
 func (ws *Server) SendEvent(ctx context.Context, wsMessage WebsocketMessage) error {
 	websocketMessage := WebsocketMessage{
 		Type:   wsMessage.Type,
@@ -129,4 +204,161 @@ func (ws *Server) SendUpdatedTask(ctx context.Context, task *domain.Task) error
 
 func (ws *Server) SendCreatedTask(ctx context.Context, task *domain.Task) error {
 	return ws.SendEvent(ctx, MapTaskCreated(task))
-}
\ No newline at end of file
+}
+
+func (ws *Server) SendCreatedTaskComment(ctx context.Context, comment *domain.TaskComment) error {
+	return ws.SendEvent(ctx, MapTaskCommentCreated(comment))
+}
+
+func (ws *Server) SendProjectActivity(ctx context.Context, activity *domain.ProjectActivity) error {
+	return ws.SendEvent(ctx, MapProjectActivity(activity))
+}
+
+// IssueTicket creates a single-use ticket to open a connection for the user, the connection
+// belongs to the session and expires with the access token the ticket was asked with. The
+// session must not be revoked.
+func (ws *Server) IssueTicket(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, tokenExpiresAt time.Time) (*Ticket, error) {
+	err := ws.sessionChecker.CheckSession(ctx, userId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(ticketDuration)
+
+	plain, err := ws.tickets.issue(wsTicket{
+		userId:         userId,
+		sessionId:      sessionId,
+		tokenExpiresAt: tokenExpiresAt,
+		expiresAt:      expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ticket{Ticket: plain, ExpiresAt: expiresAt}, nil
+}
+
+// SendUserUpdated tells the rooms where the user is a member that their profile changed.
+func (ws *Server) SendUserUpdated(ctx context.Context, user *domain.User, roomIds ...uuid.UUID) error {
+	data := UserUpdatedData{
+		UserId:    user.Id,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarUrl: user.AvatarUrl,
+	}
+
+	for _, roomId := range roomIds {
+		err := ws.sendMessageToRoom(ctx, roomId, WebsocketMessage{
+			Type:   WebsocketMessageTypeUserUpdated,
+			RoomId: roomId,
+			Data:   data,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveUserFromRooms evicts a user that lost access to the given rooms, their connections
+// are told about it and the remaining users see them disconnecting.
+func (ws *Server) RemoveUserFromRooms(ctx context.Context, userId uuid.UUID, roomIds ...uuid.UUID) error {
+	for _, roomId := range roomIds {
+		ws.disconnectUserFromRoom(userId, roomId)
+
+		data := UserDisconnectedData{
+			UserId: userId,
+			RoomId: roomId,
+		}
+
+		ws.sendMessageToUser(ctx, userId, WebsocketMessage{
+			Type:   WebsocketMessageTypeRemovedFromRoom,
+			RoomId: roomId,
+			Data:   data,
+		})
+
+		err := ws.sendMessageToRoom(ctx, roomId, WebsocketMessage{
+			Type:   WebsocketMessageTypeUserDisconnected,
+			RoomId: roomId,
+			Data:   data,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloseRooms evicts every user from the given rooms, it is used when the project or chat
+// behind a room is no longer available.
+func (ws *Server) CloseRooms(ctx context.Context, roomIds ...uuid.UUID) error {
+	for _, roomId := range roomIds {
+		ws.mutex.Lock()
+		connectionUserIds := make(map[uuid.UUID]uuid.UUID)
+		if room, ok := ws.rooms[roomId]; ok {
+			for connectionId := range room.connections {
+				if connection, ok := ws.connections[connectionId]; ok {
+					connectionUserIds[connectionId] = connection.id
+				}
+			}
+		}
+		ws.mutex.Unlock()
+
+		for connectionId, userId := range connectionUserIds {
+			ws.disconnectConnectionFromRoom(connectionId, roomId)
+
+			ws.sendMessageToConnection(ctx, connectionId, WebsocketMessage{
+				Type:   WebsocketMessageTypeRemovedFromRoom,
+				RoomId: roomId,
+				Data: UserDisconnectedData{
+					UserId: userId,
+					RoomId: roomId,
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+// CloseSession closes the connections opened with access tokens of a revoked session.
+func (ws *Server) CloseSession(ctx context.Context, sessionId uuid.UUID) error {
+	if sessionId == uuid.Nil {
+		return nil
+	}
+
+	ws.mutex.Lock()
+	closers := []func(websocket.StatusCode, string){}
+	for _, connection := range ws.connections {
+		if connection.sessionId == sessionId && connection.close != nil {
+			closers = append(closers, connection.close)
+		}
+	}
+	ws.mutex.Unlock()
+
+	for _, closeConnection := range closers {
+		closeConnection(websocket.StatusPolicyViolation, "session revoked")
+	}
+
+	return nil
+}
+
+// CloseUser closes the connections of a user whose account was deleted.
+func (ws *Server) CloseUser(ctx context.Context, userId uuid.UUID) error {
+	ws.mutex.Lock()
+	closers := []func(websocket.StatusCode, string){}
+	for _, connection := range ws.connections {
+		if connection.id == userId && connection.close != nil {
+			closers = append(closers, connection.close)
+		}
+	}
+	ws.mutex.Unlock()
+
+	for _, closeConnection := range closers {
+		closeConnection(websocket.StatusPolicyViolation, "account deleted")
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gabrielnakaema/project-chat/internal/domain"
+	"github.com/gabrielnakaema/project-chat/internal/events"
+	"github.com/gabrielnakaema/project-chat/internal/ratelimit"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokenProvider struct {
+	claims map[string]jwt.MapClaims
+}
+
+func (f *fakeTokenProvider) Verify(token string) (*jwt.Token, error) {
+	claims, ok := f.claims[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+
+	return &jwt.Token{Claims: claims, Valid: true}, nil
+}
+
+type fakeSessionChecker struct {
+	revoked map[uuid.UUID]bool
+}
+
+func (f *fakeSessionChecker) CheckSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	if f.revoked[sessionId] {
+		return domain.UnauthorizedError("session revoked")
+	}
+
+	return nil
+}
+
+type fakePublisher struct{}
+
+func (f *fakePublisher) Publish(ctx context.Context, event events.Topic, data any) error {
+	return nil
+}
+
+func newTestServer(tokenProvider tokenProvider, sessionChecker sessionChecker) *Server {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewServer(tokenProvider, sessionChecker, logger, nil, nil, &fakePublisher{}, nil, ratelimit.Limit{})
+}
+
+func TestServer_VerifyToken(t *testing.T) {
+	userId := uuid.New()
+	sessionId := uuid.New()
+	revokedSessionId := uuid.New()
+	expiresAt := time.Now().Add(15 * time.Minute).Truncate(time.Second)
+
+	claims := func(sub string, sid string, exp time.Time) jwt.MapClaims {
+		return jwt.MapClaims{"sub": sub, "sid": sid, "exp": float64(exp.Unix())}
+	}
+
+	tokenProvider := &fakeTokenProvider{
+		claims: map[string]jwt.MapClaims{
+			"valid":           claims(userId.String(), sessionId.String(), expiresAt),
+			"another user":    claims(uuid.NewString(), sessionId.String(), expiresAt),
+			"another session": claims(userId.String(), uuid.NewString(), expiresAt),
+			"no session":      claims(userId.String(), "", expiresAt),
+			"revoked session": claims(userId.String(), revokedSessionId.String(), expiresAt),
+			"expired":         claims(userId.String(), sessionId.String(), time.Now().Add(-time.Minute)),
+		},
+	}
+	sessionChecker := &fakeSessionChecker{revoked: map[uuid.UUID]bool{revokedSessionId: true}}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError bool
+	}{
+		{"valid token", "valid", false},
+		{"token of another user", "another user", true},
+		{"token of another session", "another session", true},
+		{"token without session", "no session", true},
+		{"token of a revoked session", "revoked session", true},
+		{"expired token", "expired", true},
+		{"invalid token", "invalid", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(tokenProvider, sessionChecker)
+			connectionId := uuid.New()
+			user := &WsUser{
+				id:             userId,
+				connectionId:   connectionId,
+				sessionId:      sessionId,
+				tokenExpiresAt: time.Now().Add(time.Minute),
+				expiryNotified: true,
+				rooms:          make(map[uuid.UUID]bool),
+			}
+			server.connections[connectionId] = user
+			previousExpiresAt := user.tokenExpiresAt
+
+			exp, err := server.verifyToken(context.Background(), connectionId, userId, tt.token)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.Equal(t, previousExpiresAt, user.tokenExpiresAt)
+				assert.True(t, user.expiryNotified)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, exp.Equal(expiresAt))
+			assert.True(t, user.tokenExpiresAt.Equal(expiresAt))
+			assert.False(t, user.expiryNotified)
+		})
+	}
+}
+
+func TestServer_ClosesConnectionWhenTokenExpires(t *testing.T) {
+	previousInterval := tokenCheckInterval
+	tokenCheckInterval = 20 * time.Millisecond
+	defer func() { tokenCheckInterval = previousInterval }()
+
+	server := newTestServer(&fakeTokenProvider{}, &fakeSessionChecker{})
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.Handler))
+	defer httpServer.Close()
+
+	userId := uuid.New()
+	ticket, err := server.IssueTicket(context.Background(), userId, uuid.New(), time.Now().Add(200*time.Millisecond))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "?ticket=" + ticket.Ticket
+	c, _, err := websocket.Dial(ctx, url, nil)
+	assert.NoError(t, err)
+	defer c.CloseNow()
+
+	messageTypes := []WebsocketMessageType{}
+	for {
+		var message WebsocketMessage
+		err = wsjson.Read(ctx, c, &message)
+		if err != nil {
+			break
+		}
+		messageTypes = append(messageTypes, message.Type)
+	}
+
+	assert.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err))
+	assert.Contains(t, messageTypes, WebsocketMessageTypeTokenExpiring)
+}
+
+func TestServer_ClosesEveryConnectionOfTheUser(t *testing.T) {
+	server := newTestServer(&fakeTokenProvider{}, &fakeSessionChecker{})
+
+	httpServer := httptest.NewServer(http.HandlerFunc(server.Handler))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	userId := uuid.New()
+	firstSessionId := uuid.New()
+	secondSessionId := uuid.New()
+
+	dial := func(sessionId uuid.UUID) *websocket.Conn {
+		ticket, err := server.IssueTicket(ctx, userId, sessionId, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+
+		url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "?ticket=" + ticket.Ticket
+		c, _, err := websocket.Dial(ctx, url, nil)
+		require.NoError(t, err)
+		return c
+	}
+
+	connectionCount := func() int {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		return len(server.connections)
+	}
+
+	// the close handshake needs the clients to keep reading while the server closes them
+	readCloseStatus := func(c *websocket.Conn) chan websocket.StatusCode {
+		status := make(chan websocket.StatusCode, 1)
+		go func() {
+			for {
+				var message WebsocketMessage
+				err := wsjson.Read(ctx, c, &message)
+				if err != nil {
+					status <- websocket.CloseStatus(err)
+					return
+				}
+			}
+		}()
+		return status
+	}
+
+	first := dial(firstSessionId)
+	defer first.CloseNow()
+	second := dial(secondSessionId)
+	defer second.CloseNow()
+	third := dial(secondSessionId)
+	defer third.CloseNow()
+
+	assert.Eventually(t, func() bool { return connectionCount() == 3 }, time.Second, 10*time.Millisecond)
+
+	// closing one connection leaves the others of the user open
+	first.Close(websocket.StatusNormalClosure, "bye")
+	assert.Eventually(t, func() bool { return connectionCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	secondStatus := readCloseStatus(second)
+	thirdStatus := readCloseStatus(third)
+
+	err := server.CloseSession(ctx, secondSessionId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, websocket.StatusPolicyViolation, <-secondStatus)
+	assert.Equal(t, websocket.StatusPolicyViolation, <-thirdStatus)
+	assert.Eventually(t, func() bool { return connectionCount() == 0 }, time.Second, 10*time.Millisecond)
+
+	fourth := dial(firstSessionId)
+	defer fourth.CloseNow()
+	fifth := dial(secondSessionId)
+	defer fifth.CloseNow()
+
+	assert.Eventually(t, func() bool { return connectionCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	fourthStatus := readCloseStatus(fourth)
+	fifthStatus := readCloseStatus(fifth)
+
+	err = server.CloseUser(ctx, userId)
+	assert.NoError(t, err)
+
+	assert.Equal(t, websocket.StatusPolicyViolation, <-fourthStatus)
+	assert.Equal(t, websocket.StatusPolicyViolation, <-fifthStatus)
+}
+
+func TestServer_DisconnectConnectionFromRoom(t *testing.T) {
+	server := newTestServer(&fakeTokenProvider{}, &fakeSessionChecker{})
+
+	userId := uuid.New()
+	roomId := uuid.New()
+	firstConnectionId := uuid.New()
+	secondConnectionId := uuid.New()
+
+	for _, connectionId := range []uuid.UUID{firstConnectionId, secondConnectionId} {
+		server.connections[connectionId] = &WsUser{
+			id:           userId,
+			connectionId: connectionId,
+			rooms:        map[uuid.UUID]bool{roomId: true},
+		}
+	}
+	server.rooms[roomId] = &WsRoom{
+		id:          roomId,
+		connections: map[uuid.UUID]bool{firstConnectionId: true, secondConnectionId: true},
+	}
+
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{roomId: {userId}}, server.usersOnline())
+
+	stillInRoom := server.disconnectConnectionFromRoom(firstConnectionId, roomId)
+	assert.True(t, stillInRoom)
+	assert.NotContains(t, server.connections[firstConnectionId].rooms, roomId)
+	assert.Contains(t, server.rooms, roomId)
+
+	stillInRoom = server.disconnectConnectionFromRoom(secondConnectionId, roomId)
+	assert.False(t, stillInRoom)
+	assert.NotContains(t, server.rooms, roomId)
+}
@@ -0,0 +1,94 @@
+package ws
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ticketDuration is how long a ticket can be used to open a connection, clients ask for one
+// right before connecting.
+const ticketDuration = 30 * time.Second
+
+// Ticket authenticates a single websocket connection. It is handed out by an authenticated
+// REST endpoint so access tokens never show up in the URL of the websocket request.
+type Ticket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// wsTicket is what a ticket was issued for, the connection opened with it inherits the
+// session and the expiry of the access token used to ask for it.
+type wsTicket struct {
+	userId         uuid.UUID
+	sessionId      uuid.UUID
+	tokenExpiresAt time.Time
+	expiresAt      time.Time
+}
+
+// ticketStore keeps the issued tickets in memory, only the hash of each ticket is stored and
+// it is deleted as soon as it is used.
+type ticketStore struct {
+	mutex   sync.Mutex
+	tickets map[string]wsTicket
+}
+
+func newTicketStore() *ticketStore {
+	return &ticketStore{
+		tickets: make(map[string]wsTicket),
+	}
+}
+
+func (ts *ticketStore) issue(ticket wsTicket) (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	plain := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(b)
+
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	now := time.Now()
+	for hash, issued := range ts.tickets {
+		if !now.Before(issued.expiresAt) {
+			delete(ts.tickets, hash)
+		}
+	}
+
+	ts.tickets[hashTicket(plain)] = ticket
+
+	return plain, nil
+}
+
+// redeem consumes the ticket, unknown, used and expired tickets are all reported the same way.
+func (ts *ticketStore) redeem(plain string) (wsTicket, bool) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	hash := hashTicket(plain)
+
+	ticket, ok := ts.tickets[hash]
+	if !ok {
+		return wsTicket{}, false
+	}
+
+	delete(ts.tickets, hash)
+
+	if !time.Now().Before(ticket.expiresAt) {
+		return wsTicket{}, false
+	}
+
+	return ticket, true
+}
+
+func hashTicket(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,84 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketStore_Redeem(t *testing.T) {
+	userId := uuid.New()
+	sessionId := uuid.New()
+	tokenExpiresAt := time.Now().Add(15 * time.Minute)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		redeems   []bool
+	}{
+		{"redeemed once", time.Now().Add(ticketDuration), []bool{true, false}},
+		{"expired", time.Now().Add(-time.Second), []bool{false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTicketStore()
+
+			plain, err := store.issue(wsTicket{
+				userId:         userId,
+				sessionId:      sessionId,
+				tokenExpiresAt: tokenExpiresAt,
+				expiresAt:      tt.expiresAt,
+			})
+			assert.NoError(t, err)
+
+			for _, expected := range tt.redeems {
+				ticket, ok := store.redeem(plain)
+
+				assert.Equal(t, expected, ok)
+				if expected {
+					assert.Equal(t, userId, ticket.userId)
+					assert.Equal(t, sessionId, ticket.sessionId)
+					assert.Equal(t, tokenExpiresAt, ticket.tokenExpiresAt)
+				}
+			}
+		})
+	}
+}
+
+func TestTicketStore_RedeemUnknownTicket(t *testing.T) {
+	store := newTicketStore()
+
+	_, err := store.issue(wsTicket{userId: uuid.New(), expiresAt: time.Now().Add(ticketDuration)})
+	assert.NoError(t, err)
+
+	_, ok := store.redeem("unknown")
+	assert.False(t, ok)
+}
+
+func TestTicketStore_IssueRemovesExpiredTickets(t *testing.T) {
+	store := newTicketStore()
+
+	_, err := store.issue(wsTicket{userId: uuid.New(), expiresAt: time.Now().Add(-time.Second)})
+	assert.NoError(t, err)
+
+	_, err = store.issue(wsTicket{userId: uuid.New(), expiresAt: time.Now().Add(ticketDuration)})
+	assert.NoError(t, err)
+
+	assert.Len(t, store.tickets, 1)
+}
+
+func TestTicketStore_IssueStoresOnlyTheHash(t *testing.T) {
+	store := newTicketStore()
+
+	plain, err := store.issue(wsTicket{userId: uuid.New(), expiresAt: time.Now().Add(ticketDuration)})
+	assert.NoError(t, err)
+
+	_, ok := store.tickets[plain]
+	assert.False(t, ok)
+
+	_, ok = store.tickets[hashTicket(plain)]
+	assert.True(t, ok)
+}
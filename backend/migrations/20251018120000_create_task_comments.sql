@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS task_comments (
+	id uuid primary key not null default gen_random_uuid(),
+	task_id uuid not null,
+	user_id uuid not null,
+	content text not null,
+	created_at timestamp with time zone default current_timestamp not null,
+	updated_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE task_comments ADD CONSTRAINT fk_task_comments_tasks FOREIGN KEY (task_id) REFERENCES tasks(id);
+ALTER TABLE task_comments ADD CONSTRAINT fk_task_comments_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id_created_at ON task_comments (task_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS task_comments;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE tasks ADD COLUMN parent_id uuid;
+ALTER TABLE tasks ADD CONSTRAINT fk_tasks_parent_tasks FOREIGN KEY (parent_id) REFERENCES tasks(id);
+ALTER TABLE tasks ADD CONSTRAINT tasks_parent_id_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);
+
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+	id uuid primary key not null default gen_random_uuid(),
+	task_id uuid not null,
+	title text not null,
+	done bool not null default false,
+	created_at timestamp with time zone default current_timestamp not null,
+	updated_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE task_checklist_items ADD CONSTRAINT fk_task_checklist_items_tasks FOREIGN KEY (task_id) REFERENCES tasks(id);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id_created_at ON task_checklist_items (task_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS task_checklist_items;
+
+ALTER TABLE tasks DROP CONSTRAINT tasks_parent_id_not_self;
+ALTER TABLE tasks DROP CONSTRAINT fk_tasks_parent_tasks;
+ALTER TABLE tasks DROP COLUMN parent_id;
+
+-- +goose StatementEnd
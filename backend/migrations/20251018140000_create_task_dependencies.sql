@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+	task_id uuid not null,
+	blocked_by_task_id uuid not null,
+	created_at timestamp with time zone default current_timestamp not null,
+	primary key (task_id, blocked_by_task_id)
+);
+
+ALTER TABLE task_dependencies ADD CONSTRAINT fk_task_dependencies_tasks FOREIGN KEY (task_id) REFERENCES tasks(id);
+ALTER TABLE task_dependencies ADD CONSTRAINT fk_task_dependencies_blocked_by_tasks FOREIGN KEY (blocked_by_task_id) REFERENCES tasks(id);
+ALTER TABLE task_dependencies ADD CONSTRAINT task_dependencies_not_self CHECK (task_id <> blocked_by_task_id);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_task_id ON task_dependencies (blocked_by_task_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS task_dependencies;
+
+-- +goose StatementEnd
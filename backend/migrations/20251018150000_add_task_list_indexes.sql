@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id_created_at_id ON tasks (project_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id_updated_at_id ON tasks (project_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id_status ON tasks (project_id, status);
+CREATE INDEX IF NOT EXISTS idx_tasks_author_id ON tasks (author_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_title_trgm ON tasks USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_description_trgm ON tasks USING gin (description gin_trgm_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP INDEX IF EXISTS idx_tasks_description_trgm;
+DROP INDEX IF EXISTS idx_tasks_title_trgm;
+DROP INDEX IF EXISTS idx_tasks_author_id;
+DROP INDEX IF EXISTS idx_tasks_project_id_status;
+DROP INDEX IF EXISTS idx_tasks_project_id_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_project_id_created_at_id;
+
+-- +goose StatementEnd
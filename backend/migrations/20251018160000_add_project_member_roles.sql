@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+UPDATE project_members SET role = 'owner' WHERE role = 'creator';
+
+ALTER TABLE project_members ADD CONSTRAINT project_members_role_check CHECK (role IN ('owner', 'admin', 'member', 'viewer'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE project_members DROP CONSTRAINT project_members_role_check;
+
+UPDATE project_members SET role = 'creator' WHERE role = 'owner';
+UPDATE project_members SET role = 'member' WHERE role IN ('admin', 'viewer');
+
+-- +goose StatementEnd
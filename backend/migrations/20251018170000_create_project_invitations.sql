@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS project_invitations (
+	id uuid primary key not null default gen_random_uuid(),
+	project_id uuid not null,
+	email text not null,
+	role text not null,
+	token_hash text not null,
+	invited_by uuid not null,
+	expires_at timestamp with time zone not null,
+	accepted_at timestamp with time zone,
+	revoked_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE project_invitations ADD CONSTRAINT fk_project_invitations_projects FOREIGN KEY (project_id) REFERENCES projects(id);
+ALTER TABLE project_invitations ADD CONSTRAINT fk_project_invitations_users FOREIGN KEY (invited_by) REFERENCES users(id);
+ALTER TABLE project_invitations ADD CONSTRAINT project_invitations_role_check CHECK (role IN ('admin', 'member', 'viewer'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_invitations_token_hash ON project_invitations (token_hash);
+CREATE INDEX IF NOT EXISTS idx_project_invitations_project_id ON project_invitations (project_id);
+CREATE INDEX IF NOT EXISTS idx_project_invitations_email ON project_invitations (lower(email));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS project_invitations;
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS archived_at timestamp with time zone;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE projects DROP COLUMN IF EXISTS archived_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS project_templates (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	name text not null,
+	description text not null,
+	tasks jsonb not null default '[]'::jsonb,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE project_templates ADD CONSTRAINT fk_project_templates_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_project_templates_user_id ON project_templates (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS project_templates;
+
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS organizations (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	name text not null,
+	personal boolean default false not null,
+	created_at timestamp with time zone default current_timestamp not null,
+	updated_at timestamp with time zone default current_timestamp not null
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+	id uuid primary key not null default gen_random_uuid(),
+	organization_id uuid not null,
+	user_id uuid not null,
+	role text not null,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE organizations ADD CONSTRAINT fk_organizations_users FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE organization_members ADD CONSTRAINT fk_organization_members_organizations FOREIGN KEY (organization_id) REFERENCES organizations(id);
+ALTER TABLE organization_members ADD CONSTRAINT fk_organization_members_users FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_role_check CHECK (role IN ('owner', 'admin', 'member'));
+ALTER TABLE organization_members ADD CONSTRAINT organization_members_organization_id_user_id_key UNIQUE (organization_id, user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_personal_user_id ON organizations (user_id) WHERE personal;
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members (user_id);
+
+-- every existing user gets a personal workspace that owns their projects
+INSERT INTO organizations (user_id, name, personal)
+SELECT id, 'Personal', true FROM users;
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT id, user_id, 'owner' FROM organizations WHERE personal;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS organization_id uuid;
+
+UPDATE projects p
+SET organization_id = o.id
+FROM organizations o
+WHERE o.personal AND o.user_id = p.user_id;
+
+ALTER TABLE projects ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE projects ADD CONSTRAINT fk_projects_organizations FOREIGN KEY (organization_id) REFERENCES organizations(id);
+
+CREATE INDEX IF NOT EXISTS idx_projects_organization_id ON projects (organization_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE projects DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS project_activities (
+	id uuid primary key not null default gen_random_uuid(),
+	project_id uuid not null,
+	actor_id uuid,
+	type text not null,
+	payload jsonb not null,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE project_activities ADD CONSTRAINT fk_project_activities_projects FOREIGN KEY (project_id) REFERENCES projects(id);
+ALTER TABLE project_activities ADD CONSTRAINT fk_project_activities_users FOREIGN KEY (actor_id) REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_project_activities_project_id_created_at ON project_activities (project_id, created_at DESC, id DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS project_activities;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	purpose text not null,
+	token_hash text not null,
+	expires_at timestamp with time zone not null,
+	used_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE user_tokens ADD CONSTRAINT fk_user_tokens_users FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('email_verification', 'password_reset'));
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_token_hash_key UNIQUE (token_hash);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS user_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	user_agent text not null default '',
+	ip_address text not null default '',
+	last_used_at timestamp with time zone default current_timestamp not null,
+	revoked_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE user_sessions ADD CONSTRAINT fk_user_sessions_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions (user_id);
+
+-- every existing refresh token starts its own session
+INSERT INTO user_sessions (id, user_id, last_used_at, revoked_at, created_at)
+SELECT id, user_id, created_at, CASE WHEN active THEN NULL ELSE current_timestamp END, created_at FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id uuid;
+UPDATE refresh_tokens SET session_id = id;
+ALTER TABLE refresh_tokens ALTER COLUMN session_id SET NOT NULL;
+
+ALTER TABLE refresh_tokens ADD CONSTRAINT fk_refresh_tokens_user_sessions FOREIGN KEY (session_id) REFERENCES user_sessions(id);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_user_sessions;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS user_sessions;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+	id uuid primary key not null default gen_random_uuid(),
+	email text not null,
+	ip_address text not null,
+	user_id uuid,
+	success bool not null,
+	failure_reason text not null default '',
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE login_attempts ADD CONSTRAINT fk_login_attempts_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_email_created_at ON login_attempts (email, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_address_created_at ON login_attempts (ip_address, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS login_attempts;
+
+-- +goose StatementEnd
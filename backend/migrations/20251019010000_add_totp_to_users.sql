@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at timestamp with time zone;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step bigint not null default 0;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	code_hash text not null,
+	used_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE user_recovery_codes ADD CONSTRAINT fk_user_recovery_codes_users FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE user_recovery_codes ADD CONSTRAINT user_recovery_codes_user_id_code_hash_key UNIQUE (user_id, code_hash);
+
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('email_verification', 'password_reset', 'mfa_challenge'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DELETE FROM user_tokens WHERE purpose = 'mfa_challenge';
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('email_verification', 'password_reset'));
+
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	name text not null,
+	token_hash text not null,
+	scopes text[] not null default '{}',
+	expires_at timestamp with time zone not null,
+	last_used_at timestamp with time zone,
+	revoked_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE personal_access_tokens ADD CONSTRAINT fk_personal_access_tokens_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS personal_access_tokens;
+
+-- +goose StatementEnd
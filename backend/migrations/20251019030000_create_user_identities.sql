@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+CREATE TABLE IF NOT EXISTS user_identities (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	issuer text not null,
+	subject text not null,
+	email text not null default '',
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE user_identities ADD CONSTRAINT fk_user_identities_users FOREIGN KEY (user_id) REFERENCES users(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_issuer_subject ON user_identities (issuer, subject);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+	id uuid primary key not null default gen_random_uuid(),
+	state_hash text not null,
+	nonce text not null,
+	code_verifier text not null,
+	expires_at timestamp with time zone not null,
+	used_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oidc_login_states_state_hash ON oidc_login_states (state_hash);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url text not null default '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone text not null default 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale text not null default 'en';
+
+ALTER TABLE user_tokens ADD COLUMN IF NOT EXISTS email text;
+
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('email_verification', 'password_reset', 'mfa_challenge', 'email_change'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DELETE FROM user_tokens WHERE purpose = 'email_change';
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check CHECK (purpose IN ('email_verification', 'password_reset', 'mfa_challenge'));
+
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS email;
+
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_url;
+
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS user_data_exports (
+	id uuid primary key not null default gen_random_uuid(),
+	user_id uuid not null,
+	status text not null default 'pending',
+	archive bytea,
+	expires_at timestamp with time zone not null,
+	completed_at timestamp with time zone,
+	created_at timestamp with time zone default current_timestamp not null
+);
+
+ALTER TABLE user_data_exports ADD CONSTRAINT fk_user_data_exports_users FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE user_data_exports ADD CONSTRAINT user_data_exports_status_check CHECK (status IN ('pending', 'ready', 'failed'));
+
+CREATE INDEX IF NOT EXISTS idx_user_data_exports_user_id ON user_data_exports (user_id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_user_id ON chat_messages (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+DROP INDEX IF EXISTS idx_chat_messages_user_id;
+DROP TABLE IF EXISTS user_data_exports;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
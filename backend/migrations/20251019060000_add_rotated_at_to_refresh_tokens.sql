@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at timestamp with time zone;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS actor_id uuid;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS subject_id uuid;
+ALTER TABLE chat_messages ADD CONSTRAINT fk_chat_messages_actors FOREIGN KEY (actor_id) REFERENCES users(id);
+ALTER TABLE chat_messages ADD CONSTRAINT fk_chat_messages_subjects FOREIGN KEY (subject_id) REFERENCES users(id);
+
+-- System messages named users by the name they had when the message was sent. Existing ones
+-- are matched against the users who were part of the chat, the ones that match no one keep
+-- their text.
+CREATE TEMPORARY TABLE chat_users ON COMMIT DROP AS
+SELECT chat_id, user_id FROM chat_members
+UNION
+SELECT c.id, pa.actor_id FROM chats c JOIN project_activities pa ON pa.project_id = c.project_id WHERE pa.actor_id IS NOT NULL
+UNION
+SELECT c.id, (pa.payload->>'user_id')::uuid FROM chats c JOIN project_activities pa ON pa.project_id = c.project_id WHERE pa.payload ? 'user_id'
+UNION
+SELECT c.id, (pa.payload->>'new_owner_id')::uuid FROM chats c JOIN project_activities pa ON pa.project_id = c.project_id WHERE pa.payload ? 'new_owner_id';
+
+UPDATE chat_messages cm SET content = '{subject} has joined the chat', subject_id = u.id
+FROM chat_users cu JOIN users u ON u.id = cu.user_id
+WHERE cm.chat_id = cu.chat_id AND cm.message_type = 'system' AND cm.content = u.name || ' has joined the chat';
+
+UPDATE chat_messages cm SET content = '{subject} left the chat', subject_id = u.id
+FROM chat_users cu JOIN users u ON u.id = cu.user_id
+WHERE cm.chat_id = cu.chat_id AND cm.message_type = 'system' AND cm.content = u.name || ' left the chat';
+
+UPDATE chat_messages cm SET content = '{actor} transferred project ownership to {subject}', actor_id = pu.id, subject_id = nu.id
+FROM chat_users pcu JOIN users pu ON pu.id = pcu.user_id, chat_users ncu JOIN users nu ON nu.id = ncu.user_id
+WHERE cm.chat_id = pcu.chat_id AND cm.chat_id = ncu.chat_id AND cm.message_type = 'system'
+  AND cm.content = pu.name || ' transferred project ownership to ' || nu.name;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+UPDATE chat_messages SET content = replace(
+    replace(content, '{actor}', coalesce((SELECT name FROM users WHERE users.id = chat_messages.actor_id), '')),
+    '{subject}',
+    coalesce((SELECT name FROM users WHERE users.id = chat_messages.subject_id), '')
+  )
+WHERE message_type = 'system' AND (actor_id IS NOT NULL OR subject_id IS NOT NULL);
+
+ALTER TABLE chat_messages DROP CONSTRAINT IF EXISTS fk_chat_messages_subjects;
+ALTER TABLE chat_messages DROP CONSTRAINT IF EXISTS fk_chat_messages_actors;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS subject_id;
+ALTER TABLE chat_messages DROP COLUMN IF EXISTS actor_id;
+
+-- +goose StatementEnd
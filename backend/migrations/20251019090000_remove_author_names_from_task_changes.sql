@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- The author is added to the description when it is read. Descriptions end with the name the
+-- author had back then, when it is no longer their name everything after the last " by " is
+-- taken as the name.
+UPDATE task_changes tc SET description = CASE
+    WHEN right(tc.description, length(' by ' || u.name)) = ' by ' || u.name
+    THEN left(tc.description, -length(' by ' || u.name))
+    ELSE regexp_replace(tc.description, '^(.*) by .*$', '\1')
+  END
+FROM users u
+WHERE u.id = tc.user_id;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+UPDATE task_changes tc SET description = tc.description || ' by ' || u.name
+FROM users u
+WHERE u.id = tc.user_id;
+
+-- +goose StatementEnd
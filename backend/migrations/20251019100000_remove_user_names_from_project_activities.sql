@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+
+-- The feed reads the name of the user in the payload from the users table.
+UPDATE project_activities SET payload = payload - 'user_name' WHERE payload ? 'user_name';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+
+UPDATE project_activities pa SET payload = jsonb_set(pa.payload, '{user_name}', to_jsonb(u.name))
+FROM users u
+WHERE u.id = (pa.payload->>'user_id')::uuid;
+
+-- +goose StatementEnd
@@ -1,7 +1,8 @@
 import { createContext, useCallback, useEffect, useEffectEvent, useRef, useState } from 'react';
 import type { SocketEvent } from '@/types/websocket';
 import { useAuth } from '@/hooks/use-auth';
-import { tokenService } from '@/services/api';
+import { refreshAccessToken } from '@/services/api';
+import { requestSocketTicket } from '@/services/auth';
 
 type WebSocketStatus = 'disconnected' | 'connected';
 
@@ -49,6 +50,18 @@ export const SocketProvider = ({ children }: { children: React.ReactNode }) => {
     setStatus('disconnected');
   });
 
+  // the server closes the socket when the access token it was opened with expires, a fresh
+  // token keeps it open. The refresh is shared with the api client so both never rotate the
+  // refresh token at the same time
+  const reauthenticate = async () => {
+    try {
+      const accessToken = await refreshAccessToken();
+      socket.current?.send(JSON.stringify({ type: 'authenticate', data: { token: accessToken } }));
+    } catch (error) {
+      return;
+    }
+  };
+
   const handleMessage = useEffectEvent((event: MessageEvent) => {
     if (socket.current?.readyState !== WebSocket.OPEN) {
       return;
@@ -62,6 +75,11 @@ export const SocketProvider = ({ children }: { children: React.ReactNode }) => {
         return;
       }
 
+      if (data.type === 'token_expiring') {
+        reauthenticate();
+        return;
+      }
+
       subscriptions.forEach((subscription) => subscription.handler(data));
     } catch (error) {
       return;
@@ -74,16 +92,28 @@ export const SocketProvider = ({ children }: { children: React.ReactNode }) => {
       return;
     }
 
-    const newSocket = new WebSocket(`${import.meta.env.VITE_API_URL}/ws?jwt=${tokenService.token}`);
+    let cancelled = false;
+
+    const connect = async () => {
+      const { ticket } = await requestSocketTicket();
+      if (cancelled) {
+        return;
+      }
 
-    newSocket.onopen = handleOpen;
-    newSocket.onclose = handleClose;
-    newSocket.onerror = handleError;
-    newSocket.onmessage = handleMessage;
+      const newSocket = new WebSocket(`${import.meta.env.VITE_API_URL}/ws?ticket=${encodeURIComponent(ticket)}`);
+
+      newSocket.onopen = handleOpen;
+      newSocket.onclose = handleClose;
+      newSocket.onerror = handleError;
+      newSocket.onmessage = handleMessage;
+
+      socket.current = newSocket;
+    };
 
-    socket.current = newSocket;
+    connect().catch(() => setStatus('disconnected'));
 
     return () => {
+      cancelled = true;
       socket.current?.close();
       socket.current = null;
     };
@@ -3,7 +3,7 @@ import { useQuery, useQueryClient } from '@tanstack/react-query';
 import { useSocket } from './use-socket';
 import type { Task } from '@/types/task';
 import type { SocketEvent } from '@/types/websocket';
-import type { Paginated } from '@/types/paginated';
+import type { CursorPaginated } from '@/types/paginated';
 import { taskQueryKeys } from '@/services/query-keys';
 import { listTasksByProjectId } from '@/services/tasks';
 
@@ -23,7 +23,7 @@ export const useProjectTasks = (projectId: string) => {
     if (event.type === 'task_updated') {
       const task = event.data;
 
-      queryClient.setQueryData(taskQueryKeys.listByProjectId(projectId), (old: Paginated<Task>) => {
+      queryClient.setQueryData(taskQueryKeys.listByProjectId(projectId), (old: CursorPaginated<Task>) => {
         const updated = old.data.map((t) => (t.id === task.id ? task : t));
 
         return {
@@ -36,7 +36,7 @@ export const useProjectTasks = (projectId: string) => {
     if (event.type === 'task_created') {
       const task = event.data;
 
-      queryClient.setQueryData(taskQueryKeys.listByProjectId(projectId), (old: Paginated<Task>) => {
+      queryClient.setQueryData(taskQueryKeys.listByProjectId(projectId), (old: CursorPaginated<Task>) => {
         return {
           ...old,
           data: [...old.data, task],
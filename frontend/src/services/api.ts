@@ -41,6 +41,32 @@ class TokenService {
 
 export const tokenService = new TokenService();
 
+// refreshAccessToken is the only place the refresh token is used, callers that ask while a
+// refresh is running wait for it instead of presenting a refresh token it already rotated
+export const refreshAccessToken = async (): Promise<string> => {
+  if (isRefreshing) {
+    return new Promise((resolve, reject) => {
+      failedRequests.push({ resolve: (token) => resolve(token as string), reject });
+    });
+  }
+
+  isRefreshing = true;
+
+  try {
+    const refreshTokenResponse = await attemptRefreshToken();
+
+    tokenService.setToken(refreshTokenResponse.access_token);
+    processQueue(null, refreshTokenResponse.access_token);
+
+    return refreshTokenResponse.access_token;
+  } catch (error) {
+    processQueue(error);
+    throw error;
+  } finally {
+    isRefreshing = false;
+  }
+};
+
 export const api = ky.create({
   prefixUrl: baseApiUrl,
   hooks: {
@@ -81,27 +107,18 @@ export const api = ky.create({
             });
         }
 
-        isRefreshing = true;
-
         try {
-          const refreshTokenResponse = await attemptRefreshToken();
-
-          processQueue(null, refreshTokenResponse.access_token);
+          const accessToken = await refreshAccessToken();
 
-          request.headers.set('Authorization', `Bearer ${refreshTokenResponse.access_token}`);
-          tokenService.setToken(refreshTokenResponse.access_token);
+          request.headers.set('Authorization', `Bearer ${accessToken}`);
           (options as any).isRetry = true;
 
           return ky(request, options);
         } catch (error) {
           attemptLogout();
           tokenService.setToken('');
-          failedRequests = [];
           window.location.reload();
-          processQueue(error);
           throw error;
-        } finally {
-          isRefreshing = false;
         }
       },
     ],
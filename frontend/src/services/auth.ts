@@ -1,6 +1,7 @@
 import { api } from './api';
 import type { ILoginForm } from '@/schemas/login-schema';
 import type { LoginResponse } from '@/types/auth';
+import type { SocketTicket } from '@/types/websocket';
 
 export const login = async (form: ILoginForm) => {
   const payload = {
@@ -33,3 +34,11 @@ export const attemptLogout = async () => {
     credentials: 'include',
   });
 };
+
+export const requestSocketTicket = async () => {
+  const response = await api.post('ws/tickets');
+
+  const json = await response.json<SocketTicket>();
+
+  return json;
+};
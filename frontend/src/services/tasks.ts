@@ -1,13 +1,32 @@
 import { api } from './api';
 import type { Task } from '@/types/task';
 import type { ITaskForm } from '@/schemas/task-schema';
-import type { Paginated } from '@/types/paginated';
+import type { CursorPaginated } from '@/types/paginated';
 
 export const listTasksByProjectId = async (projectId: string) => {
-  const response = await api.get(`tasks?project_id=${projectId}`);
+  const tasks: Task[] = [];
+  let cursor: string | undefined;
 
-  const json = await response.json<Paginated<Task>>();
-  return json;
+  do {
+    const searchParams = new URLSearchParams();
+    searchParams.set('project_id', projectId);
+    searchParams.set('limit', '100');
+
+    if (cursor) {
+      searchParams.set('cursor', cursor);
+    }
+
+    const response = await api.get('tasks', {
+      searchParams,
+    });
+
+    const json = await response.json<CursorPaginated<Task>>();
+    tasks.push(...json.data);
+
+    cursor = json.has_next ? json.next_cursor : undefined;
+  } while (cursor);
+
+  return { data: tasks, has_next: false };
 };
 
 interface CreateTaskRequest {
@@ -57,8 +57,17 @@ export type UserConnectedEvent = {
   };
 };
 
+export type TokenExpiringEvent = {
+  type: 'token_expiring';
+  room_id: string;
+  data: {
+    expires_at: string;
+  };
+};
+
 export type SocketEvent =
   | MessageEvent
+  | TokenExpiringEvent
   | ErrorEvent
   | PingEvent
   | TaskCreatedEvent
@@ -66,3 +75,8 @@ export type SocketEvent =
   | UsersOnlineEvent
   | UserConnectedEvent
   | UserDisconnectedEvent;
+
+export interface SocketTicket {
+  ticket: string;
+  expires_at: string;
+}